@@ -0,0 +1,384 @@
+/*
+ *    Copyright 2019 Insolar Technologies
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/core/message"
+	"github.com/insolar/insolar/core/reply"
+	"github.com/insolar/insolar/core/utils"
+	"github.com/insolar/insolar/instrumentation/inslogger"
+)
+
+// ExecutionQueuesArgs is arguments that ExecutionQueues method of Admin service accepts.
+type ExecutionQueuesArgs struct {
+	Object string
+}
+
+// QueuedRequest is a request in execution queue of an object.
+type QueuedRequest struct {
+	Request    string
+	Caller     string
+	Method     string
+	Age        int64 // in microseconds
+	FromLedger bool
+}
+
+// ExecutionQueue is execution queue of an object on a virtual node.
+type ExecutionQueue struct {
+	Object                string
+	Prototype             string
+	Current               *QueuedRequest
+	Queue                 []QueuedRequest
+	Pending               bool
+	InTransaction         bool
+	LedgerHasMoreRequests bool
+}
+
+// NodeExecutionQueues is execution queues of a virtual node.
+type NodeExecutionQueues struct {
+	Node   string
+	Error  string
+	Queues []ExecutionQueue
+}
+
+// ExecutionQueuesReply is reply for ExecutionQueues method of Admin service.
+type ExecutionQueuesReply struct {
+	Nodes   []NodeExecutionQueues
+	TraceID string
+}
+
+// CancelQueuedRequestArgs is arguments that CancelQueuedRequest method of Admin service accepts.
+type CancelQueuedRequestArgs struct {
+	Object  string
+	Request string
+	Reason  string
+}
+
+// CancelQueuedRequestReply is reply for CancelQueuedRequest method of Admin service.
+type CancelQueuedRequestReply struct {
+	TraceID string
+}
+
+// AdminService is a service that provides API for operators of the network, it is served only on admin listener.
+type AdminService struct {
+	runner *Runner
+}
+
+// NewAdminService creates new Admin service instance.
+func NewAdminService(runner *Runner) *AdminService {
+	return &AdminService{runner: runner}
+}
+
+// ExecutionQueues returns objects that virtual nodes execute requests of or keep requests in queue for.
+//
+//   Request structure:
+//   {
+//     "jsonrpc": "2.0",
+//     "method": "admin.ExecutionQueues",
+//     "params": {
+//       // Reference to object, optional. Queues of all objects are returned if it's empty
+//       "Object": str
+//     },
+//     "id": str|int|null
+//   }
+//
+//   Response structure:
+//   {
+//     "jsonrpc": "2.0",
+//     "result": {
+//       "Nodes": [
+//         {
+//           "Node": str, // reference to virtual node
+//           "Error": str, // error of fetching queues from node if any
+//           "Queues": [
+//             {
+//               "Object": str, // reference to object
+//               "Prototype": str, // reference to prototype, empty if node hasn't fetched object yet
+//               "Current": { ... }, // request executing right now with the same structure as queued ones
+//               "Queue": [
+//                 {
+//                   "Request": str, // reference to request
+//                   "Caller": str, // reference to caller object
+//                   "Method": str, // name of method or constructor
+//                   "Age": int, // time in queue (time of execution for current request) in microseconds
+//                   "FromLedger": bool // request was fetched from ledger as pending
+//                 }
+//               ],
+//               "Pending": bool, // object waits for previous executor to finish
+//               "InTransaction": bool, // object waits for decision on transaction
+//               "LedgerHasMoreRequests": bool // ledger keeps requests that don't fit into queue
+//             }
+//           ]
+//         }
+//       ],
+//       "TraceID": str // traceID for request
+//     },
+//     "id": str|int|null // same as in request
+//   }
+//
+func (s *AdminService) ExecutionQueues(r *http.Request, args *ExecutionQueuesArgs, result *ExecutionQueuesReply) error {
+	traceID := utils.RandTraceID()
+	ctx, inslog := inslogger.WithTraceField(context.Background(), traceID)
+
+	inslog.Infof("[ AdminService.ExecutionQueues ] Incoming request: %s", r.RequestURI)
+
+	var object *core.RecordRef
+	if args.Object != "" {
+		var err error
+		object, err = core.NewRefFromBase58(args.Object)
+		if err != nil {
+			return errors.Wrap(err, "[ AdminService.ExecutionQueues ] Can't parse object reference")
+		}
+	}
+
+	nodes := s.runner.NodeNetwork.GetActiveNodesByRole(core.DynamicRoleVirtualExecutor)
+	if len(nodes) == 0 {
+		return errors.New("[ AdminService.ExecutionQueues ] no active virtual nodes")
+	}
+
+	result.Nodes = make([]NodeExecutionQueues, 0, len(nodes))
+	for _, node := range nodes {
+		node := node
+		found := NodeExecutionQueues{
+			Node:   node.String(),
+			Queues: []ExecutionQueue{},
+		}
+		rep, err := s.runner.MessageBus.Send(ctx, &message.GetExecutionQueues{}, &core.MessageSendOptions{Receiver: &node})
+		if err != nil {
+			found.Error = err.Error()
+			result.Nodes = append(result.Nodes, found)
+			continue
+		}
+		queues, ok := rep.(*reply.ExecutionQueues)
+		if !ok {
+			return errors.Errorf("[ AdminService.ExecutionQueues ] unexpected reply: %#v", rep)
+		}
+		for _, queue := range queues.Queues {
+			if object != nil && !queue.Object.Equal(*object) {
+				continue
+			}
+			found.Queues = append(found.Queues, buildExecutionQueue(queue))
+		}
+		result.Nodes = append(result.Nodes, found)
+	}
+	result.TraceID = traceID
+
+	return nil
+}
+
+func buildExecutionQueue(queue core.ExecutionQueue) ExecutionQueue {
+	res := ExecutionQueue{
+		Object:                queue.Object.String(),
+		Queue:                 make([]QueuedRequest, 0, len(queue.Queue)),
+		Pending:               queue.Pending,
+		InTransaction:         queue.InTransaction,
+		LedgerHasMoreRequests: queue.LedgerHasMoreRequests,
+	}
+	if queue.Prototype != nil {
+		res.Prototype = queue.Prototype.String()
+	}
+	if queue.Current != nil {
+		current := buildQueuedRequest(*queue.Current)
+		res.Current = &current
+	}
+	for _, request := range queue.Queue {
+		res.Queue = append(res.Queue, buildQueuedRequest(request))
+	}
+	return res
+}
+
+func buildQueuedRequest(request core.QueuedRequest) QueuedRequest {
+	res := QueuedRequest{
+		Request:    request.Request.String(),
+		Method:     request.Method,
+		Age:        int64(request.Age / time.Microsecond),
+		FromLedger: request.FromLedger,
+	}
+	if !request.Caller.IsEmpty() {
+		res.Caller = request.Caller.String()
+	}
+	return res
+}
+
+// CancelQueuedRequest removes request from execution queue of the object. Request gets
+// error result with the reason, the caller gets the error if it waits for result.
+// Request that is executing right now can't be canceled. Only executor of the object can
+// cancel its requests, so it should be called on admin listener of the node that ExecutionQueues
+// reports as Node of the queue.
+//
+//   Request structure:
+//   {
+//     "jsonrpc": "2.0",
+//     "method": "admin.CancelQueuedRequest",
+//     "params": {
+//       // Reference to object
+//       "Object": str,
+//       // Reference to request
+//       "Request": str,
+//       // Reason of cancellation, it's a part of error
+//       "Reason": str
+//     },
+//     "id": str|int|null
+//   }
+//
+//   Response structure:
+//   {
+//     "jsonrpc": "2.0",
+//     "result": {
+//       "TraceID": str // traceID for request
+//     },
+//     "id": str|int|null // same as in request
+//   }
+//
+func (s *AdminService) CancelQueuedRequest(r *http.Request, args *CancelQueuedRequestArgs, result *CancelQueuedRequestReply) error {
+	traceID := utils.RandTraceID()
+	ctx, inslog := inslogger.WithTraceField(context.Background(), traceID)
+
+	inslog.Infof("[ AdminService.CancelQueuedRequest ] Incoming request: %s", r.RequestURI)
+
+	object, err := core.NewRefFromBase58(args.Object)
+	if err != nil {
+		return errors.Wrap(err, "[ AdminService.CancelQueuedRequest ] Can't parse object reference")
+	}
+	request, err := core.NewRefFromBase58(args.Request)
+	if err != nil {
+		return errors.Wrap(err, "[ AdminService.CancelQueuedRequest ] Can't parse request reference")
+	}
+
+	rep, err := s.runner.MessageBus.Send(ctx, &message.CancelQueuedRequest{
+		Object:  *object,
+		Request: *request,
+		Reason:  args.Reason,
+	}, nil)
+	if err != nil {
+		return errors.Wrap(err, "[ AdminService.CancelQueuedRequest ] Can't cancel request")
+	}
+	if _, ok := rep.(*reply.OK); !ok {
+		return errors.Errorf("[ AdminService.CancelQueuedRequest ] unexpected reply: %#v", rep)
+	}
+	result.TraceID = traceID
+
+	return nil
+}
+
+// ReplayArgs is arguments that Replay method of Admin service accepts.
+type ReplayArgs struct {
+	Request string
+}
+
+// ReplayReply is reply for Replay method of Admin service.
+type ReplayReply struct {
+	Object            string
+	Method            string
+	State             []byte
+	StoredState       []byte
+	Deactivated       bool
+	StoredDeactivated bool
+	Result            json.RawMessage
+	StoredResult      json.RawMessage
+	Error             string
+	Diff              []string
+	TraceID           string
+}
+
+// Replay executes request made in the past again on a virtual node and compares its outcome
+// with the one stored on ledger. Nothing is changed on ledger, calls to other objects are
+// answered with requests the original execution made. Request must be synced with heavy.
+//
+//   Request structure:
+//   {
+//     "jsonrpc": "2.0",
+//     "method": "admin.Replay",
+//     "params": {
+//       // Reference to request
+//       "Request": str
+//     },
+//     "id": str|int|null
+//   }
+//
+//   Response structure:
+//   {
+//     "jsonrpc": "2.0",
+//     "result": {
+//       "Object": str, // reference to called object
+//       "Method": str, // name of method
+//       "State": str, // memory of object after replay, base64
+//       "StoredState": str, // memory of object stored on ledger, base64
+//       "Deactivated": bool, // replay deactivated object
+//       "StoredDeactivated": bool, // request deactivated object
+//       "Result": [ ... ], // results of method in replay
+//       "StoredResult": [ ... ], // results of method stored on ledger
+//       "Error": str, // error of replay if any
+//       "Diff": [ str ], // differences between replay and stored outcome, empty if request is replayed exactly
+//       "TraceID": str // traceID for request
+//     },
+//     "id": str|int|null // same as in request
+//   }
+//
+func (s *AdminService) Replay(r *http.Request, args *ReplayArgs, result *ReplayReply) error {
+	traceID := utils.RandTraceID()
+	ctx, inslog := inslogger.WithTraceField(context.Background(), traceID)
+
+	inslog.Infof("[ AdminService.Replay ] Incoming request: %s", r.RequestURI)
+
+	request, err := core.NewRefFromBase58(args.Request)
+	if err != nil {
+		return errors.Wrap(err, "[ AdminService.Replay ] Can't parse request reference")
+	}
+
+	rep, err := s.runner.MessageBus.Send(ctx, &message.ReplayRequest{Request: *request}, nil)
+	if err != nil {
+		return errors.Wrap(err, "[ AdminService.Replay ] Can't replay request")
+	}
+	replay, ok := rep.(*reply.Replay)
+	if !ok {
+		return errors.Errorf("[ AdminService.Replay ] unexpected reply: %#v", rep)
+	}
+
+	result.Object = replay.Object.String()
+	result.Method = replay.Method
+	result.State = replay.State
+	result.StoredState = replay.StoredState
+	result.Deactivated = replay.Deactivated
+	result.StoredDeactivated = replay.StoredDeactivated
+	result.Result = resultJSON(replay.Result)
+	result.StoredResult = resultJSON(replay.StoredResult)
+	result.Error = replay.Error
+	result.Diff = replay.Diff
+	result.TraceID = traceID
+
+	return nil
+}
+
+func resultJSON(result []byte) json.RawMessage {
+	if result == nil {
+		return nil
+	}
+	res, err := (*core.Arguments)(&result).MarshalJSON()
+	if err != nil {
+		return nil
+	}
+	return res
+}
@@ -0,0 +1,102 @@
+/*
+ *    Copyright 2019 Insolar Technologies
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"github.com/insolar/insolar/core/utils"
+	"github.com/insolar/insolar/instrumentation/inslogger"
+	"github.com/insolar/insolar/instrumentation/instracer"
+	"github.com/pkg/errors"
+)
+
+type batchAnswer struct {
+	Error   string `json:"error,omitempty"`
+	TraceID string `json:"traceID,omitempty"`
+	// Results are answers for requests of batch in the same order.
+	Results []answer `json:"results,omitempty"`
+}
+
+// batchHandler accepts array of signed requests, each of them is processed as a separate call to /api/call.
+// Requests are dispatched concurrently, so batch must not contain requests depending on each other.
+func (ar *Runner) batchHandler() func(http.ResponseWriter, *http.Request) {
+	return func(response http.ResponseWriter, req *http.Request) {
+		traceID := utils.RandTraceID()
+		ctx, insLog := inslogger.WithTraceField(context.Background(), traceID)
+
+		ctx, span := instracer.StartSpan(ctx, "batchHandler")
+		defer span.End()
+
+		var params []Request
+		resp := batchAnswer{TraceID: traceID}
+
+		insLog.Infof("[ batchHandler ] Incoming request: %s", req.RequestURI)
+
+		defer func() {
+			res, err := json.MarshalIndent(resp, "", "    ")
+			if err != nil {
+				res = []byte(`{"error": "can't marshal answer to json'"}`)
+			}
+			response.Header().Add("Content-Type", "application/json")
+			_, err = response.Write(res)
+			if err != nil {
+				insLog.Errorf("Can't write response\n")
+			}
+		}()
+
+		_, err := UnmarshalRequest(req, &params)
+		if err != nil {
+			resp.Error = err.Error()
+			insLog.Error(errors.Wrap(err, "[ batchHandler ] Can't unmarshal request"))
+			return
+		}
+		if len(params) > ar.cfg.BatchLimit {
+			resp.Error = errors.Errorf("[ batchHandler ] Batch size %d exceeds limit %d", len(params), ar.cfg.BatchLimit).Error()
+			insLog.Error(resp.Error)
+			return
+		}
+
+		if !ar.limitBatch(req, len(params)) {
+			resp.Error = "Rate limit exceeded"
+			return
+		}
+
+		resp.Results = ar.processBatch(ctx, params)
+	}
+}
+
+// processBatch processes requests concurrently and returns answers in the order of requests.
+func (ar *Runner) processBatch(ctx context.Context, params []Request) []answer {
+	results := make([]answer, len(params))
+	var wg sync.WaitGroup
+	wg.Add(len(params))
+	for i := range params {
+		go func(i int) {
+			defer wg.Done()
+			traceID := utils.RandTraceID()
+			ctx, _ := inslogger.WithTraceField(ctx, traceID)
+			results[i].TraceID = traceID
+			ar.processCall(ctx, params[i], &results[i])
+		}(i)
+	}
+	wg.Wait()
+	return results
+}
@@ -0,0 +1,88 @@
+/*
+ *    Copyright 2019 Insolar Technologies
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+
+	"github.com/insolar/insolar/core"
+)
+
+const BatchUrl = "http://localhost:19192/api/batch"
+
+type batchResp struct {
+	Error   string
+	Results []APIresp
+}
+
+func (suite *TimeoutSuite) signedRequest(seed []byte) Request {
+	ref, err := core.NewRefFromBase58(suite.user.Caller)
+	suite.NoError(err)
+	params, err := core.Serialize([]interface{}{})
+	suite.NoError(err)
+
+	data, err := core.MarshalArgs(*ref, "Transfer", params, seed)
+	suite.NoError(err)
+	signature, err := scheme.Signer(suite.key).Sign(data)
+	suite.NoError(err)
+
+	return Request{
+		Reference: suite.user.Caller,
+		Method:    "Transfer",
+		Params:    params,
+		Seed:      seed,
+		Signature: signature.Bytes(),
+	}
+}
+
+func (suite *TimeoutSuite) sendBatch(requests []Request) batchResp {
+	body, err := json.Marshal(requests)
+	suite.NoError(err)
+	resp, err := http.Post(BatchUrl, "application/json", bytes.NewReader(body))
+	suite.NoError(err)
+	defer resp.Body.Close()
+
+	var result batchResp
+	suite.NoError(json.NewDecoder(resp.Body).Decode(&result))
+	return result
+}
+
+func (suite *TimeoutSuite) TestRunner_batchHandler() {
+	var requests []Request
+	for i := 0; i < 3; i++ {
+		seed, err := suite.api.SeedManager.Issue(suite.ctx)
+		suite.NoError(err)
+		requests = append(requests, suite.signedRequest(seed.Bytes()))
+	}
+	requests[1].Signature = []byte("wrong")
+
+	result := suite.sendBatch(requests)
+	suite.Equal("", result.Error)
+	suite.Require().Len(result.Results, 3)
+	suite.Equal("OK", result.Results[0].Result)
+	suite.Contains(result.Results[1].Error, "Incorrect signature")
+	suite.Equal("OK", result.Results[2].Result)
+}
+
+func (suite *TimeoutSuite) TestRunner_batchHandlerLimit() {
+	requests := make([]Request, 4)
+	result := suite.sendBatch(requests)
+	suite.Contains(result.Error, "Batch size 4 exceeds limit 3")
+	suite.Empty(result.Results)
+}
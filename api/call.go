@@ -18,6 +18,8 @@ package api
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/binary"
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
@@ -26,6 +28,7 @@ import (
 	"github.com/insolar/insolar/api/seedmanager"
 	"github.com/insolar/insolar/application/extractor"
 	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/core/message"
 	"github.com/insolar/insolar/core/reply"
 	"github.com/insolar/insolar/core/utils"
 	"github.com/insolar/insolar/instrumentation/inslogger"
@@ -34,6 +37,7 @@ import (
 	"github.com/insolar/insolar/platformpolicy"
 
 	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
 )
 
 var scheme = platformpolicy.NewPlatformCryptographyScheme()
@@ -45,12 +49,29 @@ type Request struct {
 	Params    []byte `json:"params"`
 	Seed      []byte `json:"seed"`
 	Signature []byte `json:"signature"`
+	// Signatures are signatures by several keys of multisig member, Signature is ignored if they are set.
+	Signatures [][]byte `json:"signatures,omitempty"`
+	// IdempotencyKey is optional, call with the key already used by member returns result of the first call
+	// instead of executing again.
+	IdempotencyKey string `json:"idempotencyKey,omitempty"`
+	// Async makes call return reference to request without waiting for result, status of request is available
+	// through call.Status.
+	Async bool `json:"async,omitempty"`
+	// Callback is optional URL notified when async call is finished.
+	Callback string `json:"callback,omitempty"`
+	// CallbackSignature is a signature of reference, method, params, seed and callback by one of member keys, it is
+	// required if Callback is set.
+	CallbackSignature []byte `json:"callbackSignature,omitempty"`
 }
 
 type answer struct {
 	Error   string      `json:"error,omitempty"`
 	Result  interface{} `json:"result,omitempty"`
 	TraceID string      `json:"traceID,omitempty"`
+	// Request is a reference to request made with idempotency key.
+	Request string `json:"request,omitempty"`
+	// InProgress is true if request made with the same idempotency key is not finished yet.
+	InProgress bool `json:"inProgress,omitempty"`
 }
 
 // UnmarshalRequest unmarshals request to api
@@ -70,14 +91,22 @@ func UnmarshalRequest(req *http.Request, params interface{}) ([]byte, error) {
 	return body, nil
 }
 
-func (ar *Runner) verifySignature(ctx context.Context, params Request) error {
-	key, err := ar.getMemberPubKey(ctx, params.Reference)
-	if err != nil {
-		return errors.Wrap(err, "[ VerifySignature ] Can't getMemberPubKey")
-	}
-	if key == "" {
-		return errors.New("[ VerifySignature ] Not found public key for this member")
+// keyMethods are methods of member that change its keys.
+var keyMethods = map[string]bool{
+	"AddKey":       true,
+	"RevokeKey":    true,
+	"RotateKey":    true,
+	"SetThreshold": true,
+}
+
+func (params Request) signatures() [][]byte {
+	if len(params.Signatures) > 0 {
+		return params.Signatures
 	}
+	return [][]byte{params.Signature}
+}
+
+func (ar *Runner) verifySignature(ctx context.Context, params Request) error {
 	ref, err := core.NewRefFromBase58(params.Reference)
 	if err != nil {
 		return errors.Wrap(err, "[ VerifySignature ] failed to parse params.Reference")
@@ -91,24 +120,104 @@ func (ar *Runner) verifySignature(ctx context.Context, params Request) error {
 	if err != nil {
 		return errors.Wrap(err, "[ VerifySignature ] Can't marshal arguments for verify signature")
 	}
-	verifier := scheme.Verifier(key)
-	verified := verifier.Verify(core.SignatureFromBytes(params.Signature), args)
-	if !verified {
+
+	keys, err := ar.getMemberKeys(ctx, params.Reference)
+	if err != nil {
+		return errors.Wrap(err, "[ VerifySignature ] Can't getMemberKeys")
+	}
+	if len(keys.keys) == 0 {
+		return errors.New("[ VerifySignature ] Not found public key for this member")
+	}
+	if verifySignatures(keys, params.signatures(), args) {
+		return nil
+	}
+
+	// Keys could be changed through another node since they were cached.
+	ar.invalidateMemberKeys(params.Reference)
+	keys, err = ar.getMemberKeys(ctx, params.Reference)
+	if err != nil {
+		return errors.Wrap(err, "[ VerifySignature ] Can't getMemberKeys")
+	}
+	if !verifySignatures(keys, params.signatures(), args) {
 		return errors.New("[ VerifySignature ] Incorrect signature")
 	}
 	return nil
 }
 
-func (ar *Runner) checkSeed(paramsSeed []byte) error {
-	seed := seedmanager.SeedFromBytes(paramsSeed)
-	if seed == nil {
-		return errors.New("[ checkSeed ] Bad seed param")
+// checkCallback checks that callback is allowed and signed by member.
+func (ar *Runner) checkCallback(ctx context.Context, params Request) error {
+	if !params.Async {
+		return errors.New("[ checkCallback ] Callback is allowed only for async call")
+	}
+	err := ar.callbacks.checkURL(params.Callback)
+	if err != nil {
+		return errors.Wrap(err, "[ checkCallback ]")
 	}
 
-	if !ar.SeedManager.Exists(*seed) {
-		return errors.New("[ checkSeed ] Incorrect seed")
+	ref, err := core.NewRefFromBase58(params.Reference)
+	if err != nil {
+		return errors.Wrap(err, "[ checkCallback ] failed to parse params.Reference")
+	}
+	args, err := core.MarshalArgs(
+		*ref,
+		params.Method,
+		params.Params,
+		params.Seed,
+		params.Callback)
+	if err != nil {
+		return errors.Wrap(err, "[ checkCallback ] Can't marshal arguments for verify signature")
+	}
+	keys, err := ar.getMemberKeys(ctx, params.Reference)
+	if err != nil {
+		return errors.Wrap(err, "[ checkCallback ] Can't getMemberKeys")
 	}
+	// Callback doesn't change call, so signature by any key of member is enough.
+	if !verifySignatures(&memberKeys{keys: keys.keys, threshold: 1}, [][]byte{params.CallbackSignature}, args) {
+		return errors.New("[ checkCallback ] Incorrect callback signature")
+	}
+	return nil
+}
 
+// verifySignatures checks that data is signed by at least threshold of different member keys.
+func verifySignatures(keys *memberKeys, signatures [][]byte, data []byte) bool {
+	signed := make([]bool, len(keys.keys))
+	count := 0
+	for _, signature := range signatures {
+		for i, key := range keys.keys {
+			if !signed[i] && scheme.Verifier(key).Verify(core.SignatureFromBytes(signature), data) {
+				signed[i] = true
+				count++
+				break
+			}
+		}
+	}
+	return count > 0 && count >= keys.threshold
+}
+
+func (ar *Runner) checkSeed(ctx context.Context, paramsSeed []byte) (*seedmanager.SignedSeed, error) {
+	seed, err := ar.SeedManager.Verify(ctx, paramsSeed)
+	if err != nil {
+		inslogger.FromContext(ctx).Debug(err)
+		return nil, errors.New("[ checkSeed ] Incorrect seed")
+	}
+
+	return seed, nil
+}
+
+// useSeed marks seed as used by member on ledger, so the same seed is rejected by any node until it expires.
+func (ar *Runner) useSeed(ctx context.Context, params Request, seed *seedmanager.SignedSeed) error {
+	reference, err := core.NewRefFromBase58(params.Reference)
+	if err != nil {
+		return errors.Wrap(err, "[ useSeed ] failed to parse params.Reference")
+	}
+
+	err = ar.ArtifactManager.RegisterSeed(ctx, *reference, seed.Seed[:], seed.Expires)
+	if err == core.ErrSeedUsed {
+		return errors.New("[ useSeed ] Incorrect seed")
+	}
+	if err != nil {
+		return errors.Wrap(err, "[ useSeed ] Can't register seed")
+	}
 	return nil
 }
 
@@ -121,18 +230,140 @@ func (ar *Runner) makeCall(ctx context.Context, params Request) (interface{}, er
 		return nil, errors.Wrap(err, "[ makeCall ] failed to parse params.Reference")
 	}
 
-	res, err := ar.ContractRequester.SendRequest(
-		ctx,
-		reference,
-		"Call",
-		[]interface{}{*ar.CertificateManager.GetCertificate().GetRootDomainReference(), params.Method, params.Params, params.Seed, params.Signature},
-	)
+	method, callArgs := ar.memberCall(params)
+	var res core.Reply
+	if params.IdempotencyKey == "" {
+		res, err = ar.ContractRequester.SendRequest(ctx, reference, method, callArgs)
+	} else {
+		res, err = ar.sendIdempotentRequest(ctx, reference, params.IdempotencyKey, method, callArgs)
+	}
 
 	if err != nil {
 		return nil, errors.Wrap(err, "[ makeCall ] Can't send request")
 	}
 
-	result, contractErr, err := extractor.CallResponse(res.(*reply.CallMethod).Result)
+	return callResult(res.(*reply.CallMethod).Result)
+}
+
+// memberCall returns method of member and its arguments for call made with params.
+func (ar *Runner) memberCall(params Request) (string, []interface{}) {
+	rootDomain := *ar.CertificateManager.GetCertificate().GetRootDomainReference()
+	if len(params.Signatures) > 0 {
+		return "CallMultisig", []interface{}{rootDomain, params.Method, params.Params, params.Seed, params.Signatures}
+	}
+	return "Call", []interface{}{rootDomain, params.Method, params.Params, params.Seed, params.Signature}
+}
+
+// sendIdempotentRequest calls member with idempotency key, the key is saved in request record.
+func (ar *Runner) sendIdempotentRequest(
+	ctx context.Context, member *core.RecordRef, key string, method string, callArgs []interface{},
+) (core.Reply, error) {
+	args, err := core.MarshalArgs(callArgs...)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ sendIdempotentRequest ] Can't marshal")
+	}
+
+	base, err := idempotentMessage(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ sendIdempotentRequest ]")
+	}
+	return ar.ContractRequester.CallMethod(ctx, base, false, member, method, args, nil)
+}
+
+func idempotentMessage(key string) (*message.BaseLogicMessage, error) {
+	buf := make([]byte, 8)
+	_, err := rand.Read(buf)
+	if err != nil {
+		return nil, errors.Wrap(err, "Can't generate nonce")
+	}
+	return &message.BaseLogicMessage{
+		Nonce:          binary.LittleEndian.Uint64(buf),
+		IdempotencyKey: key,
+	}, nil
+}
+
+// submitCall calls member without waiting for result and returns reference to request. Request is registered with
+// idempotency key, so its result can be found later.
+func (ar *Runner) submitCall(ctx context.Context, params Request) (*core.RecordRef, error) {
+	ctx, span := instracer.StartSpan(ctx, "SubmitRequest "+params.Method)
+	defer span.End()
+
+	reference, err := core.NewRefFromBase58(params.Reference)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ submitCall ] failed to parse params.Reference")
+	}
+
+	key := params.IdempotencyKey
+	if key == "" {
+		id, err := uuid.NewV4()
+		if err != nil {
+			return nil, errors.Wrap(err, "[ submitCall ] Can't generate idempotency key")
+		}
+		key = "async:" + id.String()
+	}
+	base, err := idempotentMessage(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ submitCall ]")
+	}
+	method, callArgs := ar.memberCall(params)
+	args, err := core.MarshalArgs(callArgs...)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ submitCall ] Can't marshal")
+	}
+
+	if params.Callback != "" && !ar.callbacks.reserve() {
+		return nil, errors.New("[ submitCall ] Too many pending callbacks")
+	}
+	res, err := ar.ContractRequester.CallMethod(ctx, base, true, reference, method, args, nil)
+	if err == nil {
+		if _, ok := res.(*reply.RegisterRequest); !ok {
+			err = errors.Errorf("unexpected reply: %#v", res)
+		}
+	}
+	if err != nil {
+		if params.Callback != "" {
+			ar.callbacks.release()
+		}
+		return nil, errors.Wrap(err, "[ submitCall ] Can't send request")
+	}
+
+	request := res.(*reply.RegisterRequest).Request
+	if params.Callback != "" {
+		ar.callbacks.add(params.Callback, *reference, request)
+	}
+	return &request, nil
+}
+
+// findIdempotentRequest fills answer with request made by member with the same idempotency key.
+// It returns false if there is no such request.
+func (ar *Runner) findIdempotentRequest(ctx context.Context, params Request, resp *answer) (bool, error) {
+	reference, err := core.NewRefFromBase58(params.Reference)
+	if err != nil {
+		return false, errors.Wrap(err, "[ findIdempotentRequest ] failed to parse params.Reference")
+	}
+
+	req, err := ar.ArtifactManager.GetIdempotentRequest(ctx, *reference, params.IdempotencyKey)
+	if err != nil {
+		return false, errors.Wrap(err, "[ findIdempotentRequest ] Can't get request")
+	}
+	if req == nil {
+		return false, nil
+	}
+
+	resp.Request = req.Request.String()
+	if !req.Finished {
+		resp.InProgress = true
+		return true, nil
+	}
+	resp.Result, err = callResult(req.Result)
+	if err != nil {
+		return true, err
+	}
+	return true, nil
+}
+
+func callResult(data []byte) (interface{}, error) {
+	result, contractErr, err := extractor.CallResponse(data)
 
 	if err != nil {
 		return nil, errors.Wrap(err, "[ makeCall ] Can't extract response")
@@ -161,15 +392,6 @@ func (ar *Runner) callHandler() func(http.ResponseWriter, *http.Request) {
 		params := Request{}
 		resp := answer{}
 
-		startTime := time.Now()
-		defer func() {
-			success := "success"
-			if resp.Error != "" {
-				success = "fail"
-			}
-			metrics.APIContractExecutionTime.WithLabelValues(params.Method, success).Observe(time.Since(startTime).Seconds())
-		}()
-
 		resp.TraceID = traceID
 
 		insLog.Infof("[ callHandler ] Incoming request: %s", req.RequestURI)
@@ -192,39 +414,106 @@ func (ar *Runner) callHandler() func(http.ResponseWriter, *http.Request) {
 			return
 		}
 
-		err = ar.checkSeed(params.Seed)
+		ar.processCall(ctx, params, &resp)
+	}
+}
+
+// processCall checks seed and signature of request and calls member, result of call is written to resp.
+func (ar *Runner) processCall(ctx context.Context, params Request, resp *answer) {
+	insLog := inslogger.FromContext(ctx)
+
+	startTime := time.Now()
+	defer func() {
+		success := "success"
+		if resp.Error != "" {
+			success = "fail"
+		}
+		metrics.APIContractExecutionTime.WithLabelValues(params.Method, success).Observe(time.Since(startTime).Seconds())
+	}()
+
+	seed, err := ar.checkSeed(ctx, params.Seed)
+	if err != nil {
+		processError(err, "Can't checkSeed", resp, insLog)
+		return
+	}
+
+	err = ar.verifySignature(ctx, params)
+	if err != nil {
+		processError(err, "Can't verify signature", resp, insLog)
+		return
+	}
+
+	if params.Callback != "" {
+		err = ar.checkCallback(ctx, params)
 		if err != nil {
-			processError(err, "Can't checkSeed", &resp, insLog)
+			processError(err, "Can't check callback", resp, insLog)
 			return
 		}
+	}
+
+	if !ar.limitMember(params.Reference) {
+		resp.Error = "Rate limit exceeded"
+		return
+	}
+
+	if keyMethods[params.Method] {
+		// Cached keys of member are stale after the call.
+		defer ar.invalidateMemberKeys(params.Reference)
+	}
+
+	err = ar.useSeed(ctx, params, seed)
+	if err != nil {
+		processError(err, "Can't use seed", resp, insLog)
+		return
+	}
 
-		err = ar.verifySignature(ctx, params)
+	if params.IdempotencyKey != "" {
+		found, err := ar.findIdempotentRequest(ctx, params, resp)
 		if err != nil {
-			processError(err, "Can't verify signature", &resp, insLog)
+			processError(err, "Can't find request by idempotency key", resp, insLog)
 			return
 		}
+		if found {
+			return
+		}
+	}
 
-		var result interface{}
-		ch := make(chan interface{}, 1)
-		go func() {
-			result, err = ar.makeCall(ctx, params)
-			ch <- nil
-		}()
-		select {
+	if params.Async {
+		request, err := ar.submitCall(ctx, params)
+		if err != nil {
+			processError(err, "Can't submitCall", resp, insLog)
+			return
+		}
+		resp.Request = request.String()
+		resp.InProgress = true
+		return
+	}
 
-		case <-ch:
-			if err != nil {
-				processError(err, "Can't makeCall", &resp, insLog)
+	var result interface{}
+	ch := make(chan interface{}, 1)
+	go func() {
+		result, err = ar.makeCall(ctx, params)
+		ch <- nil
+	}()
+	select {
+
+	case <-ch:
+		if err != nil && params.IdempotencyKey != "" {
+			// Concurrent call with the same key could be registered first.
+			found, findErr := ar.findIdempotentRequest(ctx, params, resp)
+			if findErr == nil && found {
 				return
 			}
-			resp.Result = result
-
-		case <-time.After(time.Duration(ar.cfg.Timeout) * time.Second):
-			resp.Error = "Messagebus timeout exceeded"
+		}
+		if err != nil {
+			processError(err, "Can't makeCall", resp, insLog)
 			return
-
 		}
-
 		resp.Result = result
+
+	case <-time.After(time.Duration(ar.cfg.Timeout) * time.Second):
+		resp.Error = "Messagebus timeout exceeded"
+		return
+
 	}
 }
@@ -18,19 +18,24 @@ package api
 
 import (
 	"context"
+	"crypto"
 	"encoding/json"
 	"net/http"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/insolar/insolar/api/requester"
 	"github.com/insolar/insolar/configuration"
 	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/core/message"
 	"github.com/insolar/insolar/core/reply"
+	"github.com/insolar/insolar/cryptography"
 	"github.com/insolar/insolar/instrumentation/inslogger"
 	"github.com/insolar/insolar/logicrunner/goplugin/foundation"
 	"github.com/insolar/insolar/platformpolicy"
 	"github.com/insolar/insolar/testutils"
+	"github.com/insolar/insolar/testutils/network"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 )
@@ -42,25 +47,29 @@ type TimeoutSuite struct {
 	ctx   context.Context
 	api   *Runner
 	user  *requester.UserConfigJSON
+	key   crypto.PrivateKey
 	delay bool
+	// keys are public keys of member returned by GetPublicKeys
+	keys []string
 }
 
 type APIresp struct {
-	Result string
-	Error  string
+	Result     string
+	Error      string
+	Request    string
+	InProgress bool
 }
 
 func (suite *TimeoutSuite) TestRunner_callHandler() {
-	seed, err := suite.api.SeedGenerator.Next()
+	seed, err := suite.api.SeedManager.Issue(suite.ctx)
 	suite.NoError(err)
-	suite.api.SeedManager.Add(*seed)
 
 	resp, err := requester.SendWithSeed(
 		suite.ctx,
 		CallUrl,
 		suite.user,
 		&requester.RequestConfigJSON{},
-		seed[:],
+		seed.Bytes(),
 	)
 	suite.NoError(err)
 
@@ -72,17 +81,17 @@ func (suite *TimeoutSuite) TestRunner_callHandler() {
 }
 
 func (suite *TimeoutSuite) TestRunner_callHandlerTimeout() {
-	seed, err := suite.api.SeedGenerator.Next()
+	seed, err := suite.api.SeedManager.Issue(suite.ctx)
 	suite.NoError(err)
-	suite.api.SeedManager.Add(*seed)
 
 	suite.delay = true
+	defer func() { suite.delay = false }()
 	resp, err := requester.SendWithSeed(
 		suite.ctx,
 		CallUrl,
 		suite.user,
 		&requester.RequestConfigJSON{},
-		seed[:],
+		seed.Bytes(),
 	)
 	suite.NoError(err)
 
@@ -93,6 +102,247 @@ func (suite *TimeoutSuite) TestRunner_callHandlerTimeout() {
 	suite.Equal("", result.Result)
 }
 
+func (suite *TimeoutSuite) TestRunner_callHandlerUsedSeed() {
+	seed, err := suite.api.SeedManager.Issue(suite.ctx)
+	suite.NoError(err)
+
+	send := func() APIresp {
+		resp, err := requester.SendWithSeed(
+			suite.ctx,
+			CallUrl,
+			suite.user,
+			&requester.RequestConfigJSON{},
+			seed.Bytes(),
+		)
+		suite.NoError(err)
+
+		var result APIresp
+		err = json.Unmarshal(resp, &result)
+		suite.NoError(err)
+		return result
+	}
+
+	result := send()
+	suite.Equal("", result.Error)
+	suite.Equal("OK", result.Result)
+
+	result = send()
+	suite.Contains(result.Error, "Incorrect seed")
+}
+
+func (suite *TimeoutSuite) TestRunner_callHandlerIdempotencyKey() {
+	send := func(key string) APIresp {
+		seed, err := suite.api.SeedManager.Issue(suite.ctx)
+		suite.NoError(err)
+
+		resp, err := requester.SendWithSeed(
+			suite.ctx,
+			CallUrl,
+			suite.user,
+			&requester.RequestConfigJSON{IdempotencyKey: key},
+			seed.Bytes(),
+		)
+		suite.NoError(err)
+
+		var result APIresp
+		err = json.Unmarshal(resp, &result)
+		suite.NoError(err)
+		return result
+	}
+
+	result := send("new")
+	suite.Equal("", result.Error)
+	suite.Equal("OK", result.Result)
+
+	result = send("finished")
+	suite.Equal("", result.Error)
+	suite.Equal("first", result.Result)
+	suite.Equal(idempotentRequest.String(), result.Request)
+	suite.False(result.InProgress)
+
+	result = send("running")
+	suite.Equal("", result.Error)
+	suite.Equal("", result.Result)
+	suite.Equal(idempotentRequest.String(), result.Request)
+	suite.True(result.InProgress)
+}
+
+func (suite *TimeoutSuite) TestRunner_callHandlerAsync() {
+	seed, err := suite.api.SeedManager.Issue(suite.ctx)
+	suite.NoError(err)
+
+	resp, err := requester.SendWithSeed(
+		suite.ctx,
+		CallUrl,
+		suite.user,
+		&requester.RequestConfigJSON{Async: true},
+		seed.Bytes(),
+	)
+	suite.NoError(err)
+
+	var result APIresp
+	err = json.Unmarshal(resp, &result)
+	suite.NoError(err)
+	suite.Equal("", result.Error)
+	suite.Equal("", result.Result)
+	suite.Equal(asyncRequest.String(), result.Request)
+	suite.True(result.InProgress)
+}
+
+func (suite *TimeoutSuite) TestRunner_callHandlerAsyncCallback() {
+	send := func(callback string) APIresp {
+		seed, err := suite.api.SeedManager.Issue(suite.ctx)
+		suite.NoError(err)
+		resp, err := requester.SendWithSeed(
+			suite.ctx,
+			CallUrl,
+			suite.user,
+			&requester.RequestConfigJSON{Async: true, Callback: callback},
+			seed.Bytes(),
+		)
+		suite.NoError(err)
+		var result APIresp
+		suite.NoError(json.Unmarshal(resp, &result))
+		return result
+	}
+
+	result := send("https://example.com/callback")
+	suite.Equal("", result.Error)
+	suite.Equal(asyncRequest.String(), result.Request)
+
+	result = send("http://127.0.0.1:19100/callback")
+	suite.Contains(result.Error, "not public")
+
+	// Callback must be signed with call.
+	err := suite.api.checkCallback(suite.ctx, Request{
+		Reference:         suite.user.Caller,
+		Async:             true,
+		Callback:          "https://example.com/callback",
+		CallbackSignature: []byte("wrong"),
+	})
+	suite.Contains(err.Error(), "Incorrect callback signature")
+}
+
+func (suite *TimeoutSuite) TestCallService_Status() {
+	service := NewCallService(suite.api)
+	req, err := http.NewRequest("POST", CallUrl, nil)
+	suite.NoError(err)
+
+	member, err := core.NewRefFromBase58(suite.user.Caller)
+	suite.NoError(err)
+	seed, err := suite.api.SeedManager.Issue(suite.ctx)
+	suite.NoError(err)
+	sign := func(request core.RecordRef) []byte {
+		data, err := core.MarshalArgs(*member, request, seed.Bytes())
+		suite.NoError(err)
+		signature, err := scheme.Signer(suite.key).Sign(data)
+		suite.NoError(err)
+		return signature.Bytes()
+	}
+
+	status := func(request core.RecordRef) (*CallStatusReply, error) {
+		var result CallStatusReply
+		err := service.Status(req, &CallStatusArgs{
+			Reference: suite.user.Caller,
+			Request:   request.String(),
+			Seed:      seed.Bytes(),
+			Signature: sign(request),
+		}, &result)
+		return &result, err
+	}
+
+	result, err := status(idempotentRequest)
+	suite.NoError(err)
+	suite.Equal(CallStatusDone, result.Status)
+	suite.Equal("first", result.Result)
+
+	result, err = status(asyncRequest)
+	suite.NoError(err)
+	suite.Equal(CallStatusPending, result.Status)
+
+	result, err = status(failedRequest)
+	suite.NoError(err)
+	suite.Equal(CallStatusFailed, result.Status)
+	suite.Contains(result.Error, "failed")
+
+	_, err = status(testutils.RandomRef())
+	suite.Contains(err.Error(), "Unknown request")
+
+	// Result is available to member that made call only.
+	result = &CallStatusReply{}
+	err = service.Status(req, &CallStatusArgs{
+		Reference: suite.user.Caller,
+		Request:   idempotentRequest.String(),
+		Seed:      seed.Bytes(),
+		Signature: sign(asyncRequest),
+	}, result)
+	suite.Contains(err.Error(), "Incorrect signature")
+	suite.Nil(result.Result)
+
+	err = service.Status(req, &CallStatusArgs{
+		Reference: suite.user.Caller,
+		Request:   idempotentRequest.String(),
+		Seed:      []byte("wrong"),
+		Signature: sign(idempotentRequest),
+	}, result)
+	suite.Contains(err.Error(), "Incorrect seed")
+}
+
+func (suite *TimeoutSuite) TestRunner_callHandlerKeyRotation() {
+	send := func(user *requester.UserConfigJSON) APIresp {
+		seed, err := suite.api.SeedManager.Issue(suite.ctx)
+		suite.NoError(err)
+
+		resp, err := requester.SendWithSeed(
+			suite.ctx,
+			CallUrl,
+			user,
+			&requester.RequestConfigJSON{},
+			seed.Bytes(),
+		)
+		suite.NoError(err)
+
+		var result APIresp
+		err = json.Unmarshal(resp, &result)
+		suite.NoError(err)
+		return result
+	}
+
+	ks := platformpolicy.NewKeyProcessor()
+	newKey, err := ks.GeneratePrivateKey()
+	suite.NoError(err)
+	newKeyString, err := ks.ExportPrivateKeyPEM(newKey)
+	suite.NoError(err)
+	newPublicKeyString, err := ks.ExportPublicKeyPEM(ks.ExtractPublicKey(newKey))
+	suite.NoError(err)
+	newUser, err := requester.CreateUserConfig(suite.user.Caller, string(newKeyString))
+	suite.NoError(err)
+
+	result := send(suite.user)
+	suite.Equal("", result.Error)
+
+	// Keys are rotated through another node, so cached key becomes stale.
+	oldKeys := suite.keys
+	suite.keys = []string{string(newPublicKeyString)}
+	defer func() {
+		suite.keys = oldKeys
+		suite.api.invalidateMemberKeys(suite.user.Caller)
+	}()
+
+	result = send(newUser)
+	suite.Equal("", result.Error)
+	suite.Equal("OK", result.Result)
+
+	result = send(suite.user)
+	suite.Contains(result.Error, "Incorrect signature")
+}
+
+var (
+	idempotentRequest = testutils.RandomRef()
+	asyncRequest      = testutils.RandomRef()
+	failedRequest     = testutils.RandomRef()
+)
+
 func TestTimeoutSuite(t *testing.T) {
 	timeoutSuite := new(TimeoutSuite)
 	timeoutSuite.ctx, _ = inslogger.WithTraceField(context.Background(), "APItests")
@@ -106,12 +356,15 @@ func TestTimeoutSuite(t *testing.T) {
 	pKeyString, err := ks.ExportPublicKeyPEM(pKey)
 	require.NoError(t, err)
 
+	timeoutSuite.key = sKey
+	timeoutSuite.keys = []string{string(pKeyString)}
 	userRef := testutils.RandomRef().String()
 	timeoutSuite.user, err = requester.CreateUserConfig(userRef, string(sKeyString))
 
 	http.DefaultServeMux = new(http.ServeMux)
 	cfg := configuration.NewAPIRunner()
 	cfg.Address = "localhost:19192"
+	cfg.BatchLimit = 3
 	timeoutSuite.api, err = NewRunner(&cfg)
 	require.NoError(t, err)
 
@@ -129,10 +382,15 @@ func TestTimeoutSuite(t *testing.T) {
 	cr := testutils.NewContractRequesterMock(t)
 	cr.SendRequestFunc = func(p context.Context, p1 *core.RecordRef, method string, p3 []interface{}) (core.Reply, error) {
 		switch method {
-		case "GetPublicKey":
-			var result = string(pKeyString)
+		case "GetPublicKeys":
 			var contractErr *foundation.Error
-			data, _ := core.MarshalArgs(result, contractErr)
+			data, _ := core.MarshalArgs(timeoutSuite.keys, contractErr)
+			return &reply.CallMethod{
+				Result: data,
+			}, nil
+		case "GetThreshold":
+			var contractErr *foundation.Error
+			data, _ := core.MarshalArgs(uint(1), contractErr)
 			return &reply.CallMethod{
 				Result: data,
 			}, nil
@@ -149,7 +407,71 @@ func TestTimeoutSuite(t *testing.T) {
 		}
 	}
 
+	cr.CallMethodFunc = func(p context.Context, p1 core.Message, p2 bool, p3 *core.RecordRef, method string, p5 core.Arguments, p6 *core.RecordRef) (core.Reply, error) {
+		key := p1.(*message.BaseLogicMessage).IdempotencyKey
+		if p2 {
+			require.True(t, strings.HasPrefix(key, "async:"))
+			return &reply.RegisterRequest{Request: asyncRequest}, nil
+		}
+		require.Equal(t, "new", key)
+		data, _ := core.MarshalArgs("OK", (*foundation.Error)(nil))
+		return &reply.CallMethod{
+			Result: data,
+		}, nil
+	}
+
+	am := testutils.NewArtifactManagerMock(t)
+	am.GetIdempotentRequestFunc = func(p context.Context, p1 core.RecordRef, key string) (*core.IdempotentRequest, error) {
+		switch key {
+		case "finished":
+			data, _ := core.MarshalArgs("first", (*foundation.Error)(nil))
+			return &core.IdempotentRequest{Request: idempotentRequest, Finished: true, Result: data}, nil
+		case "running":
+			return &core.IdempotentRequest{Request: idempotentRequest}, nil
+		}
+		return nil, nil
+	}
+
+	am.GetRequestResultFunc = func(p context.Context, p1 core.RecordRef, request core.RecordRef) (*core.IdempotentRequest, error) {
+		switch request {
+		case idempotentRequest:
+			data, _ := core.MarshalArgs("first", (*foundation.Error)(nil))
+			return &core.IdempotentRequest{Request: request, Finished: true, Result: data}, nil
+		case asyncRequest:
+			return &core.IdempotentRequest{Request: request}, nil
+		case failedRequest:
+			data, _ := core.MarshalArgs(nil, &foundation.Error{S: "call failed"})
+			return &core.IdempotentRequest{Request: request, Finished: true, Result: data}, nil
+		}
+		return nil, nil
+	}
+
+	usedSeeds := map[string]bool{}
+	am.RegisterSeedFunc = func(p context.Context, p1 core.RecordRef, seed []byte, p3 core.PulseNumber) error {
+		if usedSeeds[string(seed)] {
+			return core.ErrSeedUsed
+		}
+		usedSeeds[string(seed)] = true
+		return nil
+	}
+
+	nodeKey, err := ks.GeneratePrivateKey()
+	require.NoError(t, err)
+	node := network.NewNodeMock(t)
+	node.IDMock.Return(testutils.RandomRef())
+	node.PublicKeyMock.Return(ks.ExtractPublicKey(nodeKey))
+	nn := network.NewNodeNetworkMock(t)
+	nn.GetOriginMock.Return(node)
+	nn.GetActiveNodeMock.Return(node)
+
+	ps := testutils.NewPulseStorageMock(t)
+	ps.CurrentMock.Return(core.GenesisPulse, nil)
+
 	timeoutSuite.api.ContractRequester = cr
+	timeoutSuite.api.ArtifactManager = am
+	timeoutSuite.api.CryptographyService = cryptography.NewKeyBoundCryptographyService(nodeKey)
+	timeoutSuite.api.NodeNetwork = nn
+	timeoutSuite.api.PulseStorage = ps
 	timeoutSuite.api.CertificateManager = cm
 	timeoutSuite.api.Start(timeoutSuite.ctx)
 
@@ -158,3 +480,29 @@ func TestTimeoutSuite(t *testing.T) {
 
 	timeoutSuite.api.Stop(timeoutSuite.ctx)
 }
+
+func TestVerifySignatures(t *testing.T) {
+	ks := platformpolicy.NewKeyProcessor()
+	data := []byte("data")
+
+	keys := &memberKeys{threshold: 2}
+	var signatures [][]byte
+	for i := 0; i < 3; i++ {
+		privateKey, err := ks.GeneratePrivateKey()
+		require.NoError(t, err)
+		keys.keys = append(keys.keys, ks.ExtractPublicKey(privateKey))
+		signature, err := scheme.Signer(privateKey).Sign(data)
+		require.NoError(t, err)
+		signatures = append(signatures, signature.Bytes())
+	}
+
+	require.False(t, verifySignatures(keys, signatures[:1], data))
+	// The same key is counted once.
+	require.False(t, verifySignatures(keys, [][]byte{signatures[0], signatures[0]}, data))
+	require.True(t, verifySignatures(keys, signatures[1:], data))
+	require.True(t, verifySignatures(keys, signatures, data))
+
+	keys.threshold = 1
+	require.True(t, verifySignatures(keys, signatures[2:], data))
+	require.False(t, verifySignatures(keys, [][]byte{[]byte("wrong")}, data))
+}
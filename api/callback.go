@@ -0,0 +1,290 @@
+/*
+ *    Copyright 2019 Insolar Technologies
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+
+	"github.com/insolar/insolar/configuration"
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/instrumentation/inslogger"
+	"github.com/pkg/errors"
+)
+
+const (
+	// callbackPollPeriod is a period of checking whether async calls are finished.
+	callbackPollPeriod = time.Second
+	// callbackTimeout is a time async call is watched for callback, callback is not called if call takes longer.
+	callbackTimeout = 10 * time.Minute
+	// callbackWorkers is a number of callbacks notified concurrently.
+	callbackWorkers = 4
+)
+
+// privateNetworks are networks callbacks are not allowed to unless their hosts are listed in config.
+var privateNetworks = func() []*net.IPNet {
+	var networks []*net.IPNet
+	for _, cidr := range []string{
+		"0.0.0.0/8", "10.0.0.0/8", "100.64.0.0/10", "127.0.0.0/8", "169.254.0.0/16", "172.16.0.0/12",
+		"192.168.0.0/16", "::/128", "::1/128", "fc00::/7", "fe80::/10",
+	} {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}()
+
+func isPublicIP(ip net.IP) bool {
+	if ip.IsMulticast() {
+		return false
+	}
+	for _, network := range privateNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// callbackNotification is sent to callback URL when async call is finished.
+type callbackNotification struct {
+	Request string      `json:"request"`
+	Status  string      `json:"status"`
+	Result  interface{} `json:"result,omitempty"`
+	Error   string      `json:"error,omitempty"`
+}
+
+// pendingCallback is a callback waiting for async call to finish.
+type pendingCallback struct {
+	url      string
+	member   core.RecordRef
+	request  core.RecordRef
+	deadline time.Time
+}
+
+// callbackDelivery is a notification ready to be sent to callback.
+type callbackDelivery struct {
+	url          string
+	notification callbackNotification
+}
+
+// callbackWatcher watches async calls with callbacks and notifies callbacks when calls are finished. Number of
+// watched calls is limited by config, calls are polled by one goroutine and callbacks are notified by a fixed
+// number of workers.
+type callbackWatcher struct {
+	runner *Runner
+	client *http.Client
+	hosts  map[string]bool
+
+	// slots limits number of callbacks, slot is taken before call is submitted and released when callback is done.
+	slots      chan struct{}
+	incoming   chan *pendingCallback
+	deliveries chan *callbackDelivery
+	stop       chan struct{}
+}
+
+func newCallbackWatcher(runner *Runner, cfg configuration.APICallback) *callbackWatcher {
+	w := &callbackWatcher{
+		runner:     runner,
+		hosts:      map[string]bool{},
+		slots:      make(chan struct{}, cfg.MaxPending),
+		incoming:   make(chan *pendingCallback, cfg.MaxPending),
+		deliveries: make(chan *callbackDelivery),
+		stop:       make(chan struct{}),
+	}
+	for _, host := range cfg.Hosts {
+		w.hosts[host] = true
+	}
+
+	dialer := &net.Dialer{Timeout: time.Duration(cfg.Timeout) * time.Second}
+	if len(w.hosts) == 0 {
+		// Addresses are checked on dial, so host can't be resolved to private address after URL is checked.
+		dialer.Control = checkCallbackAddress
+	}
+	w.client = &http.Client{
+		Timeout: time.Duration(cfg.Timeout) * time.Second,
+		Transport: &http.Transport{
+			DialContext: dialer.DialContext,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	return w
+}
+
+func checkCallbackAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !isPublicIP(ip) {
+		return errors.Errorf("callback address %s is not public", host)
+	}
+	return nil
+}
+
+// checkURL checks that callback is http URL to allowed host.
+func (w *callbackWatcher) checkURL(callback string) error {
+	u, err := url.Parse(callback)
+	if err != nil {
+		return errors.Wrap(err, "Can't parse callback URL")
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return errors.New("Callback URL must be http or https")
+	}
+	if u.Hostname() == "" {
+		return errors.New("Callback URL must have host")
+	}
+	if len(w.hosts) > 0 {
+		if !w.hosts[u.Hostname()] {
+			return errors.New("Callback host is not allowed")
+		}
+		return nil
+	}
+	if ip := net.ParseIP(u.Hostname()); ip != nil && !isPublicIP(ip) {
+		return errors.New("Callback address is not public")
+	}
+	return nil
+}
+
+// reserve takes slot for callback of call that is going to be submitted, false is returned if there are too many
+// callbacks already. Slot must be passed to add or released.
+func (w *callbackWatcher) reserve() bool {
+	select {
+	case w.slots <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+func (w *callbackWatcher) release() {
+	<-w.slots
+}
+
+// add starts watching submitted call with reserved slot.
+func (w *callbackWatcher) add(callback string, member, request core.RecordRef) {
+	w.incoming <- &pendingCallback{
+		url:      callback,
+		member:   member,
+		request:  request,
+		deadline: time.Now().Add(callbackTimeout),
+	}
+}
+
+func (w *callbackWatcher) start(ctx context.Context) {
+	go w.watch(ctx)
+	for i := 0; i < callbackWorkers; i++ {
+		go w.deliver(ctx)
+	}
+}
+
+func (w *callbackWatcher) close() {
+	close(w.stop)
+}
+
+func (w *callbackWatcher) watch(ctx context.Context) {
+	ticker := time.NewTicker(callbackPollPeriod)
+	defer ticker.Stop()
+
+	var pending []*pendingCallback
+	for {
+		select {
+		case <-w.stop:
+			return
+		case cb := <-w.incoming:
+			pending = append(pending, cb)
+		case <-ticker.C:
+			pending = w.poll(ctx, pending)
+		}
+	}
+}
+
+// poll checks statuses of pending calls and passes finished ones to workers, calls left pending are returned.
+func (w *callbackWatcher) poll(ctx context.Context, pending []*pendingCallback) []*pendingCallback {
+	left := pending[:0]
+	for _, cb := range pending {
+		inslog := inslogger.FromContext(ctx).WithField("request", cb.request.String())
+
+		status, err := w.runner.callStatus(ctx, cb.member, cb.request)
+		if err != nil {
+			inslog.Debug("[ callbackWatcher ] Can't get call status: ", err)
+		}
+		if err == nil && status.Status != CallStatusPending {
+			delivery := &callbackDelivery{
+				url: cb.url,
+				notification: callbackNotification{
+					Request: cb.request.String(),
+					Status:  status.Status,
+					Result:  status.Result,
+					Error:   status.Error,
+				},
+			}
+			select {
+			case w.deliveries <- delivery:
+			case <-w.stop:
+				return nil
+			}
+			continue
+		}
+		if time.Now().After(cb.deadline) {
+			inslog.Warn("[ callbackWatcher ] Call is not finished in time, callback is not notified")
+			w.release()
+			continue
+		}
+		left = append(left, cb)
+	}
+	return left
+}
+
+func (w *callbackWatcher) deliver(ctx context.Context) {
+	for {
+		select {
+		case <-w.stop:
+			return
+		case delivery := <-w.deliveries:
+			err := w.notify(delivery)
+			if err != nil {
+				inslogger.FromContext(ctx).Error("[ callbackWatcher ] Can't notify callback: ", err)
+			}
+			w.release()
+		}
+	}
+}
+
+func (w *callbackWatcher) notify(delivery *callbackDelivery) error {
+	body, err := json.Marshal(delivery.notification)
+	if err != nil {
+		return errors.Wrap(err, "Can't marshal notification")
+	}
+	resp, err := w.client.Post(delivery.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
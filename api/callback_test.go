@@ -0,0 +1,112 @@
+/*
+ *    Copyright 2019 Insolar Technologies
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/insolar/insolar/configuration"
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/logicrunner/goplugin/foundation"
+	"github.com/insolar/insolar/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallbackWatcher_checkURL(t *testing.T) {
+	w := newCallbackWatcher(&Runner{}, configuration.APICallback{})
+	assert.NoError(t, w.checkURL("https://example.com/callback"))
+	assert.Error(t, w.checkURL("ftp://example.com/callback"))
+	assert.Error(t, w.checkURL("http:///callback"))
+	assert.Error(t, w.checkURL("http://127.0.0.1/callback"))
+	assert.Error(t, w.checkURL("http://[fe80::1]/callback"))
+
+	w = newCallbackWatcher(&Runner{}, configuration.APICallback{Hosts: []string{"callback.local"}})
+	assert.NoError(t, w.checkURL("http://callback.local:8080/callback"))
+	assert.Error(t, w.checkURL("https://example.com/callback"))
+}
+
+func TestCallbackWatcher_reserve(t *testing.T) {
+	w := newCallbackWatcher(&Runner{}, configuration.APICallback{MaxPending: 1})
+	require.True(t, w.reserve())
+	require.False(t, w.reserve())
+	w.release()
+	require.True(t, w.reserve())
+}
+
+func TestCallbackWatcher_PrivateAddress(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	defer server.Close()
+
+	w := newCallbackWatcher(&Runner{}, configuration.APICallback{Timeout: 1})
+	err := w.notify(&callbackDelivery{url: server.URL})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "is not public")
+}
+
+func TestCallbackWatcher_Notify(t *testing.T) {
+	notified := make(chan callbackNotification, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		var notification callbackNotification
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&notification))
+		notified <- notification
+	}))
+	defer server.Close()
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	request := testutils.RandomRef()
+	am := testutils.NewArtifactManagerMock(t)
+	am.GetRequestResultFunc = func(p context.Context, p1 core.RecordRef, p2 core.RecordRef) (*core.IdempotentRequest, error) {
+		data, _ := core.MarshalArgs("done", (*foundation.Error)(nil))
+		return &core.IdempotentRequest{Request: request, Finished: true, Result: data}, nil
+	}
+
+	w := newCallbackWatcher(&Runner{ArtifactManager: am}, configuration.APICallback{
+		Hosts:      []string{serverURL.Hostname()},
+		Timeout:    1,
+		MaxPending: 1,
+	})
+	ctx := context.Background()
+	w.start(ctx)
+	defer w.close()
+
+	require.True(t, w.reserve())
+	w.add(server.URL, testutils.RandomRef(), request)
+
+	select {
+	case notification := <-notified:
+		assert.Equal(t, request.String(), notification.Request)
+		assert.Equal(t, CallStatusDone, notification.Status)
+		assert.Equal(t, "done", notification.Result)
+	case <-time.After(5 * time.Second):
+		t.Fatal("callback is not notified")
+	}
+	// Slot is released after callback is notified.
+	released := false
+	for i := 0; i < 100 && !released; i++ {
+		time.Sleep(10 * time.Millisecond)
+		released = w.reserve()
+	}
+	assert.True(t, released)
+}
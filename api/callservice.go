@@ -0,0 +1,165 @@
+/*
+ *    Copyright 2019 Insolar Technologies
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/core/utils"
+	"github.com/insolar/insolar/instrumentation/inslogger"
+	"github.com/pkg/errors"
+)
+
+// Statuses of request made with idempotency key or in async mode.
+const (
+	CallStatusPending = "pending"
+	CallStatusDone    = "done"
+	CallStatusFailed  = "failed"
+)
+
+// CallStatusArgs is arguments that Call service accepts.
+type CallStatusArgs struct {
+	Reference string
+	Request   string
+	Seed      []byte
+	// Signature is a signature of reference, request and seed by one of member keys.
+	Signature []byte
+}
+
+// CallStatusReply is reply for Call service requests.
+type CallStatusReply struct {
+	Status  string
+	Result  interface{}
+	Error   string
+	TraceID string
+}
+
+// CallService is a service that provides API for checking results of calls.
+type CallService struct {
+	runner *Runner
+}
+
+// NewCallService creates new Call service instance.
+func NewCallService(runner *Runner) *CallService {
+	return &CallService{runner: runner}
+}
+
+// Status returns status of request made in async mode or with idempotency key and its result if it is finished.
+// Status is available to the member that made call only, so params are signed by one of member keys. Seed isn't
+// marked as used, the same seed can be used for polling until it expires.
+//
+//   Request structure:
+//   {
+//     "jsonrpc": "2.0",
+//     "method": "call.Status",
+//     "params": {
+//       // Reference to member that made call
+//       "Reference": str,
+//       // Reference to request returned by call
+//       "Request": str,
+//       // Seed from seed.Get, base64
+//       "Seed": str,
+//       // Signature of reference, request and seed by member key, base64
+//       "Signature": str
+//     },
+//     "id": str|int|null
+//   }
+//
+//   Response structure:
+//   {
+//     "jsonrpc": "2.0",
+//     "result": {
+//       "Status": str, // "pending", "done" or "failed"
+//       "Result": ..., // result of call if it is done
+//       "Error": str, // error of call if it is failed
+//       "TraceID": str // traceID for request
+//     },
+//     "id": str|int|null // same as in request
+//   }
+//
+func (s *CallService) Status(r *http.Request, args *CallStatusArgs, result *CallStatusReply) error {
+	traceID := utils.RandTraceID()
+	ctx, inslog := inslogger.WithTraceField(context.Background(), traceID)
+
+	inslog.Infof("[ CallService.Status ] Incoming request: %s", r.RequestURI)
+
+	member, err := core.NewRefFromBase58(args.Reference)
+	if err != nil {
+		return errors.Wrap(err, "[ CallService.Status ] Can't parse member reference")
+	}
+	request, err := core.NewRefFromBase58(args.Request)
+	if err != nil {
+		return errors.Wrap(err, "[ CallService.Status ] Can't parse request reference")
+	}
+
+	err = s.runner.checkStatusSignature(ctx, *member, *request, args)
+	if err != nil {
+		return errors.Wrap(err, "[ CallService.Status ]")
+	}
+
+	status, err := s.runner.callStatus(ctx, *member, *request)
+	if err != nil {
+		return errors.Wrap(err, "[ CallService.Status ]")
+	}
+
+	*result = *status
+	result.TraceID = traceID
+	return nil
+}
+
+// checkStatusSignature checks that status is requested by member that made call.
+func (ar *Runner) checkStatusSignature(ctx context.Context, member, request core.RecordRef, args *CallStatusArgs) error {
+	_, err := ar.checkSeed(ctx, args.Seed)
+	if err != nil {
+		return errors.Wrap(err, "[ checkStatusSignature ]")
+	}
+
+	data, err := core.MarshalArgs(member, request, args.Seed)
+	if err != nil {
+		return errors.Wrap(err, "[ checkStatusSignature ] Can't marshal arguments for verify signature")
+	}
+	keys, err := ar.getMemberKeys(ctx, member.String())
+	if err != nil {
+		return errors.Wrap(err, "[ checkStatusSignature ] Can't getMemberKeys")
+	}
+	// Status doesn't change anything, so signature by any key of member is enough.
+	if !verifySignatures(&memberKeys{keys: keys.keys, threshold: 1}, [][]byte{args.Signature}, data) {
+		return errors.New("[ checkStatusSignature ] Incorrect signature")
+	}
+	return nil
+}
+
+func (ar *Runner) callStatus(ctx context.Context, member, request core.RecordRef) (*CallStatusReply, error) {
+	req, err := ar.ArtifactManager.GetRequestResult(ctx, member, request)
+	if err != nil {
+		return nil, errors.Wrap(err, "Can't get request")
+	}
+	if req == nil {
+		return nil, errors.New("Unknown request")
+	}
+
+	if !req.Finished {
+		return &CallStatusReply{Status: CallStatusPending}, nil
+	}
+	res, err := callResult(req.Result)
+	if err != nil {
+		return &CallStatusReply{Status: CallStatusFailed, Error: err.Error()}, nil
+	}
+	return &CallStatusReply{Status: CallStatusDone, Result: res}, nil
+}
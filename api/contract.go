@@ -0,0 +1,133 @@
+/*
+ *    Copyright 2019 Insolar Technologies
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/core/utils"
+	"github.com/insolar/insolar/instrumentation/inslogger"
+	"github.com/pkg/errors"
+)
+
+// ContractABIArgs is arguments that Contract service accepts.
+type ContractABIArgs struct {
+	Reference string
+}
+
+// ContractABIReply is reply for Contract service requests.
+type ContractABIReply struct {
+	Code    string
+	ABI     json.RawMessage
+	TraceID string
+}
+
+// ContractService is a service that provides API for getting info about deployed contracts.
+type ContractService struct {
+	runner *Runner
+}
+
+// NewContractService creates new Contract service instance.
+func NewContractService(runner *Runner) *ContractService {
+	return &ContractService{runner: runner}
+}
+
+// GetABI returns ABI of contract's code.
+//
+//   Request structure:
+//   {
+//     "jsonrpc": "2.0",
+//     "method": "contract.GetABI",
+//     "params": {
+//       // Reference to code, prototype or object
+//       "Reference": str
+//     },
+//     "id": str|int|null
+//   }
+//
+//   Response structure:
+//   {
+//     "jsonrpc": "2.0",
+//     "result": {
+//       "Code": str, // reference to code record
+//       "ABI": { ... }, // JSON description of contract's constructors, methods and types
+//       "TraceID": str // traceID for request
+//     },
+//     "id": str|int|null // same as in request
+//   }
+//
+func (s *ContractService) GetABI(r *http.Request, args *ContractABIArgs, reply *ContractABIReply) error {
+	traceID := utils.RandTraceID()
+	ctx, inslog := inslogger.WithTraceField(context.Background(), traceID)
+
+	inslog.Infof("[ ContractService.GetABI ] Incoming request: %s", r.RequestURI)
+
+	ref, err := core.NewRefFromBase58(args.Reference)
+	if err != nil {
+		return errors.Wrap(err, "[ ContractService.GetABI ] Can't parse reference")
+	}
+
+	codeRef, err := s.codeRef(ctx, *ref)
+	if err != nil {
+		return errors.Wrap(err, "[ ContractService.GetABI ] Can't find code")
+	}
+
+	desc, err := s.runner.ArtifactManager.GetCode(ctx, *codeRef)
+	if err != nil {
+		return errors.Wrap(err, "[ ContractService.GetABI ] Can't get code")
+	}
+	abi, err := desc.ABI()
+	if err != nil {
+		return errors.Wrap(err, "[ ContractService.GetABI ] Can't get ABI")
+	}
+	if abi == nil {
+		return errors.New("[ ContractService.GetABI ] Code has no ABI")
+	}
+
+	reply.Code = codeRef.String()
+	reply.ABI = abi
+	reply.TraceID = traceID
+
+	return nil
+}
+
+// codeRef resolves reference to object or prototype into reference to its code,
+// any other reference is considered as code reference
+func (s *ContractService) codeRef(ctx context.Context, ref core.RecordRef) (*core.RecordRef, error) {
+	am := s.runner.ArtifactManager
+
+	obj, err := am.GetObject(ctx, ref, nil, false)
+	if err != nil {
+		return &ref, nil
+	}
+
+	if !obj.IsPrototype() {
+		protoRef, err := obj.Prototype()
+		if err != nil {
+			return nil, err
+		}
+		obj, err = am.GetObject(ctx, *protoRef, nil, false)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return obj.Code()
+}
@@ -0,0 +1,379 @@
+/*
+ *    Copyright 2019 Insolar Technologies
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/core/utils"
+	"github.com/insolar/insolar/instrumentation/inslogger"
+	"github.com/pkg/errors"
+)
+
+// Topics of events sent by events endpoint.
+const (
+	// TopicPulse events are sent on each new pulse.
+	TopicPulse = "pulse"
+	// TopicNetwork events are sent when state of network changes.
+	TopicNetwork = "network"
+	// TopicNodes events are sent when list of active nodes changes.
+	TopicNodes = "nodes"
+	// TopicSubscription events confirm subscription messages of client, they are always sent.
+	TopicSubscription = "subscription"
+)
+
+var eventTopics = []string{TopicPulse, TopicNetwork, TopicNodes}
+
+const (
+	// eventPollPeriod is a period of checking pulse storage, network switcher and node network for changes.
+	// Components don't notify about changes, so they are polled while there are subscribers.
+	eventPollPeriod = 100 * time.Millisecond
+	// eventQueueSize is a number of events waiting for sending to client, client is disconnected if it's exceeded.
+	eventQueueSize = 64
+)
+
+// PulseEvent describes new pulse.
+type PulseEvent struct {
+	PulseNumber     uint32
+	PrevPulseNumber uint32
+	NextPulseNumber uint32
+	PulseTimestamp  int64
+	Entropy         []byte
+}
+
+// Event is a message sent to subscribed client, only field related to topic is set.
+type Event struct {
+	Topic        string      `json:"topic"`
+	Pulse        *PulseEvent `json:"pulse,omitempty"`
+	NetworkState string      `json:"networkState,omitempty"`
+	ActiveList   []Node      `json:"activeList,omitempty"`
+	// Topics is a list of topics client is subscribed to after subscription message.
+	Topics []string `json:"topics,omitempty"`
+	Error  string   `json:"error,omitempty"`
+}
+
+// Subscription is a message of client changing set of topics it is subscribed to.
+type Subscription struct {
+	Subscribe   []string `json:"subscribe,omitempty"`
+	Unsubscribe []string `json:"unsubscribe,omitempty"`
+}
+
+func checkTopics(topics []string) error {
+	for _, topic := range topics {
+		known := false
+		for _, t := range eventTopics {
+			known = known || t == topic
+		}
+		if !known {
+			return errors.Errorf("unknown topic %q", topic)
+		}
+	}
+	return nil
+}
+
+type subscriber struct {
+	events chan []byte
+
+	lock   sync.Mutex
+	topics map[string]bool
+}
+
+func newSubscriber(topics []string) *subscriber {
+	s := &subscriber{
+		events: make(chan []byte, eventQueueSize),
+		topics: make(map[string]bool),
+	}
+	s.update(topics, nil)
+	return s
+}
+
+// update changes set of topics and returns resulting topics.
+func (s *subscriber) update(subscribe []string, unsubscribe []string) []string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for _, topic := range subscribe {
+		s.topics[topic] = true
+	}
+	for _, topic := range unsubscribe {
+		delete(s.topics, topic)
+	}
+	topics := []string{}
+	for _, topic := range eventTopics {
+		if s.topics[topic] {
+			topics = append(topics, topic)
+		}
+	}
+	return topics
+}
+
+func (s *subscriber) subscribed(topic string) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.topics[topic]
+}
+
+// eventHub polls components of runner while there are subscribers and sends events on changes.
+type eventHub struct {
+	runner *Runner
+	period time.Duration
+
+	lock        sync.Mutex
+	subscribers map[*subscriber]struct{}
+	stop        chan struct{}
+}
+
+func newEventHub(runner *Runner) *eventHub {
+	return &eventHub{
+		runner:      runner,
+		period:      eventPollPeriod,
+		subscribers: make(map[*subscriber]struct{}),
+	}
+}
+
+// eventState is a last observed state of components.
+type eventState struct {
+	pulse core.PulseNumber
+	state core.NetworkState
+	nodes string
+}
+
+func activeNodes(nodes []core.Node) ([]Node, string) {
+	list := make([]Node, len(nodes))
+	refs := make([]string, len(nodes))
+	for i, node := range nodes {
+		list[i] = Node{
+			Reference: node.ID().String(),
+			Role:      node.Role().String(),
+		}
+		refs[i] = list[i].Reference
+	}
+	sort.Strings(refs)
+	return list, strings.Join(refs, ",")
+}
+
+// poll compares state of components with last observed state and returns events for changes.
+func (h *eventHub) poll(ctx context.Context, last *eventState) []Event {
+	var events []Event
+
+	pulse, err := h.runner.PulseStorage.Current(ctx)
+	if err != nil {
+		inslogger.FromContext(ctx).Debug("[ eventHub ] Can't get current pulse: ", err)
+	} else if pulse.PulseNumber != last.pulse {
+		last.pulse = pulse.PulseNumber
+		events = append(events, Event{
+			Topic: TopicPulse,
+			Pulse: &PulseEvent{
+				PulseNumber:     uint32(pulse.PulseNumber),
+				PrevPulseNumber: uint32(pulse.PrevPulseNumber),
+				NextPulseNumber: uint32(pulse.NextPulseNumber),
+				PulseTimestamp:  pulse.PulseTimestamp,
+				Entropy:         pulse.Entropy[:],
+			},
+		})
+	}
+
+	state := h.runner.NetworkSwitcher.GetState()
+	if state != last.state {
+		last.state = state
+		events = append(events, Event{Topic: TopicNetwork, NetworkState: state.String()})
+	}
+
+	list, nodes := activeNodes(h.runner.NodeNetwork.GetActiveNodes())
+	if nodes != last.nodes {
+		last.nodes = nodes
+		events = append(events, Event{Topic: TopicNodes, ActiveList: list})
+	}
+
+	return events
+}
+
+func (h *eventHub) watch(ctx context.Context, last eventState, stop chan struct{}) {
+	ticker := time.NewTicker(h.period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			for _, event := range h.poll(ctx, &last) {
+				h.broadcast(ctx, event)
+			}
+		}
+	}
+}
+
+func (h *eventHub) broadcast(ctx context.Context, event Event) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		inslogger.FromContext(ctx).Error("[ eventHub ] Can't marshal event: ", err)
+		return
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	for s := range h.subscribers {
+		if !s.subscribed(event.Topic) {
+			continue
+		}
+		select {
+		case s.events <- data:
+		default:
+			inslogger.FromContext(ctx).Warn("[ eventHub ] Event queue of subscriber is full, disconnecting")
+			h.removeLocked(s)
+		}
+	}
+}
+
+// add registers subscriber, polling starts with the first subscriber. Current state is observed synchronously, so
+// subscriber receives events on all changes after add returns.
+func (h *eventHub) add(ctx context.Context, s *subscriber) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.subscribers[s] = struct{}{}
+	if h.stop != nil {
+		return
+	}
+
+	last := eventState{}
+	h.poll(ctx, &last)
+	h.stop = make(chan struct{})
+	go h.watch(ctx, last, h.stop)
+}
+
+// remove unregisters subscriber and closes its events channel, polling stops with the last subscriber.
+func (h *eventHub) remove(s *subscriber) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.removeLocked(s)
+}
+
+func (h *eventHub) removeLocked(s *subscriber) {
+	if _, ok := h.subscribers[s]; !ok {
+		return
+	}
+	delete(h.subscribers, s)
+	close(s.events)
+	if len(h.subscribers) == 0 && h.stop != nil {
+		close(h.stop)
+		h.stop = nil
+	}
+}
+
+// close disconnects all subscribers.
+func (h *eventHub) close() {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	for s := range h.subscribers {
+		h.removeLocked(s)
+	}
+}
+
+func sendEvent(conn *wsConn, event Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return conn.writeText(data)
+}
+
+// eventsHandler upgrades connection to WebSocket and sends events of topics client is subscribed to. Initial topics
+// are passed as comma separated "topics" query parameter, client is subscribed to all topics if it's missing.
+// Later client changes topics by sending Subscription messages.
+func (ar *Runner) eventsHandler() func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		traceID := utils.RandTraceID()
+		ctx, inslog := inslogger.WithTraceField(context.Background(), traceID)
+
+		topics := eventTopics
+		if param, ok := r.URL.Query()["topics"]; ok {
+			topics = []string{}
+			for _, topic := range strings.Split(strings.Join(param, ","), ",") {
+				if topic = strings.TrimSpace(topic); topic != "" {
+					topics = append(topics, topic)
+				}
+			}
+		}
+		if err := checkTopics(topics); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		conn, err := upgradeWebSocket(w, r)
+		if err != nil {
+			inslog.Error(errors.Wrap(err, "[ EventsHandler ] Can't upgrade connection"))
+			return
+		}
+		defer func() {
+			if err := conn.close(); err != nil {
+				inslog.Debug("[ EventsHandler ] Can't close connection: ", err)
+			}
+		}()
+		inslog.Infof("[ EventsHandler ] Client %s subscribed to %v", r.RemoteAddr, topics)
+
+		s := newSubscriber(topics)
+		ar.events.add(ctx, s)
+		defer ar.events.remove(s)
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for {
+				msg, err := conn.readMessage()
+				if err != nil {
+					if err != errWebSocketClosed {
+						inslog.Debug("[ EventsHandler ] Can't read message: ", err)
+					}
+					return
+				}
+				var sub Subscription
+				reply := Event{Topic: TopicSubscription}
+				if err := json.Unmarshal(msg, &sub); err != nil {
+					reply.Error = "Bad subscription message: " + err.Error()
+				} else if err := checkTopics(append(sub.Subscribe, sub.Unsubscribe...)); err != nil {
+					reply.Error = err.Error()
+				} else {
+					reply.Topics = s.update(sub.Subscribe, sub.Unsubscribe)
+				}
+				if err := sendEvent(conn, reply); err != nil {
+					return
+				}
+			}
+		}()
+
+		for {
+			select {
+			case data, ok := <-s.events:
+				if !ok {
+					return
+				}
+				if err := conn.writeText(data); err != nil {
+					inslog.Debug("[ EventsHandler ] Can't send event: ", err)
+					return
+				}
+			case <-done:
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,279 @@
+/*
+ *    Copyright 2019 Insolar Technologies
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package api
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/insolar/insolar/configuration"
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/testutils"
+	"github.com/insolar/insolar/testutils/network"
+	"github.com/stretchr/testify/require"
+)
+
+// wsClient is a minimal WebSocket client sending masked frames.
+type wsClient struct {
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+func dialWebSocket(t *testing.T, rawURL string) *wsClient {
+	u, err := url.Parse(rawURL)
+	require.NoError(t, err)
+	conn, err := net.Dial("tcp", u.Host)
+	require.NoError(t, err)
+
+	keyBytes := make([]byte, 16)
+	_, err = rand.Read(keyBytes)
+	require.NoError(t, err)
+	key := base64.StdEncoding.EncodeToString(keyBytes)
+
+	req, err := http.NewRequest("GET", rawURL, nil)
+	require.NoError(t, err)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", key)
+	require.NoError(t, req.Write(conn))
+
+	r := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(r, req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusSwitchingProtocols, resp.StatusCode)
+	require.Equal(t, wsAcceptKey(key), resp.Header.Get("Sec-WebSocket-Accept"))
+
+	return &wsClient{conn: conn, r: r}
+}
+
+func (c *wsClient) write(t *testing.T, opcode byte, payload []byte) {
+	frame := []byte{0x80 | opcode, 0x80 | byte(len(payload)), 1, 2, 3, 4}
+	for i, b := range payload {
+		frame = append(frame, b^frame[2+i%4])
+	}
+	_, err := c.conn.Write(frame)
+	require.NoError(t, err)
+}
+
+func (c *wsClient) read(t *testing.T) (byte, []byte) {
+	require.NoError(t, c.conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	var header [2]byte
+	_, err := io.ReadFull(c.r, header[:])
+	require.NoError(t, err)
+	length := uint64(header[1] & 0x7F)
+	switch length {
+	case 126:
+		var ext [2]byte
+		_, err = io.ReadFull(c.r, ext[:])
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		_, err = io.ReadFull(c.r, ext[:])
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	require.NoError(t, err)
+	payload := make([]byte, length)
+	_, err = io.ReadFull(c.r, payload)
+	require.NoError(t, err)
+	return header[0] & 0x0F, payload
+}
+
+func (c *wsClient) readEvent(t *testing.T) Event {
+	opcode, payload := c.read(t)
+	require.Equal(t, byte(wsOpText), opcode)
+	var event Event
+	require.NoError(t, json.Unmarshal(payload, &event))
+	return event
+}
+
+func (c *wsClient) subscribe(t *testing.T, sub Subscription) Event {
+	data, err := json.Marshal(sub)
+	require.NoError(t, err)
+	c.write(t, wsOpText, data)
+	event := c.readEvent(t)
+	require.Equal(t, TopicSubscription, event.Topic)
+	return event
+}
+
+// eventSource holds state returned by mocked components.
+type eventSource struct {
+	lock  sync.Mutex
+	pulse core.PulseNumber
+	state core.NetworkState
+	nodes []core.Node
+}
+
+func (s *eventSource) set(f func()) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	f()
+}
+
+func newNodeMock(t *testing.T) core.Node {
+	node := network.NewNodeMock(t)
+	node.IDMock.Return(testutils.RandomRef())
+	node.RoleMock.Return(core.StaticRoleVirtual)
+	return node
+}
+
+func newEventsRunner(t *testing.T) (*Runner, *eventSource) {
+	cfg := configuration.NewAPIRunner()
+	ar, err := NewRunner(&cfg)
+	require.NoError(t, err)
+	ar.events.period = 10 * time.Millisecond
+
+	source := &eventSource{pulse: core.FirstPulseNumber, state: core.CompleteNetworkState, nodes: []core.Node{newNodeMock(t)}}
+
+	ps := testutils.NewPulseStorageMock(t)
+	ps.CurrentMock.Set(func(context.Context) (*core.Pulse, error) {
+		source.lock.Lock()
+		defer source.lock.Unlock()
+		return &core.Pulse{PulseNumber: source.pulse, NextPulseNumber: source.pulse + 10}, nil
+	})
+	ns := testutils.NewNetworkSwitcherMock(t)
+	ns.GetStateMock.Set(func() core.NetworkState {
+		source.lock.Lock()
+		defer source.lock.Unlock()
+		return source.state
+	})
+	nn := network.NewNodeNetworkMock(t)
+	nn.GetActiveNodesMock.Set(func() []core.Node {
+		source.lock.Lock()
+		defer source.lock.Unlock()
+		return source.nodes
+	})
+
+	ar.PulseStorage = ps
+	ar.NetworkSwitcher = ns
+	ar.NodeNetwork = nn
+	return ar, source
+}
+
+func TestEventHub_poll(t *testing.T) {
+	ar, source := newEventsRunner(t)
+	ctx := context.Background()
+
+	last := eventState{}
+	events := ar.events.poll(ctx, &last)
+	require.Len(t, events, 3)
+	require.Empty(t, ar.events.poll(ctx, &last))
+
+	node := newNodeMock(t)
+	source.set(func() { source.nodes = []core.Node{source.nodes[0], node} })
+	events = ar.events.poll(ctx, &last)
+	require.Len(t, events, 1)
+	require.Equal(t, TopicNodes, events[0].Topic)
+	require.Len(t, events[0].ActiveList, 2)
+
+	// order of nodes doesn't matter
+	source.set(func() { source.nodes = []core.Node{node, source.nodes[0]} })
+	require.Empty(t, ar.events.poll(ctx, &last))
+
+	source.set(func() { source.pulse += 10 })
+	events = ar.events.poll(ctx, &last)
+	require.Len(t, events, 1)
+	require.Equal(t, TopicPulse, events[0].Topic)
+	require.Equal(t, uint32(core.FirstPulseNumber+10), events[0].Pulse.PulseNumber)
+	require.Equal(t, uint32(core.FirstPulseNumber+20), events[0].Pulse.NextPulseNumber)
+}
+
+func TestEventsHandler(t *testing.T) {
+	ar, source := newEventsRunner(t)
+	server := httptest.NewServer(http.HandlerFunc(ar.eventsHandler()))
+	defer server.Close()
+
+	client := dialWebSocket(t, server.URL+"/?topics=pulse")
+	defer client.conn.Close()
+	// subscription reply guarantees subscriber is registered
+	require.Equal(t, []string{TopicPulse}, client.subscribe(t, Subscription{}).Topics)
+
+	source.set(func() {
+		source.state = core.VoidNetworkState
+		source.pulse += 10
+	})
+	event := client.readEvent(t)
+	require.Equal(t, TopicPulse, event.Topic)
+	require.Equal(t, uint32(core.FirstPulseNumber+10), event.Pulse.PulseNumber)
+
+	event = client.subscribe(t, Subscription{Subscribe: []string{TopicNetwork}, Unsubscribe: []string{TopicPulse}})
+	require.Equal(t, []string{TopicNetwork}, event.Topics)
+	event = client.subscribe(t, Subscription{Subscribe: []string{"unknown"}})
+	require.Contains(t, event.Error, "unknown topic")
+
+	source.set(func() {
+		source.pulse += 10
+		source.state = core.CompleteNetworkState
+	})
+	event = client.readEvent(t)
+	require.Equal(t, TopicNetwork, event.Topic)
+	require.Equal(t, core.CompleteNetworkState.String(), event.NetworkState)
+
+	client.write(t, wsOpPing, []byte("ping"))
+	opcode, payload := client.read(t)
+	require.Equal(t, byte(wsOpPong), opcode)
+	require.Equal(t, []byte("ping"), payload)
+
+	// runner disconnects subscribers on stop
+	ar.events.close()
+	opcode, _ = client.read(t)
+	require.Equal(t, byte(wsOpClose), opcode)
+}
+
+func TestEventsHandler_BadRequest(t *testing.T) {
+	ar, _ := newEventsRunner(t)
+
+	recorder := httptest.NewRecorder()
+	ar.eventsHandler()(recorder, httptest.NewRequest("GET", "/api/events?topics=pulse,unknown", nil))
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	ar.eventsHandler()(recorder, httptest.NewRequest("GET", "/api/events", nil))
+	require.Equal(t, http.StatusUpgradeRequired, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/api/events", nil)
+	req.Header.Set("Connection", "keep-alive, Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "8")
+	ar.eventsHandler()(recorder, req)
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+	require.Equal(t, "13", recorder.Header().Get("Sec-WebSocket-Version"))
+
+	// Pages of other sites can't connect.
+	recorder = httptest.NewRecorder()
+	req = httptest.NewRequest("GET", "http://node.example.com/api/events", nil)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+	req.Header.Set("Origin", "http://evil.example.com")
+	ar.eventsHandler()(recorder, req)
+	require.Equal(t, http.StatusForbidden, recorder.Code)
+}
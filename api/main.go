@@ -19,7 +19,10 @@ package api
 import (
 	"context"
 	"crypto"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	"io/ioutil"
 	"net"
 	"net/http"
 	"sync"
@@ -48,13 +51,23 @@ type Runner struct {
 	NetworkSwitcher     core.NetworkSwitcher     `inject:""`
 	NodeNetwork         core.NodeNetwork         `inject:""`
 	PulseStorage        core.PulseStorage        `inject:""`
+	ArtifactManager     core.ArtifactManager     `inject:""`
+	MessageBus          core.MessageBus          `inject:""`
+	CryptographyService core.CryptographyService `inject:""`
 	server              *http.Server
 	rpcServer           *rpc.Server
+	adminServer         *http.Server
+	adminRPCServer      *rpc.Server
+	tlsConfig           *tls.Config
+	adminTLSConfig      *tls.Config
 	cfg                 *configuration.APIRunner
-	keyCache            map[string]crypto.PublicKey
+	keyCache            map[string]*memberKeys
 	cacheLock           *sync.RWMutex
+	memberLimiter       *rateLimiter
+	ipLimiter           *rateLimiter
+	events              *eventHub
+	callbacks           *callbackWatcher
 	SeedManager         *seedmanager.SeedManager
-	SeedGenerator       seedmanager.SeedGenerator
 }
 
 func checkConfig(cfg *configuration.APIRunner) error {
@@ -73,37 +86,91 @@ func checkConfig(cfg *configuration.APIRunner) error {
 	if cfg.Timeout == 0 {
 		return errors.New("[ checkConfig ] Timeout must not be null")
 	}
+	if len(cfg.Batch) != 0 && cfg.BatchLimit <= 0 {
+		return errors.New("[ checkConfig ] BatchLimit must be positive")
+	}
 
 	return nil
 }
 
-func (ar *Runner) registerServices(rpcServer *rpc.Server) error {
-	err := rpcServer.RegisterService(NewStorageExporterService(ar), "exporter")
-	if err != nil {
-		return errors.New("[ registerServices ] Can't RegisterService: exporter")
+// service is a JSON-RPC service of API runner.
+type service struct {
+	name     string
+	receiver interface{}
+	// admin services must be available only to node operators, they are served on admin listener if it is set.
+	admin bool
+	// adminOnly services are served only on admin listener and disabled without it.
+	adminOnly bool
+}
+
+func (ar *Runner) services() []service {
+	return []service{
+		{name: "seed", receiver: NewSeedService(ar)},
+		{name: "info", receiver: NewInfoService(ar)},
+		{name: "contract", receiver: NewContractService(ar)},
+		{name: "trace", receiver: NewTraceService(ar), admin: true},
+		{name: "call", receiver: NewCallService(ar)},
+		{name: "exporter", receiver: NewStorageExporterService(ar), admin: true},
+		{name: "status", receiver: NewStatusService(ar), admin: true},
+		{name: "cert", receiver: NewNodeCertService(ar), admin: true},
+		{name: "admin", receiver: NewAdminService(ar), admin: true, adminOnly: true},
 	}
+}
 
-	err = rpcServer.RegisterService(NewSeedService(ar), "seed")
-	if err != nil {
-		return errors.New("[ registerServices ] Can't RegisterService: seed")
+// registerServices registers services on servers of api listeners, adminRPCServer is nil if admin listener isn't set.
+func (ar *Runner) registerServices(rpcServer *rpc.Server, adminRPCServer *rpc.Server) error {
+	for _, s := range ar.services() {
+		server := rpcServer
+		if s.admin && adminRPCServer != nil {
+			server = adminRPCServer
+		} else if s.adminOnly {
+			continue
+		}
+		err := server.RegisterService(s.receiver, s.name)
+		if err != nil {
+			return errors.New("[ registerServices ] Can't RegisterService: " + s.name)
+		}
 	}
+	return nil
+}
 
-	err = rpcServer.RegisterService(NewInfoService(ar), "info")
-	if err != nil {
-		return errors.New("[ registerServices ] Can't RegisterService: info")
+// newTLSConfigs creates TLS configs for public and admin api listeners, nils are returned if TLS is disabled.
+// Client certificates are required only by admin listener.
+func newTLSConfigs(cfg configuration.APITLS, adminAddress string) (*tls.Config, *tls.Config, error) {
+	if cfg.CertFile == "" {
+		if cfg.ClientCAFile != "" {
+			return nil, nil, errors.New("[ newTLSConfigs ] ClientCAFile requires CertFile")
+		}
+		return nil, nil, nil
+	}
+	if cfg.ClientCAFile != "" && adminAddress == "" {
+		return nil, nil, errors.New("[ newTLSConfigs ] ClientCAFile requires AdminAddress")
 	}
 
-	err = rpcServer.RegisterService(NewStatusService(ar), "status")
+	cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
 	if err != nil {
-		return errors.New("[ registerServices ] Can't RegisterService: status")
+		return nil, nil, errors.Wrap(err, "[ newTLSConfigs ] Can't load certificate")
+	}
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if cfg.ClientCAFile == "" {
+		return tlsConfig, tlsConfig, nil
 	}
 
-	err = rpcServer.RegisterService(NewNodeCertService(ar), "cert")
+	caPEM, err := ioutil.ReadFile(cfg.ClientCAFile)
 	if err != nil {
-		return errors.New("[ registerServices ] Can't RegisterService: cert")
+		return nil, nil, errors.Wrap(err, "[ newTLSConfigs ] Can't read client CA")
 	}
-
-	return nil
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPEM) {
+		return nil, nil, errors.New("[ newTLSConfigs ] No certificates in client CA file")
+	}
+	adminTLSConfig := tlsConfig.Clone()
+	adminTLSConfig.ClientCAs = pool
+	adminTLSConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	return tlsConfig, adminTLSConfig, nil
 }
 
 // NewRunner is C-tor for API Runner
@@ -113,19 +180,39 @@ func NewRunner(cfg *configuration.APIRunner) (*Runner, error) {
 		return nil, errors.Wrap(err, "[ NewAPIRunner ] Bad config")
 	}
 
+	tlsConfig, adminTLSConfig, err := newTLSConfigs(cfg.TLS, cfg.AdminAddress)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ NewAPIRunner ] Bad TLS config")
+	}
+
 	addrStr := fmt.Sprint(cfg.Address)
 	rpcServer := rpc.NewServer()
 	ar := Runner{
-		server:    &http.Server{Addr: addrStr},
-		rpcServer: rpcServer,
-		cfg:       cfg,
-		keyCache:  make(map[string]crypto.PublicKey),
-		cacheLock: &sync.RWMutex{},
+		server:         &http.Server{Addr: addrStr},
+		rpcServer:      rpcServer,
+		tlsConfig:      tlsConfig,
+		adminTLSConfig: adminTLSConfig,
+		cfg:            cfg,
+		keyCache:       make(map[string]*memberKeys),
+		cacheLock:      &sync.RWMutex{},
+		memberLimiter:  newRateLimiter(cfg.RateLimit.MemberRate, cfg.RateLimit.MemberBurst),
+		ipLimiter:      newRateLimiter(cfg.RateLimit.IPRate, cfg.RateLimit.IPBurst),
 	}
 
+	ar.events = newEventHub(&ar)
+	ar.callbacks = newCallbackWatcher(&ar, cfg.Callback)
+
 	rpcServer.RegisterCodec(jsonrpc.NewCodec(), "application/json")
 
-	if err := ar.registerServices(rpcServer); err != nil {
+	if len(cfg.AdminAddress) != 0 {
+		ar.adminRPCServer = rpc.NewServer()
+		ar.adminRPCServer.RegisterCodec(jsonrpc.NewCodec(), "application/json")
+		adminMux := http.NewServeMux()
+		adminMux.Handle(cfg.RPC, ar.adminRPCServer)
+		ar.adminServer = &http.Server{Addr: cfg.AdminAddress, Handler: adminMux}
+	}
+
+	if err := ar.registerServices(rpcServer, ar.adminRPCServer); err != nil {
 		return nil, errors.Wrap(err, "[ NewAPIRunner ] Can't register services:")
 	}
 
@@ -139,19 +226,44 @@ func (ar *Runner) IsAPIRunner() bool {
 
 // Start runs api server
 func (ar *Runner) Start(ctx context.Context) error {
-	ar.SeedManager = seedmanager.New()
-	http.HandleFunc(ar.cfg.Call, ar.callHandler())
-	http.Handle(ar.cfg.RPC, ar.rpcServer)
+	ar.SeedManager = seedmanager.New(ar.CryptographyService, ar.NodeNetwork, ar.PulseStorage)
+	http.Handle(ar.cfg.Call, ar.limitIP(http.HandlerFunc(ar.callHandler())))
+	if len(ar.cfg.Batch) != 0 {
+		http.Handle(ar.cfg.Batch, ar.limitIP(http.HandlerFunc(ar.batchHandler())))
+	}
+	http.Handle(ar.cfg.RPC, ar.limitIP(ar.rpcServer))
+	if len(ar.cfg.Schema) != 0 {
+		http.Handle(ar.cfg.Schema, ar.limitIP(http.HandlerFunc(ar.schemaHandler())))
+	}
+	if len(ar.cfg.Events) != 0 {
+		http.Handle(ar.cfg.Events, ar.limitIP(http.HandlerFunc(ar.eventsHandler())))
+	}
+	ar.callbacks.start(ctx)
 	inslog := inslogger.FromContext(ctx)
 	inslog.Info("Starting ApiRunner ...")
 	inslog.Info("Config: ", ar.cfg)
-	listener, err := net.Listen("tcp", ar.server.Addr)
+	if err := ar.serve(ctx, ar.server, ar.tlsConfig); err != nil {
+		return err
+	}
+	if ar.adminServer != nil {
+		if err := ar.serve(ctx, ar.adminServer, ar.adminTLSConfig); err != nil {
+			return errors.Wrap(err, "Can't start admin server")
+		}
+	}
+	return nil
+}
+
+func (ar *Runner) serve(ctx context.Context, server *http.Server, tlsConfig *tls.Config) error {
+	listener, err := net.Listen("tcp", server.Addr)
 	if err != nil {
 		return errors.Wrap(err, "Can't start listening")
 	}
+	if tlsConfig != nil {
+		listener = tls.NewListener(listener, tlsConfig)
+	}
 	go func() {
-		if err := ar.server.Serve(listener); err != nil {
-			inslog.Error("Httpserver: ListenAndServe() error: ", err)
+		if err := server.Serve(listener); err != nil {
+			inslogger.FromContext(ctx).Error("Httpserver: ListenAndServe() error: ", err)
 		}
 	}()
 	return nil
@@ -164,44 +276,78 @@ func (ar *Runner) Stop(ctx context.Context) error {
 	inslogger.FromContext(ctx).Infof("Shutting down server gracefully ...(waiting for %d seconds)", timeOut)
 	ctxWithTimeout, cancel := context.WithTimeout(ctx, time.Duration(timeOut)*time.Second)
 	defer cancel()
+	// hijacked websocket connections are not closed by Shutdown
+	ar.events.close()
+	ar.callbacks.close()
 	err := ar.server.Shutdown(ctxWithTimeout)
 	if err != nil {
 		return errors.Wrap(err, "Can't gracefully stop API server")
 	}
+	if ar.adminServer != nil {
+		err = ar.adminServer.Shutdown(ctxWithTimeout)
+		if err != nil {
+			return errors.Wrap(err, "Can't gracefully stop admin API server")
+		}
+	}
 
 	return nil
 }
 
-func (ar *Runner) getMemberPubKey(ctx context.Context, ref string) (crypto.PublicKey, error) { //nolint
+// memberKeys holds keys of member allowed to sign calls and number of signatures required for call.
+type memberKeys struct {
+	keys      []crypto.PublicKey
+	threshold int
+}
+
+func (ar *Runner) getMemberKeys(ctx context.Context, ref string) (*memberKeys, error) {
 	ar.cacheLock.RLock()
-	publicKey, ok := ar.keyCache[ref]
+	keys, ok := ar.keyCache[ref]
 	ar.cacheLock.RUnlock()
 	if ok {
-		return publicKey, nil
+		return keys, nil
 	}
 
 	reference, err := core.NewRefFromBase58(ref)
 	if err != nil {
-		return nil, errors.Wrap(err, "[ getMemberPubKey ] Can't parse ref")
+		return nil, errors.Wrap(err, "[ getMemberKeys ] Can't parse ref")
+	}
+	res, err := ar.ContractRequester.SendRequest(ctx, reference, "GetPublicKeys", []interface{}{})
+	if err != nil {
+		return nil, errors.Wrap(err, "[ getMemberKeys ] Can't get public keys")
 	}
-	res, err := ar.ContractRequester.SendRequest(ctx, reference, "GetPublicKey", []interface{}{})
+	publicKeyStrings, err := extractor.PublicKeysResponse(res.(*reply.CallMethod).Result)
 	if err != nil {
-		return nil, errors.Wrap(err, "[ getMemberPubKey ] Can't get public key")
+		return nil, errors.Wrap(err, "[ getMemberKeys ] Can't extract response")
 	}
 
-	publicKeyString, err := extractor.PublicKeyResponse(res.(*reply.CallMethod).Result)
+	res, err = ar.ContractRequester.SendRequest(ctx, reference, "GetThreshold", []interface{}{})
+	if err != nil {
+		return nil, errors.Wrap(err, "[ getMemberKeys ] Can't get threshold")
+	}
+	threshold, err := extractor.ThresholdResponse(res.(*reply.CallMethod).Result)
 	if err != nil {
-		return nil, errors.Wrap(err, "[ getMemberPubKey ] Can't extract response")
+		return nil, errors.Wrap(err, "[ getMemberKeys ] Can't extract response")
 	}
 
 	kp := platformpolicy.NewKeyProcessor()
-	publicKey, err = kp.ImportPublicKeyPEM([]byte(publicKeyString))
-	if err != nil {
-		return nil, errors.Wrap(err, "Failed to convert public key")
+	keys = &memberKeys{threshold: int(threshold)}
+	for _, publicKeyString := range publicKeyStrings {
+		publicKey, err := kp.ImportPublicKeyPEM([]byte(publicKeyString))
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to convert public key")
+		}
+		keys.keys = append(keys.keys, publicKey)
 	}
 
 	ar.cacheLock.Lock()
-	ar.keyCache[ref] = publicKey
+	ar.keyCache[ref] = keys
+	ar.cacheLock.Unlock()
+	return keys, nil
+}
+
+// invalidateMemberKeys drops cached keys of member, so they are fetched again on the next call.
+func (ar *Runner) invalidateMemberKeys(ref string) {
+	ar.cacheLock.Lock()
+	delete(ar.keyCache, ref)
 	ar.cacheLock.Unlock()
-	return publicKey, nil
 }
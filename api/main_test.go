@@ -20,6 +20,7 @@ import (
 	"context"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 	"reflect"
 	"testing"
 
@@ -96,6 +97,81 @@ func (suite *MainAPISuite) TestNewApiRunnerNoRequiredParams() {
 	suite.NoError(err)
 }
 
+func (suite *MainAPISuite) TestNewApiRunnerAdminServices() {
+	cfg := configuration.NewAPIRunner()
+	api, err := NewRunner(&cfg)
+	suite.NoError(err)
+	suite.True(api.rpcServer.HasMethod("status.Get"))
+	suite.False(api.rpcServer.HasMethod("admin.CancelQueuedRequest"))
+	suite.False(api.rpcServer.HasMethod("admin.Replay"))
+	suite.Nil(api.adminServer)
+
+	cfg.AdminAddress = "localhost:19102"
+	api, err = NewRunner(&cfg)
+	suite.NoError(err)
+	suite.False(api.rpcServer.HasMethod("status.Get"))
+	suite.False(api.rpcServer.HasMethod("trace.GetCallTree"))
+	suite.False(api.rpcServer.HasMethod("admin.CancelQueuedRequest"))
+	suite.True(api.rpcServer.HasMethod("seed.Get"))
+	suite.True(api.adminRPCServer.HasMethod("status.Get"))
+	suite.True(api.adminRPCServer.HasMethod("trace.GetCallTree"))
+	suite.True(api.adminRPCServer.HasMethod("admin.CancelQueuedRequest"))
+	suite.True(api.adminRPCServer.HasMethod("admin.Replay"))
+	suite.Equal("localhost:19102", api.adminServer.Addr)
+}
+
+func (suite *MainAPISuite) TestNewApiRunnerBadTLS() {
+	cfg := configuration.NewAPIRunner()
+	cfg.TLS.ClientCAFile = "ca.pem"
+	_, err := NewRunner(&cfg)
+	suite.Contains(err.Error(), "ClientCAFile requires CertFile")
+
+	cfg.TLS.CertFile = "not_existing_cert.pem"
+	cfg.TLS.KeyFile = "not_existing_key.pem"
+	_, err = NewRunner(&cfg)
+	suite.Contains(err.Error(), "ClientCAFile requires AdminAddress")
+
+	cfg.AdminAddress = "localhost:19102"
+	_, err = NewRunner(&cfg)
+	suite.Contains(err.Error(), "Can't load certificate")
+}
+
+func (suite *MainAPISuite) TestLimitIP() {
+	cfg := configuration.NewAPIRunner()
+	cfg.RateLimit.IPRate = 1
+	cfg.RateLimit.IPBurst = 1
+	api, err := NewRunner(&cfg)
+	suite.NoError(err)
+
+	handler := api.limitIP(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	serve := func(addr string) int {
+		req := httptest.NewRequest("POST", TestUrl, nil)
+		req.RemoteAddr = addr
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+		return recorder.Code
+	}
+
+	suite.Equal(http.StatusOK, serve("10.0.0.1:1000"))
+	suite.Equal(http.StatusTooManyRequests, serve("10.0.0.1:1001"))
+	suite.Equal(http.StatusOK, serve("10.0.0.2:1000"))
+}
+
+func (suite *MainAPISuite) TestLimitBatch() {
+	cfg := configuration.NewAPIRunner()
+	cfg.RateLimit.IPRate = 1
+	cfg.RateLimit.IPBurst = 3
+	api, err := NewRunner(&cfg)
+	suite.NoError(err)
+
+	req := httptest.NewRequest("POST", TestUrl, nil)
+	req.RemoteAddr = "10.0.0.1:1000"
+	// Batch request itself takes one token in limitIP.
+	suite.True(api.ipLimiter.allow(clientIP(req)))
+	suite.True(api.limitBatch(req, 3))
+	suite.False(api.limitBatch(req, 2))
+}
+
 func TestMainTestSuite(t *testing.T) {
 	ctx, _ := inslogger.WithTraceField(context.Background(), "APItests")
 	http.DefaultServeMux = new(http.ServeMux)
@@ -0,0 +1,148 @@
+/*
+ *    Copyright 2019 Insolar Technologies
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package api
+
+import (
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/insolar/insolar/metrics"
+)
+
+// rateLimiterCleanupPeriod is a period of removing buckets of clients that have not made requests for a long time.
+const rateLimiterCleanupPeriod = time.Minute
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// rateLimiter is a token bucket limiter that allows rate events per second for each key with bursts up to burst events.
+type rateLimiter struct {
+	rate  float64
+	burst float64
+	now   func() time.Time
+
+	lock        sync.Mutex
+	buckets     map[string]*tokenBucket
+	lastCleanup time.Time
+}
+
+// newRateLimiter creates limiter, nil limiter allowing everything is returned if rate is not positive.
+func newRateLimiter(rate float64, burst int) *rateLimiter {
+	if rate <= 0 {
+		return nil
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return &rateLimiter{
+		rate:    rate,
+		burst:   float64(burst),
+		now:     time.Now,
+		buckets: make(map[string]*tokenBucket),
+	}
+}
+
+// allow takes token from bucket of key and returns false if bucket is empty.
+func (l *rateLimiter) allow(key string) bool {
+	return l.allowN(key, 1)
+}
+
+// allowN takes n tokens from bucket of key and returns false without taking any if bucket has less of them.
+func (l *rateLimiter) allowN(key string, n int) bool {
+	if l == nil || n <= 0 {
+		return true
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	now := l.now()
+	l.cleanup(now)
+
+	bucket, ok := l.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: l.burst, last: now}
+		l.buckets[key] = bucket
+	}
+	bucket.tokens += now.Sub(bucket.last).Seconds() * l.rate
+	if bucket.tokens > l.burst {
+		bucket.tokens = l.burst
+	}
+	bucket.last = now
+
+	if bucket.tokens < float64(n) {
+		return false
+	}
+	bucket.tokens -= float64(n)
+	return true
+}
+
+// cleanup removes buckets that are full again, they are the same as new ones.
+func (l *rateLimiter) cleanup(now time.Time) {
+	if now.Sub(l.lastCleanup) < rateLimiterCleanupPeriod {
+		return
+	}
+	l.lastCleanup = now
+	for key, bucket := range l.buckets {
+		if bucket.tokens+now.Sub(bucket.last).Seconds()*l.rate >= l.burst {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+func clientIP(req *http.Request) string {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+	return host
+}
+
+// limitIP rejects requests of clients exceeding rate limit for IP.
+func (ar *Runner) limitIP(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(response http.ResponseWriter, req *http.Request) {
+		if !ar.ipLimiter.allow(clientIP(req)) {
+			metrics.APIRateLimitExceeded.WithLabelValues("ip").Inc()
+			http.Error(response, "Rate limit exceeded", http.StatusTooManyRequests)
+			return
+		}
+		next.ServeHTTP(response, req)
+	})
+}
+
+// limitBatch returns false if client exceeds its rate limit with items of batch, request of batch itself is counted
+// by limitIP as one item.
+func (ar *Runner) limitBatch(req *http.Request, items int) bool {
+	if !ar.ipLimiter.allowN(clientIP(req), items-1) {
+		metrics.APIRateLimitExceeded.WithLabelValues("ip").Inc()
+		return false
+	}
+	return true
+}
+
+// limitMember returns false if member exceeds its rate limit.
+func (ar *Runner) limitMember(member string) bool {
+	if !ar.memberLimiter.allow(member) {
+		metrics.APIRateLimitExceeded.WithLabelValues("member").Inc()
+		return false
+	}
+	return true
+}
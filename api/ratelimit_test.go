@@ -0,0 +1,79 @@
+/*
+ *    Copyright 2019 Insolar Technologies
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Now()
+	l := newRateLimiter(2, 3)
+	l.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		require.True(t, l.allow("a"))
+	}
+	require.False(t, l.allow("a"))
+	// Other keys have their own buckets.
+	require.True(t, l.allow("b"))
+
+	now = now.Add(500 * time.Millisecond)
+	require.True(t, l.allow("a"))
+	require.False(t, l.allow("a"))
+
+	// Bucket is not filled over burst.
+	now = now.Add(time.Hour)
+	for i := 0; i < 3; i++ {
+		require.True(t, l.allow("a"))
+	}
+	require.False(t, l.allow("a"))
+}
+
+func TestRateLimiter_AllowN(t *testing.T) {
+	now := time.Now()
+	l := newRateLimiter(1, 5)
+	l.now = func() time.Time { return now }
+
+	require.True(t, l.allowN("a", 3))
+	// Tokens are not taken if there are not enough of them.
+	require.False(t, l.allowN("a", 3))
+	require.True(t, l.allowN("a", 2))
+	require.False(t, l.allow("a"))
+}
+
+func TestRateLimiter_Cleanup(t *testing.T) {
+	now := time.Now()
+	l := newRateLimiter(1, 1)
+	l.now = func() time.Time { return now }
+
+	require.True(t, l.allow("a"))
+	now = now.Add(rateLimiterCleanupPeriod)
+	require.True(t, l.allow("b"))
+	require.Len(t, l.buckets, 1)
+}
+
+func TestRateLimiter_Disabled(t *testing.T) {
+	l := newRateLimiter(0, 10)
+	require.Nil(t, l)
+	for i := 0; i < 100; i++ {
+		require.True(t, l.allow("a"))
+	}
+}
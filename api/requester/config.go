@@ -38,6 +38,12 @@ type UserConfigJSON struct {
 type RequestConfigJSON struct {
 	Params []interface{} `json:"params"`
 	Method string        `json:"method"`
+	// IdempotencyKey is optional, retry with the same key returns result of the first request
+	IdempotencyKey string `json:"idempotencyKey,omitempty"`
+	// Async makes call return reference to request without waiting for result
+	Async bool `json:"async,omitempty"`
+	// Callback is optional URL notified when async call is finished
+	Callback string `json:"callback,omitempty"`
 }
 
 func readFile(path string, configType interface{}) error {
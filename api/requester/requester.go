@@ -176,13 +176,37 @@ func SendWithSeed(ctx context.Context, url string, userCfg *UserConfigJSON, reqC
 	}
 	verboseInfo(ctx, "Signing request completed")
 
-	body, err := GetResponseBody(url, PostParams{
+	postParams := PostParams{
 		"params":    params,
 		"method":    reqCfg.Method,
 		"reference": userCfg.Caller,
 		"seed":      seed,
 		"signature": signature.Bytes(),
-	})
+	}
+	if reqCfg.IdempotencyKey != "" {
+		postParams["idempotencyKey"] = reqCfg.IdempotencyKey
+	}
+	if reqCfg.Async {
+		postParams["async"] = true
+	}
+	if reqCfg.Callback != "" {
+		serCallback, err := core.MarshalArgs(
+			*callerRef,
+			reqCfg.Method,
+			params,
+			seed,
+			reqCfg.Callback)
+		if err != nil {
+			return nil, errors.Wrap(err, "[ Send ] Problem with serializing callback")
+		}
+		callbackSignature, err := cs.Sign(serCallback)
+		if err != nil {
+			return nil, errors.Wrap(err, "[ Send ] Problem with signing callback")
+		}
+		postParams["callback"] = reqCfg.Callback
+		postParams["callbackSignature"] = callbackSignature.Bytes()
+	}
+	body, err := GetResponseBody(url, postParams)
 
 	if err != nil {
 		return nil, errors.Wrap(err, "[ Send ] Problem with sending target request")
@@ -267,3 +291,75 @@ func Status(url string) (*StatusResponse, error) {
 
 	return res, nil
 }
+
+// Replay makes rpc request to admin.Replay method and extracts it
+func Replay(url string, request string) (*ReplayResponse, error) {
+	params := getDefaultRPCParams("admin.Replay")
+	params["params"] = map[string]string{"Request": request}
+
+	body, err := GetResponseBody(url+"/rpc", params)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ Replay ]")
+	}
+
+	replayResp := rpcReplayResponse{}
+
+	err = json.Unmarshal(body, &replayResp)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ Replay ] Can't unmarshal")
+	}
+	if replayResp.Error != nil {
+		return nil, errors.New("[ Replay ] Field 'error' is not nil: " + fmt.Sprint(replayResp.Error))
+	}
+
+	return &replayResp.Result, nil
+}
+
+// CallStatus gets seed, signs it with reference to request made by user and makes rpc request to call.Status method
+func CallStatus(ctx context.Context, url string, userCfg *UserConfigJSON, request string) (*CallStatusResponse, error) {
+	callerRef, err := core.NewRefFromBase58(userCfg.Caller)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ CallStatus ] Failed to parse userCfg.Caller")
+	}
+	requestRef, err := core.NewRefFromBase58(request)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ CallStatus ] Failed to parse request")
+	}
+	seed, err := GetSeed(url)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ CallStatus ] Problem with getting seed")
+	}
+
+	data, err := core.MarshalArgs(*callerRef, *requestRef, seed)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ CallStatus ] Problem with serializing request")
+	}
+	verboseInfo(ctx, "Signing request ...")
+	signature, err := scheme.Signer(userCfg.privateKeyObject).Sign(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ CallStatus ] Problem with signing request")
+	}
+
+	params := getDefaultRPCParams("call.Status")
+	params["params"] = map[string]interface{}{
+		"Reference": userCfg.Caller,
+		"Request":   request,
+		"Seed":      seed,
+		"Signature": signature.Bytes(),
+	}
+	body, err := GetResponseBody(url+"/rpc", params)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ CallStatus ]")
+	}
+
+	statusResp := rpcCallStatusResponse{}
+	err = json.Unmarshal(body, &statusResp)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ CallStatus ] Can't unmarshal")
+	}
+	if statusResp.Error != nil {
+		return nil, errors.New("[ CallStatus ] Field 'error' is not nil: " + fmt.Sprint(statusResp.Error))
+	}
+
+	return &statusResp.Result, nil
+}
@@ -16,6 +16,8 @@
 
 package requester
 
+import "encoding/json"
+
 type rpcResponse struct {
 	RPCVersion string                 `json:"jsonrpc"`
 	Error      map[string]interface{} `json:"error"`
@@ -52,3 +54,36 @@ type rpcInfoResponse struct {
 	rpcResponse
 	Result InfoResponse `json:"result"`
 }
+
+// ReplayResponse represents response from rpc on admin.Replay method
+type ReplayResponse struct {
+	Object            string          `json:"Object"`
+	Method            string          `json:"Method"`
+	State             []byte          `json:"State"`
+	StoredState       []byte          `json:"StoredState"`
+	Deactivated       bool            `json:"Deactivated"`
+	StoredDeactivated bool            `json:"StoredDeactivated"`
+	Result            json.RawMessage `json:"Result"`
+	StoredResult      json.RawMessage `json:"StoredResult"`
+	Error             string          `json:"Error"`
+	Diff              []string        `json:"Diff"`
+	TraceID           string          `json:"TraceID"`
+}
+
+type rpcReplayResponse struct {
+	rpcResponse
+	Result ReplayResponse `json:"result"`
+}
+
+// CallStatusResponse represents response from rpc on call.Status method
+type CallStatusResponse struct {
+	Status  string          `json:"Status"`
+	Result  json.RawMessage `json:"Result"`
+	Error   string          `json:"Error"`
+	TraceID string          `json:"TraceID"`
+}
+
+type rpcCallStatusResponse struct {
+	rpcResponse
+	Result CallStatusResponse `json:"result"`
+}
@@ -0,0 +1,489 @@
+/*
+ *    Copyright 2019 Insolar Technologies
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/instrumentation/inslogger"
+)
+
+// SchemaVersion is a version of schema format, it is changed on incompatible changes of format.
+const SchemaVersion = "1"
+
+// Schema is a machine-readable description of API. Types are described by JSON schemas, named structs are put to
+// Definitions and referenced as "#/definitions/<name>".
+type Schema struct {
+	Version     string                 `json:"version"`
+	Endpoints   []EndpointSchema       `json:"endpoints"`
+	RPC         RPCSchema              `json:"rpc"`
+	Member      MemberSchema           `json:"member"`
+	Definitions map[string]interface{} `json:"definitions"`
+}
+
+// EndpointSchema describes HTTP endpoint accepting JSON body.
+type EndpointSchema struct {
+	Path        string      `json:"path"`
+	Description string      `json:"description"`
+	Request     interface{} `json:"request"`
+	Response    interface{} `json:"response"`
+}
+
+// RPCSchema describes JSON-RPC 2.0 methods, params and result are contents of "params" and "result" fields.
+type RPCSchema struct {
+	Path    string            `json:"path"`
+	Methods []RPCMethodSchema `json:"methods"`
+}
+
+// RPCMethodSchema describes method of JSON-RPC service.
+type RPCMethodSchema struct {
+	Name string `json:"name"`
+	// Admin methods are served on admin listener if it is configured.
+	Admin  bool        `json:"admin"`
+	Params interface{} `json:"params"`
+	Result interface{} `json:"result"`
+}
+
+// MemberSchema describes methods available through call endpoint.
+type MemberSchema struct {
+	Description string               `json:"description"`
+	Methods     []MemberMethodSchema `json:"methods"`
+}
+
+// MemberMethodSchema describes method of member, its params are CBOR-encoded array of values in order of Params.
+type MemberMethodSchema struct {
+	Name        string              `json:"name"`
+	Description string              `json:"description"`
+	Params      []MemberParamSchema `json:"params"`
+	Result      interface{}         `json:"result"`
+}
+
+// MemberParamSchema describes param of member method.
+type MemberParamSchema struct {
+	Name   string      `json:"name"`
+	Schema interface{} `json:"schema"`
+}
+
+type memberParam struct {
+	name string
+	typ  reflect.Type
+}
+
+type memberMethod struct {
+	name        string
+	description string
+	params      []memberParam
+	// result is nil if method returns nothing.
+	result reflect.Type
+}
+
+var (
+	typeString  = reflect.TypeOf("")
+	typeUint    = reflect.TypeOf(uint(0))
+	typeBytes   = reflect.TypeOf([]byte{})
+	typeStrings = reflect.TypeOf([]string{})
+
+	typeRecordRef = reflect.TypeOf(core.RecordRef{})
+	typeRecordID  = reflect.TypeOf(core.RecordID{})
+)
+
+// memberMethods are methods of Member.Call, this list must be updated with methods of member contract.
+var memberMethods = []memberMethod{
+	{
+		name:        "CreateMember",
+		description: "Creates member with wallet, called by root member. Returns reference to new member.",
+		params:      []memberParam{{"name", typeString}, {"key", typeString}},
+		result:      typeString,
+	},
+	{
+		name:        "GetMyBalance",
+		description: "Returns balance of caller.",
+		result:      typeUint,
+	},
+	{
+		name:        "GetBalance",
+		description: "Returns balance of member.",
+		params:      []memberParam{{"reference", typeString}},
+		result:      typeUint,
+	},
+	{
+		name:        "Transfer",
+		description: "Transfers amount from wallet of caller to wallet of member.",
+		params:      []memberParam{{"amount", typeUint}, {"to", typeString}},
+	},
+	{
+		name:        "DumpUserInfo",
+		description: "Returns JSON with name and balance of member, only root member can dump other members.",
+		params:      []memberParam{{"reference", typeString}},
+		result:      typeBytes,
+	},
+	{
+		name:        "DumpAllUsers",
+		description: "Returns JSON array with names and balances of all members, called by root member.",
+		result:      typeBytes,
+	},
+	{
+		name:        "RegisterNode",
+		description: "Registers node with public key and role, called by root member. Returns certificate of node.",
+		params:      []memberParam{{"publicKey", typeString}, {"role", typeString}},
+		result:      typeString,
+	},
+	{
+		name:        "GetNodeRef",
+		description: "Returns reference to node with public key.",
+		params:      []memberParam{{"publicKey", typeString}},
+		result:      typeString,
+	},
+	{
+		name:        "AddKey",
+		description: "Adds public key allowed to sign calls of caller. Proof is a signature of caller reference and key made with the key.",
+		params:      []memberParam{{"key", typeString}, {"proof", typeBytes}},
+	},
+	{
+		name:        "RevokeKey",
+		description: "Revokes public key of caller.",
+		params:      []memberParam{{"key", typeString}},
+	},
+	{
+		name:        "RotateKey",
+		description: "Replaces public key of caller with new one. Proof is a signature of caller reference and new key made with the new key.",
+		params:      []memberParam{{"oldKey", typeString}, {"newKey", typeString}, {"proof", typeBytes}},
+	},
+	{
+		name:        "SetThreshold",
+		description: "Sets number of caller keys which signatures are required to make a call.",
+		params:      []memberParam{{"threshold", typeUint}},
+	},
+	{
+		name:        "CreateAsset",
+		description: "Creates asset with unique name, whole supply is issued to caller. Supply is a decimal string. Returns reference to asset.",
+		params:      []memberParam{{"name", typeString}, {"decimals", typeUint}, {"supply", typeString}},
+		result:      typeString,
+	},
+	{
+		name:        "GetAsset",
+		description: "Returns JSON with reference, name, decimals, supply and issuer of asset.",
+		params:      []memberParam{{"name", typeString}},
+		result:      typeBytes,
+	},
+	{
+		name:        "GetAssetBalance",
+		description: "Returns balance of asset of member as a decimal string.",
+		params:      []memberParam{{"asset", typeString}, {"member", typeString}},
+		result:      typeString,
+	},
+	{
+		name:        "GetAssetBalances",
+		description: "Returns JSON object with balances of all assets of member.",
+		params:      []memberParam{{"member", typeString}},
+		result:      typeBytes,
+	},
+	{
+		name:        "TransferAsset",
+		description: "Transfers decimal amount of asset from token wallet of caller to token wallet of member.",
+		params:      []memberParam{{"asset", typeString}, {"amount", typeString}, {"to", typeString}},
+	},
+	{
+		name:        "Approve",
+		description: "Allows spender to transfer up to decimal amount of asset of caller, zero amount revokes approval.",
+		params:      []memberParam{{"asset", typeString}, {"spender", typeString}, {"amount", typeString}},
+	},
+	{
+		name:        "GetAllowance",
+		description: "Returns amount of asset of owner spender is allowed to transfer as a decimal string.",
+		params:      []memberParam{{"asset", typeString}, {"owner", typeString}, {"spender", typeString}},
+		result:      typeString,
+	},
+	{
+		name:        "TransferFrom",
+		description: "Transfers decimal amount of asset approved to caller by owner to token wallet of member.",
+		params: []memberParam{
+			{"asset", typeString}, {"owner", typeString}, {"to", typeString}, {"amount", typeString},
+		},
+	},
+	{
+		name:        "GetTransferLog",
+		description: "Returns JSON array with up to limit transfer records of member starting from offset.",
+		params:      []memberParam{{"member", typeString}, {"offset", typeUint}, {"limit", typeUint}},
+		result:      typeBytes,
+	},
+	{
+		name:        "GetMemberByName",
+		description: "Returns references to members with name in order of creation, names aren't unique.",
+		params:      []memberParam{{"name", typeString}},
+		result:      typeStrings,
+	},
+	{
+		name:        "GetMemberByPublicKey",
+		description: "Returns references to members with public key among their keys.",
+		params:      []memberParam{{"publicKey", typeString}},
+		result:      typeStrings,
+	},
+	{
+		name:        "ListMembers",
+		description: "Returns JSON array with references and names of up to limit members starting from offset in order of creation.",
+		params:      []memberParam{{"offset", typeUint}, {"limit", typeUint}},
+		result:      typeBytes,
+	},
+}
+
+// schemaBuilder builds JSON schemas of Go types as they are marshaled by encoding/json.
+type schemaBuilder struct {
+	definitions map[string]interface{}
+	names       map[reflect.Type]string
+}
+
+func newSchemaBuilder() *schemaBuilder {
+	return &schemaBuilder{
+		definitions: map[string]interface{}{},
+		names:       map[reflect.Type]string{},
+	}
+}
+
+func (b *schemaBuilder) typeSchema(t reflect.Type) interface{} {
+	if t == nil {
+		return map[string]interface{}{"type": "null"}
+	}
+	if t == typeRecordRef || t == typeRecordID {
+		return map[string]interface{}{"type": "string", "contentEncoding": "base58"}
+	}
+	if t.Implements(typeMarshaler) || reflect.PtrTo(t).Implements(typeMarshaler) {
+		// Custom JSON representation can't be described by reflection.
+		return map[string]interface{}{}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return b.typeSchema(t.Elem())
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer", "minimum": 0}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]interface{}{"type": "string", "contentEncoding": "base64"}
+		}
+		return map[string]interface{}{"type": "array", "items": b.typeSchema(t.Elem())}
+	case reflect.Array:
+		return map[string]interface{}{
+			"type":     "array",
+			"items":    b.typeSchema(t.Elem()),
+			"minItems": t.Len(),
+			"maxItems": t.Len(),
+		}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": b.typeSchema(t.Elem())}
+	case reflect.Struct:
+		return b.structSchema(t)
+	}
+	// interface{} and types without JSON representation accept anything.
+	return map[string]interface{}{}
+}
+
+func (b *schemaBuilder) structSchema(t reflect.Type) interface{} {
+	if t.Name() == "" {
+		return b.objectSchema(t)
+	}
+
+	name, ok := b.names[t]
+	if !ok {
+		name = t.Name()
+		if _, taken := b.definitions[name]; taken {
+			name = path.Base(t.PkgPath()) + "." + name
+		}
+		b.names[t] = name
+		// Placeholder stops recursion on recursive types.
+		b.definitions[name] = nil
+		b.definitions[name] = b.objectSchema(t)
+	}
+	return map[string]interface{}{"$ref": "#/definitions/" + name}
+}
+
+func (b *schemaBuilder) objectSchema(t reflect.Type) interface{} {
+	properties := map[string]interface{}{}
+	required := []string{}
+	b.addFields(t, properties, &required)
+	sort.Strings(required)
+
+	schema := map[string]interface{}{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func (b *schemaBuilder) addFields(t reflect.Type, properties map[string]interface{}, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options := tag, ""
+		if idx := strings.Index(tag, ","); idx >= 0 {
+			name, options = tag[:idx], tag[idx+1:]
+		}
+
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				b.addFields(ft, properties, required)
+				continue
+			}
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		properties[name] = b.typeSchema(field.Type)
+		if !strings.Contains(options, "omitempty") {
+			*required = append(*required, name)
+		}
+	}
+}
+
+var (
+	typeMarshaler   = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	typeHTTPRequest = reflect.TypeOf((*http.Request)(nil))
+	typeError       = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// rpcMethods returns methods of service receiver that are exported by gorilla rpc server.
+func rpcMethods(receiver interface{}) []reflect.Method {
+	var methods []reflect.Method
+	t := reflect.TypeOf(receiver)
+	for i := 0; i < t.NumMethod(); i++ {
+		method := t.Method(i)
+		mt := method.Type
+		if method.PkgPath != "" || mt.NumIn() != 4 || mt.NumOut() != 1 {
+			continue
+		}
+		if mt.In(1) != typeHTTPRequest || mt.In(2).Kind() != reflect.Ptr || mt.In(3).Kind() != reflect.Ptr {
+			continue
+		}
+		if mt.Out(0) != typeError {
+			continue
+		}
+		methods = append(methods, method)
+	}
+	return methods
+}
+
+// schema describes endpoints and services of runner.
+func (ar *Runner) schema() *Schema {
+	b := newSchemaBuilder()
+
+	schema := &Schema{
+		Version: SchemaVersion,
+		Endpoints: []EndpointSchema{
+			{
+				Path: ar.cfg.Call,
+				Description: "Calls member method. Params are CBOR-encoded array of method params, signature is made " +
+					"by member key over CBOR-encoded array of member reference, method, params and seed.",
+				Request:  b.typeSchema(reflect.TypeOf(Request{})),
+				Response: b.typeSchema(reflect.TypeOf(answer{})),
+			},
+		},
+		RPC: RPCSchema{Path: ar.cfg.RPC},
+		Member: MemberSchema{
+			Description: "Methods of member called through " + ar.cfg.Call + ". Result is the value returned by " +
+				"method, values of bytes type are base64 encoded.",
+		},
+	}
+	if len(ar.cfg.Batch) != 0 {
+		schema.Endpoints = append(schema.Endpoints, EndpointSchema{
+			Path:        ar.cfg.Batch,
+			Description: "Processes array of call requests concurrently, results are returned in the same order.",
+			Request:     b.typeSchema(reflect.TypeOf([]Request{})),
+			Response:    b.typeSchema(reflect.TypeOf(batchAnswer{})),
+		})
+	}
+	if len(ar.cfg.Events) != 0 {
+		schema.Endpoints = append(schema.Endpoints, EndpointSchema{
+			Path: ar.cfg.Events,
+			Description: "WebSocket endpoint sending events of topics \"" + strings.Join(eventTopics, "\", \"") +
+				"\". Initial topics are set by comma separated \"topics\" query parameter (all topics if it is " +
+				"missing), request describes messages changing topics, response describes events.",
+			Request:  b.typeSchema(reflect.TypeOf(Subscription{})),
+			Response: b.typeSchema(reflect.TypeOf(Event{})),
+		})
+	}
+
+	for _, s := range ar.services() {
+		if s.adminOnly && ar.adminRPCServer == nil {
+			continue
+		}
+		for _, method := range rpcMethods(s.receiver) {
+			schema.RPC.Methods = append(schema.RPC.Methods, RPCMethodSchema{
+				Name:   s.name + "." + method.Name,
+				Admin:  s.admin,
+				Params: b.typeSchema(method.Type.In(2).Elem()),
+				Result: b.typeSchema(method.Type.In(3).Elem()),
+			})
+		}
+	}
+
+	for _, m := range memberMethods {
+		method := MemberMethodSchema{
+			Name:        m.name,
+			Description: m.description,
+			Params:      []MemberParamSchema{},
+			Result:      b.typeSchema(m.result),
+		}
+		for _, p := range m.params {
+			method.Params = append(method.Params, MemberParamSchema{Name: p.name, Schema: b.typeSchema(p.typ)})
+		}
+		schema.Member.Methods = append(schema.Member.Methods, method)
+	}
+
+	schema.Definitions = b.definitions
+	return schema
+}
+
+func (ar *Runner) schemaHandler() func(http.ResponseWriter, *http.Request) {
+	return func(response http.ResponseWriter, req *http.Request) {
+		res, err := json.MarshalIndent(ar.schema(), "", "    ")
+		if err != nil {
+			inslogger.FromContext(req.Context()).Error("[ schemaHandler ] Can't marshal schema: ", err)
+			http.Error(response, "can't marshal schema", http.StatusInternalServerError)
+			return
+		}
+		response.Header().Add("Content-Type", "application/json")
+		_, err = response.Write(res)
+		if err != nil {
+			inslogger.FromContext(req.Context()).Error("[ schemaHandler ] Can't write response: ", err)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+/*
+ *    Copyright 2019 Insolar Technologies
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package api
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/insolar/insolar/configuration"
+	"github.com/stretchr/testify/require"
+)
+
+func newSchemaRunner(t *testing.T) *Runner {
+	cfg := configuration.NewAPIRunner()
+	ar, err := NewRunner(&cfg)
+	require.NoError(t, err)
+	return ar
+}
+
+func TestSchema_RPCMethods(t *testing.T) {
+	ar := newSchemaRunner(t)
+	schema := ar.schema()
+
+	names := map[string]bool{}
+	for _, method := range schema.RPC.Methods {
+		require.True(t, ar.rpcServer.HasMethod(method.Name), "method %s is not registered", method.Name)
+		names[method.Name] = true
+	}
+	require.False(t, names["admin.CancelQueuedRequest"], "admin service is described without admin listener")
+	for _, name := range []string{"seed.Get", "info.Get", "status.Get", "exporter.Export", "cert.Get", "call.Status"} {
+		require.True(t, names[name], "method %s is not described", name)
+	}
+
+	for _, s := range ar.services() {
+		require.NotEmpty(t, rpcMethods(s.receiver), "service %s has no methods", s.name)
+	}
+}
+
+// memberContractMethods returns methods dispatched by Member.call in member contract.
+func memberContractMethods(t *testing.T) []string {
+	file, err := parser.ParseFile(token.NewFileSet(), "../application/contract/member/member.go", nil, 0)
+	require.NoError(t, err)
+
+	var methods []string
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Name.Name != "call" || fn.Recv == nil {
+			continue
+		}
+		ast.Inspect(fn.Body, func(node ast.Node) bool {
+			clause, ok := node.(*ast.CaseClause)
+			if !ok {
+				return true
+			}
+			for _, expr := range clause.List {
+				lit, ok := expr.(*ast.BasicLit)
+				if !ok || lit.Kind != token.STRING {
+					continue
+				}
+				name, err := strconv.Unquote(lit.Value)
+				require.NoError(t, err)
+				methods = append(methods, name)
+			}
+			return true
+		})
+	}
+	require.NotEmpty(t, methods)
+	return methods
+}
+
+func TestSchema_MemberMethods(t *testing.T) {
+	contractMethods := memberContractMethods(t)
+	sort.Strings(contractMethods)
+
+	var described []string
+	for _, method := range newSchemaRunner(t).schema().Member.Methods {
+		described = append(described, method.Name)
+	}
+	sort.Strings(described)
+
+	require.Equal(t, contractMethods, described)
+}
+
+// collectRefs returns definitions referenced by schema.
+func collectRefs(v interface{}, refs map[string]bool) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if ref, ok := value.(string); ok && key == "$ref" {
+				refs[strings.TrimPrefix(ref, "#/definitions/")] = true
+			}
+			collectRefs(value, refs)
+		}
+	case []interface{}:
+		for _, value := range v {
+			collectRefs(value, refs)
+		}
+	}
+}
+
+func TestSchemaHandler(t *testing.T) {
+	ar := newSchemaRunner(t)
+	recorder := httptest.NewRecorder()
+	ar.schemaHandler()(recorder, httptest.NewRequest("GET", "/api/schema", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var schema map[string]interface{}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &schema))
+	require.Equal(t, SchemaVersion, schema["version"])
+
+	definitions := schema["definitions"].(map[string]interface{})
+	refs := map[string]bool{}
+	collectRefs(schema, refs)
+	for ref := range refs {
+		require.NotNil(t, definitions[ref], "definition %s is missing", ref)
+	}
+
+	request := definitions["Request"].(map[string]interface{})
+	required := request["required"].([]interface{})
+	require.Contains(t, required, "reference")
+	require.NotContains(t, required, "idempotencyKey")
+	properties := request["properties"].(map[string]interface{})
+	require.Equal(t, "base64", properties["seed"].(map[string]interface{})["contentEncoding"])
+}
@@ -0,0 +1,180 @@
+/*
+ *    Copyright 2019 Insolar Technologies
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package sdk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/insolar/insolar/core"
+	"github.com/pkg/errors"
+)
+
+type rpcRequest struct {
+	Version string      `json:"jsonrpc"`
+	ID      string      `json:"id"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+}
+
+type rpcResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+type callRequest struct {
+	Reference string `json:"reference"`
+	Method    string `json:"method"`
+	Params    []byte `json:"params"`
+	Seed      []byte `json:"seed"`
+	Signature []byte `json:"signature"`
+}
+
+type callResponse struct {
+	Error   string          `json:"error"`
+	Result  json.RawMessage `json:"result"`
+	TraceID string          `json:"traceID"`
+}
+
+type seedResponse struct {
+	Seed    []byte
+	TraceID string
+}
+
+func (sdk *SDK) post(ctx context.Context, url string, request interface{}) ([]byte, error) {
+	data, err := json.Marshal(request)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ post ] can't marshal request")
+	}
+
+	req, err := http.NewRequest("POST", url, bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.Wrap(err, "[ post ] can't create request")
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := sdk.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ post ] can't send request")
+	}
+	defer resp.Body.Close() //nolint: errcheck
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ post ] can't read response")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, &HTTPError{URL: url, StatusCode: resp.StatusCode, Body: string(body)}
+	}
+	return body, nil
+}
+
+// rpc calls method of RPC service on node with url and unmarshals its result.
+func (sdk *SDK) rpc(ctx context.Context, url string, method string, params interface{}, result interface{}) error {
+	body, err := sdk.post(ctx, url+"/rpc", rpcRequest{Version: "2.0", Method: method, Params: params})
+	if err != nil {
+		return errors.Wrapf(err, "[ rpc ] %s", method)
+	}
+
+	resp := rpcResponse{}
+	err = json.Unmarshal(body, &resp)
+	if err != nil {
+		return errors.Wrapf(err, "[ rpc ] %s: can't unmarshal response", method)
+	}
+	if resp.Error != nil {
+		return &RPCError{Method: method, Code: resp.Error.Code, Message: resp.Error.Message}
+	}
+
+	err = json.Unmarshal(resp.Result, result)
+	if err != nil {
+		return errors.Wrapf(err, "[ rpc ] %s: can't unmarshal result", method)
+	}
+	return nil
+}
+
+// call makes signed call of member method. Call is retried with fresh seed if API rejects seed. Response is
+// returned with error if API answered, so trace id of failed call is available.
+func (sdk *SDK) call(ctx context.Context, member *Member, method string, params ...interface{}) (*callResponse, error) {
+	signer, err := member.signer()
+	if err != nil {
+		return nil, errors.Wrap(err, "[ call ]")
+	}
+	reference, err := core.NewRefFromBase58(member.Reference)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ call ] can't parse member reference")
+	}
+	serialized, err := core.MarshalArgs(params...)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ call ] can't serialize params")
+	}
+
+	var resp *callResponse
+	for attempt := 0; ; attempt++ {
+		url := sdk.apiURLs.next()
+		resp, err = sdk.callOnce(ctx, url, signer, *reference, method, serialized)
+		if err == nil || !IsIncorrectSeed(err) || attempt >= sdk.seedRetries {
+			return resp, err
+		}
+	}
+}
+
+func (sdk *SDK) callOnce(
+	ctx context.Context, url string, signer Signer, reference core.RecordRef, method string, params []byte,
+) (*callResponse, error) {
+	seed := seedResponse{}
+	err := sdk.rpc(ctx, url, "seed.Get", nil, &seed)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ call ] can't get seed")
+	}
+
+	data, err := core.MarshalArgs(reference, method, params, seed.Seed)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ call ] can't serialize request")
+	}
+	signature, err := signer.Sign(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ call ] can't sign request")
+	}
+
+	body, err := sdk.post(ctx, url+"/call", callRequest{
+		Reference: reference.String(),
+		Method:    method,
+		Params:    params,
+		Seed:      seed.Seed,
+		Signature: signature,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "[ call ]")
+	}
+
+	resp := &callResponse{}
+	err = json.Unmarshal(body, resp)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ call ] can't unmarshal response")
+	}
+	if resp.Error != "" {
+		return resp, &CallError{Method: method, Kind: errorKind(resp.Error), Message: resp.Error, TraceID: resp.TraceID}
+	}
+	return resp, nil
+}
@@ -0,0 +1,117 @@
+/*
+ *    Copyright 2019 Insolar Technologies
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package sdk
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ErrorKind classifies errors returned by API.
+type ErrorKind int
+
+const (
+	// KindUnknown is an error not recognized by SDK.
+	KindUnknown ErrorKind = iota
+	// KindIncorrectSeed is returned if seed is expired, already used or issued by unknown node.
+	KindIncorrectSeed
+	// KindIncorrectSignature is returned if request is not signed by member keys.
+	KindIncorrectSignature
+	// KindRateLimited is returned if member or client exceeds rate limit.
+	KindRateLimited
+	// KindTimeout is returned if call is not finished in time on API side.
+	KindTimeout
+	// KindContract is an error returned by called contract.
+	KindContract
+)
+
+// kindMarkers are substrings of API error messages that identify error kind.
+var kindMarkers = []struct {
+	marker string
+	kind   ErrorKind
+}{
+	{"Incorrect seed", KindIncorrectSeed},
+	{"Incorrect signature", KindIncorrectSignature},
+	{"Not enough signatures", KindIncorrectSignature},
+	{"Rate limit exceeded", KindRateLimited},
+	{"timeout exceeded", KindTimeout},
+	{"Error in called method", KindContract},
+}
+
+func errorKind(message string) ErrorKind {
+	for _, m := range kindMarkers {
+		if strings.Contains(message, m.marker) {
+			return m.kind
+		}
+	}
+	return KindUnknown
+}
+
+// CallError is an error returned by call endpoint.
+type CallError struct {
+	Method  string
+	Kind    ErrorKind
+	Message string
+	TraceID string
+}
+
+func (e *CallError) Error() string {
+	return fmt.Sprintf("call of %s failed (trace %s): %s", e.Method, e.TraceID, e.Message)
+}
+
+// RPCError is an error returned by RPC service.
+type RPCError struct {
+	Method  string
+	Code    int
+	Message string
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("rpc %s failed with code %d: %s", e.Method, e.Code, e.Message)
+}
+
+// HTTPError is returned if API responds with unexpected HTTP status.
+type HTTPError struct {
+	URL        string
+	StatusCode int
+	Body       string
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("request to %s failed with status %d: %s", e.URL, e.StatusCode, e.Body)
+}
+
+// Kind returns kind of error returned by SDK, KindRateLimited is returned for HTTP 429 responses.
+func Kind(err error) ErrorKind {
+	switch e := errors.Cause(err).(type) {
+	case *CallError:
+		return e.Kind
+	case *HTTPError:
+		if e.StatusCode == http.StatusTooManyRequests {
+			return KindRateLimited
+		}
+	}
+	return KindUnknown
+}
+
+// IsIncorrectSeed returns true if request is rejected because of its seed.
+func IsIncorrectSeed(err error) bool {
+	return Kind(err) == KindIncorrectSeed
+}
@@ -0,0 +1,234 @@
+/*
+ *    Copyright 2019 Insolar Technologies
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package sdk
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/platformpolicy"
+	"github.com/pkg/errors"
+)
+
+// MockCall is a call of member method received by MockServer.
+type MockCall struct {
+	Member string
+	Method string
+	Params []byte
+}
+
+// UnmarshalParams unmarshals params of call.
+func (c MockCall) UnmarshalParams(to ...interface{}) error {
+	return core.Deserialize(c.Params, to)
+}
+
+// MockCallFunc handles call of member method, its result is returned by call endpoint.
+type MockCallFunc func(call MockCall) (interface{}, error)
+
+// MockServer is an HTTP server serving call endpoint and RPC services like API runner does, so SDK can be used in
+// tests without network. Calls are dispatched to handlers by method names.
+type MockServer struct {
+	server *httptest.Server
+
+	lock        sync.Mutex
+	info        InfoResponse
+	status      StatusResponse
+	handlers    map[string]MockCallFunc
+	keys        map[string]string
+	seeds       map[string]bool
+	rejectSeeds int
+	calls       []MockCall
+}
+
+// NewMockServer starts mock server, it must be closed after use.
+func NewMockServer() *MockServer {
+	s := &MockServer{
+		handlers: map[string]MockCallFunc{},
+		keys:     map[string]string{},
+		seeds:    map[string]bool{},
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/call", s.handleCall)
+	mux.HandleFunc("/api/rpc", s.handleRPC)
+	s.server = httptest.NewServer(mux)
+	return s
+}
+
+// URL returns url of API to pass to SDK.
+func (s *MockServer) URL() string {
+	return s.server.URL + "/api"
+}
+
+// Close stops server.
+func (s *MockServer) Close() {
+	s.server.Close()
+}
+
+// Handle sets handler for calls of member method.
+func (s *MockServer) Handle(method string, handler MockCallFunc) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.handlers[method] = handler
+}
+
+// SetMemberKey makes server verify signatures of calls made by member with public key.
+// Signatures of members without keys are not verified.
+func (s *MockServer) SetMemberKey(member string, publicKey string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.keys[member] = publicKey
+}
+
+// SetInfo sets result of info.Get.
+func (s *MockServer) SetInfo(info InfoResponse) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.info = info
+}
+
+// SetStatus sets result of status.Get.
+func (s *MockServer) SetStatus(status StatusResponse) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.status = status
+}
+
+// RejectSeeds makes server reject seeds of next n calls.
+func (s *MockServer) RejectSeeds(n int) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.rejectSeeds = n
+}
+
+// Calls returns calls handled by server.
+func (s *MockServer) Calls() []MockCall {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return append([]MockCall{}, s.calls...)
+}
+
+func writeJSON(response http.ResponseWriter, v interface{}) {
+	response.Header().Add("Content-Type", "application/json")
+	json.NewEncoder(response).Encode(v) //nolint: errcheck
+}
+
+func (s *MockServer) handleRPC(response http.ResponseWriter, req *http.Request) {
+	request := rpcRequest{}
+	if err := json.NewDecoder(req.Body).Decode(&request); err != nil {
+		http.Error(response, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var result interface{}
+	s.lock.Lock()
+	switch request.Method {
+	case "seed.Get":
+		seed := make([]byte, 32)
+		_, _ = rand.Read(seed)
+		s.seeds[string(seed)] = true
+		result = seedResponse{Seed: seed}
+	case "info.Get":
+		result = s.info
+	case "status.Get":
+		result = s.status
+	case "exporter.Export":
+		result = ExportResponse{Data: map[string]interface{}{}}
+	}
+	s.lock.Unlock()
+
+	resp := map[string]interface{}{"jsonrpc": "2.0", "id": request.ID}
+	if result == nil {
+		resp["error"] = map[string]interface{}{"code": -32601, "message": "rpc: can't find method " + request.Method}
+	} else {
+		resp["result"] = result
+	}
+	writeJSON(response, resp)
+}
+
+func (s *MockServer) handleCall(response http.ResponseWriter, req *http.Request) {
+	request := callRequest{}
+	if err := json.NewDecoder(req.Body).Decode(&request); err != nil {
+		http.Error(response, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	result, err := s.call(request)
+	resp := callResponse{TraceID: "mock"}
+	if err != nil {
+		resp.Error = err.Error()
+	} else {
+		resp.Result, err = json.Marshal(result)
+		if err != nil {
+			resp.Error = err.Error()
+		}
+	}
+	writeJSON(response, resp)
+}
+
+func (s *MockServer) call(request callRequest) (interface{}, error) {
+	s.lock.Lock()
+	if !s.seeds[string(request.Seed)] || s.rejectSeeds > 0 {
+		if s.rejectSeeds > 0 {
+			s.rejectSeeds--
+		}
+		s.lock.Unlock()
+		return nil, errors.New("[ checkSeed ] Incorrect seed")
+	}
+	delete(s.seeds, string(request.Seed))
+	key, hasKey := s.keys[request.Reference]
+	handler, hasHandler := s.handlers[request.Method]
+	call := MockCall{Member: request.Reference, Method: request.Method, Params: request.Params}
+	s.calls = append(s.calls, call)
+	s.lock.Unlock()
+
+	if hasKey {
+		if err := verifyMockSignature(request, key); err != nil {
+			return nil, errors.Wrap(err, "[ VerifySignature ]")
+		}
+	}
+	if !hasHandler {
+		return nil, errors.New("[ makeCall ] Error in called method: Unknown method")
+	}
+	result, err := handler(call)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ makeCall ] Error in called method")
+	}
+	return result, nil
+}
+
+func verifyMockSignature(request callRequest, publicKeyPEM string) error {
+	publicKey, err := platformpolicy.NewKeyProcessor().ImportPublicKeyPEM([]byte(publicKeyPEM))
+	if err != nil {
+		return errors.Wrap(err, "Invalid public key")
+	}
+	reference, err := core.NewRefFromBase58(request.Reference)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse reference")
+	}
+	data, err := core.MarshalArgs(*reference, request.Method, request.Params, request.Seed)
+	if err != nil {
+		return errors.Wrap(err, "Can't marshal arguments")
+	}
+	if !scheme.Verifier(publicKey).Verify(core.SignatureFromBytes(request.Signature), data) {
+		return errors.New("Incorrect signature")
+	}
+	return nil
+}
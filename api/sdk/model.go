@@ -16,10 +16,20 @@
 
 package sdk
 
+import (
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
 // Member model object
 type Member struct {
 	Reference  string
 	PrivateKey string
+	// Signer signs requests of member, signer with PrivateKey is used if it is nil.
+	Signer Signer `json:"-"`
+
+	lock sync.Mutex
 }
 
 // NewMember creates new Member
@@ -29,3 +39,85 @@ func NewMember(ref string, key string) *Member {
 		PrivateKey: key,
 	}
 }
+
+// NewMemberWithSigner creates new Member which requests are signed by signer
+func NewMemberWithSigner(ref string, signer Signer) *Member {
+	return &Member{
+		Reference: ref,
+		Signer:    signer,
+	}
+}
+
+func (m *Member) signer() (Signer, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if m.Signer == nil {
+		signer, err := NewKeySigner(m.PrivateKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "[ signer ] can't create signer of member")
+		}
+		m.Signer = signer
+	}
+	return m.Signer, nil
+}
+
+// UserInfo is info about member returned by DumpUserInfo and DumpAllUsers
+type UserInfo struct {
+	Member string `json:"member"`
+	Wallet uint   `json:"wallet"`
+}
+
+// MemberInfo is an entry of members list returned by ListMembers
+type MemberInfo struct {
+	Reference string `json:"reference"`
+	Name      string `json:"name"`
+}
+
+// AssetInfo is info about asset returned by GetAsset, amounts are decimal strings
+type AssetInfo struct {
+	Reference string `json:"reference"`
+	Name      string `json:"name"`
+	Decimals  uint   `json:"decimals"`
+	Supply    string `json:"supply"`
+	Issuer    string `json:"issuer"`
+}
+
+// TransferRecord is an entry of transfer log of member returned by GetTransferLog
+type TransferRecord struct {
+	Kind         string `json:"kind"`
+	Asset        string `json:"asset"`
+	Amount       string `json:"amount"`
+	Counterparty string `json:"counterparty"`
+	Pulse        uint32 `json:"pulse"`
+}
+
+// InfoResponse is info about genesis objects returned by info.Get
+type InfoResponse struct {
+	RootDomain string
+	RootMember string
+	NodeDomain string
+	TraceID    string
+}
+
+// Node is a node of network returned by status.Get
+type Node struct {
+	Reference string
+	Role      string
+}
+
+// StatusResponse is status of node returned by status.Get
+type StatusResponse struct {
+	NetworkState   string
+	Origin         Node
+	ActiveListSize int
+	ActiveList     []Node
+	PulseNumber    uint32
+	Entropy        []byte
+}
+
+// ExportResponse is a part of storage data returned by exporter.Export
+type ExportResponse struct {
+	Data     map[string]interface{}
+	NextFrom *uint32
+	Size     int
+}
@@ -20,20 +20,22 @@ import (
 	"context"
 	"encoding/json"
 	"io/ioutil"
+	"net/http"
 	"sync"
+	"time"
 
-	"github.com/insolar/insolar/api/requester"
-	"github.com/insolar/insolar/instrumentation/inslogger"
+	"github.com/insolar/insolar/core"
 	"github.com/insolar/insolar/platformpolicy"
 	"github.com/insolar/insolar/testutils"
 	"github.com/pkg/errors"
 )
 
-type response struct {
-	Error   string
-	Result  interface{}
-	TraceID string
-}
+const (
+	// DefaultSeedRetries is a number of times call is retried with fresh seed if API rejects seed.
+	DefaultSeedRetries = 3
+	// DefaultTimeout is a timeout of HTTP requests made by default client.
+	DefaultTimeout = 15 * time.Second
+)
 
 type ringBuffer struct {
 	sync.Mutex
@@ -58,14 +60,54 @@ type memberKeys struct {
 
 // SDK is used to send messages to API
 type SDK struct {
-	apiURLs    *ringBuffer
-	rootMember *requester.UserConfigJSON
+	apiURLs     *ringBuffer
+	adminURL    string
+	rootMember  *Member
+	client      *http.Client
+	seedRetries int
+}
+
+// Option configures SDK
+type Option func(*SDK)
+
+// WithHTTPClient sets client used for requests to API, e.g. with TLS client certificate.
+func WithHTTPClient(client *http.Client) Option {
+	return func(sdk *SDK) {
+		sdk.client = client
+	}
+}
+
+// WithAdminURL sets url of admin API listener used for status and exporter services.
+// API urls are used for them if it is not set.
+func WithAdminURL(url string) Option {
+	return func(sdk *SDK) {
+		sdk.adminURL = url
+	}
 }
 
-// NewSDK creates insSDK object
-func NewSDK(urls []string, rootMemberKeysPath string) (*SDK, error) {
-	buffer := &ringBuffer{urls: urls}
+// WithSeedRetries sets number of times call is retried with fresh seed if API rejects seed.
+func WithSeedRetries(retries int) Option {
+	return func(sdk *SDK) {
+		sdk.seedRetries = retries
+	}
+}
 
+// New creates insSDK object with given root member, root member is needed only for root member methods.
+func New(urls []string, rootMember *Member, options ...Option) *SDK {
+	sdk := &SDK{
+		apiURLs:     &ringBuffer{urls: urls},
+		rootMember:  rootMember,
+		client:      &http.Client{Timeout: DefaultTimeout},
+		seedRetries: DefaultSeedRetries,
+	}
+	for _, option := range options {
+		option(sdk)
+	}
+	return sdk
+}
+
+// NewSDK creates insSDK object with root member which keys are read from file
+func NewSDK(urls []string, rootMemberKeysPath string, options ...Option) (*SDK, error) {
 	rawConf, err := ioutil.ReadFile(rootMemberKeysPath)
 	if err != nil {
 		return nil, errors.Wrap(err, "[ NewSDK ] can't read keys from file")
@@ -77,50 +119,77 @@ func NewSDK(urls []string, rootMemberKeysPath string) (*SDK, error) {
 		return nil, errors.Wrap(err, "[ NewSDK ] can't unmarshal keys")
 	}
 
-	response, err := requester.Info(buffer.next())
+	sdk := New(urls, nil, options...)
+	info, err := sdk.Info(context.Background())
 	if err != nil {
 		return nil, errors.Wrap(err, "[ NewSDK ] can't get info")
 	}
 
-	rootMember, err := requester.CreateUserConfig(response.RootMember, keys.Private)
-	if err != nil {
+	rootMember := NewMember(info.RootMember, keys.Private)
+	if _, err := rootMember.signer(); err != nil {
 		return nil, errors.Wrap(err, "[ NewSDK ] can't create user config")
 	}
+	sdk.rootMember = rootMember
+	return sdk, nil
+}
 
-	return &SDK{
-		apiURLs:    buffer,
-		rootMember: rootMember,
-	}, nil
-
+func (sdk *SDK) getRootMember() (*Member, error) {
+	if sdk.rootMember == nil {
+		return nil, errors.New("root member is not set")
+	}
+	return sdk.rootMember, nil
 }
 
-func (sdk *SDK) sendRequest(ctx context.Context, method string, params []interface{}, userCfg *requester.UserConfigJSON) ([]byte, error) {
-	reqCfg := &requester.RequestConfigJSON{
-		Params: params,
-		Method: method,
+func (sdk *SDK) adminAPIURL() string {
+	if sdk.adminURL != "" {
+		return sdk.adminURL
 	}
+	return sdk.apiURLs.next()
+}
 
-	body, err := requester.Send(ctx, sdk.apiURLs.next(), userCfg, reqCfg)
+// Info returns references to genesis objects.
+func (sdk *SDK) Info(ctx context.Context) (*InfoResponse, error) {
+	info := &InfoResponse{}
+	err := sdk.rpc(ctx, sdk.apiURLs.next(), "info.Get", nil, info)
 	if err != nil {
-		return nil, errors.Wrap(err, "[ sendRequest ] can not send request")
+		return nil, errors.Wrap(err, "[ Info ]")
 	}
+	return info, nil
+}
 
-	return body, nil
+// Status returns status of node and network.
+func (sdk *SDK) Status(ctx context.Context) (*StatusResponse, error) {
+	status := &StatusResponse{}
+	err := sdk.rpc(ctx, sdk.adminAPIURL(), "status.Get", nil, status)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ Status ]")
+	}
+	return status, nil
 }
 
-func (sdk *SDK) getResponse(body []byte) (*response, error) {
-	res := &response{}
-	err := json.Unmarshal(body, &res)
+// GetSeed returns new seed for signing call.
+func (sdk *SDK) GetSeed(ctx context.Context) ([]byte, error) {
+	seed := seedResponse{}
+	err := sdk.rpc(ctx, sdk.apiURLs.next(), "seed.Get", nil, &seed)
 	if err != nil {
-		return nil, errors.Wrap(err, "[ getResponse ] problems with unmarshal response")
+		return nil, errors.Wrap(err, "[ GetSeed ]")
 	}
+	return seed.Seed, nil
+}
 
-	return res, nil
+// Export returns storage data of size pulses starting from pulse from.
+func (sdk *SDK) Export(ctx context.Context, from uint32, size int) (*ExportResponse, error) {
+	result := &ExportResponse{}
+	params := map[string]interface{}{"From": from, "Size": size}
+	err := sdk.rpc(ctx, sdk.adminAPIURL(), "exporter.Export", params, result)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ Export ]")
+	}
+	return result, nil
 }
 
 // CreateMember api request creates member with new random keys
-func (sdk *SDK) CreateMember() (*Member, string, error) {
-	ctx := inslogger.ContextWithTrace(context.Background(), "CreateMember")
+func (sdk *SDK) CreateMember(ctx context.Context) (*Member, string, error) {
 	memberName := testutils.RandomString()
 	ks := platformpolicy.NewKeyProcessor()
 
@@ -139,73 +208,338 @@ func (sdk *SDK) CreateMember() (*Member, string, error) {
 		return nil, "", errors.Wrap(err, "[ CreateMember ] can't extract public key")
 	}
 
-	params := []interface{}{memberName, string(memberPubKeyStr)}
-	body, err := sdk.sendRequest(ctx, "CreateMember", params, sdk.rootMember)
+	ref, traceID, err := sdk.CreateMemberWithKey(ctx, memberName, string(memberPubKeyStr))
 	if err != nil {
-		return nil, "", errors.Wrap(err, "[ CreateMember ] can't send request")
+		return nil, traceID, errors.Wrap(err, "[ CreateMember ]")
 	}
+	return NewMember(ref, string(privateKeyStr)), traceID, nil
+}
 
-	response, err := sdk.getResponse(body)
+// CreateMemberWithKey api request creates member with given name and public key, so private key can be kept by
+// external signer. Reference to new member is returned.
+func (sdk *SDK) CreateMemberWithKey(ctx context.Context, name string, publicKey string) (string, string, error) {
+	rootMember, err := sdk.getRootMember()
 	if err != nil {
-		return nil, "", errors.Wrap(err, "[ CreateMember ] can't get response")
+		return "", "", errors.Wrap(err, "[ CreateMemberWithKey ]")
 	}
 
-	if response.Error != "" {
-		return nil, response.TraceID, errors.New(response.Error)
+	var ref string
+	traceID, err := sdk.callMember(ctx, rootMember, &ref, "CreateMember", name, publicKey)
+	if err != nil {
+		return "", traceID, errors.Wrap(err, "[ CreateMemberWithKey ]")
 	}
-
-	return NewMember(response.Result.(string), string(privateKeyStr)), response.TraceID, nil
+	return ref, traceID, nil
 }
 
 // Transfer method send money from one member to another
-func (sdk *SDK) Transfer(amount uint, from *Member, to *Member) (string, error) {
-	ctx := inslogger.ContextWithTrace(context.Background(), "Transfer")
-	params := []interface{}{amount, to.Reference}
-	config, err := requester.CreateUserConfig(from.Reference, from.PrivateKey)
+func (sdk *SDK) Transfer(ctx context.Context, amount uint, from *Member, to *Member) (string, error) {
+	traceID, err := sdk.callMember(ctx, from, nil, "Transfer", amount, to.Reference)
+	if err != nil {
+		return traceID, errors.Wrap(err, "[ Transfer ]")
+	}
+	return traceID, nil
+}
+
+// GetBalance returns current balance of the given member.
+func (sdk *SDK) GetBalance(ctx context.Context, m *Member) (uint64, error) {
+	var balance uint64
+	_, err := sdk.callMember(ctx, m, &balance, "GetBalance", m.Reference)
+	if err != nil {
+		return 0, errors.Wrap(err, "[ GetBalance ]")
+	}
+	return balance, nil
+}
+
+// DumpUserInfo returns info about member with reference, caller can dump only itself unless it is root member.
+func (sdk *SDK) DumpUserInfo(ctx context.Context, caller *Member, reference string) (*UserInfo, error) {
+	var data []byte
+	_, err := sdk.callMember(ctx, caller, &data, "DumpUserInfo", reference)
 	if err != nil {
-		return "", errors.Wrap(err, "[ Transfer ] can't create user config")
+		return nil, errors.Wrap(err, "[ DumpUserInfo ]")
 	}
 
-	body, err := sdk.sendRequest(ctx, "Transfer", params, config)
+	info := &UserInfo{}
+	err = json.Unmarshal(data, info)
 	if err != nil {
-		return "", errors.Wrap(err, "[ Transfer ] can't send request")
+		return nil, errors.Wrap(err, "[ DumpUserInfo ] can't unmarshal user info")
 	}
+	return info, nil
+}
 
-	response, err := sdk.getResponse(body)
+// DumpAllUsers returns info about all members, it is called by root member.
+func (sdk *SDK) DumpAllUsers(ctx context.Context) ([]UserInfo, error) {
+	rootMember, err := sdk.getRootMember()
 	if err != nil {
-		return "", errors.Wrap(err, "[ Transfer ] can't get response")
+		return nil, errors.Wrap(err, "[ DumpAllUsers ]")
 	}
 
-	if response.Error != "" {
-		return response.TraceID, errors.New(response.Error)
+	var data []byte
+	_, err = sdk.callMember(ctx, rootMember, &data, "DumpAllUsers")
+	if err != nil {
+		return nil, errors.Wrap(err, "[ DumpAllUsers ]")
 	}
 
-	return response.TraceID, nil
+	var users []UserInfo
+	err = json.Unmarshal(data, &users)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ DumpAllUsers ] can't unmarshal users")
+	}
+	return users, nil
 }
 
-// GetBalance returns current balance of the given member.
-func (sdk *SDK) GetBalance(m *Member) (uint64, error) {
-	ctx := inslogger.ContextWithTrace(context.Background(), "GetBalance")
-	params := []interface{}{m.Reference}
-	config, err := requester.CreateUserConfig(m.Reference, m.PrivateKey)
+// RegisterNode registers node with public key and role, certificate of node is returned.
+// It is called by root member.
+func (sdk *SDK) RegisterNode(ctx context.Context, publicKey string, role string) (string, error) {
+	rootMember, err := sdk.getRootMember()
+	if err != nil {
+		return "", errors.Wrap(err, "[ RegisterNode ]")
+	}
+
+	var cert string
+	_, err = sdk.callMember(ctx, rootMember, &cert, "RegisterNode", publicKey, role)
 	if err != nil {
-		return 0, errors.Wrap(err, "[ GetBalance ] can't create user config")
+		return "", errors.Wrap(err, "[ RegisterNode ]")
 	}
+	return cert, nil
+}
 
-	body, err := sdk.sendRequest(ctx, "GetBalance", params, config)
+// GetNodeRef returns reference to node with public key.
+func (sdk *SDK) GetNodeRef(ctx context.Context, publicKey string) (string, error) {
+	rootMember, err := sdk.getRootMember()
 	if err != nil {
-		return 0, errors.Wrap(err, "[ GetBalance ] can't send request")
+		return "", errors.Wrap(err, "[ GetNodeRef ]")
 	}
 
-	response, err := sdk.getResponse(body)
+	var ref string
+	_, err = sdk.callMember(ctx, rootMember, &ref, "GetNodeRef", publicKey)
 	if err != nil {
-		return 0, errors.Wrap(err, "[ GetBalance ] can't get response")
+		return "", errors.Wrap(err, "[ GetNodeRef ]")
 	}
+	return ref, nil
+}
 
-	if response.Error != "" {
-		return 0, errors.New(response.Error)
+// keyProof signs reference of member and public key with signer of the key, member accepts the key only with it.
+func keyProof(m *Member, publicKey string, keySigner Signer) ([]byte, error) {
+	reference, err := core.NewRefFromBase58(m.Reference)
+	if err != nil {
+		return nil, errors.Wrap(err, "can't parse member reference")
+	}
+	data, err := core.MarshalArgs(*reference, publicKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "can't marshal key proof")
+	}
+	proof, err := keySigner.Sign(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "can't sign key proof")
 	}
+	return proof, nil
+}
+
+// AddKey adds public key allowed to sign calls of member, keySigner signs with the key to prove caller owns it.
+func (sdk *SDK) AddKey(ctx context.Context, m *Member, publicKey string, keySigner Signer) (string, error) {
+	proof, err := keyProof(m, publicKey, keySigner)
+	if err != nil {
+		return "", errors.Wrap(err, "[ AddKey ]")
+	}
+	traceID, err := sdk.callMember(ctx, m, nil, "AddKey", publicKey, proof)
+	if err != nil {
+		return traceID, errors.Wrap(err, "[ AddKey ]")
+	}
+	return traceID, nil
+}
+
+// RevokeKey revokes public key of member.
+func (sdk *SDK) RevokeKey(ctx context.Context, m *Member, publicKey string) (string, error) {
+	traceID, err := sdk.callMember(ctx, m, nil, "RevokeKey", publicKey)
+	if err != nil {
+		return traceID, errors.Wrap(err, "[ RevokeKey ]")
+	}
+	return traceID, nil
+}
+
+// RotateKey replaces public key of member with new one, newSigner signs with the new key to prove caller owns it.
+// Signer of m must be replaced by caller after that.
+func (sdk *SDK) RotateKey(
+	ctx context.Context, m *Member, oldPublicKey string, newPublicKey string, newSigner Signer,
+) (string, error) {
+	proof, err := keyProof(m, newPublicKey, newSigner)
+	if err != nil {
+		return "", errors.Wrap(err, "[ RotateKey ]")
+	}
+	traceID, err := sdk.callMember(ctx, m, nil, "RotateKey", oldPublicKey, newPublicKey, proof)
+	if err != nil {
+		return traceID, errors.Wrap(err, "[ RotateKey ]")
+	}
+	return traceID, nil
+}
 
-	// TODO FIXME don't transfer money in floats!
-	return uint64(response.Result.(float64)), nil
+// SetThreshold sets number of member keys which signatures are required to make a call.
+func (sdk *SDK) SetThreshold(ctx context.Context, m *Member, threshold uint) (string, error) {
+	traceID, err := sdk.callMember(ctx, m, nil, "SetThreshold", threshold)
+	if err != nil {
+		return traceID, errors.Wrap(err, "[ SetThreshold ]")
+	}
+	return traceID, nil
+}
+
+// CreateAsset creates asset with unique name and decimal supply issued to member, reference to asset is returned.
+func (sdk *SDK) CreateAsset(ctx context.Context, m *Member, name string, decimals uint, supply string) (string, error) {
+	var ref string
+	_, err := sdk.callMember(ctx, m, &ref, "CreateAsset", name, decimals, supply)
+	if err != nil {
+		return "", errors.Wrap(err, "[ CreateAsset ]")
+	}
+	return ref, nil
+}
+
+// GetAsset returns info about asset with name.
+func (sdk *SDK) GetAsset(ctx context.Context, caller *Member, name string) (*AssetInfo, error) {
+	var data []byte
+	_, err := sdk.callMember(ctx, caller, &data, "GetAsset", name)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ GetAsset ]")
+	}
+
+	info := &AssetInfo{}
+	err = json.Unmarshal(data, info)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ GetAsset ] can't unmarshal asset info")
+	}
+	return info, nil
+}
+
+// GetAssetBalance returns balance of asset of member with reference as a decimal string.
+func (sdk *SDK) GetAssetBalance(ctx context.Context, caller *Member, asset string, reference string) (string, error) {
+	var balance string
+	_, err := sdk.callMember(ctx, caller, &balance, "GetAssetBalance", asset, reference)
+	if err != nil {
+		return "", errors.Wrap(err, "[ GetAssetBalance ]")
+	}
+	return balance, nil
+}
+
+// GetAssetBalances returns balances of all assets of member with reference by names of assets.
+func (sdk *SDK) GetAssetBalances(ctx context.Context, caller *Member, reference string) (map[string]string, error) {
+	var data []byte
+	_, err := sdk.callMember(ctx, caller, &data, "GetAssetBalances", reference)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ GetAssetBalances ]")
+	}
+
+	balances := map[string]string{}
+	err = json.Unmarshal(data, &balances)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ GetAssetBalances ] can't unmarshal balances")
+	}
+	return balances, nil
+}
+
+// TransferAsset transfers decimal amount of asset from member to member with reference.
+func (sdk *SDK) TransferAsset(ctx context.Context, from *Member, asset string, amount string, to string) (string, error) {
+	traceID, err := sdk.callMember(ctx, from, nil, "TransferAsset", asset, amount, to)
+	if err != nil {
+		return traceID, errors.Wrap(err, "[ TransferAsset ]")
+	}
+	return traceID, nil
+}
+
+// Approve allows spender to transfer up to decimal amount of asset of member, zero amount revokes approval.
+func (sdk *SDK) Approve(ctx context.Context, m *Member, asset string, spender string, amount string) (string, error) {
+	traceID, err := sdk.callMember(ctx, m, nil, "Approve", asset, spender, amount)
+	if err != nil {
+		return traceID, errors.Wrap(err, "[ Approve ]")
+	}
+	return traceID, nil
+}
+
+// GetAllowance returns amount of asset of owner spender is allowed to transfer as a decimal string.
+func (sdk *SDK) GetAllowance(ctx context.Context, caller *Member, asset string, owner string, spender string) (string, error) {
+	var amount string
+	_, err := sdk.callMember(ctx, caller, &amount, "GetAllowance", asset, owner, spender)
+	if err != nil {
+		return "", errors.Wrap(err, "[ GetAllowance ]")
+	}
+	return amount, nil
+}
+
+// TransferFrom transfers decimal amount of asset approved to member by owner to member with reference.
+func (sdk *SDK) TransferFrom(ctx context.Context, m *Member, asset string, owner string, to string, amount string) (string, error) {
+	traceID, err := sdk.callMember(ctx, m, nil, "TransferFrom", asset, owner, to, amount)
+	if err != nil {
+		return traceID, errors.Wrap(err, "[ TransferFrom ]")
+	}
+	return traceID, nil
+}
+
+// GetTransferLog returns up to limit transfer records of member with reference starting from offset.
+func (sdk *SDK) GetTransferLog(ctx context.Context, caller *Member, reference string, offset uint, limit uint) ([]TransferRecord, error) {
+	var data []byte
+	_, err := sdk.callMember(ctx, caller, &data, "GetTransferLog", reference, offset, limit)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ GetTransferLog ]")
+	}
+
+	var records []TransferRecord
+	err = json.Unmarshal(data, &records)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ GetTransferLog ] can't unmarshal transfer records")
+	}
+	return records, nil
+}
+
+// GetMemberByName returns references to members with name in order of creation, names aren't unique.
+func (sdk *SDK) GetMemberByName(ctx context.Context, caller *Member, name string) ([]string, error) {
+	var refs []string
+	_, err := sdk.callMember(ctx, caller, &refs, "GetMemberByName", name)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ GetMemberByName ]")
+	}
+	return refs, nil
+}
+
+// GetMemberByPublicKey returns references to members with public key among their keys.
+func (sdk *SDK) GetMemberByPublicKey(ctx context.Context, caller *Member, publicKey string) ([]string, error) {
+	var refs []string
+	_, err := sdk.callMember(ctx, caller, &refs, "GetMemberByPublicKey", publicKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ GetMemberByPublicKey ]")
+	}
+	return refs, nil
+}
+
+// ListMembers returns up to limit members starting from offset in order of creation.
+func (sdk *SDK) ListMembers(ctx context.Context, caller *Member, offset uint, limit uint) ([]MemberInfo, error) {
+	var data []byte
+	_, err := sdk.callMember(ctx, caller, &data, "ListMembers", offset, limit)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ ListMembers ]")
+	}
+
+	var members []MemberInfo
+	err = json.Unmarshal(data, &members)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ ListMembers ] can't unmarshal members")
+	}
+	return members, nil
+}
+
+// callMember calls method of member and unmarshals its result into result if it is not nil. Trace id of call is
+// returned.
+func (sdk *SDK) callMember(ctx context.Context, m *Member, result interface{}, method string, params ...interface{}) (string, error) {
+	resp, err := sdk.call(ctx, m, method, params...)
+	traceID := ""
+	if resp != nil {
+		traceID = resp.TraceID
+	}
+	if err != nil {
+		return traceID, err
+	}
+
+	if result != nil {
+		err = json.Unmarshal(resp.Result, result)
+		if err != nil {
+			return traceID, errors.Wrap(err, "can't unmarshal result")
+		}
+	}
+	return traceID, nil
 }
@@ -0,0 +1,247 @@
+/*
+ *    Copyright 2019 Insolar Technologies
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package sdk
+
+import (
+	"context"
+	"testing"
+
+	"github.com/insolar/insolar/platformpolicy"
+	"github.com/insolar/insolar/testutils"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestMember(t *testing.T) (*Member, string) {
+	ks := platformpolicy.NewKeyProcessor()
+	privateKey, err := ks.GeneratePrivateKey()
+	require.NoError(t, err)
+	privateKeyPEM, err := ks.ExportPrivateKeyPEM(privateKey)
+	require.NoError(t, err)
+	publicKeyPEM, err := ks.ExportPublicKeyPEM(ks.ExtractPublicKey(privateKey))
+	require.NoError(t, err)
+	return NewMember(testutils.RandomRef().String(), string(privateKeyPEM)), string(publicKeyPEM)
+}
+
+func newTestSDK(t *testing.T) (*SDK, *MockServer, *Member) {
+	server := NewMockServer()
+	root, rootKey := newTestMember(t)
+	server.SetMemberKey(root.Reference, rootKey)
+	server.SetInfo(InfoResponse{RootMember: root.Reference})
+	return New([]string{server.URL()}, root), server, root
+}
+
+func TestSDK_CreateMember(t *testing.T) {
+	sdk, server, root := newTestSDK(t)
+	defer server.Close()
+	ctx := context.Background()
+
+	ref := testutils.RandomRef().String()
+	server.Handle("CreateMember", func(call MockCall) (interface{}, error) {
+		require.Equal(t, root.Reference, call.Member)
+		var name, key string
+		require.NoError(t, call.UnmarshalParams(&name, &key))
+		require.NotEmpty(t, key)
+		return ref, nil
+	})
+
+	member, traceID, err := sdk.CreateMember(ctx)
+	require.NoError(t, err)
+	require.Equal(t, ref, member.Reference)
+	require.Equal(t, "mock", traceID)
+	require.NotEmpty(t, member.PrivateKey)
+}
+
+func TestSDK_GetBalanceAndDumpUserInfo(t *testing.T) {
+	sdk, server, _ := newTestSDK(t)
+	defer server.Close()
+	ctx := context.Background()
+	member, key := newTestMember(t)
+	server.SetMemberKey(member.Reference, key)
+
+	server.Handle("GetBalance", func(call MockCall) (interface{}, error) {
+		return uint64(1000000000001), nil
+	})
+	server.Handle("DumpUserInfo", func(call MockCall) (interface{}, error) {
+		return []byte(`{"member": "name", "wallet": 42}`), nil
+	})
+
+	balance, err := sdk.GetBalance(ctx, member)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1000000000001), balance)
+
+	info, err := sdk.DumpUserInfo(ctx, member, member.Reference)
+	require.NoError(t, err)
+	require.Equal(t, &UserInfo{Member: "name", Wallet: 42}, info)
+}
+
+func TestSDK_Assets(t *testing.T) {
+	sdk, server, _ := newTestSDK(t)
+	defer server.Close()
+	ctx := context.Background()
+	member, key := newTestMember(t)
+	server.SetMemberKey(member.Reference, key)
+
+	server.Handle("CreateAsset", func(call MockCall) (interface{}, error) {
+		var name, supply string
+		var decimals uint
+		require.NoError(t, call.UnmarshalParams(&name, &decimals, &supply))
+		require.Equal(t, "GOLD", name)
+		require.Equal(t, uint(2), decimals)
+		require.Equal(t, "10.5", supply)
+		return "asset", nil
+	})
+	server.Handle("GetAssetBalances", func(call MockCall) (interface{}, error) {
+		return []byte(`{"GOLD": "10.5"}`), nil
+	})
+	server.Handle("GetTransferLog", func(call MockCall) (interface{}, error) {
+		var reference string
+		var offset, limit uint
+		require.NoError(t, call.UnmarshalParams(&reference, &offset, &limit))
+		require.Equal(t, member.Reference, reference)
+		require.Equal(t, uint(10), limit)
+		return []byte(`[{"kind": "issue", "asset": "GOLD", "amount": "10.5", "counterparty": "asset", "pulse": 1}]`), nil
+	})
+
+	ref, err := sdk.CreateAsset(ctx, member, "GOLD", 2, "10.5")
+	require.NoError(t, err)
+	require.Equal(t, "asset", ref)
+
+	balances, err := sdk.GetAssetBalances(ctx, member, member.Reference)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"GOLD": "10.5"}, balances)
+
+	records, err := sdk.GetTransferLog(ctx, member, member.Reference, 0, 10)
+	require.NoError(t, err)
+	require.Equal(t, []TransferRecord{{Kind: "issue", Asset: "GOLD", Amount: "10.5", Counterparty: "asset", Pulse: 1}}, records)
+}
+
+func TestSDK_Members(t *testing.T) {
+	sdk, server, root := newTestSDK(t)
+	defer server.Close()
+	ctx := context.Background()
+
+	server.Handle("GetMemberByName", func(call MockCall) (interface{}, error) {
+		var name string
+		require.NoError(t, call.UnmarshalParams(&name))
+		require.Equal(t, "alice", name)
+		return []string{"alice-ref"}, nil
+	})
+	server.Handle("ListMembers", func(call MockCall) (interface{}, error) {
+		var offset, limit uint
+		require.NoError(t, call.UnmarshalParams(&offset, &limit))
+		require.Equal(t, uint(1), offset)
+		require.Equal(t, uint(2), limit)
+		return []byte(`[{"reference": "alice-ref", "name": "alice"}]`), nil
+	})
+
+	refs, err := sdk.GetMemberByName(ctx, root, "alice")
+	require.NoError(t, err)
+	require.Equal(t, []string{"alice-ref"}, refs)
+
+	members, err := sdk.ListMembers(ctx, root, 1, 2)
+	require.NoError(t, err)
+	require.Equal(t, []MemberInfo{{Reference: "alice-ref", Name: "alice"}}, members)
+}
+
+func TestSDK_RetryIncorrectSeed(t *testing.T) {
+	sdk, server, _ := newTestSDK(t)
+	defer server.Close()
+	ctx := context.Background()
+	from, _ := newTestMember(t)
+	to, _ := newTestMember(t)
+	server.Handle("Transfer", func(call MockCall) (interface{}, error) {
+		return nil, nil
+	})
+
+	server.RejectSeeds(DefaultSeedRetries)
+	_, err := sdk.Transfer(ctx, 1, from, to)
+	require.NoError(t, err)
+	require.Len(t, server.Calls(), 1)
+
+	server.RejectSeeds(DefaultSeedRetries + 1)
+	_, err = sdk.Transfer(ctx, 1, from, to)
+	require.True(t, IsIncorrectSeed(err))
+}
+
+func TestSDK_TypedErrors(t *testing.T) {
+	sdk, server, _ := newTestSDK(t)
+	defer server.Close()
+	ctx := context.Background()
+	member, _ := newTestMember(t)
+	other, otherKey := newTestMember(t)
+	server.SetMemberKey(member.Reference, otherKey)
+
+	server.Handle("Transfer", func(call MockCall) (interface{}, error) {
+		return nil, errors.New("not enough balance")
+	})
+	traceID, err := sdk.Transfer(ctx, 1, other, member)
+	require.Equal(t, "mock", traceID)
+	callErr, ok := errors.Cause(err).(*CallError)
+	require.True(t, ok)
+	require.Equal(t, KindContract, callErr.Kind)
+	require.Equal(t, "Transfer", callErr.Method)
+	require.Contains(t, callErr.Message, "not enough balance")
+
+	_, err = sdk.Transfer(ctx, 1, member, other)
+	require.Equal(t, KindIncorrectSignature, Kind(err))
+
+	_, err = sdk.Status(ctx)
+	require.NoError(t, err)
+	_, err = sdk.RegisterNode(ctx, "key", "virtual")
+	require.Equal(t, KindContract, Kind(err))
+}
+
+func TestSDK_Context(t *testing.T) {
+	sdk, server, _ := newTestSDK(t)
+	defer server.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := sdk.Info(ctx)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), context.Canceled.Error())
+}
+
+type countingSigner struct {
+	Signer
+	count int
+}
+
+func (s *countingSigner) Sign(data []byte) ([]byte, error) {
+	s.count++
+	return s.Signer.Sign(data)
+}
+
+func TestSDK_Signer(t *testing.T) {
+	sdk, server, _ := newTestSDK(t)
+	defer server.Close()
+	member, key := newTestMember(t)
+	server.SetMemberKey(member.Reference, key)
+	server.Handle("AddKey", func(call MockCall) (interface{}, error) {
+		return nil, nil
+	})
+
+	keySigner, err := NewKeySigner(member.PrivateKey)
+	require.NoError(t, err)
+	signer := &countingSigner{Signer: keySigner}
+	external := NewMemberWithSigner(member.Reference, signer)
+
+	_, err = sdk.AddKey(context.Background(), external, "new key", keySigner)
+	require.NoError(t, err)
+	require.Equal(t, 1, signer.count)
+}
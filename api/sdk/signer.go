@@ -0,0 +1,55 @@
+/*
+ *    Copyright 2019 Insolar Technologies
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package sdk
+
+import (
+	"crypto"
+
+	"github.com/insolar/insolar/platformpolicy"
+	"github.com/pkg/errors"
+)
+
+var scheme = platformpolicy.NewPlatformCryptographyScheme()
+
+// Signer signs requests of member. Implement it to keep keys outside of the process, e.g. in HSM or remote
+// signing service.
+type Signer interface {
+	Sign(data []byte) ([]byte, error)
+}
+
+// KeySigner signs requests with private key kept in memory.
+type KeySigner struct {
+	privateKey crypto.PrivateKey
+}
+
+// NewKeySigner creates signer from private key in PEM format.
+func NewKeySigner(privateKeyPEM string) (*KeySigner, error) {
+	privateKey, err := platformpolicy.NewKeyProcessor().ImportPrivateKeyPEM([]byte(privateKeyPEM))
+	if err != nil {
+		return nil, errors.Wrap(err, "[ NewKeySigner ] can't import private key")
+	}
+	return &KeySigner{privateKey: privateKey}, nil
+}
+
+// Sign signs data with private key.
+func (s *KeySigner) Sign(data []byte) ([]byte, error) {
+	signature, err := scheme.Signer(s.privateKey).Sign(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ Sign ] can't sign data")
+	}
+	return signature.Bytes(), nil
+}
@@ -26,11 +26,15 @@ import (
 )
 
 // SeedArgs is arguments that Seed service accepts.
-type SeedArgs struct{}
+type SeedArgs struct {
+	// Count is optional number of seeds to issue, seeds are returned in Seeds if it is set.
+	Count int
+}
 
 // SeedReply is reply for Seed service requests.
 type SeedReply struct {
 	Seed    []byte
+	Seeds   [][]byte
 	TraceID string
 }
 
@@ -44,12 +48,17 @@ func NewSeedService(runner *Runner) *SeedService {
 	return &SeedService{runner: runner}
 }
 
-// Get returns new active seed.
+// Get returns new active seed. Seed is signed by node and can be used in call to any node of network
+// until it expires. Several seeds for batch call are issued at once if Count is set.
 //
 //   Request structure:
 //   {
 //     "jsonrpc": "2.0",
 //     "method": "seed.Get",
+//     "params": {
+//       // Optional number of seeds, not more than batch limit
+//       "Count": int
+//     },
 //     "id": str|int|null
 //   }
 //
@@ -58,6 +67,7 @@ func NewSeedService(runner *Runner) *SeedService {
 // 		"jsonrpc": "2.0",
 // 		"result": {
 // 			"Seed": str, // correct seed for new Call request
+// 			"Seeds": [str], // Count seeds if Count is set, the first of them is in Seed too
 // 			"TraceID": str // traceID for request
 // 		},
 // 		"id": str|int|null // same as in request
@@ -65,17 +75,26 @@ func NewSeedService(runner *Runner) *SeedService {
 //
 func (s *SeedService) Get(r *http.Request, args *SeedArgs, reply *SeedReply) error {
 	traceID := utils.RandTraceID()
-	_, inslog := inslogger.WithTraceField(context.Background(), traceID)
+	ctx, inslog := inslogger.WithTraceField(context.Background(), traceID)
 
 	inslog.Infof("[ SeedService.Get ] Incoming request: %s", r.RequestURI)
 
-	seed, err := s.runner.SeedGenerator.Next()
-	if err != nil {
-		return errors.Wrap(err, "[ GetSeed ]")
+	count := args.Count
+	if count > 1 && count > s.runner.cfg.BatchLimit {
+		return errors.Errorf("[ GetSeed ] Count %d exceeds limit %d", count, s.runner.cfg.BatchLimit)
+	}
+	for i := 0; i < count || i == 0; i++ {
+		seed, err := s.runner.SeedManager.Issue(ctx)
+		if err != nil {
+			return errors.Wrap(err, "[ GetSeed ]")
+		}
+		if count > 0 {
+			reply.Seeds = append(reply.Seeds, seed.Bytes())
+		}
+		if i == 0 {
+			reply.Seed = seed.Bytes()
+		}
 	}
-	s.runner.SeedManager.Add(*seed)
-
-	reply.Seed = seed[:]
 	reply.TraceID = traceID
 
 	return nil
@@ -0,0 +1,49 @@
+/*
+ *    Copyright 2019 Insolar Technologies
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package api
+
+import (
+	"net/http"
+)
+
+func (suite *TimeoutSuite) TestSeedService_GetCount() {
+	service := NewSeedService(suite.api)
+	req, err := http.NewRequest("POST", CallUrl, nil)
+	suite.NoError(err)
+
+	var result SeedReply
+	err = service.Get(req, &SeedArgs{}, &result)
+	suite.NoError(err)
+	suite.NotEmpty(result.Seed)
+	suite.Empty(result.Seeds)
+
+	result = SeedReply{}
+	err = service.Get(req, &SeedArgs{Count: 3}, &result)
+	suite.NoError(err)
+	suite.Require().Len(result.Seeds, 3)
+	suite.Equal(result.Seeds[0], result.Seed)
+	used := map[string]bool{}
+	for _, seed := range result.Seeds {
+		_, err := suite.api.SeedManager.Verify(suite.ctx, seed)
+		suite.NoError(err)
+		used[string(seed)] = true
+	}
+	suite.Len(used, 3)
+
+	err = service.Get(req, &SeedArgs{Count: 4}, &SeedReply{})
+	suite.Contains(err.Error(), "Count 4 exceeds limit 3")
+}
@@ -17,83 +17,141 @@
 package seedmanager
 
 import (
-	"sync"
-	"time"
-)
+	"bytes"
+	"context"
 
-// Expiration represents time of expiration
-type Expiration = int64
+	"github.com/insolar/insolar/core"
+	"github.com/pkg/errors"
+)
 
-// DefaultTTL is default time period for deleting expired seeds
-const DefaultTTL = time.Duration(1 * time.Second)
+// DefaultTTL is default number of pulses seed is valid. Pulse numbers are seconds, so it is a minute.
+const DefaultTTL = core.PulseNumber(60)
 
-// DefaultCleanPeriod default time period for launching cleaning goroutine
-const DefaultCleanPeriod = time.Duration(1 * time.Second)
+const (
+	pulseOffset     = int(SeedSize)
+	expiresOffset   = pulseOffset + core.PulseNumberSize
+	nodeOffset      = expiresOffset + core.PulseNumberSize
+	signatureOffset = nodeOffset + core.RecordRefSize
+)
 
-// SeedManager manages working with seed pool
-// It's thread safe
-type SeedManager struct {
-	mutex    sync.RWMutex
-	seedPool map[Seed]Expiration
-	ttl      time.Duration
+// SignedSeed is a seed any node of network can verify. It is issued on pulse, expires on another pulse and
+// is signed by node that issued it.
+type SignedSeed struct {
+	Seed      Seed
+	Pulse     core.PulseNumber
+	Expires   core.PulseNumber
+	Node      core.RecordRef
+	Signature []byte
 }
 
-// New creates new seed manager with default params
-func New() *SeedManager {
-	return NewSpecified(DefaultTTL, DefaultCleanPeriod)
+func (s *SignedSeed) signedData() []byte {
+	var buf bytes.Buffer
+	buf.Write(s.Seed[:])
+	buf.Write(s.Pulse.Bytes())
+	buf.Write(s.Expires.Bytes())
+	buf.Write(s.Node[:])
+	return buf.Bytes()
 }
 
-// NewSpecified creates new seed manager with custom params
-func NewSpecified(TTL time.Duration, cleanPeriod time.Duration) *SeedManager {
-	sm := SeedManager{seedPool: make(map[Seed]Expiration), ttl: TTL}
-	go func() {
-		for range time.Tick(cleanPeriod) {
-			sm.deleteExpired()
-		}
-	}()
-
-	return &sm
+// Bytes serializes seed for client.
+func (s *SignedSeed) Bytes() []byte {
+	return append(s.signedData(), s.Signature...)
 }
 
-// Add adds seed to pool
-func (sm *SeedManager) Add(seed Seed) {
-	expTime := time.Now().Add(sm.ttl).UnixNano()
+// ParseSignedSeed deserializes seed received from client.
+func ParseSignedSeed(data []byte) (*SignedSeed, error) {
+	if len(data) <= signatureOffset {
+		return nil, errors.New("[ ParseSignedSeed ] seed is too short")
+	}
+	s := SignedSeed{
+		Seed:      *SeedFromBytes(data[:pulseOffset]),
+		Pulse:     core.NewPulseNumber(data[pulseOffset:expiresOffset]),
+		Expires:   core.NewPulseNumber(data[expiresOffset:nodeOffset]),
+		Signature: data[signatureOffset:],
+	}
+	copy(s.Node[:], data[nodeOffset:signatureOffset])
+	return &s, nil
+}
 
-	sm.mutex.Lock()
-	sm.seedPool[seed] = expTime
-	sm.mutex.Unlock()
+// SeedManager issues seeds signed with node key and verifies seeds issued by any active node of network,
+// so seeds are not bound to node that issued them and survive its restarts.
+//
+// SeedManager doesn't remember seeds, checking that seed is not used twice is up to caller.
+type SeedManager struct {
+	cryptography core.CryptographyService
+	nodeNetwork  core.NodeNetwork
+	pulseStorage core.PulseStorage
+	generator    SeedGenerator
+	ttl          core.PulseNumber
+}
 
+// New creates new seed manager with default params
+func New(cs core.CryptographyService, nn core.NodeNetwork, ps core.PulseStorage) *SeedManager {
+	return NewSpecified(cs, nn, ps, DefaultTTL)
 }
 
-func (sm *SeedManager) isExpired(expTime Expiration) bool {
-	return expTime < time.Now().UnixNano()
+// NewSpecified creates new seed manager with custom params
+func NewSpecified(
+	cs core.CryptographyService, nn core.NodeNetwork, ps core.PulseStorage, ttl core.PulseNumber,
+) *SeedManager {
+	return &SeedManager{
+		cryptography: cs,
+		nodeNetwork:  nn,
+		pulseStorage: ps,
+		ttl:          ttl,
+	}
 }
 
-// Exists checks whether seed in the pool
-func (sm *SeedManager) Exists(seed Seed) bool {
-	sm.mutex.RLock()
-	expTime, ok := sm.seedPool[seed]
-	sm.mutex.RUnlock()
-
-	isSeedOk := ok && !sm.isExpired(expTime)
-	if isSeedOk {
-		sm.mutex.Lock()
-		delete(sm.seedPool, seed)
-		sm.mutex.Unlock()
+// Issue returns new seed signed by current node.
+func (sm *SeedManager) Issue(ctx context.Context) (*SignedSeed, error) {
+	seed, err := sm.generator.Next()
+	if err != nil {
+		return nil, errors.Wrap(err, "[ Issue ]")
+	}
+	pulse, err := sm.pulseStorage.Current(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ Issue ] Can't get current pulse")
+	}
+	origin := sm.nodeNetwork.GetOrigin()
+	if origin == nil {
+		return nil, errors.New("[ Issue ] Node is not active")
 	}
 
-	return isSeedOk
+	s := SignedSeed{
+		Seed:    *seed,
+		Pulse:   pulse.PulseNumber,
+		Expires: pulse.PulseNumber + sm.ttl,
+		Node:    origin.ID(),
+	}
+	signature, err := sm.cryptography.Sign(s.signedData())
+	if err != nil {
+		return nil, errors.Wrap(err, "[ Issue ] Can't sign seed")
+	}
+	s.Signature = signature.Bytes()
+	return &s, nil
 }
 
-func (sm *SeedManager) deleteExpired() {
-	sm.mutex.Lock()
-	defer sm.mutex.Unlock()
-
-	for seed, expTime := range sm.seedPool {
-		if sm.isExpired(expTime) {
-			delete(sm.seedPool, seed)
-		}
+// Verify checks that seed is signed by active node and not expired.
+func (sm *SeedManager) Verify(ctx context.Context, data []byte) (*SignedSeed, error) {
+	s, err := ParseSignedSeed(data)
+	if err != nil {
+		return nil, err
+	}
+	pulse, err := sm.pulseStorage.Current(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ Verify ] Can't get current pulse")
+	}
+	if s.Pulse > pulse.PulseNumber || s.Expires < pulse.PulseNumber {
+		return nil, errors.New("[ Verify ] Seed is expired")
+	}
+	node := sm.nodeNetwork.GetActiveNode(s.Node)
+	if node == nil {
+		return nil, errors.New("[ Verify ] Seed is issued by unknown node")
+	}
+	if !sm.cryptography.Verify(node.PublicKey(), core.SignatureFromBytes(s.Signature), s.signedData()) {
+		return nil, errors.New("[ Verify ] Bad seed signature")
 	}
+	return s, nil
 }
 
 // SeedFromBytes converts slice of bytes to Seed. Returns nil if slice's size is not equal to SeedSize
@@ -17,10 +17,14 @@
 package seedmanager
 
 import (
-	"sync"
 	"testing"
-	"time"
 
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/cryptography"
+	"github.com/insolar/insolar/instrumentation/inslogger"
+	"github.com/insolar/insolar/platformpolicy"
+	"github.com/insolar/insolar/testutils"
+	"github.com/insolar/insolar/testutils/network"
 	"github.com/stretchr/testify/require"
 )
 
@@ -40,75 +44,86 @@ func TestSeedFromBytes(t *testing.T) {
 	require.Equal(t, seedBytes, res[:])
 }
 
-func TestNew(t *testing.T) {
-	sm := New()
-	require.Empty(t, sm.seedPool)
+func newSeedManager(t *testing.T, ttl core.PulseNumber) (*SeedManager, *testutils.PulseStorageMock) {
+	ks := platformpolicy.NewKeyProcessor()
+	key, err := ks.GeneratePrivateKey()
+	require.NoError(t, err)
+
+	node := network.NewNodeMock(t)
+	node.IDMock.Return(testutils.RandomRef())
+	node.PublicKeyMock.Return(ks.ExtractPublicKey(key))
+	nn := network.NewNodeNetworkMock(t)
+	nn.GetOriginMock.Return(node)
+	nn.GetActiveNodeFunc = func(ref core.RecordRef) core.Node {
+		if ref == node.ID() {
+			return node
+		}
+		return nil
+	}
+
+	ps := testutils.NewPulseStorageMock(t)
+	ps.CurrentMock.Return(&core.Pulse{PulseNumber: core.FirstPulseNumber}, nil)
+
+	return NewSpecified(cryptography.NewKeyBoundCryptographyService(key), nn, ps, ttl), ps
 }
 
-func getSeed(t *testing.T) Seed {
-	sg := SeedGenerator{}
-	seed, err := sg.Next()
+func TestSeedManager_Verify(t *testing.T) {
+	ctx := inslogger.TestContext(t)
+	sm, _ := newSeedManager(t, DefaultTTL)
+
+	seed, err := sm.Issue(ctx)
+	require.NoError(t, err)
+	require.Equal(t, core.PulseNumber(core.FirstPulseNumber), seed.Pulse)
+	require.Equal(t, core.FirstPulseNumber+DefaultTTL, seed.Expires)
+
+	verified, err := sm.Verify(ctx, seed.Bytes())
 	require.NoError(t, err)
-	return *seed
+	require.Equal(t, seed, verified)
 }
 
-func TestSeedManager_Add(t *testing.T) {
-	sm := NewSpecified(time.Duration(5*time.Millisecond), DefaultCleanPeriod)
-	seed := getSeed(t)
-	sm.Add(seed)
-	require.True(t, sm.Exists(seed))
+func TestSeedManager_VerifyOnOtherNode(t *testing.T) {
+	ctx := inslogger.TestContext(t)
+	issuer, _ := newSeedManager(t, DefaultTTL)
+	seed, err := issuer.Issue(ctx)
+	require.NoError(t, err)
+
+	// Other node knows issuer as active node.
+	sm, _ := newSeedManager(t, DefaultTTL)
+	sm.nodeNetwork = issuer.nodeNetwork
+	_, err = sm.Verify(ctx, seed.Bytes())
+	require.NoError(t, err)
+
+	// Other node doesn't know issuer.
+	sm, _ = newSeedManager(t, DefaultTTL)
+	_, err = sm.Verify(ctx, seed.Bytes())
+	require.Error(t, err)
 }
 
 func TestSeedManager_ExpiredSeed(t *testing.T) {
-	expTime := time.Duration(5 * time.Millisecond)
-	sm := NewSpecified(expTime, DefaultCleanPeriod)
-	seed := getSeed(t)
-	sm.Add(seed)
-	<-time.After(expTime * 2)
-	require.False(t, sm.Exists(seed))
-}
+	ctx := inslogger.TestContext(t)
+	sm, ps := newSeedManager(t, 10)
+	seed, err := sm.Issue(ctx)
+	require.NoError(t, err)
 
-func TestSeedManager_ExistsThanExpiredSeed(t *testing.T) {
-	seed := getSeed(t)
-	ttl := time.Duration(8 * time.Millisecond)
-	sm := NewSpecified(ttl, DefaultCleanPeriod)
-	sm.Add(seed)
-	require.True(t, sm.Exists(seed))
-	<-time.After(ttl * 2)
-	require.False(t, sm.Exists(seed))
-}
+	ps.CurrentMock.Return(&core.Pulse{PulseNumber: core.FirstPulseNumber + 10}, nil)
+	_, err = sm.Verify(ctx, seed.Bytes())
+	require.NoError(t, err)
 
-func TestSeedManager_ExpiredSeedAfterCleaning(t *testing.T) {
-	expTime := time.Duration(2 * time.Millisecond)
-	sm := NewSpecified(expTime, 2*time.Millisecond)
-	seed := getSeed(t)
-	sm.Add(seed)
-	<-time.After(8 * time.Millisecond)
-	require.False(t, sm.Exists(seed))
+	ps.CurrentMock.Return(&core.Pulse{PulseNumber: core.FirstPulseNumber + 11}, nil)
+	_, err = sm.Verify(ctx, seed.Bytes())
+	require.Error(t, err)
 }
 
-func TestRace(t *testing.T) {
-	const numConcurrent = 15
-
-	expTime := time.Duration(2 * time.Millisecond)
-	cleanPeriod := time.Duration(1 * time.Millisecond)
-	sm := NewSpecified(expTime, cleanPeriod)
-
-	wg := sync.WaitGroup{}
-	wg.Add(numConcurrent)
-	for i := 0; i < numConcurrent; i++ {
-		go func() {
-			defer wg.Done()
-			var seeds []Seed
-			for j := 0; j < 500; j++ {
-				seeds = append(seeds, getSeed(t))
-				sm.Add(seeds[len(seeds)-1])
-			}
-			<-time.After(cleanPeriod)
-			for j := 0; j < 500; j++ {
-				sm.Exists(seeds[j])
-			}
-		}()
-	}
-	wg.Wait()
+func TestSeedManager_TamperedSeed(t *testing.T) {
+	ctx := inslogger.TestContext(t)
+	sm, _ := newSeedManager(t, 10)
+	seed, err := sm.Issue(ctx)
+	require.NoError(t, err)
+
+	seed.Expires += 1000
+	_, err = sm.Verify(ctx, seed.Bytes())
+	require.Error(t, err)
+
+	_, err = sm.Verify(ctx, []byte{1, 2, 3})
+	require.Error(t, err)
 }
@@ -0,0 +1,203 @@
+/*
+ *    Copyright 2019 Insolar Technologies
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/core/message"
+	"github.com/insolar/insolar/core/reply"
+	"github.com/insolar/insolar/core/utils"
+	"github.com/insolar/insolar/instrumentation/inslogger"
+	"github.com/pkg/errors"
+)
+
+// maxCallTreeSize limits number of requests in a call tree
+const maxCallTreeSize = 1000
+
+// CallTreeArgs is arguments that Trace service accepts.
+type CallTreeArgs struct {
+	Request string
+}
+
+// CallTreeNode is a request in a tree of cross-contract calls.
+type CallTreeNode struct {
+	Request   string
+	Object    string
+	Prototype string
+	Method    string
+	Executor  string
+	Start     string
+	Duration  int64 // in microseconds
+	Result    json.RawMessage
+	Error     string
+	Children  []*CallTreeNode
+}
+
+// CallTreeReply is reply for Trace service requests.
+type CallTreeReply struct {
+	Tree    *CallTreeNode
+	TraceID string
+}
+
+// TraceService is a service that provides API for tracing of contract calls.
+type TraceService struct {
+	runner *Runner
+}
+
+// NewTraceService creates new Trace service instance.
+func NewTraceService(runner *Runner) *TraceService {
+	return &TraceService{runner: runner}
+}
+
+// GetCallTree returns tree of cross-contract calls made while executing the request.
+// Traces are kept by virtual nodes for a limited number of pulses.
+// Results of all nested calls are returned, so the service is served on admin listener.
+//
+//   Request structure:
+//   {
+//     "jsonrpc": "2.0",
+//     "method": "trace.GetCallTree",
+//     "params": {
+//       // Reference to root request
+//       "Request": str
+//     },
+//     "id": str|int|null
+//   }
+//
+//   Response structure:
+//   {
+//     "jsonrpc": "2.0",
+//     "result": {
+//       "Tree": {
+//         "Request": str, // reference to request
+//         "Object": str, // reference to called object
+//         "Prototype": str, // reference to prototype of called object
+//         "Method": str, // name of method or constructor
+//         "Executor": str, // reference to node that executed request
+//         "Start": str, // time when execution started
+//         "Duration": int, // duration of execution in microseconds
+//         "Result": [ ... ], // results of method
+//         "Error": str, // error of execution if any
+//         "Children": [ ... ] // requests made during execution with the same structure
+//       },
+//       "TraceID": str // traceID for request
+//     },
+//     "id": str|int|null // same as in request
+//   }
+//
+func (s *TraceService) GetCallTree(r *http.Request, args *CallTreeArgs, reply *CallTreeReply) error {
+	traceID := utils.RandTraceID()
+	ctx, inslog := inslogger.WithTraceField(context.Background(), traceID)
+
+	inslog.Infof("[ TraceService.GetCallTree ] Incoming request: %s", r.RequestURI)
+
+	root, err := core.NewRefFromBase58(args.Request)
+	if err != nil {
+		return errors.Wrap(err, "[ TraceService.GetCallTree ] Can't parse request reference")
+	}
+
+	traces, err := s.collectTraces(ctx, *root)
+	if err != nil {
+		return errors.Wrap(err, "[ TraceService.GetCallTree ] Can't collect traces")
+	}
+	if _, ok := traces[*root]; !ok {
+		return errors.New("[ TraceService.GetCallTree ] Trace of request not found")
+	}
+
+	reply.Tree = buildCallTree(*root, traces)
+	reply.TraceID = traceID
+
+	return nil
+}
+
+// collectTraces asks virtual nodes for traces level by level starting with root request
+func (s *TraceService) collectTraces(ctx context.Context, root core.RecordRef) (map[core.RecordRef]core.CallTrace, error) {
+	nodes := s.runner.NodeNetwork.GetActiveNodesByRole(core.DynamicRoleVirtualExecutor)
+	if len(nodes) == 0 {
+		return nil, errors.New("no active virtual nodes")
+	}
+
+	traces := make(map[core.RecordRef]core.CallTrace)
+	level := []core.RecordRef{root}
+	for len(level) > 0 && len(traces) < maxCallTreeSize {
+		next := make([]core.RecordRef, 0)
+		for _, node := range nodes {
+			node := node
+			rep, err := s.runner.MessageBus.Send(
+				ctx,
+				&message.GetCallTraces{Requests: level},
+				&core.MessageSendOptions{Receiver: &node},
+			)
+			if err != nil {
+				inslogger.FromContext(ctx).Warnf("Can't get call traces from node %s: %s", node, err)
+				continue
+			}
+			found, ok := rep.(*reply.CallTraces)
+			if !ok {
+				return nil, errors.Errorf("unexpected reply: %#v", rep)
+			}
+			for _, trace := range found.Traces {
+				if _, ok := traces[trace.Request]; ok {
+					continue
+				}
+				traces[trace.Request] = trace
+				next = append(next, trace.Children...)
+			}
+		}
+		level = next
+	}
+
+	return traces, nil
+}
+
+func buildCallTree(request core.RecordRef, traces map[core.RecordRef]core.CallTrace) *CallTreeNode {
+	node := &CallTreeNode{
+		Request:  request.String(),
+		Children: []*CallTreeNode{},
+	}
+
+	trace, ok := traces[request]
+	if !ok {
+		node.Error = "trace not found"
+		return node
+	}
+
+	node.Object = trace.Object.String()
+	node.Prototype = trace.Prototype.String()
+	node.Method = trace.Method
+	node.Executor = trace.Executor.String()
+	node.Start = trace.Start.String()
+	node.Duration = int64(trace.Duration / time.Microsecond)
+	node.Error = trace.Error
+	if trace.Result != nil {
+		result, err := (*core.Arguments)(&trace.Result).MarshalJSON()
+		if err == nil {
+			node.Result = result
+		}
+	}
+
+	for _, child := range trace.Children {
+		node.Children = append(node.Children, buildCallTree(child, traces))
+	}
+
+	return node
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/insolar/insolar/application/proxy/tokenwallet"
 	"github.com/insolar/insolar/application/proxy/wallet"
 	"github.com/insolar/insolar/core"
 	"github.com/insolar/insolar/logicrunner/goplugin/foundation"
@@ -30,12 +31,24 @@ type Allowance struct {
 	To         core.RecordRef
 	Amount     uint
 	ExpireTime int64
+	// Asset is a name of asset for allowances of token wallets, it's empty for wallet allowances.
+	Asset string
+	// From is a member owning token wallet that made allowance.
+	From core.RecordRef
 }
 
+// isExpired returns true if expire time has passed, allowances of token wallets with zero expire time never expire.
 func (a *Allowance) isExpired() bool {
+	if a.ExpireTime == 0 && a.Asset != "" {
+		return false
+	}
 	return a.GetContext().Time.After(time.Unix(a.ExpireTime, 0))
 }
 
+func (a *Allowance) callerIsOwner() bool {
+	return *(a.GetContext().Caller) == *(a.GetContext().Parent)
+}
+
 // TakeAmount allows take amount and delete allowance
 func (a *Allowance) TakeAmount() (uint, error) {
 	if *(a.GetContext().Caller) != a.To {
@@ -55,7 +68,7 @@ func (a *Allowance) GetBalanceForOwner() (uint, error) {
 
 // GetExpiredBalance gets balance from expired allowance and delete allowance
 func (a *Allowance) GetExpiredBalance() (uint, error) {
-	if *(a.GetContext().Caller) != *(a.GetContext().Parent) {
+	if !a.callerIsOwner() {
 		return 0, fmt.Errorf("[ DeleteExpiredAllowance ] Only owner can delete expiried Allowance")
 	}
 	if a.isExpired() {
@@ -65,6 +78,40 @@ func (a *Allowance) GetExpiredBalance() (uint, error) {
 	return 0, nil
 }
 
+// GetAsset returns name of asset
+func (a *Allowance) GetAsset() (string, error) {
+	return a.Asset, nil
+}
+
+// GetFrom returns member owning token wallet that made allowance
+func (a *Allowance) GetFrom() (core.RecordRef, error) {
+	return a.From, nil
+}
+
+// Spend allows owner to take part of amount on behalf of recipient, recipient is checked by owner
+func (a *Allowance) Spend(amount uint) error {
+	if !a.callerIsOwner() {
+		return fmt.Errorf("[ Spend ] Only owner can spend allowance")
+	}
+	if a.isExpired() {
+		return fmt.Errorf("[ Spend ] Allowance expiried")
+	}
+	if amount > a.Amount {
+		return fmt.Errorf("[ Spend ] Amount exceeds allowance")
+	}
+	a.Amount -= amount
+	return nil
+}
+
+// Revoke returns remaining amount to owner and deletes allowance
+func (a *Allowance) Revoke() (uint, error) {
+	if !a.callerIsOwner() {
+		return 0, fmt.Errorf("[ Revoke ] Only owner can revoke allowance")
+	}
+	a.SelfDestruct()
+	return a.Amount, nil
+}
+
 // New check is caller wallet and makes new allowance
 func New(to *core.RecordRef, amount uint, expire int64) (*Allowance, error) {
 	if !wallet.PrototypeReference.Equal(*foundation.GetContext().CallerPrototype) {
@@ -72,3 +119,14 @@ func New(to *core.RecordRef, amount uint, expire int64) (*Allowance, error) {
 	}
 	return &Allowance{To: *to, Amount: amount, ExpireTime: expire}, nil
 }
+
+// NewAssetAllowance check is caller token wallet and makes new allowance of asset, zero expire means no expiration
+func NewAssetAllowance(to *core.RecordRef, asset string, amount uint, expire int64, from core.RecordRef) (*Allowance, error) {
+	if !tokenwallet.PrototypeReference.Equal(*foundation.GetContext().CallerPrototype) {
+		return nil, fmt.Errorf("[ NewAssetAllowance ] : Can't create allowance from not token wallet contract")
+	}
+	if asset == "" {
+		return nil, fmt.Errorf("[ NewAssetAllowance ] : Asset is required")
+	}
+	return &Allowance{To: *to, Amount: amount, ExpireTime: expire, Asset: asset, From: from}, nil
+}
@@ -0,0 +1,89 @@
+/*
+ *    Copyright 2019 Insolar Technologies
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package asset
+
+import (
+	"fmt"
+
+	"github.com/insolar/insolar/application/contract/wallet/safemath"
+	"github.com/insolar/insolar/application/proxy/tokenwallet"
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/logicrunner/goplugin/foundation"
+)
+
+// Asset describes named asset, amounts of asset are fixed-point numbers with Decimals fractional digits kept as
+// integer number of minimal units. Whole supply is issued to token wallet of issuer.
+type Asset struct {
+	foundation.BaseContract
+	Name     string
+	Decimals uint
+	Supply   uint
+	Issuer   core.RecordRef
+	Issued   bool
+}
+
+// GetName returns name of asset
+func (a *Asset) GetName() (string, error) {
+	return a.Name, nil
+}
+
+// GetDecimals returns number of fractional digits of amounts
+func (a *Asset) GetDecimals() (uint, error) {
+	return a.Decimals, nil
+}
+
+// GetSupply returns total amount of asset in minimal units
+func (a *Asset) GetSupply() (uint, error) {
+	return a.Supply, nil
+}
+
+// GetIssuer returns reference to member issued asset
+func (a *Asset) GetIssuer() (core.RecordRef, error) {
+	return a.Issuer, nil
+}
+
+// Issue returns supply to token wallet of issuer, it can be done only once
+func (a *Asset) Issue() (uint, error) {
+	if a.Issued {
+		return 0, fmt.Errorf("[ Issue ] Asset is already issued")
+	}
+	w, err := tokenwallet.GetImplementationFrom(a.Issuer)
+	if err != nil {
+		return 0, fmt.Errorf("[ Issue ] Can't get token wallet of issuer: %s", err.Error())
+	}
+	if *a.GetContext().Caller != w.GetReference() {
+		return 0, fmt.Errorf("[ Issue ] Only token wallet of issuer can issue asset")
+	}
+	a.Issued = true
+	return a.Supply, nil
+}
+
+// New creates new asset
+func New(name string, decimals uint, supply uint, issuer core.RecordRef) (*Asset, error) {
+	if name == "" {
+		return nil, fmt.Errorf("[ New Asset ] Name is required")
+	}
+	if decimals > safemath.MaxDecimals {
+		return nil, fmt.Errorf("[ New Asset ] Decimals must not be greater than %d", safemath.MaxDecimals)
+	}
+	return &Asset{
+		Name:     name,
+		Decimals: decimals,
+		Supply:   supply,
+		Issuer:   issuer,
+	}, nil
+}
@@ -0,0 +1,196 @@
+/*
+ *    Copyright 2019 Insolar Technologies
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package member
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/insolar/insolar/application/contract/allowance"
+	"github.com/insolar/insolar/application/contract/asset"
+	"github.com/insolar/insolar/application/contract/rootdomain"
+	"github.com/insolar/insolar/application/contract/tokenwallet"
+	allowanceproxy "github.com/insolar/insolar/application/proxy/allowance"
+	assetproxy "github.com/insolar/insolar/application/proxy/asset"
+	memberproxy "github.com/insolar/insolar/application/proxy/member"
+	rootdomainproxy "github.com/insolar/insolar/application/proxy/rootdomain"
+	tokenwalletproxy "github.com/insolar/insolar/application/proxy/tokenwallet"
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/logicrunner/goplugin/contracttest"
+	"github.com/stretchr/testify/require"
+)
+
+type assetTest struct {
+	t          *testing.T
+	h          *contracttest.Harness
+	rootDomain core.RecordRef
+}
+
+func newAssetTest(t *testing.T) *assetTest {
+	h := contracttest.NewHarness()
+	require.NoError(t, h.Register(memberproxy.PrototypeReference, &Member{}, New))
+	require.NoError(t, h.Register(rootdomainproxy.PrototypeReference, &rootdomain.RootDomain{}, rootdomain.NewRootDomain))
+	require.NoError(t, h.Register(tokenwalletproxy.PrototypeReference, &tokenwallet.TokenWallet{}, tokenwallet.New))
+	require.NoError(t, h.Register(assetproxy.PrototypeReference, &asset.Asset{}, asset.New))
+	require.NoError(t, h.Register(allowanceproxy.PrototypeReference, &allowance.Allowance{}, allowance.New, allowance.NewAssetAllowance))
+
+	rd, err := rootdomainproxy.NewRootDomain().AsChild(h.Root())
+	require.NoError(t, err)
+	return &assetTest{t: t, h: h, rootDomain: rd.GetReference()}
+}
+
+// member creates member, token wallet is created only if `withWallet` is set
+func (at *assetTest) member(name string, key testKey, withWallet bool) *testMember {
+	m, err := memberproxy.New(name, key.public).AsChild(at.rootDomain)
+	require.NoError(at.t, err)
+	if withWallet {
+		_, err = tokenwalletproxy.New().AsDelegate(m.GetReference())
+		require.NoError(at.t, err)
+	}
+	return &testMember{t: at.t, proxy: m, rootDomain: at.rootDomain}
+}
+
+func (at *assetTest) result(m *testMember, method string, key testKey, args ...interface{}) interface{} {
+	res, err := m.callResult(method, []testKey{key}, args...)
+	require.NoError(at.t, err)
+	require.Empty(at.t, at.h.NoWaitErrors())
+	return res
+}
+
+func (at *assetTest) balance(m *testMember, key testKey, name string, of *testMember) string {
+	return at.result(m, "GetAssetBalance", key, name, of.proxy.GetReference().String()).(string)
+}
+
+func TestMember_Asset(t *testing.T) {
+	at := newAssetTest(t)
+	aliceKey, bobKey, carolKey := newTestKey(t), newTestKey(t), newTestKey(t)
+	alice := at.member("alice", aliceKey, false)
+	bob := at.member("bob", bobKey, true)
+	carol := at.member("carol", carolKey, true)
+	aliceRef := alice.proxy.GetReference().String()
+	bobRef := bob.proxy.GetReference().String()
+	carolRef := carol.proxy.GetReference().String()
+
+	ref := at.result(alice, "CreateAsset", aliceKey, "GOLD", uint(2), "1000.50").(string)
+	err := alice.call("CreateAsset", []testKey{aliceKey}, "GOLD", uint(2), "1")
+	require.Contains(t, err.Error(), "already exists")
+	err = alice.call("CreateAsset", []testKey{aliceKey}, "SILVER", uint(2), "1.001")
+	require.Contains(t, err.Error(), "Invalid supply")
+
+	var info struct {
+		Reference string
+		Name      string
+		Decimals  uint
+		Supply    string
+		Issuer    string
+	}
+	require.NoError(t, json.Unmarshal(at.result(bob, "GetAsset", bobKey, "GOLD").([]byte), &info))
+	require.Equal(t, ref, info.Reference)
+	require.Equal(t, "GOLD", info.Name)
+	require.Equal(t, uint(2), info.Decimals)
+	require.Equal(t, "1000.5", info.Supply)
+	require.Equal(t, aliceRef, info.Issuer)
+	require.Equal(t, "1000.5", at.balance(bob, bobKey, "GOLD", alice))
+
+	at.result(alice, "TransferAsset", aliceKey, "GOLD", "100.25", bobRef)
+	require.Equal(t, "900.25", at.balance(alice, aliceKey, "GOLD", alice))
+	require.Equal(t, "100.25", at.balance(alice, aliceKey, "GOLD", bob))
+
+	err = alice.call("TransferAsset", []testKey{aliceKey}, "GOLD", "1.005", bobRef)
+	require.Contains(t, err.Error(), "Invalid amount")
+	err = alice.call("TransferAsset", []testKey{aliceKey}, "GOLD", "5000", bobRef)
+	require.Contains(t, err.Error(), "Not enough balance")
+	err = alice.call("TransferAsset", []testKey{aliceKey}, "SILVER", "1", bobRef)
+	require.Contains(t, err.Error(), "not found")
+	require.Equal(t, "900.25", at.balance(alice, aliceKey, "GOLD", alice))
+
+	// approved amount is reserved on the owner's wallet
+	at.result(alice, "Approve", aliceKey, "GOLD", bobRef, "50")
+	require.Equal(t, "50", at.result(carol, "GetAllowance", carolKey, "GOLD", aliceRef, bobRef))
+	require.Equal(t, "850.25", at.balance(alice, aliceKey, "GOLD", alice))
+
+	at.result(bob, "TransferFrom", bobKey, "GOLD", aliceRef, carolRef, "20")
+	require.Equal(t, "20", at.balance(carol, carolKey, "GOLD", carol))
+	require.Equal(t, "30", at.result(carol, "GetAllowance", carolKey, "GOLD", aliceRef, bobRef))
+	err = bob.call("TransferFrom", []testKey{bobKey}, "GOLD", aliceRef, carolRef, "40")
+	require.Contains(t, err.Error(), "exceeds allowance")
+	err = carol.call("TransferFrom", []testKey{carolKey}, "GOLD", aliceRef, carolRef, "1")
+	require.Contains(t, err.Error(), "not approved")
+
+	// zero approve revokes allowance and returns the rest
+	at.result(alice, "Approve", aliceKey, "GOLD", bobRef, "0")
+	require.Equal(t, "0", at.result(carol, "GetAllowance", carolKey, "GOLD", aliceRef, bobRef))
+	require.Equal(t, "880.25", at.balance(alice, aliceKey, "GOLD", alice))
+	err = bob.call("TransferFrom", []testKey{bobKey}, "GOLD", aliceRef, carolRef, "1")
+	require.Contains(t, err.Error(), "not approved")
+
+	var balances map[string]string
+	require.NoError(t, json.Unmarshal(at.result(bob, "GetAssetBalances", bobKey, carolRef).([]byte), &balances))
+	require.Equal(t, map[string]string{"GOLD": "20"}, balances)
+
+	type record struct {
+		Kind         string
+		Asset        string
+		Amount       string
+		Counterparty string
+	}
+	var log []record
+	res := at.result(bob, "GetTransferLog", bobKey, aliceRef, uint(0), uint(10))
+	require.NoError(t, json.Unmarshal(res.([]byte), &log))
+	require.Equal(t, []record{
+		{Kind: tokenwallet.KindIssue, Asset: "GOLD", Amount: "1000.5", Counterparty: ref},
+		{Kind: tokenwallet.KindSend, Asset: "GOLD", Amount: "100.25", Counterparty: bobRef},
+		{Kind: tokenwallet.KindApprove, Asset: "GOLD", Amount: "50", Counterparty: bobRef},
+		{Kind: tokenwallet.KindTransferFrom, Asset: "GOLD", Amount: "20", Counterparty: carolRef},
+		{Kind: tokenwallet.KindApprove, Asset: "GOLD", Amount: "0", Counterparty: bobRef},
+	}, log)
+
+	res = at.result(bob, "GetTransferLog", bobKey, carolRef, uint(0), uint(1))
+	require.NoError(t, json.Unmarshal(res.([]byte), &log))
+	require.Equal(t, []record{
+		{Kind: tokenwallet.KindReceive, Asset: "GOLD", Amount: "20", Counterparty: aliceRef},
+	}, log)
+	res = at.result(bob, "GetTransferLog", bobKey, carolRef, uint(1), uint(1))
+	require.NoError(t, json.Unmarshal(res.([]byte), &log))
+	require.Empty(t, log)
+
+	err = bob.call("GetTransferLog", []testKey{bobKey}, aliceRef, uint(0), uint(0))
+	require.Contains(t, err.Error(), "Limit must be from 1")
+}
+
+func TestMember_Asset_Wallets(t *testing.T) {
+	at := newAssetTest(t)
+	aliceKey, bobKey := newTestKey(t), newTestKey(t)
+	alice := at.member("alice", aliceKey, false)
+	bob := at.member("bob", bobKey, false)
+	bobRef := bob.proxy.GetReference().String()
+
+	err := bob.call("GetAssetBalances", []testKey{bobKey}, bobRef)
+	require.Contains(t, err.Error(), "no token wallet")
+
+	at.result(alice, "CreateAsset", aliceKey, "GOLD", uint(0), "10")
+	err = alice.call("TransferAsset", []testKey{aliceKey}, "GOLD", "1", bobRef)
+	require.Contains(t, err.Error(), "Recipient has no token wallet")
+	err = alice.call("Approve", []testKey{aliceKey}, "GOLD", bobRef, "1")
+	require.Contains(t, err.Error(), "Spender has no token wallet")
+	err = alice.call("TransferAsset", []testKey{aliceKey}, "GOLD", "1", alice.proxy.GetReference().String())
+	require.Contains(t, err.Error(), "different from the sender")
+
+	// wallet of the caller is created on the first call, so transfer itself is checked
+	err = bob.call("TransferAsset", []testKey{bobKey}, "GOLD", "0", alice.proxy.GetReference().String())
+	require.Contains(t, err.Error(), "Amount must be positive")
+}
@@ -17,11 +17,15 @@
 package member
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/insolar/insolar/application/contract/member/signer"
+	"github.com/insolar/insolar/application/contract/wallet/safemath"
+	"github.com/insolar/insolar/application/proxy/asset"
 	"github.com/insolar/insolar/application/proxy/nodedomain"
 	"github.com/insolar/insolar/application/proxy/rootdomain"
+	"github.com/insolar/insolar/application/proxy/tokenwallet"
 	"github.com/insolar/insolar/application/proxy/wallet"
 	"github.com/insolar/insolar/core"
 	"github.com/insolar/insolar/logicrunner/goplugin/foundation"
@@ -31,6 +35,11 @@ type Member struct {
 	foundation.BaseContract
 	Name      string
 	PublicKey string
+	// PublicKeys are keys allowed to sign calls of member, PublicKey is the first of them.
+	// Members created before key rotation have only PublicKey set.
+	PublicKeys []string
+	// Threshold is a number of signatures by different keys required to make a call.
+	Threshold uint
 }
 
 func (m *Member) GetName() (string, error) {
@@ -39,35 +48,85 @@ func (m *Member) GetName() (string, error) {
 
 var INSATTR_GetPublicKey_API = true
 
+// GetPublicKey returns the first key of member.
 func (m *Member) GetPublicKey() (string, error) {
 	return m.PublicKey, nil
 }
 
+var INSATTR_GetPublicKeys_API = true
+
+// GetPublicKeys returns all keys allowed to sign calls of member.
+func (m *Member) GetPublicKeys() ([]string, error) {
+	return m.keys(), nil
+}
+
+var INSATTR_GetThreshold_API = true
+
+// GetThreshold returns a number of signatures required to make a call.
+func (m *Member) GetThreshold() (uint, error) {
+	return m.threshold(), nil
+}
+
 func New(name string, key string) (*Member, error) {
 	return &Member{
-		Name:      name,
-		PublicKey: key,
+		Name:       name,
+		PublicKey:  key,
+		PublicKeys: []string{key},
+		Threshold:  1,
 	}, nil
 }
 
-func (m *Member) verifySig(method string, params []byte, seed []byte, sign []byte) error {
-	args, err := core.MarshalArgs(m.GetReference(), method, params, seed)
-	if err != nil {
-		return fmt.Errorf("[ verifySig ] Can't MarshalArgs: %s", err.Error())
+func (m *Member) keys() []string {
+	if len(m.PublicKeys) == 0 {
+		return []string{m.PublicKey}
 	}
-	key, err := m.GetPublicKey()
-	if err != nil {
-		return fmt.Errorf("[ verifySig ]: %s", err.Error())
+	return m.PublicKeys
+}
+
+func (m *Member) setKeys(keys []string) {
+	m.PublicKeys = keys
+	m.PublicKey = keys[0]
+}
+
+func (m *Member) threshold() uint {
+	if m.Threshold == 0 {
+		return 1
 	}
+	return m.Threshold
+}
 
-	publicKey, err := foundation.ImportPublicKey(key)
+// verifySigs checks that call is signed by at least threshold of different member keys.
+func (m *Member) verifySigs(method string, params []byte, seed []byte, signs [][]byte) error {
+	args, err := core.MarshalArgs(m.GetReference(), method, params, seed)
 	if err != nil {
-		return fmt.Errorf("[ verifySig ] Invalid public key")
+		return fmt.Errorf("[ verifySigs ] Can't MarshalArgs: %s", err.Error())
 	}
 
-	verified := foundation.Verify(args, sign, publicKey)
-	if !verified {
-		return fmt.Errorf("[ verifySig ] Incorrect signature")
+	keys := m.keys()
+	signed := make([]bool, len(keys))
+	var count uint
+	for _, sign := range signs {
+		for i, key := range keys {
+			if signed[i] {
+				continue
+			}
+			publicKey, err := foundation.ImportPublicKey(key)
+			if err != nil {
+				return fmt.Errorf("[ verifySigs ] Invalid public key")
+			}
+			if foundation.Verify(args, sign, publicKey) {
+				signed[i] = true
+				count++
+				break
+			}
+		}
+	}
+
+	if count == 0 {
+		return fmt.Errorf("[ verifySigs ] Incorrect signature")
+	}
+	if count < m.threshold() {
+		return fmt.Errorf("[ verifySigs ] Not enough signatures: %d of %d", count, m.threshold())
 	}
 	return nil
 }
@@ -76,11 +135,23 @@ var INSATTR_Call_API = true
 
 // Call method for authorized calls
 func (m *Member) Call(rootDomain core.RecordRef, method string, params []byte, seed []byte, sign []byte) (interface{}, error) {
-
-	if err := m.verifySig(method, params, seed, sign); err != nil {
+	if err := m.verifySigs(method, params, seed, [][]byte{sign}); err != nil {
 		return nil, fmt.Errorf("[ Call ]: %s", err.Error())
 	}
+	return m.call(rootDomain, method, params)
+}
+
+var INSATTR_CallMultisig_API = true
+
+// CallMultisig method for authorized calls signed by several keys of member
+func (m *Member) CallMultisig(rootDomain core.RecordRef, method string, params []byte, seed []byte, signs [][]byte) (interface{}, error) {
+	if err := m.verifySigs(method, params, seed, signs); err != nil {
+		return nil, fmt.Errorf("[ CallMultisig ]: %s", err.Error())
+	}
+	return m.call(rootDomain, method, params)
+}
 
+func (m *Member) call(rootDomain core.RecordRef, method string, params []byte) (interface{}, error) {
 	switch method {
 	case "CreateMember":
 		return m.createMemberCall(rootDomain, params)
@@ -98,6 +169,38 @@ func (m *Member) Call(rootDomain core.RecordRef, method string, params []byte, s
 		return m.registerNodeCall(rootDomain, params)
 	case "GetNodeRef":
 		return m.getNodeRefCall(rootDomain, params)
+	case "AddKey":
+		return m.addKeyCall(rootDomain, params)
+	case "RevokeKey":
+		return m.revokeKeyCall(rootDomain, params)
+	case "RotateKey":
+		return m.rotateKeyCall(rootDomain, params)
+	case "SetThreshold":
+		return m.setThresholdCall(params)
+	case "CreateAsset":
+		return m.createAssetCall(rootDomain, params)
+	case "GetAsset":
+		return m.getAssetCall(rootDomain, params)
+	case "GetAssetBalance":
+		return m.getAssetBalanceCall(rootDomain, params)
+	case "GetAssetBalances":
+		return m.getAssetBalancesCall(rootDomain, params)
+	case "TransferAsset":
+		return m.transferAssetCall(rootDomain, params)
+	case "Approve":
+		return m.approveCall(rootDomain, params)
+	case "GetAllowance":
+		return m.getAllowanceCall(rootDomain, params)
+	case "TransferFrom":
+		return m.transferFromCall(rootDomain, params)
+	case "GetTransferLog":
+		return m.getTransferLogCall(rootDomain, params)
+	case "GetMemberByName":
+		return m.getMemberByNameCall(rootDomain, params)
+	case "GetMemberByPublicKey":
+		return m.getMemberByPublicKeyCall(rootDomain, params)
+	case "ListMembers":
+		return m.listMembersCall(rootDomain, params)
 	}
 	return nil, &foundation.Error{S: "Unknown method"}
 }
@@ -215,3 +318,430 @@ func (m *Member) getNodeRefCall(ref core.RecordRef, params []byte) (interface{},
 
 	return nodeRef, nil
 }
+
+func (m *Member) keyIndex(key string) int {
+	for i, k := range m.keys() {
+		if k == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// verifyKeyProof checks that caller owns key, proof is a signature of member reference and key made with the key.
+func (m *Member) verifyKeyProof(key string, proof []byte) error {
+	publicKey, err := foundation.ImportPublicKey(key)
+	if err != nil {
+		return fmt.Errorf("Invalid public key")
+	}
+	data, err := core.MarshalArgs(m.GetReference(), key)
+	if err != nil {
+		return fmt.Errorf("Can't MarshalArgs: %s", err.Error())
+	}
+	if !foundation.Verify(data, proof, publicKey) {
+		return fmt.Errorf("Incorrect key proof")
+	}
+	return nil
+}
+
+func (m *Member) addKeyCall(rootDomain core.RecordRef, params []byte) (interface{}, error) {
+	var key string
+	var proof []byte
+	if err := signer.UnmarshalParams(params, &key, &proof); err != nil {
+		return nil, fmt.Errorf("[ addKeyCall ] Can't unmarshal params: %s", err.Error())
+	}
+	if err := m.verifyKeyProof(key, proof); err != nil {
+		return nil, fmt.Errorf("[ addKeyCall ] %s", err.Error())
+	}
+	if m.keyIndex(key) >= 0 {
+		return nil, fmt.Errorf("[ addKeyCall ] Key already added")
+	}
+	if err := rootdomain.GetObject(rootDomain).IndexMemberKey(key); err != nil {
+		return nil, fmt.Errorf("[ addKeyCall ] %s", err.Error())
+	}
+
+	m.setKeys(append(append([]string{}, m.keys()...), key))
+	return nil, nil
+}
+
+func (m *Member) revokeKeyCall(rootDomain core.RecordRef, params []byte) (interface{}, error) {
+	var key string
+	if err := signer.UnmarshalParams(params, &key); err != nil {
+		return nil, fmt.Errorf("[ revokeKeyCall ] Can't unmarshal params: %s", err.Error())
+	}
+	i := m.keyIndex(key)
+	if i < 0 {
+		return nil, fmt.Errorf("[ revokeKeyCall ] Key not found")
+	}
+	keys := m.keys()
+	if uint(len(keys)-1) < m.threshold() {
+		return nil, fmt.Errorf("[ revokeKeyCall ] Member must have at least %d keys", m.threshold())
+	}
+	if err := rootdomain.GetObject(rootDomain).UnindexMemberKey(key); err != nil {
+		return nil, fmt.Errorf("[ revokeKeyCall ] %s", err.Error())
+	}
+
+	m.setKeys(append(append([]string{}, keys[:i]...), keys[i+1:]...))
+	return nil, nil
+}
+
+func (m *Member) rotateKeyCall(rootDomain core.RecordRef, params []byte) (interface{}, error) {
+	var oldKey, newKey string
+	var proof []byte
+	if err := signer.UnmarshalParams(params, &oldKey, &newKey, &proof); err != nil {
+		return nil, fmt.Errorf("[ rotateKeyCall ] Can't unmarshal params: %s", err.Error())
+	}
+	i := m.keyIndex(oldKey)
+	if i < 0 {
+		return nil, fmt.Errorf("[ rotateKeyCall ] Key not found")
+	}
+	if err := m.verifyKeyProof(newKey, proof); err != nil {
+		return nil, fmt.Errorf("[ rotateKeyCall ] %s", err.Error())
+	}
+	if m.keyIndex(newKey) >= 0 {
+		return nil, fmt.Errorf("[ rotateKeyCall ] Key already added")
+	}
+	rd := rootdomain.GetObject(rootDomain)
+	if err := rd.IndexMemberKey(newKey); err != nil {
+		return nil, fmt.Errorf("[ rotateKeyCall ] %s", err.Error())
+	}
+	if err := rd.UnindexMemberKey(oldKey); err != nil {
+		return nil, fmt.Errorf("[ rotateKeyCall ] %s", err.Error())
+	}
+
+	keys := append([]string{}, m.keys()...)
+	keys[i] = newKey
+	m.setKeys(keys)
+	return nil, nil
+}
+
+func (m *Member) setThresholdCall(params []byte) (interface{}, error) {
+	var threshold uint
+	if err := signer.UnmarshalParams(params, &threshold); err != nil {
+		return nil, fmt.Errorf("[ setThresholdCall ] Can't unmarshal params: %s", err.Error())
+	}
+	if threshold == 0 || threshold > uint(len(m.keys())) {
+		return nil, fmt.Errorf("[ setThresholdCall ] Threshold must be from 1 to %d", len(m.keys()))
+	}
+
+	m.Threshold = threshold
+	return nil, nil
+}
+
+// tokenWallet returns token wallet of member, it's created for members registered before token wallets were added.
+func (m *Member) tokenWallet() (*tokenwallet.TokenWallet, error) {
+	w, err := tokenwallet.GetImplementationFrom(m.GetReference())
+	if err == nil {
+		return w, nil
+	}
+	return tokenwallet.New().AsDelegate(m.GetReference())
+}
+
+func memberTokenWallet(member string) (*tokenwallet.TokenWallet, error) {
+	ref, err := core.NewRefFromBase58(member)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse member reference: %s", err.Error())
+	}
+	w, err := tokenwallet.GetImplementationFrom(*ref)
+	if err != nil {
+		return nil, fmt.Errorf("Member has no token wallet: %s", err.Error())
+	}
+	return w, nil
+}
+
+// assetDecimals returns number of fractional digits of amounts of asset with name.
+func assetDecimals(rootDomain core.RecordRef, name string) (uint, error) {
+	ref, err := rootdomain.GetObject(rootDomain).GetAssetRef(name)
+	if err != nil {
+		return 0, err
+	}
+	return asset.GetObject(ref).GetDecimals()
+}
+
+// parseAmount converts fixed-point amount of asset to minimal units.
+func parseAmount(rootDomain core.RecordRef, name string, amount string) (uint, error) {
+	decimals, err := assetDecimals(rootDomain, name)
+	if err != nil {
+		return 0, err
+	}
+	res, err := safemath.ParseFixed(amount, decimals)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid amount: %s", err.Error())
+	}
+	return res, nil
+}
+
+func (m *Member) createAssetCall(rootDomain core.RecordRef, params []byte) (interface{}, error) {
+	var name string
+	var decimals uint
+	var supplyStr string
+	if err := signer.UnmarshalParams(params, &name, &decimals, &supplyStr); err != nil {
+		return nil, fmt.Errorf("[ createAssetCall ] Can't unmarshal params: %s", err.Error())
+	}
+	if decimals > safemath.MaxDecimals {
+		return nil, fmt.Errorf("[ createAssetCall ] Decimals must not be greater than %d", safemath.MaxDecimals)
+	}
+	supply, err := safemath.ParseFixed(supplyStr, decimals)
+	if err != nil {
+		return nil, fmt.Errorf("[ createAssetCall ] Invalid supply: %s", err.Error())
+	}
+
+	assetRef, err := rootdomain.GetObject(rootDomain).CreateAsset(name, decimals, supply)
+	if err != nil {
+		return nil, fmt.Errorf("[ createAssetCall ] %s", err.Error())
+	}
+	w, err := m.tokenWallet()
+	if err != nil {
+		return nil, fmt.Errorf("[ createAssetCall ] Can't get token wallet: %s", err.Error())
+	}
+	if err := w.Issue(&assetRef); err != nil {
+		return nil, fmt.Errorf("[ createAssetCall ] %s", err.Error())
+	}
+	return assetRef.String(), nil
+}
+
+func (m *Member) getAssetCall(rootDomain core.RecordRef, params []byte) (interface{}, error) {
+	var name string
+	if err := signer.UnmarshalParams(params, &name); err != nil {
+		return nil, fmt.Errorf("[ getAssetCall ] Can't unmarshal params: %s", err.Error())
+	}
+	ref, err := rootdomain.GetObject(rootDomain).GetAssetRef(name)
+	if err != nil {
+		return nil, fmt.Errorf("[ getAssetCall ] %s", err.Error())
+	}
+	a := asset.GetObject(ref)
+	decimals, err := a.GetDecimals()
+	if err != nil {
+		return nil, fmt.Errorf("[ getAssetCall ] Can't get decimals: %s", err.Error())
+	}
+	supply, err := a.GetSupply()
+	if err != nil {
+		return nil, fmt.Errorf("[ getAssetCall ] Can't get supply: %s", err.Error())
+	}
+	issuer, err := a.GetIssuer()
+	if err != nil {
+		return nil, fmt.Errorf("[ getAssetCall ] Can't get issuer: %s", err.Error())
+	}
+
+	return json.Marshal(map[string]interface{}{
+		"reference": ref.String(),
+		"name":      name,
+		"decimals":  decimals,
+		"supply":    safemath.FormatFixed(supply, decimals),
+		"issuer":    issuer.String(),
+	})
+}
+
+func (m *Member) getAssetBalanceCall(rootDomain core.RecordRef, params []byte) (interface{}, error) {
+	var name, member string
+	if err := signer.UnmarshalParams(params, &name, &member); err != nil {
+		return nil, fmt.Errorf("[ getAssetBalanceCall ] Can't unmarshal params: %s", err.Error())
+	}
+	decimals, err := assetDecimals(rootDomain, name)
+	if err != nil {
+		return nil, fmt.Errorf("[ getAssetBalanceCall ] %s", err.Error())
+	}
+	w, err := memberTokenWallet(member)
+	if err != nil {
+		return nil, fmt.Errorf("[ getAssetBalanceCall ] %s", err.Error())
+	}
+	balance, err := w.GetBalance(name)
+	if err != nil {
+		return nil, fmt.Errorf("[ getAssetBalanceCall ] %s", err.Error())
+	}
+	return safemath.FormatFixed(balance, decimals), nil
+}
+
+func (m *Member) getAssetBalancesCall(rootDomain core.RecordRef, params []byte) (interface{}, error) {
+	var member string
+	if err := signer.UnmarshalParams(params, &member); err != nil {
+		return nil, fmt.Errorf("[ getAssetBalancesCall ] Can't unmarshal params: %s", err.Error())
+	}
+	w, err := memberTokenWallet(member)
+	if err != nil {
+		return nil, fmt.Errorf("[ getAssetBalancesCall ] %s", err.Error())
+	}
+	balances, err := w.GetBalances()
+	if err != nil {
+		return nil, fmt.Errorf("[ getAssetBalancesCall ] %s", err.Error())
+	}
+
+	res := map[string]string{}
+	for _, b := range balances {
+		decimals, err := assetDecimals(rootDomain, b.Asset)
+		if err != nil {
+			return nil, fmt.Errorf("[ getAssetBalancesCall ] %s", err.Error())
+		}
+		res[b.Asset] = safemath.FormatFixed(b.Amount, decimals)
+	}
+	return json.Marshal(res)
+}
+
+func (m *Member) transferAssetCall(rootDomain core.RecordRef, params []byte) (interface{}, error) {
+	var name, amountStr, toStr string
+	if err := signer.UnmarshalParams(params, &name, &amountStr, &toStr); err != nil {
+		return nil, fmt.Errorf("[ transferAssetCall ] Can't unmarshal params: %s", err.Error())
+	}
+	amount, err := parseAmount(rootDomain, name, amountStr)
+	if err != nil {
+		return nil, fmt.Errorf("[ transferAssetCall ] %s", err.Error())
+	}
+	to, err := core.NewRefFromBase58(toStr)
+	if err != nil {
+		return nil, fmt.Errorf("[ transferAssetCall ] Failed to parse 'to' param: %s", err.Error())
+	}
+	w, err := m.tokenWallet()
+	if err != nil {
+		return nil, fmt.Errorf("[ transferAssetCall ] Can't get token wallet: %s", err.Error())
+	}
+	return nil, w.Transfer(name, amount, to)
+}
+
+func (m *Member) approveCall(rootDomain core.RecordRef, params []byte) (interface{}, error) {
+	var name, spenderStr, amountStr string
+	if err := signer.UnmarshalParams(params, &name, &spenderStr, &amountStr); err != nil {
+		return nil, fmt.Errorf("[ approveCall ] Can't unmarshal params: %s", err.Error())
+	}
+	amount, err := parseAmount(rootDomain, name, amountStr)
+	if err != nil {
+		return nil, fmt.Errorf("[ approveCall ] %s", err.Error())
+	}
+	spender, err := core.NewRefFromBase58(spenderStr)
+	if err != nil {
+		return nil, fmt.Errorf("[ approveCall ] Failed to parse 'spender' param: %s", err.Error())
+	}
+	w, err := m.tokenWallet()
+	if err != nil {
+		return nil, fmt.Errorf("[ approveCall ] Can't get token wallet: %s", err.Error())
+	}
+	return nil, w.Approve(name, spender, amount)
+}
+
+func (m *Member) getAllowanceCall(rootDomain core.RecordRef, params []byte) (interface{}, error) {
+	var name, owner, spenderStr string
+	if err := signer.UnmarshalParams(params, &name, &owner, &spenderStr); err != nil {
+		return nil, fmt.Errorf("[ getAllowanceCall ] Can't unmarshal params: %s", err.Error())
+	}
+	decimals, err := assetDecimals(rootDomain, name)
+	if err != nil {
+		return nil, fmt.Errorf("[ getAllowanceCall ] %s", err.Error())
+	}
+	w, err := memberTokenWallet(owner)
+	if err != nil {
+		return nil, fmt.Errorf("[ getAllowanceCall ] %s", err.Error())
+	}
+	spender, err := core.NewRefFromBase58(spenderStr)
+	if err != nil {
+		return nil, fmt.Errorf("[ getAllowanceCall ] Failed to parse 'spender' param: %s", err.Error())
+	}
+	amount, err := w.GetAllowance(name, spender)
+	if err != nil {
+		return nil, fmt.Errorf("[ getAllowanceCall ] %s", err.Error())
+	}
+	return safemath.FormatFixed(amount, decimals), nil
+}
+
+func (m *Member) transferFromCall(rootDomain core.RecordRef, params []byte) (interface{}, error) {
+	var name, ownerStr, toStr, amountStr string
+	if err := signer.UnmarshalParams(params, &name, &ownerStr, &toStr, &amountStr); err != nil {
+		return nil, fmt.Errorf("[ transferFromCall ] Can't unmarshal params: %s", err.Error())
+	}
+	amount, err := parseAmount(rootDomain, name, amountStr)
+	if err != nil {
+		return nil, fmt.Errorf("[ transferFromCall ] %s", err.Error())
+	}
+	owner, err := core.NewRefFromBase58(ownerStr)
+	if err != nil {
+		return nil, fmt.Errorf("[ transferFromCall ] Failed to parse 'owner' param: %s", err.Error())
+	}
+	to, err := core.NewRefFromBase58(toStr)
+	if err != nil {
+		return nil, fmt.Errorf("[ transferFromCall ] Failed to parse 'to' param: %s", err.Error())
+	}
+	w, err := m.tokenWallet()
+	if err != nil {
+		return nil, fmt.Errorf("[ transferFromCall ] Can't get token wallet: %s", err.Error())
+	}
+	return nil, w.TransferFrom(name, owner, to, amount)
+}
+
+func (m *Member) getTransferLogCall(rootDomain core.RecordRef, params []byte) (interface{}, error) {
+	var member string
+	var offset, limit uint
+	if err := signer.UnmarshalParams(params, &member, &offset, &limit); err != nil {
+		return nil, fmt.Errorf("[ getTransferLogCall ] Can't unmarshal params: %s", err.Error())
+	}
+	w, err := memberTokenWallet(member)
+	if err != nil {
+		return nil, fmt.Errorf("[ getTransferLogCall ] %s", err.Error())
+	}
+	records, err := w.GetLog(offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("[ getTransferLogCall ] %s", err.Error())
+	}
+
+	decimals := map[string]uint{}
+	res := []map[string]interface{}{}
+	for _, r := range records {
+		d, ok := decimals[r.Asset]
+		if !ok {
+			d, err = assetDecimals(rootDomain, r.Asset)
+			if err != nil {
+				return nil, fmt.Errorf("[ getTransferLogCall ] %s", err.Error())
+			}
+			decimals[r.Asset] = d
+		}
+		res = append(res, map[string]interface{}{
+			"kind":         r.Kind,
+			"asset":        r.Asset,
+			"amount":       safemath.FormatFixed(r.Amount, d),
+			"counterparty": r.Counterparty.String(),
+			"pulse":        r.Pulse,
+		})
+	}
+	return json.Marshal(res)
+}
+
+func (m *Member) getMemberByNameCall(rootDomain core.RecordRef, params []byte) (interface{}, error) {
+	var name string
+	if err := signer.UnmarshalParams(params, &name); err != nil {
+		return nil, fmt.Errorf("[ getMemberByNameCall ] Can't unmarshal params: %s", err.Error())
+	}
+	refs, err := rootdomain.GetObject(rootDomain).GetMemberByName(name)
+	if err != nil {
+		return nil, fmt.Errorf("[ getMemberByNameCall ] %s", err.Error())
+	}
+	return refs, nil
+}
+
+func (m *Member) getMemberByPublicKeyCall(rootDomain core.RecordRef, params []byte) (interface{}, error) {
+	var key string
+	if err := signer.UnmarshalParams(params, &key); err != nil {
+		return nil, fmt.Errorf("[ getMemberByPublicKeyCall ] Can't unmarshal params: %s", err.Error())
+	}
+	refs, err := rootdomain.GetObject(rootDomain).GetMemberByPublicKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("[ getMemberByPublicKeyCall ] %s", err.Error())
+	}
+	return refs, nil
+}
+
+func (m *Member) listMembersCall(rootDomain core.RecordRef, params []byte) (interface{}, error) {
+	var offset, limit uint
+	if err := signer.UnmarshalParams(params, &offset, &limit); err != nil {
+		return nil, fmt.Errorf("[ listMembersCall ] Can't unmarshal params: %s", err.Error())
+	}
+	records, err := rootdomain.GetObject(rootDomain).ListMembers(offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("[ listMembersCall ] %s", err.Error())
+	}
+
+	res := []map[string]interface{}{}
+	for _, r := range records {
+		res = append(res, map[string]interface{}{
+			"reference": r.Reference.String(),
+			"name":      r.Name,
+		})
+	}
+	return json.Marshal(res)
+}
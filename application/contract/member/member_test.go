@@ -0,0 +1,172 @@
+/*
+ *    Copyright 2019 Insolar Technologies
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package member
+
+import (
+	"crypto"
+	"testing"
+
+	"github.com/insolar/insolar/application/contract/rootdomain"
+	memberproxy "github.com/insolar/insolar/application/proxy/member"
+	rootdomainproxy "github.com/insolar/insolar/application/proxy/rootdomain"
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/logicrunner/goplugin/contracttest"
+	"github.com/insolar/insolar/logicrunner/goplugin/foundation"
+	"github.com/insolar/insolar/platformpolicy"
+	"github.com/stretchr/testify/require"
+)
+
+type testKey struct {
+	private crypto.PrivateKey
+	public  string
+}
+
+func newTestKey(t *testing.T) testKey {
+	ks := platformpolicy.NewKeyProcessor()
+	privateKey, err := ks.GeneratePrivateKey()
+	require.NoError(t, err)
+	publicKey, err := ks.ExportPublicKeyPEM(ks.ExtractPublicKey(privateKey))
+	require.NoError(t, err)
+	return testKey{private: privateKey, public: string(publicKey)}
+}
+
+type testMember struct {
+	t          *testing.T
+	proxy      *memberproxy.Member
+	rootDomain core.RecordRef
+	seed       byte
+}
+
+func (m *testMember) sign(method string, params []byte, seed []byte, keys ...testKey) [][]byte {
+	data, err := core.MarshalArgs(m.proxy.GetReference(), method, params, seed)
+	require.NoError(m.t, err)
+	var signs [][]byte
+	for _, key := range keys {
+		sign, err := foundation.Sign(data, key.private)
+		require.NoError(m.t, err)
+		signs = append(signs, sign)
+	}
+	return signs
+}
+
+func (m *testMember) callResult(method string, keys []testKey, args ...interface{}) (interface{}, error) {
+	params, err := core.Serialize(args)
+	require.NoError(m.t, err)
+	m.seed++
+	seed := []byte{m.seed}
+
+	signs := m.sign(method, params, seed, keys...)
+	if len(signs) == 1 {
+		return m.proxy.Call(m.rootDomain, method, params, seed, signs[0])
+	}
+	return m.proxy.CallMultisig(m.rootDomain, method, params, seed, signs)
+}
+
+// keyProof signs reference of member and key with the key, as AddKey and RotateKey require.
+func (m *testMember) keyProof(key testKey) []byte {
+	data, err := core.MarshalArgs(m.proxy.GetReference(), key.public)
+	require.NoError(m.t, err)
+	proof, err := foundation.Sign(data, key.private)
+	require.NoError(m.t, err)
+	return proof
+}
+
+func (m *testMember) call(method string, keys []testKey, args ...interface{}) error {
+	_, err := m.callResult(method, keys, args...)
+	return err
+}
+
+func newTestMember(t *testing.T, key testKey) (*contracttest.Harness, *testMember) {
+	h := contracttest.NewHarness()
+	require.NoError(t, h.Register(memberproxy.PrototypeReference, &Member{}, New))
+	require.NoError(t, h.Register(rootdomainproxy.PrototypeReference, &rootdomain.RootDomain{}, rootdomain.NewRootDomain))
+	rd, err := rootdomainproxy.NewRootDomain().AsChild(h.Root())
+	require.NoError(t, err)
+	m, err := memberproxy.New("member", key.public).AsChild(rd.GetReference())
+	require.NoError(t, err)
+	return h, &testMember{t: t, proxy: m, rootDomain: rd.GetReference()}
+}
+
+func TestMember_RotateKey(t *testing.T) {
+	oldKey, newKey := newTestKey(t), newTestKey(t)
+	_, m := newTestMember(t, oldKey)
+
+	err := m.call("RotateKey", []testKey{oldKey}, oldKey.public, newKey.public, m.keyProof(oldKey))
+	require.Contains(t, err.Error(), "Incorrect key proof")
+	require.NoError(t, m.call("RotateKey", []testKey{oldKey}, oldKey.public, newKey.public, m.keyProof(newKey)))
+
+	keys, err := m.proxy.GetPublicKeys()
+	require.NoError(t, err)
+	require.Equal(t, []string{newKey.public}, keys)
+	key, err := m.proxy.GetPublicKey()
+	require.NoError(t, err)
+	require.Equal(t, newKey.public, key)
+
+	err = m.call("AddKey", []testKey{oldKey}, oldKey.public, m.keyProof(oldKey))
+	require.Contains(t, err.Error(), "Incorrect signature")
+	require.NoError(t, m.call("AddKey", []testKey{newKey}, oldKey.public, m.keyProof(oldKey)))
+}
+
+func TestMember_AddRevokeKey(t *testing.T) {
+	first, second := newTestKey(t), newTestKey(t)
+	_, m := newTestMember(t, first)
+
+	err := m.call("RevokeKey", []testKey{first}, first.public)
+	require.Contains(t, err.Error(), "at least 1 keys")
+
+	require.NoError(t, m.call("AddKey", []testKey{first}, second.public, m.keyProof(second)))
+	err = m.call("AddKey", []testKey{first}, second.public, m.keyProof(second))
+	require.Contains(t, err.Error(), "Key already added")
+	err = m.call("AddKey", []testKey{first}, "not a key", []byte{})
+	require.Contains(t, err.Error(), "Invalid public key")
+	// Key can't be added without its private key.
+	third := newTestKey(t)
+	err = m.call("AddKey", []testKey{first}, third.public, m.keyProof(first))
+	require.Contains(t, err.Error(), "Incorrect key proof")
+
+	require.NoError(t, m.call("RevokeKey", []testKey{second}, first.public))
+	keys, err := m.proxy.GetPublicKeys()
+	require.NoError(t, err)
+	require.Equal(t, []string{second.public}, keys)
+
+	err = m.call("AddKey", []testKey{first}, first.public, m.keyProof(first))
+	require.Contains(t, err.Error(), "Incorrect signature")
+}
+
+func TestMember_Multisig(t *testing.T) {
+	first, second, third := newTestKey(t), newTestKey(t), newTestKey(t)
+	_, m := newTestMember(t, first)
+
+	require.NoError(t, m.call("AddKey", []testKey{first}, second.public, m.keyProof(second)))
+	require.NoError(t, m.call("AddKey", []testKey{first}, third.public, m.keyProof(third)))
+	err := m.call("SetThreshold", []testKey{first}, uint(4))
+	require.Contains(t, err.Error(), "Threshold must be from 1 to 3")
+	require.NoError(t, m.call("SetThreshold", []testKey{first}, uint(2)))
+
+	threshold, err := m.proxy.GetThreshold()
+	require.NoError(t, err)
+	require.Equal(t, uint(2), threshold)
+
+	err = m.call("RevokeKey", []testKey{first}, third.public)
+	require.Contains(t, err.Error(), "Not enough signatures")
+	err = m.call("RevokeKey", []testKey{first, first}, third.public)
+	require.Contains(t, err.Error(), "Not enough signatures")
+	require.NoError(t, m.call("RevokeKey", []testKey{first, third}, third.public))
+
+	err = m.call("RevokeKey", []testKey{first, second}, second.public)
+	require.Contains(t, err.Error(), "at least 2 keys")
+}
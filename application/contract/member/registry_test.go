@@ -0,0 +1,130 @@
+/*
+ *    Copyright 2019 Insolar Technologies
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package member
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/insolar/insolar/application/contract/rootdomain"
+	"github.com/insolar/insolar/application/contract/tokenwallet"
+	"github.com/insolar/insolar/application/contract/wallet"
+	memberproxy "github.com/insolar/insolar/application/proxy/member"
+	tokenwalletproxy "github.com/insolar/insolar/application/proxy/tokenwallet"
+	walletproxy "github.com/insolar/insolar/application/proxy/wallet"
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/logicrunner/goplugin/contracttest"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestRootMember makes member root member of its root domain, like genesis does
+func newTestRootMember(t *testing.T, key testKey) (*contracttest.Harness, *testMember) {
+	h, root := newTestMember(t, key)
+	require.NoError(t, h.Register(walletproxy.PrototypeReference, &wallet.Wallet{}, wallet.New))
+	require.NoError(t, h.Register(tokenwalletproxy.PrototypeReference, &tokenwallet.TokenWallet{}, tokenwallet.New))
+
+	var state rootdomain.RootDomain
+	require.NoError(t, h.State(root.rootDomain, &state))
+	state.RootMember = root.proxy.GetReference()
+	var data []byte
+	require.NoError(t, h.Serialize(&state, &data))
+	h.AM.Objects[root.rootDomain].Data = data
+	return h, root
+}
+
+func (m *testMember) createMember(rootKey testKey, name string, key testKey) *testMember {
+	res, err := m.callResult("CreateMember", []testKey{rootKey}, name, key.public)
+	require.NoError(m.t, err)
+	ref, err := core.NewRefFromBase58(res.(string))
+	require.NoError(m.t, err)
+	return &testMember{t: m.t, proxy: memberproxy.GetObject(*ref), rootDomain: m.rootDomain}
+}
+
+func TestMember_Registry(t *testing.T) {
+	rootKey, aliceKey, bobKey, newKey := newTestKey(t), newTestKey(t), newTestKey(t), newTestKey(t)
+	_, root := newTestRootMember(t, rootKey)
+
+	alice := root.createMember(rootKey, "alice", aliceKey)
+	bob := root.createMember(rootKey, "bob", bobKey)
+	aliceRef := alice.proxy.GetReference().String()
+	bobRef := bob.proxy.GetReference().String()
+
+	res, err := alice.callResult("GetMemberByName", []testKey{aliceKey}, "bob")
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{bobRef}, res)
+	_, err = alice.callResult("GetMemberByName", []testKey{aliceKey}, "carol")
+	require.Contains(t, err.Error(), "Member not found")
+
+	// names aren't unique
+	otherAlice := root.createMember(rootKey, "alice", newTestKey(t))
+	res, err = bob.callResult("GetMemberByName", []testKey{bobKey}, "alice")
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{aliceRef, otherAlice.proxy.GetReference().String()}, res)
+
+	res, err = bob.callResult("GetMemberByPublicKey", []testKey{bobKey}, aliceKey.public)
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{aliceRef}, res)
+
+	// index follows keys of members
+	require.NoError(t, alice.call("RotateKey", []testKey{aliceKey}, aliceKey.public, newKey.public, alice.keyProof(newKey)))
+	res, err = bob.callResult("GetMemberByPublicKey", []testKey{bobKey}, newKey.public)
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{aliceRef}, res)
+	_, err = bob.callResult("GetMemberByPublicKey", []testKey{bobKey}, aliceKey.public)
+	require.Contains(t, err.Error(), "Member not found")
+
+	require.NoError(t, bob.call("AddKey", []testKey{bobKey}, newKey.public, bob.keyProof(newKey)))
+	res, err = bob.callResult("GetMemberByPublicKey", []testKey{bobKey}, newKey.public)
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{aliceRef, bobRef}, res)
+	require.NoError(t, bob.call("RevokeKey", []testKey{bobKey}, newKey.public))
+	res, err = bob.callResult("GetMemberByPublicKey", []testKey{bobKey}, newKey.public)
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{aliceRef}, res)
+}
+
+func TestMember_ListMembers(t *testing.T) {
+	rootKey := newTestKey(t)
+	_, root := newTestRootMember(t, rootKey)
+	names := []string{"alice", "bob", "carol"}
+	var refs []string
+	for _, name := range names {
+		refs = append(refs, root.createMember(rootKey, name, newTestKey(t)).proxy.GetReference().String())
+	}
+
+	type record struct {
+		Reference string
+		Name      string
+	}
+	list := func(offset, limit uint) []record {
+		res, err := root.callResult("ListMembers", []testKey{rootKey}, offset, limit)
+		require.NoError(t, err)
+		var records []record
+		require.NoError(t, json.Unmarshal(res.([]byte), &records))
+		return records
+	}
+
+	require.Equal(t, []record{{refs[0], "alice"}, {refs[1], "bob"}, {refs[2], "carol"}}, list(0, 10))
+	require.Equal(t, []record{{refs[1], "bob"}}, list(1, 1))
+	require.Equal(t, []record{{refs[2], "carol"}}, list(2, 2))
+	require.Empty(t, list(3, 2))
+
+	err := root.call("ListMembers", []testKey{rootKey}, uint(0), uint(0))
+	require.Contains(t, err.Error(), "Limit must be from 1")
+	err = root.call("ListMembers", []testKey{rootKey}, uint(0), uint(rootdomain.MaxMembersLimit+1))
+	require.Contains(t, err.Error(), "Limit must be from 1")
+}
@@ -43,6 +43,8 @@ func (nd *NodeDomain) getNodeRecord(ref core.RecordRef) *noderecord.NodeRecord {
 	return noderecord.GetObject(ref)
 }
 
+var INSATTR_RegisterNode_Access = "root_member"
+
 // RegisterNode registers node in system
 func (nd *NodeDomain) RegisterNode(publicKey string, role string) (string, error) {
 
@@ -77,6 +77,8 @@ func (nr *NodeRecord) GetRole() (core.StaticRole, error) {
 	return nr.Record.Role, nil
 }
 
+var INSATTR_Destroy_Access = "parent"
+
 // Destroy makes request to destroy current node record
 func (nr *NodeRecord) Destroy() error {
 	nr.SelfDestruct()
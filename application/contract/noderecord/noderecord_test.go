@@ -19,7 +19,11 @@ package noderecord
 import (
 	"testing"
 
+	"github.com/insolar/insolar/application/contract/nodedomain"
+	nodedomainproxy "github.com/insolar/insolar/application/proxy/nodedomain"
+	noderecordproxy "github.com/insolar/insolar/application/proxy/noderecord"
 	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/logicrunner/goplugin/contracttest"
 	"github.com/stretchr/testify/require"
 )
 
@@ -68,3 +72,23 @@ func TestNodeRecord_GetRole(t *testing.T) {
 	r := core.GetStaticRoleFromString(TestRole)
 	require.Equal(t, r, role)
 }
+
+func TestNodeRecord_DestroyAccess(t *testing.T) {
+	h := contracttest.NewHarness()
+	require.NoError(t, h.Register(nodedomainproxy.PrototypeReference, &nodedomain.NodeDomain{}, nodedomain.NewNodeDomain))
+	require.NoError(t, h.Register(noderecordproxy.PrototypeReference, &NodeRecord{}, NewNodeRecord))
+	nd, err := nodedomainproxy.NewNodeDomain().AsChild(h.Root())
+	require.NoError(t, err)
+
+	_, err = nd.RegisterNode(TestPubKey, TestRole)
+	require.Contains(t, err.Error(), "access to method RegisterNode denied")
+
+	node, err := noderecordproxy.NewNodeRecord(TestPubKey, TestRole).AsChild(nd.GetReference())
+	require.NoError(t, err)
+	err = node.Destroy()
+	require.Contains(t, err.Error(), "access to method Destroy denied")
+	require.True(t, h.IsActive(node.GetReference()))
+
+	require.NoError(t, nd.RemoveNode(node.GetReference()))
+	require.False(t, h.IsActive(node.GetReference()))
+}
@@ -20,17 +20,100 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/insolar/insolar/application/proxy/asset"
 	"github.com/insolar/insolar/application/proxy/member"
+	"github.com/insolar/insolar/application/proxy/tokenwallet"
 	"github.com/insolar/insolar/application/proxy/wallet"
 	"github.com/insolar/insolar/core"
 	"github.com/insolar/insolar/logicrunner/goplugin/foundation"
 )
 
+// MaxMembersLimit is a maximum number of members returned by ListMembers at once.
+const MaxMembersLimit = 100
+
+// MemberRecord is an entry of members list of RootDomain
+type MemberRecord struct {
+	Reference core.RecordRef
+	Name      string
+}
+
 // RootDomain is smart contract representing entrance point to system
 type RootDomain struct {
 	foundation.BaseContract
 	RootMember    core.RecordRef
 	NodeDomainRef core.RecordRef
+	// Assets are references to assets by name
+	Assets map[string]core.RecordRef
+	// MemberIndexName holds references to members by name, names aren't unique
+	MemberIndexName *foundation.PagedMap
+	// MemberIndexPK holds references to members by every key of member
+	MemberIndexPK *foundation.PagedMap
+	// Members holds records of members created by CreateMember by zero padded index in order of creation
+	Members *foundation.PagedMap
+}
+
+func memberKey(i uint) string {
+	return fmt.Sprintf("%020d", i)
+}
+
+func indexOf(refs []string, ref string) int {
+	for i, r := range refs {
+		if r == ref {
+			return i
+		}
+	}
+	return -1
+}
+
+// initIndexes creates member indexes of root domain deployed before they were added
+func (rd *RootDomain) initIndexes() {
+	if rd.Members == nil {
+		rd.Members = foundation.NewPagedMap(0)
+	}
+	if rd.MemberIndexName == nil {
+		rd.MemberIndexName = foundation.NewPagedMap(0)
+	}
+	if rd.MemberIndexPK == nil {
+		rd.MemberIndexPK = foundation.NewPagedMap(0)
+	}
+}
+
+// getFromIndex returns refs of key, false is returned if there are none
+func getFromIndex(index *foundation.PagedMap, key string) ([]string, bool, error) {
+	if index == nil {
+		return nil, false, nil
+	}
+	var refs []string
+	found, err := index.Get(key, &refs)
+	return refs, found, err
+}
+
+// addToIndex adds ref to refs of key if it's not there yet
+func addToIndex(index *foundation.PagedMap, key string, ref string) error {
+	refs, _, err := getFromIndex(index, key)
+	if err != nil {
+		return err
+	}
+	if indexOf(refs, ref) >= 0 {
+		return nil
+	}
+	return index.Set(key, append(refs, ref))
+}
+
+// removeFromIndex removes ref from refs of key
+func removeFromIndex(index *foundation.PagedMap, key string, ref string) error {
+	refs, _, err := getFromIndex(index, key)
+	if err != nil {
+		return err
+	}
+	i := indexOf(refs, ref)
+	if i < 0 {
+		return nil
+	}
+	if len(refs) == 1 {
+		return index.Delete(key)
+	}
+	return index.Set(key, append(refs[:i], refs[i+1:]...))
 }
 
 // CreateMember processes create member request
@@ -50,7 +133,96 @@ func (rd *RootDomain) CreateMember(name string, key string) (string, error) {
 		return "", fmt.Errorf("[ CreateMember ] Can't save as delegate: %s", err.Error())
 	}
 
-	return m.GetReference().String(), nil
+	twHolder := tokenwallet.New()
+	_, err = twHolder.AsDelegate(m.GetReference())
+	if err != nil {
+		return "", fmt.Errorf("[ CreateMember ] Can't save token wallet as delegate: %s", err.Error())
+	}
+
+	ref := m.GetReference().String()
+	rd.initIndexes()
+	err = rd.Members.Set(memberKey(uint(rd.Members.Len())), MemberRecord{Reference: m.GetReference(), Name: name})
+	if err != nil {
+		return "", fmt.Errorf("[ CreateMember ] Can't add member to list: %s", err.Error())
+	}
+	if err := addToIndex(rd.MemberIndexName, name, ref); err != nil {
+		return "", fmt.Errorf("[ CreateMember ] Can't index member name: %s", err.Error())
+	}
+	if err := addToIndex(rd.MemberIndexPK, key, ref); err != nil {
+		return "", fmt.Errorf("[ CreateMember ] Can't index member key: %s", err.Error())
+	}
+
+	return ref, nil
+}
+
+// GetMemberByName returns references of members with name in order they were created
+func (rd *RootDomain) GetMemberByName(name string) ([]string, error) {
+	refs, ok, err := getFromIndex(rd.MemberIndexName, name)
+	if err != nil {
+		return nil, fmt.Errorf("[ GetMemberByName ] Can't get index: %s", err.Error())
+	}
+	if !ok {
+		return nil, fmt.Errorf("[ GetMemberByName ] Member not found by name: %s", name)
+	}
+	return refs, nil
+}
+
+// GetMemberByPublicKey returns references of members with public key among their keys
+func (rd *RootDomain) GetMemberByPublicKey(publicKey string) ([]string, error) {
+	refs, ok, err := getFromIndex(rd.MemberIndexPK, publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("[ GetMemberByPublicKey ] Can't get index: %s", err.Error())
+	}
+	if !ok {
+		return nil, fmt.Errorf("[ GetMemberByPublicKey ] Member not found by PK: %s", publicKey)
+	}
+	return refs, nil
+}
+
+// IndexMemberKey adds key of calling member to index
+func (rd *RootDomain) IndexMemberKey(publicKey string) error {
+	if !rd.GetContext().CallerPrototype.Equal(member.GetPrototype()) {
+		return fmt.Errorf("[ IndexMemberKey ] Only members can index keys")
+	}
+	rd.initIndexes()
+	if err := addToIndex(rd.MemberIndexPK, publicKey, rd.GetContext().Caller.String()); err != nil {
+		return fmt.Errorf("[ IndexMemberKey ] Can't index key: %s", err.Error())
+	}
+	return nil
+}
+
+// UnindexMemberKey removes key of calling member from index
+func (rd *RootDomain) UnindexMemberKey(publicKey string) error {
+	if !rd.GetContext().CallerPrototype.Equal(member.GetPrototype()) {
+		return fmt.Errorf("[ UnindexMemberKey ] Only members can unindex keys")
+	}
+	if err := removeFromIndex(rd.MemberIndexPK, publicKey, rd.GetContext().Caller.String()); err != nil {
+		return fmt.Errorf("[ UnindexMemberKey ] Can't unindex key: %s", err.Error())
+	}
+	return nil
+}
+
+// ListMembers returns up to limit records of members starting from offset in order members were created
+func (rd *RootDomain) ListMembers(offset uint, limit uint) ([]MemberRecord, error) {
+	if limit == 0 || limit > MaxMembersLimit {
+		return nil, fmt.Errorf("[ ListMembers ] Limit must be from 1 to %d", MaxMembersLimit)
+	}
+	res := []MemberRecord{}
+	if rd.Members == nil {
+		return res, nil
+	}
+	for i := offset; i < uint(rd.Members.Len()) && uint(len(res)) < limit; i++ {
+		var record MemberRecord
+		found, err := rd.Members.Get(memberKey(i), &record)
+		if err != nil {
+			return nil, fmt.Errorf("[ ListMembers ] Can't get member: %s", err.Error())
+		}
+		if !found {
+			return nil, fmt.Errorf("[ ListMembers ] Member %d not found", i)
+		}
+		res = append(res, record)
+	}
+	return res, nil
 }
 
 // GetRootMemberRef returns root member's reference
@@ -99,6 +271,8 @@ func (rd *RootDomain) DumpUserInfo(reference string) ([]byte, error) {
 	return json.Marshal(res)
 }
 
+var INSATTR_DumpAllUsers_Access = "root_member"
+
 // DumpAllUsers processes dump all users request
 func (rd *RootDomain) DumpAllUsers() ([]byte, error) {
 	if *rd.GetContext().Caller != rd.RootMember {
@@ -150,7 +324,42 @@ func (rd *RootDomain) GetNodeDomainRef() (core.RecordRef, error) {
 	return rd.NodeDomainRef, nil
 }
 
+// CreateAsset creates asset issued by calling member, names of assets are unique
+func (rd *RootDomain) CreateAsset(name string, decimals uint, supply uint) (core.RecordRef, error) {
+	if !rd.GetContext().CallerPrototype.Equal(member.GetPrototype()) {
+		return core.RecordRef{}, fmt.Errorf("[ CreateAsset ] Only members can create assets")
+	}
+	if _, ok := rd.Assets[name]; ok {
+		return core.RecordRef{}, fmt.Errorf("[ CreateAsset ] Asset %s already exists", name)
+	}
+
+	aHolder := asset.New(name, decimals, supply, *rd.GetContext().Caller)
+	a, err := aHolder.AsChild(rd.GetReference())
+	if err != nil {
+		return core.RecordRef{}, fmt.Errorf("[ CreateAsset ] Can't save as child: %s", err.Error())
+	}
+
+	if rd.Assets == nil {
+		rd.Assets = map[string]core.RecordRef{}
+	}
+	rd.Assets[name] = a.GetReference()
+	return a.GetReference(), nil
+}
+
+// GetAssetRef returns reference of asset with name
+func (rd *RootDomain) GetAssetRef(name string) (core.RecordRef, error) {
+	ref, ok := rd.Assets[name]
+	if !ok {
+		return core.RecordRef{}, fmt.Errorf("[ GetAssetRef ] Asset %s not found", name)
+	}
+	return ref, nil
+}
+
 // NewRootDomain creates new RootDomain
 func NewRootDomain() (*RootDomain, error) {
-	return &RootDomain{}, nil
+	return &RootDomain{
+		MemberIndexName: foundation.NewPagedMap(0),
+		MemberIndexPK:   foundation.NewPagedMap(0),
+		Members:         foundation.NewPagedMap(0),
+	}, nil
 }
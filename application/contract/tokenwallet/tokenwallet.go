@@ -0,0 +1,400 @@
+/*
+ *    Copyright 2019 Insolar Technologies
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package tokenwallet
+
+import (
+	"fmt"
+
+	"github.com/insolar/insolar/application/contract/wallet/safemath"
+	"github.com/insolar/insolar/application/proxy/allowance"
+	"github.com/insolar/insolar/application/proxy/asset"
+	"github.com/insolar/insolar/application/proxy/tokenwallet"
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/logicrunner/goplugin/foundation"
+)
+
+// Kinds of transfer records
+const (
+	KindIssue        = "issue"
+	KindSend         = "send"
+	KindReceive      = "receive"
+	KindApprove      = "approve"
+	KindTransferFrom = "transferFrom"
+	KindRefund       = "refund"
+)
+
+const (
+	// transferTimeout is a number of seconds recipient has to accept transfer, amount is refunded after that.
+	transferTimeout = 10
+	// MaxLogLimit is a maximum number of transfer records returned at once.
+	MaxLogLimit = 100
+)
+
+// TransferRecord is an entry of transfer log of token wallet
+type TransferRecord struct {
+	Kind   string
+	Asset  string
+	Amount uint
+	// Counterparty is a member on the other side of transfer, asset for issue and allowance for refund.
+	Counterparty core.RecordRef
+	Pulse        core.PulseNumber
+}
+
+// AssetBalance is a balance of asset
+type AssetBalance struct {
+	Asset  string
+	Amount uint
+}
+
+// TokenWallet is a delegate of member holding balances of assets. Amounts are moved between token wallets through
+// allowances, allowance is a child of sender taken by recipient. Allowances without expiration are made on approve,
+// spender takes amount from them with TransferFrom.
+type TokenWallet struct {
+	foundation.BaseContract
+	Balances map[string]uint
+	// Assets are names of assets in order they came to wallet, maps must not be iterated in contracts.
+	Assets []string
+	// Allowances are approved allowances by asset and spender token wallet.
+	Allowances map[string]core.RecordRef
+	// Log holds transfer records by zero padded index.
+	Log *foundation.PagedMap
+}
+
+// New creates empty token wallet
+func New() (*TokenWallet, error) {
+	return &TokenWallet{
+		Balances:   map[string]uint{},
+		Allowances: map[string]core.RecordRef{},
+		Log:        foundation.NewPagedMap(0),
+	}, nil
+}
+
+func allowanceKey(asset string, spender core.RecordRef) string {
+	return asset + "/" + spender.String()
+}
+
+func logKey(i uint) string {
+	return fmt.Sprintf("%020d", i)
+}
+
+// member returns reference to member owning wallet
+func (w *TokenWallet) member() core.RecordRef {
+	return *w.GetContext().Parent
+}
+
+func (w *TokenWallet) checkOwner(method string) error {
+	if *w.GetContext().Caller != w.member() {
+		return fmt.Errorf("[ %s ] Only owner can call this method", method)
+	}
+	return nil
+}
+
+func (w *TokenWallet) log(kind string, asset string, amount uint, counterparty core.RecordRef) error {
+	return w.Log.Set(logKey(uint(w.Log.Len())), TransferRecord{
+		Kind:         kind,
+		Asset:        asset,
+		Amount:       amount,
+		Counterparty: counterparty,
+		Pulse:        w.GetContext().Pulse.PulseNumber,
+	})
+}
+
+func (w *TokenWallet) credit(asset string, amount uint) error {
+	balance, err := safemath.Add(w.Balances[asset], amount)
+	if err != nil {
+		return err
+	}
+	if w.Balances == nil {
+		w.Balances = map[string]uint{}
+	}
+	if _, ok := w.Balances[asset]; !ok {
+		w.Assets = append(w.Assets, asset)
+	}
+	w.Balances[asset] = balance
+	return nil
+}
+
+// refund takes amounts of expired transfers back to balance
+func (w *TokenWallet) refund() error {
+	iterator, err := w.NewChildrenTypedIterator(allowance.GetPrototype())
+	if err != nil {
+		return fmt.Errorf("[ refund ] Can't get children: %s", err.Error())
+	}
+
+	for iterator.HasNext() {
+		cref, err := iterator.Next()
+		if err != nil {
+			return fmt.Errorf("[ refund ] Can't get next child: %s", err.Error())
+		}
+		if cref.IsEmpty() {
+			continue
+		}
+
+		a := allowance.GetObject(cref)
+		// taken and revoked allowances are skipped
+		asset, err := a.GetAsset()
+		if err != nil {
+			continue
+		}
+		amount, err := a.GetExpiredBalance()
+		if err != nil || amount == 0 {
+			continue
+		}
+		if err := w.credit(asset, amount); err != nil {
+			return fmt.Errorf("[ refund ] Couldn't add refund to balance: %s", err.Error())
+		}
+		if err := w.log(KindRefund, asset, amount, cref); err != nil {
+			return fmt.Errorf("[ refund ] Can't log transfer: %s", err.Error())
+		}
+	}
+	return nil
+}
+
+func recipient(to core.RecordRef) (*tokenwallet.TokenWallet, error) {
+	w, err := tokenwallet.GetImplementationFrom(to)
+	if err != nil {
+		return nil, fmt.Errorf("Recipient has no token wallet: %s", err.Error())
+	}
+	return w, nil
+}
+
+// send makes allowance for recipient token wallet, it takes amount without waiting
+func (w *TokenWallet) send(asset string, amount uint, toWallet *tokenwallet.TokenWallet) error {
+	toWalletRef := toWallet.GetReference()
+	expire := w.GetContext().Time.Unix() + transferTimeout
+	ah := allowance.NewAssetAllowance(&toWalletRef, asset, amount, expire, w.member())
+	a, err := ah.AsChild(w.GetReference())
+	if err != nil {
+		return fmt.Errorf("Can't save as child: %s", err.Error())
+	}
+
+	r := a.GetReference()
+	return toWallet.AcceptNoWait(&r)
+}
+
+// Issue takes whole supply of asset issued by owner
+func (w *TokenWallet) Issue(assetRef *core.RecordRef) error {
+	if err := w.checkOwner("Issue"); err != nil {
+		return err
+	}
+	a := asset.GetObject(*assetRef)
+	name, err := a.GetName()
+	if err != nil {
+		return fmt.Errorf("[ Issue ] Can't get name of asset: %s", err.Error())
+	}
+	supply, err := a.Issue()
+	if err != nil {
+		return fmt.Errorf("[ Issue ] Can't issue asset: %s", err.Error())
+	}
+	if err := w.credit(name, supply); err != nil {
+		return fmt.Errorf("[ Issue ] Couldn't add supply to balance: %s", err.Error())
+	}
+	return w.log(KindIssue, name, supply, *assetRef)
+}
+
+// Transfer transfers amount of asset to token wallet of member
+func (w *TokenWallet) Transfer(asset string, amount uint, to *core.RecordRef) error {
+	if err := w.checkOwner("Transfer"); err != nil {
+		return err
+	}
+	if amount == 0 {
+		return fmt.Errorf("[ Transfer ] Amount must be positive")
+	}
+	if *to == w.member() {
+		return fmt.Errorf("[ Transfer ] Recipient must be different from the sender")
+	}
+	toWallet, err := recipient(*to)
+	if err != nil {
+		return fmt.Errorf("[ Transfer ] %s", err.Error())
+	}
+	if w.Balances[asset] < amount {
+		if err := w.refund(); err != nil {
+			return fmt.Errorf("[ Transfer ] %s", err.Error())
+		}
+		if w.Balances[asset] < amount {
+			return fmt.Errorf("[ Transfer ] Not enough balance for transfer")
+		}
+	}
+
+	if err := w.send(asset, amount, toWallet); err != nil {
+		return fmt.Errorf("[ Transfer ] %s", err.Error())
+	}
+	// Changing balance only after allowance was successfully created
+	w.Balances[asset] -= amount
+	return w.log(KindSend, asset, amount, *to)
+}
+
+// Accept transforms allowance to balance
+func (w *TokenWallet) Accept(aRef *core.RecordRef) error {
+	a := allowance.GetObject(*aRef)
+	asset, err := a.GetAsset()
+	if err != nil {
+		return fmt.Errorf("[ Accept ] Can't get asset: %s", err.Error())
+	}
+	from, err := a.GetFrom()
+	if err != nil {
+		return fmt.Errorf("[ Accept ] Can't get sender: %s", err.Error())
+	}
+	amount, err := a.TakeAmount()
+	if err != nil {
+		return fmt.Errorf("[ Accept ] Can't take amount: %s", err.Error())
+	}
+	if err := w.credit(asset, amount); err != nil {
+		return fmt.Errorf("[ Accept ] Couldn't add amount to balance: %s", err.Error())
+	}
+	return w.log(KindReceive, asset, amount, from)
+}
+
+// Approve allows member `spender` to transfer up to amount of asset from this wallet, amount is reserved until
+// it is spent or approve is called again, zero amount revokes approval
+func (w *TokenWallet) Approve(asset string, spender *core.RecordRef, amount uint) error {
+	if err := w.checkOwner("Approve"); err != nil {
+		return err
+	}
+	if *spender == w.member() {
+		return fmt.Errorf("[ Approve ] Spender must be different from the owner")
+	}
+	spenderWallet, err := tokenwallet.GetImplementationFrom(*spender)
+	if err != nil {
+		return fmt.Errorf("[ Approve ] Spender has no token wallet: %s", err.Error())
+	}
+	spenderWalletRef := spenderWallet.GetReference()
+	key := allowanceKey(asset, spenderWalletRef)
+
+	if ref, ok := w.Allowances[key]; ok {
+		rest, err := allowance.GetObject(ref).Revoke()
+		if err != nil {
+			return fmt.Errorf("[ Approve ] Can't revoke previous allowance: %s", err.Error())
+		}
+		if err := w.credit(asset, rest); err != nil {
+			return fmt.Errorf("[ Approve ] Couldn't add revoked amount to balance: %s", err.Error())
+		}
+		delete(w.Allowances, key)
+	}
+
+	if amount > 0 {
+		if w.Balances[asset] < amount {
+			return fmt.Errorf("[ Approve ] Not enough balance for approve")
+		}
+		ah := allowance.NewAssetAllowance(&spenderWalletRef, asset, amount, 0, w.member())
+		a, err := ah.AsChild(w.GetReference())
+		if err != nil {
+			return fmt.Errorf("[ Approve ] Can't save as child: %s", err.Error())
+		}
+		w.Balances[asset] -= amount
+		if w.Allowances == nil {
+			w.Allowances = map[string]core.RecordRef{}
+		}
+		w.Allowances[key] = a.GetReference()
+	}
+	return w.log(KindApprove, asset, amount, *spender)
+}
+
+// GetAllowance returns amount of asset member `spender` is allowed to transfer from this wallet
+func (w *TokenWallet) GetAllowance(asset string, spender *core.RecordRef) (uint, error) {
+	spenderWallet, err := tokenwallet.GetImplementationFrom(*spender)
+	if err != nil {
+		return 0, nil
+	}
+	ref, ok := w.Allowances[allowanceKey(asset, spenderWallet.GetReference())]
+	if !ok {
+		return 0, nil
+	}
+	return allowance.GetObject(ref).GetBalanceForOwner()
+}
+
+// TransferFrom transfers amount of asset approved by member `owner` to token wallet of member `to`
+func (w *TokenWallet) TransferFrom(asset string, owner *core.RecordRef, to *core.RecordRef, amount uint) error {
+	if err := w.checkOwner("TransferFrom"); err != nil {
+		return err
+	}
+	ownerWallet, err := tokenwallet.GetImplementationFrom(*owner)
+	if err != nil {
+		return fmt.Errorf("[ TransferFrom ] Owner has no token wallet: %s", err.Error())
+	}
+	return ownerWallet.SpendAllowance(asset, to, amount)
+}
+
+// SpendAllowance transfers amount of asset to token wallet of member `to` from allowance approved for caller
+func (w *TokenWallet) SpendAllowance(asset string, to *core.RecordRef, amount uint) error {
+	if amount == 0 {
+		return fmt.Errorf("[ SpendAllowance ] Amount must be positive")
+	}
+	ref, ok := w.Allowances[allowanceKey(asset, *w.GetContext().Caller)]
+	if !ok {
+		return fmt.Errorf("[ SpendAllowance ] Transfer is not approved")
+	}
+	var toWallet *tokenwallet.TokenWallet
+	if *to != w.member() {
+		var err error
+		toWallet, err = recipient(*to)
+		if err != nil {
+			return fmt.Errorf("[ SpendAllowance ] %s", err.Error())
+		}
+	}
+	if err := allowance.GetObject(ref).Spend(amount); err != nil {
+		return fmt.Errorf("[ SpendAllowance ] Can't spend allowance: %s", err.Error())
+	}
+
+	if toWallet == nil {
+		if err := w.credit(asset, amount); err != nil {
+			return fmt.Errorf("[ SpendAllowance ] Couldn't add amount to balance: %s", err.Error())
+		}
+	} else if err := w.send(asset, amount, toWallet); err != nil {
+		return fmt.Errorf("[ SpendAllowance ] %s", err.Error())
+	}
+	return w.log(KindTransferFrom, asset, amount, *to)
+}
+
+// GetBalance returns balance of asset
+func (w *TokenWallet) GetBalance(asset string) (uint, error) {
+	return w.Balances[asset], nil
+}
+
+// GetBalances returns balances of all assets that came to wallet
+func (w *TokenWallet) GetBalances() ([]AssetBalance, error) {
+	res := make([]AssetBalance, len(w.Assets))
+	for i, asset := range w.Assets {
+		res[i] = AssetBalance{Asset: asset, Amount: w.Balances[asset]}
+	}
+	return res, nil
+}
+
+// GetLogSize returns number of transfer records
+func (w *TokenWallet) GetLogSize() (uint, error) {
+	return uint(w.Log.Len()), nil
+}
+
+// GetLog returns up to limit transfer records starting from offset in order of transfers
+func (w *TokenWallet) GetLog(offset uint, limit uint) ([]TransferRecord, error) {
+	if limit == 0 || limit > MaxLogLimit {
+		return nil, fmt.Errorf("[ GetLog ] Limit must be from 1 to %d", MaxLogLimit)
+	}
+	res := []TransferRecord{}
+	for i := offset; i < uint(w.Log.Len()) && uint(len(res)) < limit; i++ {
+		var record TransferRecord
+		found, err := w.Log.Get(logKey(i), &record)
+		if err != nil {
+			return nil, fmt.Errorf("[ GetLog ] Can't get record: %s", err.Error())
+		}
+		if !found {
+			return nil, fmt.Errorf("[ GetLog ] Record %d not found", i)
+		}
+		res = append(res, record)
+	}
+	return res, nil
+}
@@ -0,0 +1,84 @@
+/*
+ *    Copyright 2019 Insolar Technologies
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package safemath
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// MaxDecimals is a maximum number of fractional digits of fixed-point amounts, 10^MaxDecimals fits into uint64.
+const MaxDecimals = 18
+
+// ParseFixed parses decimal string like "12.05" into integer amount of minimal units with given number of
+// fractional digits, errors on overflow and on more fractional digits than decimals.
+func ParseFixed(s string, decimals uint) (uint, error) {
+	if decimals > MaxDecimals {
+		return 0, errors.New("too many decimals")
+	}
+
+	intPart, fracPart := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		intPart, fracPart = s[:i], s[i+1:]
+		if len(fracPart) == 0 {
+			return 0, errors.New("missing fractional part")
+		}
+	}
+	if len(intPart) == 0 {
+		return 0, errors.New("missing integer part")
+	}
+	if uint(len(fracPart)) > decimals {
+		return 0, errors.New("too many fractional digits")
+	}
+
+	res := uint(0)
+	digits := intPart + fracPart + strings.Repeat("0", int(decimals)-len(fracPart))
+	for _, c := range digits {
+		if c < '0' || c > '9' {
+			return 0, errors.New("invalid digit")
+		}
+		var err error
+		res, err = Mul(res, 10)
+		if err != nil {
+			return 0, err
+		}
+		res, err = Add(res, uint(c-'0'))
+		if err != nil {
+			return 0, err
+		}
+	}
+	return res, nil
+}
+
+// FormatFixed formats integer amount of minimal units as decimal string with given number of fractional digits,
+// trailing zeros of fractional part are omitted.
+func FormatFixed(v uint, decimals uint) string {
+	s := strconv.FormatUint(uint64(v), 10)
+	if decimals == 0 {
+		return s
+	}
+	if uint(len(s)) <= decimals {
+		s = strings.Repeat("0", int(decimals)-len(s)+1) + s
+	}
+	point := uint(len(s)) - decimals
+	frac := strings.TrimRight(s[point:], "0")
+	if len(frac) == 0 {
+		return s[:point]
+	}
+	return s[:point] + "." + frac
+}
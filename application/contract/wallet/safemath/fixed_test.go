@@ -0,0 +1,81 @@
+/*
+ *    Copyright 2019 Insolar Technologies
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package safemath
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFixed(t *testing.T) {
+	for _, tc := range []struct {
+		s        string
+		decimals uint
+		res      uint
+	}{
+		{"0", 0, 0},
+		{"12", 0, 12},
+		{"12", 2, 1200},
+		{"12.5", 2, 1250},
+		{"12.05", 2, 1205},
+		{"0.01", 2, 1},
+		{"007.10", 3, 7100},
+		{"18446744073709551615", 0, 18446744073709551615},
+		{"18.446744073709551615", 18, 18446744073709551615},
+	} {
+		res, err := ParseFixed(tc.s, tc.decimals)
+		require.NoError(t, err, tc.s)
+		require.Equal(t, tc.res, res, tc.s)
+	}
+
+	for _, tc := range []struct {
+		s        string
+		decimals uint
+	}{
+		{"", 2},
+		{".5", 2},
+		{"5.", 2},
+		{"-5", 2},
+		{"1.5", 0},
+		{"1.005", 2},
+		{"1e5", 2},
+		{"1.2.3", 2},
+		{"18446744073709551616", 0},
+		{"184467440737095516.16", 2},
+		{"1", MaxDecimals + 1},
+	} {
+		_, err := ParseFixed(tc.s, tc.decimals)
+		require.Error(t, err, tc.s)
+	}
+}
+
+func TestFormatFixed(t *testing.T) {
+	require.Equal(t, "12", FormatFixed(12, 0))
+	require.Equal(t, "12", FormatFixed(1200, 2))
+	require.Equal(t, "12.5", FormatFixed(1250, 2))
+	require.Equal(t, "12.05", FormatFixed(1205, 2))
+	require.Equal(t, "0.01", FormatFixed(1, 2))
+	require.Equal(t, "0", FormatFixed(0, 2))
+	require.Equal(t, "0.000000000000000001", FormatFixed(1, MaxDecimals))
+
+	for _, v := range []uint{0, 1, 10, 1205, 18446744073709551615} {
+		res, err := ParseFixed(FormatFixed(v, 6), 6)
+		require.NoError(t, err)
+		require.Equal(t, v, res)
+	}
+}
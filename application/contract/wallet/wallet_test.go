@@ -0,0 +1,86 @@
+/*
+ *    Copyright 2019 Insolar Technologies
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package wallet
+
+import (
+	"testing"
+
+	"github.com/insolar/insolar/application/contract/allowance"
+	"github.com/insolar/insolar/application/contract/member"
+	allowanceproxy "github.com/insolar/insolar/application/proxy/allowance"
+	memberproxy "github.com/insolar/insolar/application/proxy/member"
+	walletproxy "github.com/insolar/insolar/application/proxy/wallet"
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/logicrunner/goplugin/contracttest"
+	"github.com/stretchr/testify/require"
+)
+
+func newHarness(t *testing.T) *contracttest.Harness {
+	h := contracttest.NewHarness()
+	require.NoError(t, h.Register(memberproxy.PrototypeReference, &member.Member{}, member.New))
+	require.NoError(t, h.Register(walletproxy.PrototypeReference, &Wallet{}, New))
+	require.NoError(t, h.Register(allowanceproxy.PrototypeReference, &allowance.Allowance{}, allowance.New))
+	return h
+}
+
+func newMemberWallet(t *testing.T, h *contracttest.Harness, name string, balance uint) (core.RecordRef, *walletproxy.Wallet) {
+	m, err := memberproxy.New(name, "").AsChild(h.Root())
+	require.NoError(t, err)
+	w, err := walletproxy.New(balance).AsDelegate(m.GetReference())
+	require.NoError(t, err)
+	return m.GetReference(), w
+}
+
+func TestWallet_Transfer(t *testing.T) {
+	h := newHarness(t)
+	_, from := newMemberWallet(t, h, "from", 1000)
+	toMember, to := newMemberWallet(t, h, "to", 100)
+
+	h.NextPulse(1)
+	err := from.Transfer(300, &toMember)
+	require.NoError(t, err)
+	require.Empty(t, h.NoWaitErrors())
+
+	var state Wallet
+	require.NoError(t, h.State(from.GetReference(), &state))
+	require.Equal(t, uint(700), state.Balance)
+	require.NoError(t, h.State(to.GetReference(), &state))
+	require.Equal(t, uint(400), state.Balance)
+
+	// allowance is taken by the receiver and deactivated
+	require.Empty(t, h.Children(from.GetReference()))
+
+	balance, err := to.GetBalance()
+	require.NoError(t, err)
+	require.Equal(t, uint(400), balance)
+}
+
+func TestWallet_Transfer_NotEnoughBalance(t *testing.T) {
+	h := newHarness(t)
+	_, from := newMemberWallet(t, h, "from", 10)
+	toMember, to := newMemberWallet(t, h, "to", 0)
+
+	err := from.Transfer(300, &toMember)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Not enough balance")
+
+	var state Wallet
+	require.NoError(t, h.State(from.GetReference(), &state))
+	require.Equal(t, uint(10), state.Balance)
+	require.NoError(t, h.State(to.GetReference(), &state))
+	require.Equal(t, uint(0), state.Balance)
+}
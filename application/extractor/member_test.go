@@ -62,6 +62,28 @@ func TestPublicKeyResponse_UnmarshalError(t *testing.T) {
 	require.Equal(t, "", result)
 }
 
+func TestPublicKeysResponse(t *testing.T) {
+	testValue := []string{"first_key", "second_key"}
+
+	data, err := core.Serialize([]interface{}{testValue, nil})
+	require.NoError(t, err)
+
+	result, err := PublicKeysResponse(data)
+
+	require.NoError(t, err)
+	require.Equal(t, testValue, result)
+}
+
+func TestThresholdResponse(t *testing.T) {
+	data, err := core.Serialize([]interface{}{uint(2), nil})
+	require.NoError(t, err)
+
+	result, err := ThresholdResponse(data)
+
+	require.NoError(t, err)
+	require.Equal(t, uint(2), result)
+}
+
 func TestCallResponse(t *testing.T) {
 	testValue := map[interface{}]interface{}{
 		"string_value": "test_string",
@@ -17,6 +17,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"sync"
 
@@ -25,7 +26,7 @@ import (
 
 func oneSimpleRequest(insSDK *sdk.SDK) {
 	fmt.Println("Try to create new member:")
-	m, traceID, err := insSDK.CreateMember()
+	m, traceID, err := insSDK.CreateMember(context.Background())
 	check("Can not create member, error: ", err)
 	fmt.Println("Success! New member ref: ", m.Reference, ". TraceId: ", traceID)
 	fmt.Print("oneSimpleRequest done just fine\n\n")
@@ -34,7 +35,7 @@ func oneSimpleRequest(insSDK *sdk.SDK) {
 func severalSimpleRequestToRootMember(insSDK *sdk.SDK) {
 	fmt.Println("Try to create several new members:")
 	for i := 0; i < 10; i++ {
-		m, traceID, err := insSDK.CreateMember()
+		m, traceID, err := insSDK.CreateMember(context.Background())
 		check("Can not create member, error: ", err)
 		fmt.Println("Success! New member ref: ", m.Reference, ". TraceId: ", traceID)
 	}
@@ -46,14 +47,14 @@ func severalSimpleRequestToDifferentMembers(insSDK *sdk.SDK) {
 	fmt.Println("Creating some members for transfer ...")
 	var members []*sdk.Member
 	for i := 0; i < 20; i++ {
-		m, traceID, err := insSDK.CreateMember()
+		m, traceID, err := insSDK.CreateMember(context.Background())
 		check("Can not create member, error: ", err)
 		members = append(members, m)
 		fmt.Println("Success! New member ref: ", m.Reference, ". TraceId: ", traceID)
 	}
 
 	for i := 0; i < 10; i++ {
-		traceID, err := insSDK.Transfer(1, members[i], members[i+10])
+		traceID, err := insSDK.Transfer(context.Background(), 1, members[i], members[i+10])
 		check("Can not transfer money, error: ", err)
 		fmt.Println("Transfer success. TraceId: ", traceID)
 	}
@@ -67,7 +68,7 @@ func severalParallelRequestToRootMember(insSDK *sdk.SDK) {
 	for i := 0; i < 10; i++ {
 		go func(i int) {
 			defer wg.Done()
-			m, traceID, err := insSDK.CreateMember()
+			m, traceID, err := insSDK.CreateMember(context.Background())
 			check("Can not create member, error: ", err)
 			fmt.Println("Success! New member ref: ", m.Reference, ". TraceId: ", traceID)
 		}(i)
@@ -81,7 +82,7 @@ func severalParallelRequestToDifferentMembers(insSDK *sdk.SDK) {
 	fmt.Println("Creating some members for transfer ...")
 	var members []*sdk.Member
 	for i := 0; i < 20; i++ {
-		m, traceID, err := insSDK.CreateMember()
+		m, traceID, err := insSDK.CreateMember(context.Background())
 		check("Can not create member, error: ", err)
 		fmt.Println("Success! New member ref: ", m.Reference, ". TraceId: ", traceID)
 		members = append(members, m)
@@ -91,7 +92,7 @@ func severalParallelRequestToDifferentMembers(insSDK *sdk.SDK) {
 	for i := 0; i < 10; i++ {
 		go func(i int) {
 			defer wg.Done()
-			traceID, err := insSDK.Transfer(1, members[i], members[i+10])
+			traceID, err := insSDK.Transfer(context.Background(), 1, members[i], members[i+10])
 			check("Can not transfer money, error: ", err)
 			fmt.Println("Transfer success. TraceId: ", traceID)
 		}(i)
@@ -138,7 +138,7 @@ func createMembers(insSDK *sdk.SDK, count int) []*sdk.Member {
 
 	for i := 0; i < count; i++ {
 		for j := 0; j < numRetries; j++ {
-			member, traceID, err = insSDK.CreateMember()
+			member, traceID, err = insSDK.CreateMember(context.Background())
 			if err == nil {
 				members = append(members, member)
 				break
@@ -169,7 +169,7 @@ func getTotalBalance(insSDK *sdk.SDK, members []*sdk.Member) uint64 {
 		go func(m *sdk.Member, num int) {
 			res := Result{num: num}
 			for attempt := 0; attempt < 3; attempt++ {
-				res.balance, res.err = insSDK.GetBalance(m)
+				res.balance, res.err = insSDK.GetBalance(context.Background(), m)
 				if res.err == nil {
 					break
 				}
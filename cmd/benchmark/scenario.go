@@ -117,7 +117,7 @@ func (s *transferDifferentMembersScenario) startMember(ctx context.Context, inde
 		to := s.members[index+1]
 
 		start := time.Now()
-		traceID, err := s.insSDK.Transfer(1, from, to)
+		traceID, err := s.insSDK.Transfer(ctx, 1, from, to)
 		stop := time.Since(start)
 
 		if err == nil {
@@ -170,9 +170,33 @@ func main() {
 	}
 	cmdImports.Flags().VarP(output, "output", "o", "output file (use - for STDOUT)")
 
+	var cmdABI = &cobra.Command{
+		Use:   "abi [flags] <file name to process>",
+		Short: "Generate contract's ABI in JSON",
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) != 1 {
+				fmt.Println("abi command should be followed by exactly one file name to process")
+				os.Exit(1)
+			}
+			parsed, err := preprocessor.ParseFile(args[0])
+			if err != nil {
+				fmt.Println(errors.Wrap(err, "couldn't parse"))
+				os.Exit(1)
+			}
+
+			err = parsed.WriteABI(output.writer)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+		},
+	}
+	cmdABI.Flags().VarP(output, "output", "o", "output file (use - for STDOUT)")
+
 	// PLEASE NOTE that `insgocc compile` is in fact not used for compiling contracts by insolard.
 	// Instead contracts are compiled when `insolard genesis` is executed without using `insgocc`.
 	keepTemp := false
+	skipSandbox := false
 	var cmdCompile = &cobra.Command{
 		Use:   "compile [flags] <file name to compile>",
 		Short: "Compile contract",
@@ -192,6 +216,17 @@ func main() {
 				os.Exit(1)
 			}
 
+			if !skipSandbox {
+				violations := parsed.CheckSandbox()
+				if len(violations) > 0 {
+					for _, v := range violations {
+						fmt.Println(v.String())
+					}
+					fmt.Printf("contract has %d sandbox violation(s), binary is not produced\n", len(violations))
+					os.Exit(1)
+				}
+			}
+
 			// make temporary dir
 			tmpDir, err := ioutil.TempDir("", "temp-")
 			if err != nil {
@@ -247,15 +282,29 @@ func main() {
 				fmt.Println(errors.Wrap(err, "can't build contract: "+string(out)))
 				os.Exit(1)
 			}
+
+			abi, err := os.Create(path.Join(dir, outdir, name+".abi.json"))
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			defer abi.Close()
+
+			err = parsed.WriteABI(abi)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
 		},
 	}
 	// default value for string flags is displayed automatically
 	cmdCompile.Flags().StringVarP(&outdir, "output-dir", "o", ".", "output dir")
 	// default value for bool flags is not displayed automatically, thus it's done manually here
 	cmdCompile.Flags().BoolVarP(&keepTemp, "keep-temp", "k", false, "keep temp directory (default \"false\")")
+	cmdCompile.Flags().BoolVar(&skipSandbox, "skip-sandbox-checks", false, "don't check contract for non deterministic code (default \"false\")")
 
 	var rootCmd = &cobra.Command{Use: "insgocc"}
-	rootCmd.AddCommand(cmdProxy, cmdWrapper, cmdImports, cmdCompile)
+	rootCmd.AddCommand(cmdProxy, cmdWrapper, cmdImports, cmdABI, cmdCompile)
 	err := rootCmd.Execute()
 	if err != nil {
 		fmt.Println(err)
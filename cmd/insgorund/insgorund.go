@@ -66,6 +66,11 @@ func main() {
 
 	insider := ginsider.NewGoInsider(*path, *rpcProtocol, *rpcAddress)
 
+	err = insider.CollectGarbage(context.Background())
+	if err != nil {
+		log.Error("Couldn't remove orphaned plugin files: ", err)
+	}
+
 	if *code != "" {
 		codeSlice := strings.Split(*code, ":")
 		if len(codeSlice) != 2 {
@@ -92,12 +92,13 @@ var (
 	verbose            bool
 	sendUrls           string
 	rootAsCaller       bool
+	requestRef         string
 )
 
 func parseInputParams() {
 	var rootCmd = &cobra.Command{}
 	rootCmd.Flags().StringVarP(&cmd, "cmd", "c", "",
-		"available commands: default_config | random_ref | version | gen_keys | gen_certificate | send_request | gen_send_configs")
+		"available commands: default_config | random_ref | version | gen_keys | gen_certificate | send_request | gen_send_configs | replay_request")
 	rootCmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "be verbose (default false)")
 	rootCmd.Flags().StringVarP(&output, "output", "o", defaultStdoutPath, "output file (use - for STDOUT)")
 	rootCmd.Flags().StringVarP(&sendUrls, "url", "u", defaultURL, "api url")
@@ -105,6 +106,7 @@ func parseInputParams() {
 	rootCmd.Flags().StringVarP(&configPath, "config", "g", "config.json", "path to configuration file")
 	rootCmd.Flags().StringVarP(&paramsPath, "params", "p", "", "path to params file (default params.json)")
 	rootCmd.Flags().BoolVarP(&rootAsCaller, "root_as_caller", "r", false, "use root member as caller")
+	rootCmd.Flags().StringVarP(&requestRef, "request", "q", "", "reference to request for replay")
 	err := rootCmd.Execute()
 	check("Wrong input params:", err)
 
@@ -203,6 +205,20 @@ func sendRequest(out io.Writer) {
 	writeToOutput(out, string(response))
 }
 
+func replayRequest(out io.Writer) {
+	if len(requestRef) == 0 {
+		check("[ replayRequest ]", errors.New("request reference is not set"))
+	}
+	requester.SetVerbose(verbose)
+
+	response, err := requester.Replay(sendUrls, requestRef)
+	check("[ replayRequest ]", err)
+
+	data, err := json.MarshalIndent(response, "", "    ")
+	check("[ replayRequest ] Can't marshal response", err)
+	writeToOutput(out, string(data)+"\n")
+}
+
 func genSendConfigs(out io.Writer) {
 	reqConf, err := genDefaultConfig(requester.RequestConfigJSON{})
 	check("[ genSendConfigs ]", err)
@@ -238,5 +254,7 @@ func main() {
 		sendRequest(out)
 	case "gen_send_configs":
 		genSendConfigs(out)
+	case "replay_request":
+		replayRequest(out)
 	}
 }
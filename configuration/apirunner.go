@@ -26,19 +26,73 @@ type APIRunner struct {
 	Call    string
 	RPC     string
 	Timeout uint32
+	// Batch is a path of batch call endpoint, the endpoint is disabled if it is empty.
+	Batch string
+	// BatchLimit is a maximum number of requests in batch.
+	BatchLimit int
+	// Schema is a path of endpoint serving description of api, the endpoint is disabled if it is empty.
+	Schema string
+	// Events is a path of WebSocket endpoint sending pulse, network state and active nodes events, the endpoint is
+	// disabled if it is empty.
+	Events string
+	// AdminAddress is an address of listener for exporter, status, cert and admin services. Exporter, status and
+	// cert are served on Address if it is empty, admin service is disabled then.
+	AdminAddress string
+	TLS          APITLS
+	RateLimit    APIRateLimit
+	Callback     APICallback
+}
+
+// APITLS holds TLS configuration of api listeners, TLS is disabled if CertFile is empty
+type APITLS struct {
+	CertFile string
+	KeyFile  string
+	// ClientCAFile is optional, clients of admin listener must present certificate signed by CA from this file if
+	// it is set, it requires AdminAddress
+	ClientCAFile string
+}
+
+// APIRateLimit holds token bucket limits for api requests, limit is disabled if its rate is zero
+type APIRateLimit struct {
+	// MemberRate is a number of calls per second allowed for member
+	MemberRate  float64
+	MemberBurst int
+	// IPRate is a number of requests per second allowed for client IP
+	IPRate  float64
+	IPBurst int
+}
+
+// APICallback holds configuration of callbacks notified when async calls are finished
+type APICallback struct {
+	// Hosts are hosts callbacks are allowed to, callbacks are allowed to any host with public address if it is empty
+	Hosts []string
+	// Timeout is a timeout of callback request in seconds
+	Timeout uint32
+	// MaxPending is a maximum number of async calls waiting for callback, calls with callback are rejected above it,
+	// callbacks are disabled if it is zero
+	MaxPending int
 }
 
 // NewAPIRunner creates new api config
 func NewAPIRunner() APIRunner {
 	return APIRunner{
-		Address: "localhost:19101",
-		Call:    "/api/call",
-		RPC:     "/api/rpc",
-		Timeout: 15,
+		Address:    "localhost:19101",
+		Call:       "/api/call",
+		RPC:        "/api/rpc",
+		Timeout:    15,
+		Batch:      "/api/batch",
+		BatchLimit: 100,
+		Schema:     "/api/schema",
+		Events:     "/api/events",
+		Callback: APICallback{
+			Timeout:    10,
+			MaxPending: 1000,
+		},
 	}
 }
 
 func (ar *APIRunner) String() string {
-	res := fmt.Sprintln("Addr ->", ar.Address, ", Call ->", ar.Call, ", RPC ->", ar.RPC)
+	res := fmt.Sprintln("Addr ->", ar.Address, ", Call ->", ar.Call, ", RPC ->", ar.RPC, ", Batch ->", ar.Batch, ", Events ->", ar.Events, ", Admin ->", ar.AdminAddress,
+		", TLS ->", ar.TLS.CertFile != "", ", mTLS ->", ar.TLS.ClientCAFile != "")
 	return res
 }
@@ -16,6 +16,10 @@
 
 package configuration
 
+import (
+	"time"
+)
+
 // LogicRunner configuration
 type LogicRunner struct {
 	// RPCListen - address logic runner binds RPC API to
@@ -27,6 +31,15 @@ type LogicRunner struct {
 	BuiltIn *BuiltIn
 	// GoPlugin - configuration of executor based on Go plugins
 	GoPlugin *GoPlugin
+	// Wasm - configuration of executor based on WebAssembly interpreter
+	Wasm *Wasm
+	// SlowCallThreshold - requests that spent more time in queue and execution
+	// are reported to log, zero disables reporting
+	SlowCallThreshold time.Duration
+	// TransactionTimeout - time an object keeps changes staged in transaction
+	// waiting for decision of coordinator, then they are aborted. Coordinator
+	// aborts transactions that run longer than half of it.
+	TransactionTimeout time.Duration
 }
 
 // BuiltIn configuration, no options at the moment
@@ -39,17 +52,44 @@ type GoPlugin struct {
 	// RunnerProtocol - protocol (network) of above address,
 	// e.g. "tcp", "unix"... see `net.Dial`
 	RunnerProtocol string
+	// RunnerPath - path to insgorund binary, if set the node spawns and supervises
+	// a pool of RunnerCount insgorund processes instead of connecting to RunnerListen,
+	// worker N listens on RunnerListen with port increased by N (or suffix .N for unix sockets)
+	RunnerPath string
+	// RunnerCount - number of insgorund processes in the pool
+	RunnerCount int
+	// RunnerCodePath - directory where workers cache code, every worker uses own subdirectory
+	RunnerCodePath string
+	// RunnerRestartDelay - delay before restart of crashed worker
+	RunnerRestartDelay time.Duration
+}
+
+// Wasm configuration
+type Wasm struct {
+	// Fuel - number of instructions a contract may execute in one call,
+	// the call fails when fuel is exhausted
+	Fuel uint64
+	// MaxMemoryPages - limit of linear memory of a contract in 64KiB pages
+	MaxMemoryPages uint32
 }
 
 // NewLogicRunner - returns default config of the logic runner
 func NewLogicRunner() LogicRunner {
 	return LogicRunner{
-		RPCListen:   "127.0.0.1:7778",
-		RPCProtocol: "tcp",
-		BuiltIn:     &BuiltIn{},
+		RPCListen:          "127.0.0.1:7778",
+		RPCProtocol:        "tcp",
+		BuiltIn:            &BuiltIn{},
+		SlowCallThreshold:  5 * time.Second,
+		TransactionTimeout: 30 * time.Second,
 		GoPlugin: &GoPlugin{
-			RunnerListen:   "127.0.0.1:7777",
-			RunnerProtocol: "tcp",
+			RunnerListen:       "127.0.0.1:7777",
+			RunnerProtocol:     "tcp",
+			RunnerCount:        1,
+			RunnerRestartDelay: time.Second,
+		},
+		Wasm: &Wasm{
+			Fuel:           10000000,
+			MaxMemoryPages: 256,
 		},
 	}
 }
@@ -165,8 +165,9 @@ func (cr *ContractRequester) CallMethod(ctx context.Context, base core.Message,
 			return nil, errors.New("Reply is not CallMethod")
 		}
 		result = &reply.CallMethod{
-			Request: r.Request,
-			Result:  retReply.Result,
+			Request:      r.Request,
+			Result:       retReply.Result,
+			Participants: retReply.Participants,
 		}
 	case <-ctx.Done():
 		cr.ResultMutex.Lock()
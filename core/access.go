@@ -0,0 +1,68 @@
+/*
+ *    Copyright 2019 Insolar Technologies
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package core
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// AccessRule restricts who can call a method of contract. Method with rules
+// can be called only by callers satisfying at least one of them. Rules are declared
+// in code of contract next to the method:
+//
+//   var INSATTR_RegisterNode_Access = "root_member"
+//   var INSATTR_Transfer_Access = "parent, prototype:Wallet"
+type AccessRule string
+
+const (
+	// AccessParent allows calls from parent of the object.
+	AccessParent = AccessRule("parent")
+	// AccessSelf allows calls from the object itself.
+	AccessSelf = AccessRule("self")
+	// AccessRootMember allows calls from root member.
+	AccessRootMember = AccessRule("root_member")
+	// AccessPrototypePrefix followed by name of contract allows calls from objects of the contract.
+	AccessPrototypePrefix = "prototype:"
+)
+
+// Prototype returns name of contract allowed by "prototype:<Contract>" rule.
+func (r AccessRule) Prototype() (string, bool) {
+	if !strings.HasPrefix(string(r), AccessPrototypePrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(string(r), AccessPrototypePrefix), true
+}
+
+// ParseAccessRules parses comma separated list of access rules.
+func ParseAccessRules(s string) ([]AccessRule, error) {
+	var res []AccessRule
+	for _, part := range strings.Split(s, ",") {
+		rule := AccessRule(strings.TrimSpace(part))
+		switch rule {
+		case AccessParent, AccessSelf, AccessRootMember:
+		default:
+			contract, ok := rule.Prototype()
+			if !ok || contract == "" {
+				return nil, errors.Errorf("unknown access rule %q", rule)
+			}
+		}
+		res = append(res, rule)
+	}
+	return res, nil
+}
@@ -29,4 +29,6 @@ var (
 	ErrHotDataTimeout = errors.New("requests were abandoned due to hot-data timeout")
 	// ErrNoPendingRequest is returned when there are no pending requests on current LME
 	ErrNoPendingRequest = errors.New("no pending requests are available")
+	// ErrSeedUsed is returned when seed is already used in call of object
+	ErrSeedUsed = errors.New("seed is already used")
 )
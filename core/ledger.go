@@ -151,6 +151,21 @@ type ArtifactManager interface {
 	// HasPendingRequests returns true if object has unclosed requests.
 	HasPendingRequests(ctx context.Context, object RecordRef) (bool, error)
 
+	// GetIdempotentRequest returns request registered on object with provided idempotency key.
+	//
+	// Nil is returned if there is no such request.
+	GetIdempotentRequest(ctx context.Context, object RecordRef, key string) (*IdempotentRequest, error)
+
+	// GetRequestResult returns request registered on object with idempotency key by its reference.
+	//
+	// Nil is returned if object has no such request.
+	GetRequestResult(ctx context.Context, object, request RecordRef) (*IdempotentRequest, error)
+
+	// RegisterSeed marks seed as used in call of object until expires pulse.
+	//
+	// ErrSeedUsed is returned if seed is already used.
+	RegisterSeed(ctx context.Context, object RecordRef, seed []byte, expires PulseNumber) error
+
 	// GetDelegate returns provided object's delegate reference for provided type.
 	//
 	// Object delegate should be previously created for this object. If object delegate does not exist, an error will
@@ -169,8 +184,8 @@ type ArtifactManager interface {
 
 	// DeployCode creates new code record in storage.
 	//
-	// Code records are used to activate prototype.
-	DeployCode(ctx context.Context, domain, request RecordRef, code []byte, machineType MachineType) (*RecordID, error)
+	// Code records are used to activate prototype. ABI is a JSON description of contract's interface, could be nil.
+	DeployCode(ctx context.Context, domain, request RecordRef, code []byte, machineType MachineType, abi []byte) (*RecordID, error)
 
 	// ActivatePrototype creates activate object record in storage. Provided prototype reference will be used as objects prototype
 	// memory as memory of created object. If memory is not provided, the prototype default memory will be used.
@@ -236,6 +251,12 @@ type CodeDescriptor interface {
 
 	// Code returns code data.
 	Code() ([]byte, error)
+
+	// ABI returns JSON description of code's interface, nil if it wasn't provided on deploy.
+	ABI() ([]byte, error)
+
+	// Hash returns hash of code data as it's stored in code record, used to verify code integrity.
+	Hash() []byte
 }
 
 // ObjectDescriptor represents meta info required to fetch all object data.
@@ -275,6 +296,14 @@ type RefIterator interface {
 	HasNext() bool
 }
 
+// IdempotentRequest is a request registered with idempotency key.
+type IdempotentRequest struct {
+	Request RecordRef
+	// Finished is true if request has result, Result is empty otherwise.
+	Finished bool
+	Result   []byte
+}
+
 // LocalStorage allows a node to save local data.
 //go:generate minimock -i github.com/insolar/insolar/core.LocalStorage -o ../testutils -s _mock.go
 type LocalStorage interface {
@@ -384,6 +384,15 @@ type HotData struct {
 	PendingRequests    map[core.RecordID]recentstorage.PendingObjectContext
 	PulseNumber        core.PulseNumber
 	JetDropSizeHistory jet.DropSizeHistory
+	// UsedSeeds are seeds used in calls of objects that are not expired yet.
+	UsedSeeds []UsedSeed
+}
+
+// UsedSeed is a seed used in call of object.
+type UsedSeed struct {
+	Object  core.RecordID
+	Seed    []byte
+	Expires core.PulseNumber
 }
 
 // AllowedSenderObjectAndRole implements interface method
@@ -410,6 +419,8 @@ func (*HotData) Type() core.MessageType {
 type HotIndex struct {
 	TTL   int
 	Index []byte
+	// IdempotentRequests are recent requests registered on object with idempotency keys. Older ones are on heavy.
+	IdempotentRequests [][]byte
 }
 
 // GetPendingRequests fetches pending requests for object.
@@ -547,3 +558,90 @@ func (*GetPendingRequestID) DefaultRole() core.DynamicRole {
 func (m *GetPendingRequestID) DefaultTarget() *core.RecordRef {
 	return core.NewRecordRef(core.DomainID, m.ObjectID)
 }
+
+// GetRequestTrace fetches from heavy request, its result, states of object around it
+// and requests registered while executing it.
+type GetRequestTrace struct {
+	ledgerMessage
+
+	Request core.RecordID
+}
+
+// Type implementation of Message interface.
+func (*GetRequestTrace) Type() core.MessageType {
+	return core.TypeGetRequestTrace
+}
+
+// AllowedSenderObjectAndRole implements interface method
+func (m *GetRequestTrace) AllowedSenderObjectAndRole() (*core.RecordRef, core.DynamicRole) {
+	return nil, core.DynamicRoleUndefined
+}
+
+// DefaultRole returns role for this event
+func (*GetRequestTrace) DefaultRole() core.DynamicRole {
+	return core.DynamicRoleHeavyExecutor
+}
+
+// DefaultTarget returns of target of this event.
+func (m *GetRequestTrace) DefaultTarget() *core.RecordRef {
+	return core.NewRecordRef(core.DomainID, m.Request)
+}
+
+// GetIdempotentRequest fetches request registered on object with idempotency key and its result.
+type GetIdempotentRequest struct {
+	ledgerMessage
+
+	Object core.RecordRef
+	Key    string
+	// Request is searched instead of key if set.
+	Request *core.RecordID
+}
+
+// Type implementation of Message interface.
+func (*GetIdempotentRequest) Type() core.MessageType {
+	return core.TypeGetIdempotentRequest
+}
+
+// AllowedSenderObjectAndRole implements interface method
+func (m *GetIdempotentRequest) AllowedSenderObjectAndRole() (*core.RecordRef, core.DynamicRole) {
+	return nil, core.DynamicRoleUndefined
+}
+
+// DefaultRole returns role for this event
+func (*GetIdempotentRequest) DefaultRole() core.DynamicRole {
+	return core.DynamicRoleLightExecutor
+}
+
+// DefaultTarget returns of target of this event.
+func (m *GetIdempotentRequest) DefaultTarget() *core.RecordRef {
+	return &m.Object
+}
+
+// RegisterSeed marks seed as used in call of object until it expires.
+type RegisterSeed struct {
+	ledgerMessage
+
+	Object  core.RecordRef
+	Seed    []byte
+	Expires core.PulseNumber
+}
+
+// Type implementation of Message interface.
+func (*RegisterSeed) Type() core.MessageType {
+	return core.TypeRegisterSeed
+}
+
+// AllowedSenderObjectAndRole implements interface method
+func (m *RegisterSeed) AllowedSenderObjectAndRole() (*core.RecordRef, core.DynamicRole) {
+	return nil, core.DynamicRoleUndefined
+}
+
+// DefaultRole returns role for this event
+func (*RegisterSeed) DefaultRole() core.DynamicRole {
+	return core.DynamicRoleLightExecutor
+}
+
+// DefaultTarget returns of target of this event.
+func (m *RegisterSeed) DefaultTarget() *core.RecordRef {
+	return &m.Object
+}
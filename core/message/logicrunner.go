@@ -56,6 +56,12 @@ type BaseLogicMessage struct {
 	CallerPrototype core.RecordRef
 	Nonce           uint64
 	Sequence        uint64
+	// Transaction is a request that started transaction the call belongs to
+	Transaction core.RecordRef
+	// Coordinator is an object the request that started transaction was made on
+	Coordinator core.RecordRef
+	// IdempotencyKey is a client key of request, requests with the same key are registered on object only once
+	IdempotencyKey string
 }
 
 func (m *BaseLogicMessage) GetBaseLogicMessage() *BaseLogicMessage {
@@ -145,6 +151,7 @@ func (cm *CallMethod) ToMap() (map[string]interface{}, error) {
 	msg["CallerPrototype"] = cm.BaseLogicMessage.CallerPrototype.String()
 	msg["Nonce"] = cm.BaseLogicMessage.Nonce
 	msg["Sequence"] = cm.BaseLogicMessage.Sequence
+	msg["Transaction"] = cm.BaseLogicMessage.Transaction.String()
 
 	// CallMethod fields
 	msg["ReturnMode"] = cm.ReturnMode
@@ -456,3 +463,140 @@ func (se *StillExecuting) DefaultTarget() *core.RecordRef {
 func (se *StillExecuting) Type() core.MessageType {
 	return core.TypeStillExecuting
 }
+
+// GetCallTraces fetches traces of requests executed by receiver node
+type GetCallTraces struct {
+	Requests []core.RecordRef
+}
+
+func (gct *GetCallTraces) GetCaller() *core.RecordRef {
+	return nil
+}
+
+func (gct *GetCallTraces) AllowedSenderObjectAndRole() (*core.RecordRef, core.DynamicRole) {
+	return nil, 0
+}
+
+func (gct *GetCallTraces) DefaultRole() core.DynamicRole {
+	return core.DynamicRoleVirtualExecutor
+}
+
+func (gct *GetCallTraces) DefaultTarget() *core.RecordRef {
+	if len(gct.Requests) == 0 {
+		return nil
+	}
+	return &gct.Requests[0]
+}
+
+func (gct *GetCallTraces) Type() core.MessageType {
+	return core.TypeGetCallTraces
+}
+
+// TransactionDecision is sent by coordinator of transaction to executors of objects
+// that took part in it. The same message with list of participants is stored on ledger
+// as the record of decision, executors apply decision only if it matches the record.
+type TransactionDecision struct {
+	Transaction  core.RecordRef
+	Coordinator  core.RecordRef
+	Object       core.RecordRef
+	Commit       bool
+	Participants []core.RecordRef
+}
+
+func (td *TransactionDecision) GetCaller() *core.RecordRef {
+	return &td.Transaction
+}
+
+func (td *TransactionDecision) AllowedSenderObjectAndRole() (*core.RecordRef, core.DynamicRole) {
+	return &td.Coordinator, core.DynamicRoleVirtualExecutor
+}
+
+func (td *TransactionDecision) DefaultRole() core.DynamicRole {
+	return core.DynamicRoleVirtualExecutor
+}
+
+func (td *TransactionDecision) DefaultTarget() *core.RecordRef {
+	return &td.Object
+}
+
+func (td *TransactionDecision) Type() core.MessageType {
+	return core.TypeTransactionDecision
+}
+
+// ReplayRequest asks virtual node to execute again a request made in the past
+// and compare results with the ones stored on ledger
+type ReplayRequest struct {
+	Request core.RecordRef
+}
+
+func (rr *ReplayRequest) GetCaller() *core.RecordRef {
+	return nil
+}
+
+func (rr *ReplayRequest) AllowedSenderObjectAndRole() (*core.RecordRef, core.DynamicRole) {
+	return nil, 0
+}
+
+func (rr *ReplayRequest) DefaultRole() core.DynamicRole {
+	return core.DynamicRoleVirtualExecutor
+}
+
+func (rr *ReplayRequest) DefaultTarget() *core.RecordRef {
+	return &rr.Request
+}
+
+func (rr *ReplayRequest) Type() core.MessageType {
+	return core.TypeReplayRequest
+}
+
+// GetExecutionQueues asks virtual node for execution queues of objects it has
+type GetExecutionQueues struct {
+}
+
+func (geq *GetExecutionQueues) GetCaller() *core.RecordRef {
+	return nil
+}
+
+func (geq *GetExecutionQueues) AllowedSenderObjectAndRole() (*core.RecordRef, core.DynamicRole) {
+	return nil, 0
+}
+
+func (geq *GetExecutionQueues) DefaultRole() core.DynamicRole {
+	return core.DynamicRoleVirtualExecutor
+}
+
+func (geq *GetExecutionQueues) DefaultTarget() *core.RecordRef {
+	return nil
+}
+
+func (geq *GetExecutionQueues) Type() core.MessageType {
+	return core.TypeGetExecutionQueues
+}
+
+// CancelQueuedRequest asks executor of the object to remove request from execution queue,
+// request gets error result with the reason. Only the executor itself can send it.
+type CancelQueuedRequest struct {
+	Object  core.RecordRef
+	Request core.RecordRef
+	Reason  string
+}
+
+func (cqr *CancelQueuedRequest) GetCaller() *core.RecordRef {
+	return nil
+}
+
+func (cqr *CancelQueuedRequest) AllowedSenderObjectAndRole() (*core.RecordRef, core.DynamicRole) {
+	return &cqr.Object, core.DynamicRoleVirtualExecutor
+}
+
+func (cqr *CancelQueuedRequest) DefaultRole() core.DynamicRole {
+	return core.DynamicRoleVirtualExecutor
+}
+
+func (cqr *CancelQueuedRequest) DefaultTarget() *core.RecordRef {
+	return &cqr.Object
+}
+
+func (cqr *CancelQueuedRequest) Type() core.MessageType {
+	return core.TypeCancelQueuedRequest
+}
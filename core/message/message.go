@@ -51,6 +51,16 @@ func getEmptyMessage(mt core.MessageType) (core.Message, error) {
 		return &PendingFinished{}, nil
 	case core.TypeStillExecuting:
 		return &StillExecuting{}, nil
+	case core.TypeGetCallTraces:
+		return &GetCallTraces{}, nil
+	case core.TypeTransactionDecision:
+		return &TransactionDecision{}, nil
+	case core.TypeReplayRequest:
+		return &ReplayRequest{}, nil
+	case core.TypeGetExecutionQueues:
+		return &GetExecutionQueues{}, nil
+	case core.TypeCancelQueuedRequest:
+		return &CancelQueuedRequest{}, nil
 
 	// Ledger
 	case core.TypeGetCode:
@@ -83,6 +93,12 @@ func getEmptyMessage(mt core.MessageType) (core.Message, error) {
 		return &GetPendingRequestID{}, nil
 	case core.TypeGetRequest:
 		return &GetRequest{}, nil
+	case core.TypeGetRequestTrace:
+		return &GetRequestTrace{}, nil
+	case core.TypeGetIdempotentRequest:
+		return &GetIdempotentRequest{}, nil
+	case core.TypeRegisterSeed:
+		return &RegisterSeed{}, nil
 
 	// heavy sync
 	case core.TypeHeavyStartStop:
@@ -203,6 +219,11 @@ func init() {
 	gob.Register(&ValidationResults{})
 	gob.Register(&PendingFinished{})
 	gob.Register(&StillExecuting{})
+	gob.Register(&GetCallTraces{})
+	gob.Register(&TransactionDecision{})
+	gob.Register(&ReplayRequest{})
+	gob.Register(&GetExecutionQueues{})
+	gob.Register(&CancelQueuedRequest{})
 
 	// Ledger
 	gob.Register(&GetCode{})
@@ -222,6 +243,9 @@ func init() {
 	gob.Register(&HotData{})
 	gob.Register(&GetPendingRequestID{})
 	gob.Register(&GetRequest{})
+	gob.Register(&GetRequestTrace{})
+	gob.Register(&GetIdempotentRequest{})
+	gob.Register(&RegisterSeed{})
 
 	// heavy
 	gob.Register(&HeavyStartStop{})
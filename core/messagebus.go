@@ -222,6 +222,16 @@ const (
 	// TypeStillExecuting is sent by an old executor on pulse switch if it wants to continue executing
 	// to the current executor
 	TypeStillExecuting
+	// TypeGetCallTraces fetches traces of requests executed by a virtual node
+	TypeGetCallTraces
+	// TypeTransactionDecision commits or aborts changes an object staged in transaction
+	TypeTransactionDecision
+	// TypeReplayRequest re-executes a request made in the past and compares its effects with the stored ones
+	TypeReplayRequest
+	// TypeGetExecutionQueues fetches execution queues of objects from a virtual node
+	TypeGetExecutionQueues
+	// TypeCancelQueuedRequest removes request from execution queue with error result
+	TypeCancelQueuedRequest
 
 	// Ledger
 
@@ -259,6 +269,12 @@ const (
 	TypeGetRequest
 	// TypeGetPendingRequestID fetches a pending request id from ledger
 	TypeGetPendingRequestID
+	// TypeGetRequestTrace fetches request with its result and effects from heavy.
+	TypeGetRequestTrace
+	// TypeGetIdempotentRequest fetches request registered with idempotency key and its result.
+	TypeGetIdempotentRequest
+	// TypeRegisterSeed marks seed as used in call of object.
+	TypeRegisterSeed
 
 	// TypeValidationCheck checks if validation of a particular record can be performed.
 	TypeValidationCheck
@@ -51,6 +51,12 @@ const (
 	TypeCallConstructor
 	// TypeRegisterRequest - request for execution was registered
 	TypeRegisterRequest
+	// TypeCallTraces - traces of executed requests
+	TypeCallTraces
+	// TypeReplay - outcome of request replay
+	TypeReplay
+	// TypeExecutionQueues - execution queues of a virtual node
+	TypeExecutionQueues
 
 	// Ledger
 
@@ -74,6 +80,10 @@ const (
 	TypeJet
 	// TypeRequest contains request.
 	TypeRequest
+	// TypeRequestTrace contains request with its result and effects.
+	TypeRequestTrace
+	// TypeIdempotentRequest contains request registered with idempotency key and its result.
+	TypeIdempotentRequest
 	// TypeHeavyError carries heavy record sync
 	TypeHeavyError
 
@@ -92,6 +102,8 @@ const (
 	ErrHotDataTimeout
 	// ErrNoPendingRequests is returned when there are no pending requests on current LME
 	ErrNoPendingRequests
+	// ErrSeedUsed is returned when seed is already used in call of object
+	ErrSeedUsed
 )
 
 func getEmptyReply(t core.ReplyType) (core.Reply, error) {
@@ -102,6 +114,12 @@ func getEmptyReply(t core.ReplyType) (core.Reply, error) {
 		return &CallConstructor{}, nil
 	case TypeRegisterRequest:
 		return &RegisterRequest{}, nil
+	case TypeCallTraces:
+		return &CallTraces{}, nil
+	case TypeReplay:
+		return &Replay{}, nil
+	case TypeExecutionQueues:
+		return &ExecutionQueues{}, nil
 	case TypeCode:
 		return &Code{}, nil
 	case TypeObject:
@@ -134,6 +152,10 @@ func getEmptyReply(t core.ReplyType) (core.Reply, error) {
 		return &Jet{}, nil
 	case TypeRequest:
 		return &Request{}, nil
+	case TypeRequestTrace:
+		return &RequestTrace{}, nil
+	case TypeIdempotentRequest:
+		return &IdempotentRequest{}, nil
 
 	case TypeNodeSign:
 		return &NodeSign{}, nil
@@ -186,6 +208,9 @@ func init() {
 	gob.Register(&CallMethod{})
 	gob.Register(&CallConstructor{})
 	gob.Register(&RegisterRequest{})
+	gob.Register(&CallTraces{})
+	gob.Register(&Replay{})
+	gob.Register(&ExecutionQueues{})
 	gob.Register(&Code{})
 	gob.Register(&Object{})
 	gob.Register(&Delegate{})
@@ -202,4 +227,6 @@ func init() {
 	gob.Register(&NodeSign{})
 	gob.Register(&HasPendingRequests{})
 	gob.Register(&Request{})
+	gob.Register(&RequestTrace{})
+	gob.Register(&IdempotentRequest{})
 }
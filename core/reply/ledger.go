@@ -24,6 +24,8 @@ import (
 type Code struct {
 	Code        []byte
 	MachineType core.MachineType
+	ABI         []byte
+	Hash        []byte
 }
 
 // Type implementation of Reply interface.
@@ -129,3 +131,43 @@ type Request struct {
 func (r *Request) Type() core.ReplyType {
 	return TypeRequest
 }
+
+// RequestTrace contains request with its result, states of object the request
+// was executed on and made, and requests registered while executing it.
+type RequestTrace struct {
+	Parcel      []byte         // serialized parcel of request
+	Result      []byte         // payload of result record
+	Finished    bool           // result of request is registered
+	Before      *core.RecordID // state of object request was executed on
+	After       *core.RecordID // state request made, nil if object wasn't changed
+	Deactivated bool           // request deactivated object
+	Children    []TracedRequest
+}
+
+// Type implementation of Reply interface.
+func (r *RequestTrace) Type() core.ReplyType {
+	return TypeRequestTrace
+}
+
+// TracedRequest is a request registered while executing traced request.
+type TracedRequest struct {
+	ID       core.RecordID
+	Object   core.RecordID // object request is registered on
+	Parcel   []byte
+	Result   []byte
+	Finished bool
+}
+
+// IdempotentRequest contains request registered with idempotency key and its result.
+type IdempotentRequest struct {
+	Key string
+	// Request is nil if there is no request with the key.
+	Request  *core.RecordID
+	Finished bool
+	Payload  []byte
+}
+
+// Type implementation of Reply interface.
+func (e *IdempotentRequest) Type() core.ReplyType {
+	return TypeIdempotentRequest
+}
@@ -24,6 +24,14 @@ import (
 type CallMethod struct {
 	Request core.RecordRef
 	Result  []byte
+	// Participants are objects that staged changes in transaction of the call
+	Participants []Participant
+}
+
+// Participant is an object that took part in transaction and node that keeps its staged changes
+type Participant struct {
+	Object core.RecordRef
+	Node   core.RecordRef
 }
 
 // Type returns type of the reply
@@ -48,3 +56,43 @@ type RegisterRequest struct {
 func (r *RegisterRequest) Type() core.ReplyType {
 	return TypeRegisterRequest
 }
+
+// CallTraces - traces of requests that node executed, unknown requests are skipped
+type CallTraces struct {
+	Traces []core.CallTrace
+}
+
+// Type returns type of the reply
+func (r *CallTraces) Type() core.ReplyType {
+	return TypeCallTraces
+}
+
+// Replay - outcome of request executed again, stored fields are taken from ledger
+type Replay struct {
+	Object            core.RecordRef
+	Method            string
+	State             []byte
+	StoredState       []byte
+	Deactivated       bool
+	StoredDeactivated bool
+	Result            []byte
+	StoredResult      []byte
+	Error             string   // error of execution, if it failed
+	Diff              []string // differences between replay and stored outcome
+}
+
+// Type returns type of the reply
+func (r *Replay) Type() core.ReplyType {
+	return TypeReplay
+}
+
+// ExecutionQueues - execution queues of objects a virtual node has
+type ExecutionQueues struct {
+	Node   core.RecordRef
+	Queues []core.ExecutionQueue
+}
+
+// Type returns type of the reply
+func (r *ExecutionQueues) Type() core.ReplyType {
+	return TypeExecutionQueues
+}
@@ -29,6 +29,7 @@ const (
 	MachineTypeNotExist             = 0
 	MachineTypeBuiltin  MachineType = iota + 1
 	MachineTypeGoPlugin
+	MachineTypeWasm
 
 	MachineTypesLastID
 )
@@ -64,7 +65,7 @@ type LogicRunner interface {
 
 // LogicCallContext is a context of contract execution
 type LogicCallContext struct {
-	Mode            string     // either "execution" or "validation"
+	Mode            string     // either "execution", "validation" or "replay"
 	Callee          *RecordRef // Contract that was called
 	Request         *RecordRef // ref of request
 	Prototype       *RecordRef // Image of the callee
@@ -76,3 +77,38 @@ type LogicCallContext struct {
 	Pulse           Pulse      // Number of the pulse
 	TraceID         string
 }
+
+// CallTrace is a node of cross-contract call tree, it's collected by the node that executed the request
+type CallTrace struct {
+	Request   RecordRef     // ref of request
+	Parent    RecordRef     // request that made the call, empty for requests from outside
+	Object    RecordRef     // Contract that was called
+	Prototype RecordRef     // Image of the callee
+	Method    string        // method or constructor name
+	Executor  RecordRef     // node that executed the request
+	Start     time.Time     // Time when execution started
+	Duration  time.Duration // Time spent on execution
+	Result    []byte        // serialized results of the method
+	Error     string
+	Children  []RecordRef // requests made during execution
+}
+
+// ExecutionQueue describes requests of an object a virtual node executes or keeps in queue
+type ExecutionQueue struct {
+	Object                RecordRef
+	Prototype             *RecordRef      // Image of the object, nil if executor hasn't fetched it yet
+	Current               *QueuedRequest  // request executing right now, its Age is time of execution
+	Queue                 []QueuedRequest // requests waiting for execution
+	Pending               bool            // object waits for previous executor to finish
+	InTransaction         bool            // object waits for decision on transaction
+	LedgerHasMoreRequests bool            // ledger keeps requests that don't fit into queue
+}
+
+// QueuedRequest is a request in execution queue of an object
+type QueuedRequest struct {
+	Request    RecordRef
+	Caller     RecordRef
+	Method     string
+	Age        time.Duration // time since request was queued
+	FromLedger bool          // request was fetched from ledger as pending
+}
@@ -63,7 +63,7 @@ func TestDumpAllUsersNoRoot(t *testing.T) {
 	member := createMember(t, "Member")
 
 	_, err := signedRequest(member, "DumpAllUsers")
-	require.Contains(t, err.Error(), "[ DumpAllUsers ] Only root can call this method")
+	require.Contains(t, err.Error(), "access to method DumpAllUsers denied")
 }
 
 // todo fix this deadlock
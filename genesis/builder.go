@@ -18,6 +18,7 @@ package genesis
 
 import (
 	"context"
+	"encoding/json"
 	"go/build"
 	"io/ioutil"
 	"os"
@@ -147,7 +148,7 @@ func (cb *ContractsBuilder) Build(ctx context.Context, contracts map[string]*pre
 		}
 	}
 
-	for name := range contracts {
+	for name, code := range contracts {
 		log.Debugf("Building plugin for contract %q in %q", name, cb.root)
 		err := cb.plugin(name)
 		if err != nil {
@@ -166,11 +167,16 @@ func (cb *ContractsBuilder) Build(ctx context.Context, contracts map[string]*pre
 			return errors.Wrap(err, "[ Build ] Can't RegisterRequest")
 		}
 
+		abi, err := json.Marshal(code.ABI())
+		if err != nil {
+			return errors.Wrap(err, "[ Build ] Can't marshal ABI")
+		}
+
 		log.Debugf("Deploying code for contract %q", name)
 		codeID, err := cb.ArtifactManager.DeployCode(
 			ctx,
 			*domainRef, *core.NewRecordRef(*domain, *codeReq),
-			pluginBinary, core.MachineTypeGoPlugin,
+			pluginBinary, core.MachineTypeGoPlugin, abi,
 		)
 		codeRef := core.NewRecordRef(*domain, *codeID)
 		if err != nil {
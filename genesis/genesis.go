@@ -42,16 +42,19 @@ import (
 )
 
 const (
-	nodeDomain        = "nodedomain"
-	nodeRecord        = "noderecord"
-	rootDomain        = "rootdomain"
-	walletContract    = "wallet"
-	memberContract    = "member"
-	allowanceContract = "allowance"
-	nodeAmount        = 32
+	nodeDomain          = "nodedomain"
+	nodeRecord          = "noderecord"
+	rootDomain          = "rootdomain"
+	walletContract      = "wallet"
+	memberContract      = "member"
+	allowanceContract   = "allowance"
+	assetContract       = "asset"
+	tokenWalletContract = "tokenwallet"
+	nodeAmount          = 32
 )
 
-var contractNames = []string{walletContract, memberContract, allowanceContract, rootDomain, nodeDomain, nodeRecord}
+var contractNames = []string{walletContract, memberContract, allowanceContract, assetContract, tokenWalletContract,
+	rootDomain, nodeDomain, nodeRecord}
 
 type messageBusLocker interface {
 	Lock(ctx context.Context)
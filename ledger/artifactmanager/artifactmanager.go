@@ -99,6 +99,13 @@ func (m *LedgerArtifactManager) RegisterRequest(
 		MessageHash: m.PlatformCryptographyScheme.IntegrityHasher().Hash(message.MustSerializeBytes(parcel.Message())),
 		Object:      *obj.Record(),
 	}
+	if msg, ok := parcel.Message().(message.IBaseLogicMessage); ok {
+		rec.Parent = msg.GetBaseLogicMessage().Request
+	}
+	// Keys are stored in idempotent request records of called object, so only calls of existing objects can have them.
+	if msg, ok := parcel.Message().(*message.CallMethod); ok {
+		rec.IdempotencyKey = msg.IdempotencyKey
+	}
 	recID := record.NewRecordIDFromRecord(
 		m.PlatformCryptographyScheme,
 		currentPulse.PulseNumber,
@@ -156,6 +163,8 @@ func (m *LedgerArtifactManager) GetCode(
 			ref:         code,
 			machineType: rep.MachineType,
 			code:        rep.Code,
+			abi:         rep.ABI,
+			hash:        rep.Hash,
 		}
 		return &desc, nil
 	case *reply.Error:
@@ -347,6 +356,131 @@ func (m *LedgerArtifactManager) HasPendingRequests(
 	}
 }
 
+// GetIdempotentRequest returns request registered on object with provided idempotency key.
+//
+// Nil is returned if there is no such request.
+func (m *LedgerArtifactManager) GetIdempotentRequest(
+	ctx context.Context, object core.RecordRef, key string,
+) (*core.IdempotentRequest, error) {
+	var err error
+	ctx, span := instracer.StartSpan(ctx, "artifactmanager.GetIdempotentRequest")
+	instrumenter := instrument(ctx, "GetIdempotentRequest").err(&err)
+	defer func() {
+		if err != nil {
+			span.AddAttributes(trace.StringAttribute("error", err.Error()))
+		}
+		span.End()
+		instrumenter.end()
+	}()
+
+	req, err := m.getIdempotentRequest(ctx, &message.GetIdempotentRequest{
+		Object: object,
+		Key:    key,
+	})
+	return req, err
+}
+
+// GetRequestResult returns request registered on object with idempotency key by its reference.
+//
+// Nil is returned if object has no such request.
+func (m *LedgerArtifactManager) GetRequestResult(
+	ctx context.Context, object, request core.RecordRef,
+) (*core.IdempotentRequest, error) {
+	var err error
+	ctx, span := instracer.StartSpan(ctx, "artifactmanager.GetRequestResult")
+	instrumenter := instrument(ctx, "GetRequestResult").err(&err)
+	defer func() {
+		if err != nil {
+			span.AddAttributes(trace.StringAttribute("error", err.Error()))
+		}
+		span.End()
+		instrumenter.end()
+	}()
+
+	req, err := m.getIdempotentRequest(ctx, &message.GetIdempotentRequest{
+		Object:  object,
+		Request: request.Record(),
+	})
+	return req, err
+}
+
+func (m *LedgerArtifactManager) getIdempotentRequest(
+	ctx context.Context, msg *message.GetIdempotentRequest,
+) (*core.IdempotentRequest, error) {
+	currentPulse, err := m.PulseStorage.Current(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	bus := core.MessageBusFromContext(ctx, m.DefaultBus)
+	sender := BuildSender(bus.Send, retryJetSender(currentPulse.PulseNumber, m.JetStorage))
+	genericReact, err := sender(ctx, msg, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	switch rep := genericReact.(type) {
+	case *reply.IdempotentRequest:
+		if rep.Request == nil {
+			return nil, nil
+		}
+		return &core.IdempotentRequest{
+			Request:  *core.NewRecordRef(*msg.Object.Domain(), *rep.Request),
+			Finished: rep.Finished,
+			Result:   rep.Payload,
+		}, nil
+	case *reply.Error:
+		return nil, rep.Error()
+	default:
+		return nil, fmt.Errorf("GetIdempotentRequest: unexpected reply: %#v", rep)
+	}
+}
+
+// RegisterSeed marks seed as used in call of object until expires pulse.
+//
+// ErrSeedUsed is returned if seed is already used.
+func (m *LedgerArtifactManager) RegisterSeed(
+	ctx context.Context, object core.RecordRef, seed []byte, expires core.PulseNumber,
+) error {
+	var err error
+	ctx, span := instracer.StartSpan(ctx, "artifactmanager.RegisterSeed")
+	instrumenter := instrument(ctx, "RegisterSeed").err(&err)
+	defer func() {
+		if err != nil {
+			span.AddAttributes(trace.StringAttribute("error", err.Error()))
+		}
+		span.End()
+		instrumenter.end()
+	}()
+
+	currentPulse, err := m.PulseStorage.Current(ctx)
+	if err != nil {
+		return err
+	}
+
+	bus := core.MessageBusFromContext(ctx, m.DefaultBus)
+	sender := BuildSender(bus.Send, retryJetSender(currentPulse.PulseNumber, m.JetStorage))
+	genericReact, err := sender(ctx, &message.RegisterSeed{
+		Object:  object,
+		Seed:    seed,
+		Expires: expires,
+	}, nil)
+	if err != nil {
+		return err
+	}
+
+	switch rep := genericReact.(type) {
+	case *reply.OK:
+		return nil
+	case *reply.Error:
+		err = rep.Error()
+		return err
+	default:
+		err = fmt.Errorf("RegisterSeed: unexpected reply: %#v", rep)
+		return err
+	}
+}
+
 // GetDelegate returns provided object's delegate reference for provided prototype.
 //
 // Object delegate should be previously created for this object. If object delegate does not exist, an error will
@@ -459,12 +593,14 @@ func (m *LedgerArtifactManager) DeclareType(
 // DeployCode creates new code record in storage.
 //
 // CodeRef records are used to activate prototype or as migration code for an object.
+// ABI is stored as a separate blob and could be nil.
 func (m *LedgerArtifactManager) DeployCode(
 	ctx context.Context,
 	domain core.RecordRef,
 	request core.RecordRef,
 	code []byte,
 	machineType core.MachineType,
+	abi []byte,
 ) (*core.RecordID, error) {
 	var err error
 	ctx, span := instracer.StartSpan(ctx, "artifactmanager.DeployCode")
@@ -490,6 +626,9 @@ func (m *LedgerArtifactManager) DeployCode(
 		Code:        record.CalculateIDForBlob(m.PlatformCryptographyScheme, currentPulse.PulseNumber, code),
 		MachineType: machineType,
 	}
+	if abi != nil {
+		codeRec.ABI = record.CalculateIDForBlob(m.PlatformCryptographyScheme, currentPulse.PulseNumber, abi)
+	}
 	codeID := record.NewRecordIDFromRecord(m.PlatformCryptographyScheme, currentPulse.PulseNumber, codeRec)
 	codeRef := core.NewRecordRef(*domain.Record(), *codeID)
 
@@ -497,6 +636,12 @@ func (m *LedgerArtifactManager) DeployCode(
 	if err != nil {
 		return nil, err
 	}
+	if abi != nil {
+		_, err = m.setBlob(ctx, abi, *codeRef, *currentPulse)
+		if err != nil {
+			return nil, err
+		}
+	}
 	id, err := m.setRecord(
 		ctx,
 		codeRec,
@@ -300,6 +300,7 @@ func (s *amSuite) TestLedgerArtifactManager_DeployCode_CreatesCorrectRecord() {
 		requestRef,
 		[]byte{1, 2, 3},
 		core.MachineTypeBuiltin,
+		nil,
 	)
 	assert.NoError(s.T(), err)
 	codeRec, err := os.GetRecord(ctx, *jet.NewID(0, nil), id)
@@ -314,6 +315,31 @@ func (s *amSuite) TestLedgerArtifactManager_DeployCode_CreatesCorrectRecord() {
 	})
 }
 
+func (s *amSuite) TestLedgerArtifactManager_DeployCode_StoresABI() {
+	ctx, os, am := getTestData(s)
+	jetID := *jet.NewID(0, nil)
+	abi := []byte(`{"contract":"Test"}`)
+
+	id, err := am.DeployCode(
+		ctx,
+		domainRef,
+		requestRef,
+		[]byte{1, 2, 3},
+		core.MachineTypeBuiltin,
+		abi,
+	)
+	require.NoError(s.T(), err)
+	codeRec, err := os.GetRecord(ctx, jetID, id)
+	require.NoError(s.T(), err)
+
+	abiID := record.CalculateIDForBlob(am.PlatformCryptographyScheme, core.GenesisPulse.PulseNumber, abi)
+	assert.Equal(s.T(), abiID, codeRec.(*record.CodeRecord).ABI)
+
+	blob, err := os.GetBlob(ctx, jetID, abiID)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), abi, blob)
+}
+
 func (s *amSuite) TestLedgerArtifactManager_ActivateObject_CreatesCorrectRecord() {
 	ctx, os, am := getTestData(s)
 	jetID := *jet.NewID(0, nil)
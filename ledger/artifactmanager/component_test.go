@@ -120,6 +120,7 @@ func (s *componentSuite) TestLedgerArtifactManager_PendingRequest() {
 	jcMock := testutils.NewJetCoordinatorMock(s.T())
 	jcMock.LightExecutorForJetMock.Return(&core.RecordRef{}, nil)
 	jcMock.MeMock.Return(core.RecordRef{})
+	jcMock.IsBeyondLimitMock.Return(false, nil)
 
 	certificate := testutils.NewCertificateMock(s.T())
 	certificate.GetRoleMock.Return(core.StaticRoleLightMaterial)
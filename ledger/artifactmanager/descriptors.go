@@ -30,6 +30,8 @@ import (
 // CodeDescriptor represents meta info required to fetch all code data.
 type CodeDescriptor struct {
 	code        []byte
+	abi         []byte
+	hash        []byte
 	machineType core.MachineType
 	ref         core.RecordRef
 
@@ -52,6 +54,16 @@ func (d *CodeDescriptor) Code() ([]byte, error) {
 	return d.code, nil
 }
 
+// ABI returns JSON description of code's interface.
+func (d *CodeDescriptor) ABI() ([]byte, error) {
+	return d.abi, nil
+}
+
+// Hash returns hash of code data from code record.
+func (d *CodeDescriptor) Hash() []byte {
+	return d.hash
+}
+
 // ObjectDescriptor represents meta info required to fetch all object data.
 type ObjectDescriptor struct {
 	ctx context.Context
@@ -25,4 +25,6 @@ var (
 	ErrInvalidRef        = errors.New("invalid reference")
 	ErrObjectDeactivated = errors.New("object is deactivated")
 	ErrNotFound          = errors.New("object not found")
+
+	ErrDuplicateIdempotencyKey = errors.New("request with the same idempotency key is already registered")
 )
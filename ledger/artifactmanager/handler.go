@@ -195,6 +195,20 @@ func (h *MessageHandler) setHandlersForLight(m *middleware) {
 			m.checkJet,
 			m.waitForHotData))
 
+	h.Bus.MustRegister(core.TypeGetIdempotentRequest,
+		BuildMiddleware(h.handleGetIdempotentRequest,
+			instrumentHandler("handleGetIdempotentRequest"),
+			m.addFieldsToLogger,
+			m.checkJet,
+			m.waitForHotData))
+
+	h.Bus.MustRegister(core.TypeRegisterSeed,
+		BuildMiddleware(h.handleRegisterSeed,
+			instrumentHandler("handleRegisterSeed"),
+			m.addFieldsToLogger,
+			m.checkJet,
+			m.waitForHotData))
+
 	h.Bus.MustRegister(core.TypeGetJet,
 		BuildMiddleware(h.handleGetJet,
 			instrumentHandler("handleGetJet")))
@@ -293,6 +307,16 @@ func (h *MessageHandler) setHandlersForHeavy(m *middleware) {
 		BuildMiddleware(h.handleGetObjectIndex,
 			instrumentHandler("handleGetObjectIndex"),
 			m.zeroJetForHeavy))
+
+	h.Bus.MustRegister(core.TypeGetRequestTrace,
+		BuildMiddleware(h.handleGetRequestTrace,
+			instrumentHandler("handleGetRequestTrace"),
+			m.zeroJetForHeavy))
+
+	h.Bus.MustRegister(core.TypeGetIdempotentRequest,
+		BuildMiddleware(h.handleGetIdempotentRequest,
+			instrumentHandler("handleGetIdempotentRequest"),
+			m.zeroJetForHeavy))
 }
 
 func (h *MessageHandler) handleSetRecord(ctx context.Context, parcel core.Parcel) (core.Reply, error) {
@@ -306,6 +330,14 @@ func (h *MessageHandler) handleSetRecord(ctx context.Context, parcel core.Parcel
 
 	id := record.NewRecordIDFromRecord(h.PlatformCryptographyScheme, parcel.Pulse(), rec)
 
+	if r, ok := rec.(*record.RequestRecord); ok && r.IdempotencyKey != "" {
+		object := core.NewRecordRef(*msg.TargetRef.Domain(), r.Object)
+		err := h.registerIdempotentRequest(ctx, parcel, jetID, *object, r, *id)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	switch r := rec.(type) {
 	case record.Request:
 		recentStorage := h.RecentStorageProvider.GetPendingStorage(ctx, jetID)
@@ -315,16 +347,166 @@ func (h *MessageHandler) handleSetRecord(ctx context.Context, parcel core.Parcel
 		recentStorage.RemovePendingRequest(ctx, r.Object, *r.Request.Record())
 	}
 
-	id, err := h.ObjectStorage.SetRecord(ctx, jetID, parcel.Pulse(), rec)
+	// Storage returns no id on override, id calculated above is used then.
+	_, err := h.ObjectStorage.SetRecord(ctx, jetID, parcel.Pulse(), rec)
 	if err == storage.ErrOverride {
 		inslogger.FromContext(ctx).WithField("type", fmt.Sprintf("%T", rec)).Warnln("set record override")
 	} else if err != nil {
 		return nil, err
 	}
 
+	if r, ok := rec.(*record.ResultRecord); ok {
+		object := core.NewRecordRef(*msg.TargetRef.Domain(), r.Object)
+		err := h.setIdempotentResult(ctx, parcel, jetID, *object, r, *id)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return &reply.ID{ID: *id}, nil
 }
 
+// idempotencyKeyTTL is a number of pulses object remembers requests registered with idempotency keys.
+// Pulse numbers are seconds, so keys are kept for a day.
+const idempotencyKeyTTL = 24 * 60 * 60
+
+// registerIdempotentRequest remembers request by its idempotency key in a separate record of called object.
+// Registering another request with the key that is already known fails, registering the same request again is allowed.
+func (h *MessageHandler) registerIdempotentRequest(
+	ctx context.Context, parcel core.Parcel, jetID core.RecordID, object core.RecordRef,
+	req *record.RequestRecord, id core.RecordID,
+) error {
+	return h.updateObjectIndex(ctx, parcel, jetID, object, func(tx *storage.TransactionManager, idx *index.ObjectLifeline) error {
+		// Object is already locked with its lifeline.
+		prev, err := tx.GetIdempotentRequest(ctx, jetID, object.Record(), req.IdempotencyKey, false)
+		if err == storage.ErrNotFound {
+			// Keys registered before light chain limit could be synced to heavy and removed from our node.
+			prev, err = h.getIdempotentRequestFromHeavy(ctx, parcel, object.Record().Pulse(), &message.GetIdempotentRequest{
+				Object: object,
+				Key:    req.IdempotencyKey,
+			})
+		}
+		if err != nil {
+			return err
+		}
+		if prev != nil && prev.Request == id {
+			return nil
+		}
+		if prev != nil && prev.Request.Pulse()+idempotencyKeyTTL >= parcel.Pulse() {
+			return ErrDuplicateIdempotencyKey
+		}
+
+		return tx.SetIdempotentRequest(ctx, jetID, object.Record(), &index.IdempotentRequest{
+			Key:     req.IdempotencyKey,
+			Request: id,
+		})
+	})
+}
+
+// updateObjectIndex changes lifeline of object with provided function. Lifeline is fetched from heavy if it is
+// not on our node.
+func (h *MessageHandler) updateObjectIndex(
+	ctx context.Context, parcel core.Parcel, jetID core.RecordID, object core.RecordRef,
+	update func(tx *storage.TransactionManager, idx *index.ObjectLifeline) error,
+) error {
+	h.RecentStorageProvider.GetIndexStorage(ctx, jetID).AddObject(ctx, *object.Record())
+
+	return h.DBContext.Update(ctx, func(tx *storage.TransactionManager) error {
+		idx, err := tx.GetObjectIndex(ctx, jetID, object.Record(), true)
+		if err == storage.ErrNotFound {
+			heavy, err := h.JetCoordinator.Heavy(ctx, parcel.Pulse())
+			if err != nil {
+				return err
+			}
+			idx, err = h.saveIndexFromHeavy(ctx, jetID, object, heavy)
+			if err != nil {
+				return errors.Wrap(err, "failed to fetch index from heavy")
+			}
+		} else if err != nil {
+			return err
+		}
+
+		err = update(tx, idx)
+		if err != nil {
+			return err
+		}
+		idx.LatestUpdate = parcel.Pulse()
+		return tx.SetObjectIndex(ctx, jetID, object.Record(), idx)
+	})
+}
+
+// setIdempotentResult saves result of request registered with idempotency key on called object.
+func (h *MessageHandler) setIdempotentResult(
+	ctx context.Context, parcel core.Parcel, jetID core.RecordID, object core.RecordRef,
+	res *record.ResultRecord, id core.RecordID,
+) error {
+	return h.DBContext.Update(ctx, func(tx *storage.TransactionManager) error {
+		var req *index.IdempotentRequest
+		// Most of requests have no keys, so object is locked only if request has to be changed.
+		key, err := tx.GetIdempotencyKey(ctx, jetID, object.Record(), res.Request.Record())
+		if err == nil {
+			req, err = tx.GetIdempotentRequest(ctx, jetID, object.Record(), key, true)
+		} else if err == storage.ErrNotFound {
+			req, err = h.getIdempotentRequestFromHeavy(ctx, parcel, res.Request.Record().Pulse(), &message.GetIdempotentRequest{
+				Object:  object,
+				Request: res.Request.Record(),
+			})
+		}
+		if err != nil {
+			return err
+		}
+		if req == nil {
+			return nil
+		}
+
+		req.Result = &id
+		return tx.SetIdempotentRequest(ctx, jetID, object.Record(), req)
+	})
+}
+
+// isIdempotentOnHeavy tells if request registered with idempotency key on provided pulse could be synced to heavy and
+// removed from our node. Recent requests are passed between light nodes with hot data.
+func (h *MessageHandler) isIdempotentOnHeavy(
+	ctx context.Context, parcel core.Parcel, registered core.PulseNumber,
+) (bool, error) {
+	if h.isHeavy {
+		return false, nil
+	}
+	return h.JetCoordinator.IsBeyondLimit(ctx, parcel.Pulse(), registered)
+}
+
+// getIdempotentRequestFromHeavy fetches request registered with idempotency key from heavy if it could be there.
+//
+// Nil is returned if there is no such request.
+func (h *MessageHandler) getIdempotentRequestFromHeavy(
+	ctx context.Context, parcel core.Parcel, registered core.PulseNumber, msg *message.GetIdempotentRequest,
+) (*index.IdempotentRequest, error) {
+	onHeavy, err := h.isIdempotentOnHeavy(ctx, parcel, registered)
+	if err != nil || !onHeavy {
+		return nil, err
+	}
+	heavy, err := h.JetCoordinator.Heavy(ctx, parcel.Pulse())
+	if err != nil {
+		return nil, err
+	}
+	genericReply, err := h.Bus.Send(ctx, msg, &core.MessageSendOptions{Receiver: heavy})
+	if err != nil {
+		return nil, err
+	}
+
+	switch rep := genericReply.(type) {
+	case *reply.IdempotentRequest:
+		if rep.Request == nil {
+			return nil, nil
+		}
+		return &index.IdempotentRequest{Key: rep.Key, Request: *rep.Request}, nil
+	case *reply.Error:
+		return nil, rep.Error()
+	default:
+		return nil, fmt.Errorf("getIdempotentRequestFromHeavy: unexpected reply: %#v", rep)
+	}
+}
+
 func (h *MessageHandler) handleSetBlob(ctx context.Context, parcel core.Parcel) (core.Reply, error) {
 	if h.isHeavy {
 		return nil, errors.New("heavy updates are forbidden")
@@ -372,10 +554,19 @@ func (h *MessageHandler) handleGetCode(ctx context.Context, parcel core.Parcel)
 	if err != nil {
 		return nil, err
 	}
+	var abi []byte
+	if codeRec.ABI != nil {
+		abi, err = h.ObjectStorage.GetBlob(ctx, jetID, codeRec.ABI)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	rep := reply.Code{
 		Code:        code,
 		MachineType: codeRec.MachineType,
+		ABI:         abi,
+		Hash:        codeRec.Code.Hash(),
 	}
 
 	return &rep, nil
@@ -734,6 +925,128 @@ func (h *MessageHandler) handleGetRequest(ctx context.Context, parcel core.Parce
 	return &rep, nil
 }
 
+// requestTraceDepth is a number of pulses after request searched for its result and requests it made
+const requestTraceDepth = 10
+
+func (h *MessageHandler) handleGetRequestTrace(ctx context.Context, parcel core.Parcel) (core.Reply, error) {
+	jetID := jetFromContext(ctx)
+	msg := parcel.Message().(*message.GetRequestTrace)
+
+	rec, err := h.ObjectStorage.GetRecord(ctx, jetID, &msg.Request)
+	if err == storage.ErrNotFound {
+		return nil, errors.New("request not found, it may be not synced with heavy yet")
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch request")
+	}
+	req, ok := rec.(*record.RequestRecord)
+	if !ok {
+		return nil, errors.New("record is not a request")
+	}
+
+	rep := reply.RequestTrace{Parcel: req.Parcel}
+	results := map[core.RecordID]*record.ResultRecord{}
+	finished := map[core.RecordID]core.PulseNumber{}
+	pn := msg.Request.Pulse()
+	for i := 0; i < requestTraceDepth; i++ {
+		err := h.DBContext.IterateRecordsOnPulse(ctx, jetID, pn, func(id core.RecordID, rec record.Record) error {
+			switch r := rec.(type) {
+			case *record.ResultRecord:
+				results[*r.Request.Record()] = r
+				finished[*r.Request.Record()] = pn
+			case *record.RequestRecord:
+				if *r.Parent.Record() == msg.Request {
+					rep.Children = append(rep.Children, reply.TracedRequest{ID: id, Object: r.Object, Parcel: r.Parcel})
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to iterate records")
+		}
+
+		pulse, err := h.PulseTracker.GetPulse(ctx, pn)
+		if err != nil || pulse.Next == nil {
+			break
+		}
+		pn = *pulse.Next
+	}
+
+	finishedOn := msg.Request.Pulse()
+	if res, ok := results[msg.Request]; ok {
+		rep.Result = res.Payload
+		rep.Finished = true
+		finishedOn = finished[msg.Request]
+	}
+	for i, child := range rep.Children {
+		if res, ok := results[child.ID]; ok {
+			rep.Children[i].Result = res.Payload
+			rep.Children[i].Finished = true
+		}
+	}
+
+	err = h.findRequestStates(ctx, jetID, req.Object, msg.Request, finishedOn, &rep)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rep, nil
+}
+
+// findRequestStates walks states of object back from the latest one, looking for the state
+// request made. If request didn't change object, state object had at the end of pulse
+// request was finished on is taken as the one request was executed on.
+func (h *MessageHandler) findRequestStates(
+	ctx context.Context, jetID core.RecordID, object core.RecordID, request core.RecordID, finishedOn core.PulseNumber,
+	rep *reply.RequestTrace,
+) error {
+	idx, err := h.ObjectStorage.GetObjectIndex(ctx, jetID, &object, false)
+	if err != nil {
+		return errors.Wrapf(err, "failed to fetch object index %s", object.DebugString())
+	}
+
+	stateID := idx.LatestState
+	for stateID != nil && stateID.Pulse() >= request.Pulse() {
+		rec, err := h.ObjectStorage.GetRecord(ctx, jetID, stateID)
+		if err != nil {
+			return errors.Wrap(err, "failed to fetch object state")
+		}
+		state, ok := rec.(record.ObjectState)
+		if !ok {
+			return errors.New("invalid object record")
+		}
+
+		stateReq := stateRequest(state)
+		if *stateReq.Record() == request {
+			rep.After = stateID
+			rep.Before = state.PrevStateID()
+			rep.Deactivated = state.State() == record.StateDeactivation
+			return nil
+		}
+		if rep.Before == nil && stateID.Pulse() <= finishedOn {
+			rep.Before = stateID
+		}
+		stateID = state.PrevStateID()
+	}
+	if rep.Before == nil {
+		rep.Before = stateID
+	}
+	return nil
+}
+
+// stateRequest returns reference to request that made state
+func stateRequest(state record.ObjectState) core.RecordRef {
+	switch s := state.(type) {
+	case *record.ObjectActivateRecord:
+		return s.Request
+	case *record.ObjectAmendRecord:
+		return s.Request
+	case *record.DeactivationRecord:
+		return s.Request
+	}
+	return core.RecordRef{}
+}
+
 func (h *MessageHandler) handleGetPendingRequestID(ctx context.Context, parcel core.Parcel) (core.Reply, error) {
 	jetID := jetFromContext(ctx)
 	msg := parcel.Message().(*message.GetPendingRequestID)
@@ -750,6 +1063,98 @@ func (h *MessageHandler) handleGetPendingRequestID(ctx context.Context, parcel c
 	return &rep, nil
 }
 
+func (h *MessageHandler) handleGetIdempotentRequest(ctx context.Context, parcel core.Parcel) (core.Reply, error) {
+	jetID := jetFromContext(ctx)
+	msg := parcel.Message().(*message.GetIdempotentRequest)
+
+	fromHeavy := func() (core.Reply, error) {
+		heavy, err := h.JetCoordinator.Heavy(ctx, parcel.Pulse())
+		if err != nil {
+			return nil, err
+		}
+		return h.Bus.Send(ctx, msg, &core.MessageSendOptions{Receiver: heavy})
+	}
+	notFound := func(registered core.PulseNumber) (core.Reply, error) {
+		onHeavy, err := h.isIdempotentOnHeavy(ctx, parcel, registered)
+		if err != nil {
+			return nil, err
+		}
+		if onHeavy {
+			return fromHeavy()
+		}
+		return &reply.IdempotentRequest{}, nil
+	}
+
+	key := msg.Key
+	if msg.Request != nil {
+		var err error
+		key, err = h.ObjectStorage.GetIdempotencyKey(ctx, jetID, msg.Object.Record(), msg.Request)
+		if err == storage.ErrNotFound {
+			return notFound(msg.Request.Pulse())
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to fetch idempotency key")
+		}
+	}
+
+	req, err := h.ObjectStorage.GetIdempotentRequest(ctx, jetID, msg.Object.Record(), key)
+	if err == storage.ErrNotFound {
+		return notFound(msg.Object.Record().Pulse())
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch idempotent request")
+	}
+	rep := reply.IdempotentRequest{Key: req.Key, Request: &req.Request}
+	if req.Result == nil {
+		return &rep, nil
+	}
+
+	rec, err := h.ObjectStorage.GetRecord(ctx, jetID, req.Result)
+	// Result could be saved on other light node in previous pulses.
+	if err == storage.ErrNotFound && !h.isHeavy {
+		return fromHeavy()
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch result")
+	}
+	res, ok := rec.(*record.ResultRecord)
+	if !ok {
+		return nil, errors.New("record is not a result")
+	}
+	rep.Finished = true
+	rep.Payload = res.Payload
+
+	return &rep, nil
+}
+
+func (h *MessageHandler) handleRegisterSeed(ctx context.Context, parcel core.Parcel) (core.Reply, error) {
+	if h.isHeavy {
+		return nil, errors.New("heavy updates are forbidden")
+	}
+
+	msg := parcel.Message().(*message.RegisterSeed)
+	jetID := jetFromContext(ctx)
+
+	err := h.DBContext.Update(ctx, func(tx *storage.TransactionManager) error {
+		used, err := tx.IsSeedUsed(ctx, jetID, msg.Object.Record(), msg.Seed, msg.Expires, true)
+		if err != nil {
+			return err
+		}
+		if used {
+			return core.ErrSeedUsed
+		}
+		return tx.SetSeedUsed(ctx, jetID, msg.Object.Record(), msg.Seed, msg.Expires)
+	})
+	if err == core.ErrSeedUsed {
+		return &reply.Error{ErrType: reply.ErrSeedUsed}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &reply.OK{}, nil
+}
+
 func (h *MessageHandler) handleUpdateObject(ctx context.Context, parcel core.Parcel) (core.Reply, error) {
 	if h.isHeavy {
 		return nil, errors.New("heavy updates are forbidden")
@@ -1191,9 +1596,28 @@ func (h *MessageHandler) handleHotRecords(ctx context.Context, parcel core.Parce
 			continue
 		}
 
+		for _, encoded := range meta.IdempotentRequests {
+			req, err := index.DecodeIdempotentRequest(encoded)
+			if err != nil {
+				logger.Error(err)
+				continue
+			}
+			err = h.ObjectStorage.SetIdempotentRequest(ctx, jetID, &id, req)
+			if err != nil {
+				logger.Error(err)
+			}
+		}
+
 		indexStorage.AddObjectWithTLL(ctx, id, meta.TTL)
 	}
 
+	for _, used := range msg.UsedSeeds {
+		err = h.ObjectStorage.SetSeedUsed(ctx, jetID, &used.Object, used.Seed, used.Expires)
+		if err != nil {
+			logger.Error(err)
+		}
+	}
+
 	err = h.JetStorage.UpdateJetTree(
 		ctx, msg.PulseNumber, true, jetID,
 	)
@@ -1274,3 +1274,175 @@ func (s *handlerSuite) TestMessageHandler_HandleGetRequest() {
 	require.True(s.T(), ok)
 	assert.Equal(s.T(), req, *record.DeserializeRecord(reqReply.Record).(*record.RequestRecord))
 }
+
+func (s *handlerSuite) TestMessageHandler_HandleGetIdempotentRequest() {
+	mc := minimock.NewController(s.T())
+	defer mc.Finish()
+
+	jetID := *jet.NewID(0, nil)
+	objRef := genRandomRef(core.FirstPulseNumber)
+	err := s.objectStorage.SetObjectIndex(s.ctx, jetID, objRef.Record(), &index.ObjectLifeline{
+		State: record.StateActivation,
+	})
+	require.NoError(s.T(), err)
+
+	certificate := testutils.NewCertificateMock(s.T())
+	certificate.GetRoleMock.Return(core.StaticRoleLightMaterial)
+
+	heavyRef := genRandomRef(0)
+	jc := testutils.NewJetCoordinatorMock(mc)
+	jc.IsBeyondLimitFunc = func(p context.Context, current, target core.PulseNumber) (bool, error) {
+		return current-target >= 10, nil
+	}
+	jc.HeavyMock.Return(heavyRef, nil)
+	oldReqID := genRandomID(core.FirstPulseNumber)
+	mb := testutils.NewMessageBusMock(mc)
+	mb.SendFunc = func(c context.Context, gm core.Message, o *core.MessageSendOptions) (core.Reply, error) {
+		require.Equal(s.T(), heavyRef, o.Receiver)
+		msg, ok := gm.(*message.GetIdempotentRequest)
+		require.True(s.T(), ok)
+		if msg.Key == "old" || (msg.Request != nil && *msg.Request == *oldReqID) {
+			return &reply.IdempotentRequest{Key: "old", Request: oldReqID}, nil
+		}
+		return &reply.IdempotentRequest{}, nil
+	}
+
+	h := NewMessageHandler(&configuration.Ledger{}, certificate)
+	h.ObjectStorage = s.objectStorage
+	h.DBContext = s.db
+	h.PlatformCryptographyScheme = s.scheme
+	h.RecentStorageProvider = recentstorage.NewRecentStorageProvider(0)
+	h.JetCoordinator = jc
+	h.Bus = mb
+
+	ctx := contextWithJet(s.ctx, jetID)
+	setRecord := func(rec record.Record, pulse core.PulseNumber) (core.Reply, error) {
+		return h.handleSetRecord(ctx, &message.Parcel{
+			Msg:         &message.SetRecord{Record: record.SerializeRecord(rec), TargetRef: *objRef},
+			PulseNumber: pulse,
+		})
+	}
+	getRequest := func(msg *message.GetIdempotentRequest, pulse core.PulseNumber) *reply.IdempotentRequest {
+		rep, err := h.handleGetIdempotentRequest(ctx, &message.Parcel{
+			Msg:         msg,
+			PulseNumber: pulse,
+		})
+		require.NoError(s.T(), err)
+		return rep.(*reply.IdempotentRequest)
+	}
+
+	rep, err := setRecord(&record.RequestRecord{
+		MessageHash:    []byte{1},
+		Object:         *objRef.Record(),
+		IdempotencyKey: "key",
+	}, core.FirstPulseNumber+1)
+	require.NoError(s.T(), err)
+	reqID := rep.(*reply.ID).ID
+
+	// The same request can be registered again.
+	_, err = setRecord(&record.RequestRecord{
+		MessageHash:    []byte{1},
+		Object:         *objRef.Record(),
+		IdempotencyKey: "key",
+	}, core.FirstPulseNumber+1)
+	require.NoError(s.T(), err)
+
+	_, err = setRecord(&record.RequestRecord{
+		MessageHash:    []byte{2},
+		Object:         *objRef.Record(),
+		IdempotencyKey: "key",
+	}, core.FirstPulseNumber+1)
+	require.Equal(s.T(), ErrDuplicateIdempotencyKey, err)
+
+	// Recent object can't have requests on heavy.
+	assert.Nil(s.T(), getRequest(&message.GetIdempotentRequest{Object: *objRef, Key: "unknown"}, core.FirstPulseNumber+1).Request)
+	assert.Equal(s.T(), uint64(0), mb.SendCounter)
+
+	inProgress := getRequest(&message.GetIdempotentRequest{Object: *objRef, Key: "key"}, core.FirstPulseNumber+1)
+	require.NotNil(s.T(), inProgress.Request)
+	assert.Equal(s.T(), reqID, *inProgress.Request)
+	assert.False(s.T(), inProgress.Finished)
+
+	_, err = setRecord(&record.ResultRecord{
+		Object:  *objRef.Record(),
+		Request: *core.NewRecordRef(*objRef.Domain(), reqID),
+		Payload: []byte{3, 4},
+	}, core.FirstPulseNumber+1)
+	require.NoError(s.T(), err)
+
+	finished := getRequest(&message.GetIdempotentRequest{Object: *objRef, Key: "key"}, core.FirstPulseNumber+1)
+	assert.Equal(s.T(), reqID, *finished.Request)
+	assert.True(s.T(), finished.Finished)
+	assert.Equal(s.T(), []byte{3, 4}, finished.Payload)
+
+	byRequest := getRequest(&message.GetIdempotentRequest{Object: *objRef, Request: &reqID}, core.FirstPulseNumber+1)
+	assert.Equal(s.T(), "key", byRequest.Key)
+	assert.Equal(s.T(), reqID, *byRequest.Request)
+	assert.True(s.T(), byRequest.Finished)
+
+	// Keys of old objects are searched on heavy.
+	_, err = setRecord(&record.RequestRecord{
+		MessageHash:    []byte{3},
+		Object:         *objRef.Record(),
+		IdempotencyKey: "old",
+	}, core.FirstPulseNumber+20)
+	require.Equal(s.T(), ErrDuplicateIdempotencyKey, err)
+	_, err = setRecord(&record.RequestRecord{
+		MessageHash:    []byte{4},
+		Object:         *objRef.Record(),
+		IdempotencyKey: "new",
+	}, core.FirstPulseNumber+20)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), uint64(2), mb.SendCounter)
+
+	// Results of old requests are saved with keys from heavy.
+	_, err = setRecord(&record.ResultRecord{
+		Object:  *objRef.Record(),
+		Request: *core.NewRecordRef(*objRef.Domain(), *oldReqID),
+		Payload: []byte{5},
+	}, core.FirstPulseNumber+20)
+	require.NoError(s.T(), err)
+	saved, err := s.objectStorage.GetIdempotentRequest(s.ctx, jetID, objRef.Record(), "old")
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), *oldReqID, saved.Request)
+	assert.NotNil(s.T(), saved.Result)
+}
+
+func (s *handlerSuite) TestMessageHandler_HandleRegisterSeed() {
+	jetID := *jet.NewID(0, nil)
+	objRef := genRandomRef(0)
+
+	certificate := testutils.NewCertificateMock(s.T())
+	certificate.GetRoleMock.Return(core.StaticRoleLightMaterial)
+
+	h := NewMessageHandler(&configuration.Ledger{}, certificate)
+	h.ObjectStorage = s.objectStorage
+	h.DBContext = s.db
+
+	ctx := contextWithJet(s.ctx, jetID)
+	registerSeed := func(seed []byte, pulse core.PulseNumber) core.Reply {
+		rep, err := h.handleRegisterSeed(ctx, &message.Parcel{
+			Msg:         &message.RegisterSeed{Object: *objRef, Seed: seed, Expires: core.FirstPulseNumber + 10},
+			PulseNumber: pulse,
+		})
+		require.NoError(s.T(), err)
+		return rep
+	}
+
+	assert.Equal(s.T(), &reply.OK{}, registerSeed([]byte{1}, core.FirstPulseNumber))
+	assert.Equal(s.T(), &reply.OK{}, registerSeed([]byte{2}, core.FirstPulseNumber))
+	assert.Equal(s.T(), &reply.Error{ErrType: reply.ErrSeedUsed}, registerSeed([]byte{1}, core.FirstPulseNumber+10))
+
+	// Seeds are kept apart from object lifeline.
+	_, err := s.objectStorage.GetObjectIndex(s.ctx, jetID, objRef.Record(), false)
+	assert.Equal(s.T(), storage.ErrNotFound, err)
+	var seeds [][]byte
+	err = s.objectStorage.IterateUsedSeeds(s.ctx, jetID, func(object core.RecordID, seed []byte, expires core.PulseNumber) error {
+		assert.Equal(s.T(), *objRef.Record(), object)
+		assert.Equal(s.T(), core.FirstPulseNumber+10, int(expires))
+		seeds = append(seeds, seed)
+		return nil
+	})
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), [][]byte{{1}, {2}}, seeds)
+}
@@ -314,9 +314,15 @@ func (m *PulseManager) getExecutorHotData(
 			logger.Error(err)
 			continue
 		}
+		idempotentRequests, err := m.getHotIdempotentRequests(ctx, jetID, id, pulse)
+		if err != nil {
+			logger.Error(err)
+			continue
+		}
 		recentObjects[id] = message.HotIndex{
-			TTL:   ttl,
-			Index: encoded,
+			TTL:                ttl,
+			Index:              encoded,
+			IdempotentRequests: idempotentRequests,
 		}
 	}
 
@@ -339,6 +345,17 @@ func (m *PulseManager) getExecutorHotData(
 		return nil, errors.Wrap(err, "[ processRecentObjects ] Can't GetDropSizeHistory")
 	}
 
+	var usedSeeds []message.UsedSeed
+	err = m.ObjectStorage.IterateUsedSeeds(ctx, jetID, func(object core.RecordID, seed []byte, expires core.PulseNumber) error {
+		if expires >= pulse {
+			usedSeeds = append(usedSeeds, message.UsedSeed{Object: object, Seed: seed, Expires: expires})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "[ processRecentObjects ] Can't IterateUsedSeeds")
+	}
+
 	msg := &message.HotData{
 		Drop:               *drop,
 		DropJet:            jetID,
@@ -346,10 +363,35 @@ func (m *PulseManager) getExecutorHotData(
 		RecentObjects:      recentObjects,
 		PendingRequests:    pendingRequests,
 		JetDropSizeHistory: dropSizeHistory,
+		UsedSeeds:          usedSeeds,
 	}
 	return msg, nil
 }
 
+// getHotIdempotentRequests returns requests registered on object with idempotency keys within light chain limit.
+// Older requests are fetched from heavy.
+func (m *PulseManager) getHotIdempotentRequests(
+	ctx context.Context,
+	jetID core.RecordID,
+	object core.RecordID,
+	pulse core.PulseNumber,
+) ([][]byte, error) {
+	var hot [][]byte
+	err := m.ObjectStorage.IterateIdempotentRequests(ctx, jetID, &object, func(req *index.IdempotentRequest) error {
+		beyond, err := m.JetCoordinator.IsBeyondLimit(ctx, pulse, req.Request.Pulse())
+		if err != nil || beyond {
+			return err
+		}
+		encoded, err := index.EncodeIdempotentRequest(req)
+		if err != nil {
+			return err
+		}
+		hot = append(hot, encoded)
+		return nil
+	})
+	return hot, err
+}
+
 // TODO: @andreyromancev. 12.01.19. Remove when dynamic split is working.
 var splitCount = 5
 
@@ -108,6 +108,13 @@ func (c *cleaner) CleanJetRecordsUntilPulse(
 	}
 	allstat["drops"] = stat
 
+	if stat, err = c.RemoveJetSeedsUntil(ctx, jetID, pn); err != nil {
+		result = multierror.Append(result, errors.Wrap(err, "RemoveJetSeedsUntil"))
+		stat.Errors = stat.Scanned
+		stat.Removed = 0
+	}
+	allstat["seeds"] = stat
+
 	recordCleanupMetrics(ctx, allstat)
 
 	return allstat, result
@@ -129,6 +136,11 @@ func (c *cleaner) RemoveJetDropsUntil(ctx context.Context, jetID core.RecordID,
 	return c.removeJetRecordsUntil(ctx, scopeIDJetDrop, jetID, pn)
 }
 
+// RemoveJetSeedsUntil removes for provided JetID all used seeds expired before provided pulse number.
+func (c *cleaner) RemoveJetSeedsUntil(ctx context.Context, jetID core.RecordID, pn core.PulseNumber) (RmStat, error) {
+	return c.removeJetRecordsUntil(ctx, scopeIDSeed, jetID, pn)
+}
+
 func (c *cleaner) removeJetRecordsUntil(
 	ctx context.Context,
 	namespace byte,
@@ -179,7 +191,12 @@ func (c *cleaner) CleanJetIndexes(
 		for _, recID := range fordelete {
 			stat.Scanned++
 			key := prefixkey(scopeIDLifeline, prefix, recID[:])
+			idempotencyPrefix := prefixkey(scopeIDIdempotency, prefix, recID[:])
 			err := c.DB.GetBadgerDB().Update(func(txn *badger.Txn) error {
+				err := removeByPrefix(txn, idempotencyPrefix)
+				if err != nil {
+					return err
+				}
 				return txn.Delete(key)
 			})
 			if err != nil {
@@ -198,3 +215,22 @@ func (c *cleaner) CleanJetIndexes(
 	)
 	return stat, nil
 }
+
+// removeByPrefix removes all keys with provided prefix.
+func removeByPrefix(txn *badger.Txn, prefix []byte) error {
+	opts := badger.DefaultIteratorOptions
+	opts.PrefetchValues = false
+	it := txn.NewIterator(opts)
+	defer it.Close()
+
+	var keys [][]byte
+	for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
+		keys = append(keys, it.Item().KeyCopy(nil))
+	}
+	for _, key := range keys {
+		if err := txn.Delete(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
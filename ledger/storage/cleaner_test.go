@@ -26,6 +26,7 @@ import (
 	"github.com/insolar/insolar/instrumentation/inslogger"
 	"github.com/insolar/insolar/ledger/recentstorage"
 	"github.com/insolar/insolar/ledger/storage"
+	"github.com/insolar/insolar/ledger/storage/index"
 	"github.com/insolar/insolar/ledger/storage/storagetest"
 	"github.com/insolar/insolar/platformpolicy"
 	"github.com/insolar/insolar/testutils"
@@ -160,6 +161,20 @@ func (s *cleanerSuite) Test_RemoveRecords() {
 				cleanCase:   dropCC,
 				dropStorage: s.dropStorage,
 			})
+
+			err = s.objectStorage.SetSeedUsed(ctx, jetID, recID, []byte{1}, pn)
+			require.NoError(t, err)
+			seedCC := cleanCase{
+				rectype:    "seed",
+				id:         recID,
+				jetID:      jetID,
+				pulseNum:   pn,
+				shouldLeft: shouldLeft,
+			}
+			checks = append(checks, seedCase{
+				cleanCase:     seedCC,
+				objectStorage: s.objectStorage,
+			})
 		}
 	}
 
@@ -190,6 +205,11 @@ func (s *cleanerSuite) Test_RemoveJetIndexes() {
 			pn := core.PulseNumber(core.FirstPulseNumber + i)
 			idxID, err := storagetest.AddRandIndex(ctx, s.objectStorage, jetID, pn)
 			require.NoError(t, err)
+			err = s.objectStorage.SetIdempotentRequest(ctx, jetID, idxID, &index.IdempotentRequest{
+				Key:     "key",
+				Request: testutils.RandomID(),
+			})
+			require.NoError(t, err)
 
 			shouldLeft := true
 			if jetID == rmJetID {
@@ -208,6 +228,9 @@ func (s *cleanerSuite) Test_RemoveJetIndexes() {
 			checks = append(checks, indexCase{
 				cleanCase:     cc,
 				objectStorage: s.objectStorage,
+			}, idempotentCase{
+				cleanCase:     cc,
+				objectStorage: s.objectStorage,
 			})
 		}
 	}
@@ -266,6 +289,33 @@ func (c indexCase) Check(ctx context.Context, t *testing.T) {
 	c.check(t, err)
 }
 
+type idempotentCase struct {
+	cleanCase
+	objectStorage storage.ObjectStorage
+}
+
+func (c idempotentCase) Check(ctx context.Context, t *testing.T) {
+	_, err := c.objectStorage.GetIdempotentRequest(ctx, c.jetID, c.id, "key")
+	c.check(t, err)
+}
+
+type seedCase struct {
+	cleanCase
+	objectStorage storage.ObjectStorage
+}
+
+func (c seedCase) Check(ctx context.Context, t *testing.T) {
+	err := storage.ErrNotFound
+	iterErr := c.objectStorage.IterateUsedSeeds(ctx, c.jetID, func(object core.RecordID, seed []byte, expires core.PulseNumber) error {
+		if object == *c.id && expires == c.pulseNum {
+			err = nil
+		}
+		return nil
+	})
+	require.NoError(t, iterErr)
+	c.check(t, err)
+}
+
 type blobCase struct {
 	cleanCase
 	objectStorage storage.ObjectStorage
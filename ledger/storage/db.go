@@ -39,6 +39,11 @@ const (
 	scopeIDMessage  byte = 6
 	scopeIDBlob     byte = 7
 	scopeIDLocal    byte = 8
+	// scopeIDIdempotency keeps requests registered on objects with idempotency keys.
+	scopeIDIdempotency byte = 9
+	// scopeIDSeed keeps seeds used in calls of objects by pulses they expire on. Seeds are passed between light
+	// nodes with hot data until they expire, so they are not synced to heavy.
+	scopeIDSeed byte = 10
 
 	sysGenesis                byte = 1
 	sysLatestPulse            byte = 2
@@ -47,6 +52,9 @@ const (
 	sysJetTree                byte = 5
 	sysJetList                byte = 6
 	sysDropSizeHistory        byte = 7
+
+	idempotencyByKey     byte = 1
+	idempotencyByRequest byte = 2
 )
 
 // DBContext provides base db methods
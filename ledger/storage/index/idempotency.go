@@ -0,0 +1,53 @@
+/*
+ *    Copyright 2019 Insolar Technologies
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package index
+
+import (
+	"bytes"
+
+	"github.com/insolar/insolar/core"
+	"github.com/ugorji/go/codec"
+)
+
+// IdempotentRequest is a request registered on object with idempotency key and its result if request is finished.
+type IdempotentRequest struct {
+	Key     string
+	Request core.RecordID
+	Result  *core.RecordID
+}
+
+// EncodeIdempotentRequest converts idempotent request into binary format.
+func EncodeIdempotentRequest(req *IdempotentRequest) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := codec.NewEncoder(&buf, &codec.CborHandle{})
+	err := enc.Encode(req)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// DecodeIdempotentRequest converts byte array into idempotent request struct.
+func DecodeIdempotentRequest(buf []byte) (*IdempotentRequest, error) {
+	dec := codec.NewDecoder(bytes.NewReader(buf), &codec.CborHandle{})
+	var req IdempotentRequest
+	err := dec.Decode(&req)
+	if err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
@@ -27,6 +27,16 @@ type ObjectStorageMock struct {
 	GetBlobPreCounter uint64
 	GetBlobMock       mObjectStorageMockGetBlob
 
+	GetIdempotencyKeyFunc       func(p context.Context, p1 core.RecordID, p2 *core.RecordID, p3 *core.RecordID) (r string, r1 error)
+	GetIdempotencyKeyCounter    uint64
+	GetIdempotencyKeyPreCounter uint64
+	GetIdempotencyKeyMock       mObjectStorageMockGetIdempotencyKey
+
+	GetIdempotentRequestFunc       func(p context.Context, p1 core.RecordID, p2 *core.RecordID, p3 string) (r *index.IdempotentRequest, r1 error)
+	GetIdempotentRequestCounter    uint64
+	GetIdempotentRequestPreCounter uint64
+	GetIdempotentRequestMock       mObjectStorageMockGetIdempotentRequest
+
 	GetObjectIndexFunc       func(p context.Context, p1 core.RecordID, p2 *core.RecordID, p3 bool) (r *index.ObjectLifeline, r1 error)
 	GetObjectIndexCounter    uint64
 	GetObjectIndexPreCounter uint64
@@ -37,11 +47,21 @@ type ObjectStorageMock struct {
 	GetRecordPreCounter uint64
 	GetRecordMock       mObjectStorageMockGetRecord
 
+	IterateIdempotentRequestsFunc       func(p context.Context, p1 core.RecordID, p2 *core.RecordID, p3 func(p *index.IdempotentRequest) (r error)) (r error)
+	IterateIdempotentRequestsCounter    uint64
+	IterateIdempotentRequestsPreCounter uint64
+	IterateIdempotentRequestsMock       mObjectStorageMockIterateIdempotentRequests
+
 	IterateIndexIDsFunc       func(p context.Context, p1 core.RecordID, p2 func(p core.RecordID) (r error)) (r error)
 	IterateIndexIDsCounter    uint64
 	IterateIndexIDsPreCounter uint64
 	IterateIndexIDsMock       mObjectStorageMockIterateIndexIDs
 
+	IterateUsedSeedsFunc       func(p context.Context, p1 core.RecordID, p2 func(p core.RecordID, p1 []byte, p2 core.PulseNumber) (r error)) (r error)
+	IterateUsedSeedsCounter    uint64
+	IterateUsedSeedsPreCounter uint64
+	IterateUsedSeedsMock       mObjectStorageMockIterateUsedSeeds
+
 	RemoveObjectIndexFunc       func(p context.Context, p1 core.RecordID, p2 *core.RecordID) (r error)
 	RemoveObjectIndexCounter    uint64
 	RemoveObjectIndexPreCounter uint64
@@ -52,6 +72,11 @@ type ObjectStorageMock struct {
 	SetBlobPreCounter uint64
 	SetBlobMock       mObjectStorageMockSetBlob
 
+	SetIdempotentRequestFunc       func(p context.Context, p1 core.RecordID, p2 *core.RecordID, p3 *index.IdempotentRequest) (r error)
+	SetIdempotentRequestCounter    uint64
+	SetIdempotentRequestPreCounter uint64
+	SetIdempotentRequestMock       mObjectStorageMockSetIdempotentRequest
+
 	SetMessageFunc       func(p context.Context, p1 core.RecordID, p2 core.PulseNumber, p3 core.Message) (r error)
 	SetMessageCounter    uint64
 	SetMessagePreCounter uint64
@@ -66,6 +91,11 @@ type ObjectStorageMock struct {
 	SetRecordCounter    uint64
 	SetRecordPreCounter uint64
 	SetRecordMock       mObjectStorageMockSetRecord
+
+	SetSeedUsedFunc       func(p context.Context, p1 core.RecordID, p2 *core.RecordID, p3 []byte, p4 core.PulseNumber) (r error)
+	SetSeedUsedCounter    uint64
+	SetSeedUsedPreCounter uint64
+	SetSeedUsedMock       mObjectStorageMockSetSeedUsed
 }
 
 //NewObjectStorageMock returns a mock for github.com/insolar/insolar/ledger/storage.ObjectStorage
@@ -77,14 +107,20 @@ func NewObjectStorageMock(t minimock.Tester) *ObjectStorageMock {
 	}
 
 	m.GetBlobMock = mObjectStorageMockGetBlob{mock: m}
+	m.GetIdempotencyKeyMock = mObjectStorageMockGetIdempotencyKey{mock: m}
+	m.GetIdempotentRequestMock = mObjectStorageMockGetIdempotentRequest{mock: m}
 	m.GetObjectIndexMock = mObjectStorageMockGetObjectIndex{mock: m}
 	m.GetRecordMock = mObjectStorageMockGetRecord{mock: m}
+	m.IterateIdempotentRequestsMock = mObjectStorageMockIterateIdempotentRequests{mock: m}
 	m.IterateIndexIDsMock = mObjectStorageMockIterateIndexIDs{mock: m}
+	m.IterateUsedSeedsMock = mObjectStorageMockIterateUsedSeeds{mock: m}
 	m.RemoveObjectIndexMock = mObjectStorageMockRemoveObjectIndex{mock: m}
 	m.SetBlobMock = mObjectStorageMockSetBlob{mock: m}
+	m.SetIdempotentRequestMock = mObjectStorageMockSetIdempotentRequest{mock: m}
 	m.SetMessageMock = mObjectStorageMockSetMessage{mock: m}
 	m.SetObjectIndexMock = mObjectStorageMockSetObjectIndex{mock: m}
 	m.SetRecordMock = mObjectStorageMockSetRecord{mock: m}
+	m.SetSeedUsedMock = mObjectStorageMockSetSeedUsed{mock: m}
 
 	return m
 }
@@ -241,6 +277,312 @@ func (m *ObjectStorageMock) GetBlobFinished() bool {
 	return true
 }
 
+type mObjectStorageMockGetIdempotencyKey struct {
+	mock              *ObjectStorageMock
+	mainExpectation   *ObjectStorageMockGetIdempotencyKeyExpectation
+	expectationSeries []*ObjectStorageMockGetIdempotencyKeyExpectation
+}
+
+type ObjectStorageMockGetIdempotencyKeyExpectation struct {
+	input  *ObjectStorageMockGetIdempotencyKeyInput
+	result *ObjectStorageMockGetIdempotencyKeyResult
+}
+
+type ObjectStorageMockGetIdempotencyKeyInput struct {
+	p  context.Context
+	p1 core.RecordID
+	p2 *core.RecordID
+	p3 *core.RecordID
+}
+
+type ObjectStorageMockGetIdempotencyKeyResult struct {
+	r  string
+	r1 error
+}
+
+//Expect specifies that invocation of ObjectStorage.GetIdempotencyKey is expected from 1 to Infinity times
+func (m *mObjectStorageMockGetIdempotencyKey) Expect(p context.Context, p1 core.RecordID, p2 *core.RecordID, p3 *core.RecordID) *mObjectStorageMockGetIdempotencyKey {
+	m.mock.GetIdempotencyKeyFunc = nil
+	m.expectationSeries = nil
+
+	if m.mainExpectation == nil {
+		m.mainExpectation = &ObjectStorageMockGetIdempotencyKeyExpectation{}
+	}
+	m.mainExpectation.input = &ObjectStorageMockGetIdempotencyKeyInput{p, p1, p2, p3}
+	return m
+}
+
+//Return specifies results of invocation of ObjectStorage.GetIdempotencyKey
+func (m *mObjectStorageMockGetIdempotencyKey) Return(r string, r1 error) *ObjectStorageMock {
+	m.mock.GetIdempotencyKeyFunc = nil
+	m.expectationSeries = nil
+
+	if m.mainExpectation == nil {
+		m.mainExpectation = &ObjectStorageMockGetIdempotencyKeyExpectation{}
+	}
+	m.mainExpectation.result = &ObjectStorageMockGetIdempotencyKeyResult{r, r1}
+	return m.mock
+}
+
+//ExpectOnce specifies that invocation of ObjectStorage.GetIdempotencyKey is expected once
+func (m *mObjectStorageMockGetIdempotencyKey) ExpectOnce(p context.Context, p1 core.RecordID, p2 *core.RecordID, p3 *core.RecordID) *ObjectStorageMockGetIdempotencyKeyExpectation {
+	m.mock.GetIdempotencyKeyFunc = nil
+	m.mainExpectation = nil
+
+	expectation := &ObjectStorageMockGetIdempotencyKeyExpectation{}
+	expectation.input = &ObjectStorageMockGetIdempotencyKeyInput{p, p1, p2, p3}
+	m.expectationSeries = append(m.expectationSeries, expectation)
+	return expectation
+}
+
+func (e *ObjectStorageMockGetIdempotencyKeyExpectation) Return(r string, r1 error) {
+	e.result = &ObjectStorageMockGetIdempotencyKeyResult{r, r1}
+}
+
+//Set uses given function f as a mock of ObjectStorage.GetIdempotencyKey method
+func (m *mObjectStorageMockGetIdempotencyKey) Set(f func(p context.Context, p1 core.RecordID, p2 *core.RecordID, p3 *core.RecordID) (r string, r1 error)) *ObjectStorageMock {
+	m.mainExpectation = nil
+	m.expectationSeries = nil
+
+	m.mock.GetIdempotencyKeyFunc = f
+	return m.mock
+}
+
+//GetIdempotencyKey implements github.com/insolar/insolar/ledger/storage.ObjectStorage interface
+func (m *ObjectStorageMock) GetIdempotencyKey(p context.Context, p1 core.RecordID, p2 *core.RecordID, p3 *core.RecordID) (r string, r1 error) {
+	counter := atomic.AddUint64(&m.GetIdempotencyKeyPreCounter, 1)
+	defer atomic.AddUint64(&m.GetIdempotencyKeyCounter, 1)
+
+	if len(m.GetIdempotencyKeyMock.expectationSeries) > 0 {
+		if counter > uint64(len(m.GetIdempotencyKeyMock.expectationSeries)) {
+			m.t.Fatalf("Unexpected call to ObjectStorageMock.GetIdempotencyKey. %v %v %v %v", p, p1, p2, p3)
+			return
+		}
+
+		input := m.GetIdempotencyKeyMock.expectationSeries[counter-1].input
+		testify_assert.Equal(m.t, *input, ObjectStorageMockGetIdempotencyKeyInput{p, p1, p2, p3}, "ObjectStorage.GetIdempotencyKey got unexpected parameters")
+
+		result := m.GetIdempotencyKeyMock.expectationSeries[counter-1].result
+		if result == nil {
+			m.t.Fatal("No results are set for the ObjectStorageMock.GetIdempotencyKey")
+			return
+		}
+
+		r = result.r
+		r1 = result.r1
+
+		return
+	}
+
+	if m.GetIdempotencyKeyMock.mainExpectation != nil {
+
+		input := m.GetIdempotencyKeyMock.mainExpectation.input
+		if input != nil {
+			testify_assert.Equal(m.t, *input, ObjectStorageMockGetIdempotencyKeyInput{p, p1, p2, p3}, "ObjectStorage.GetIdempotencyKey got unexpected parameters")
+		}
+
+		result := m.GetIdempotencyKeyMock.mainExpectation.result
+		if result == nil {
+			m.t.Fatal("No results are set for the ObjectStorageMock.GetIdempotencyKey")
+		}
+
+		r = result.r
+		r1 = result.r1
+
+		return
+	}
+
+	if m.GetIdempotencyKeyFunc == nil {
+		m.t.Fatalf("Unexpected call to ObjectStorageMock.GetIdempotencyKey. %v %v %v %v", p, p1, p2, p3)
+		return
+	}
+
+	return m.GetIdempotencyKeyFunc(p, p1, p2, p3)
+}
+
+//GetIdempotencyKeyMinimockCounter returns a count of ObjectStorageMock.GetIdempotencyKeyFunc invocations
+func (m *ObjectStorageMock) GetIdempotencyKeyMinimockCounter() uint64 {
+	return atomic.LoadUint64(&m.GetIdempotencyKeyCounter)
+}
+
+//GetIdempotencyKeyMinimockPreCounter returns the value of ObjectStorageMock.GetIdempotencyKey invocations
+func (m *ObjectStorageMock) GetIdempotencyKeyMinimockPreCounter() uint64 {
+	return atomic.LoadUint64(&m.GetIdempotencyKeyPreCounter)
+}
+
+//GetIdempotencyKeyFinished returns true if mock invocations count is ok
+func (m *ObjectStorageMock) GetIdempotencyKeyFinished() bool {
+	// if expectation series were set then invocations count should be equal to expectations count
+	if len(m.GetIdempotencyKeyMock.expectationSeries) > 0 {
+		return atomic.LoadUint64(&m.GetIdempotencyKeyCounter) == uint64(len(m.GetIdempotencyKeyMock.expectationSeries))
+	}
+
+	// if main expectation was set then invocations count should be greater than zero
+	if m.GetIdempotencyKeyMock.mainExpectation != nil {
+		return atomic.LoadUint64(&m.GetIdempotencyKeyCounter) > 0
+	}
+
+	// if func was set then invocations count should be greater than zero
+	if m.GetIdempotencyKeyFunc != nil {
+		return atomic.LoadUint64(&m.GetIdempotencyKeyCounter) > 0
+	}
+
+	return true
+}
+
+type mObjectStorageMockGetIdempotentRequest struct {
+	mock              *ObjectStorageMock
+	mainExpectation   *ObjectStorageMockGetIdempotentRequestExpectation
+	expectationSeries []*ObjectStorageMockGetIdempotentRequestExpectation
+}
+
+type ObjectStorageMockGetIdempotentRequestExpectation struct {
+	input  *ObjectStorageMockGetIdempotentRequestInput
+	result *ObjectStorageMockGetIdempotentRequestResult
+}
+
+type ObjectStorageMockGetIdempotentRequestInput struct {
+	p  context.Context
+	p1 core.RecordID
+	p2 *core.RecordID
+	p3 string
+}
+
+type ObjectStorageMockGetIdempotentRequestResult struct {
+	r  *index.IdempotentRequest
+	r1 error
+}
+
+//Expect specifies that invocation of ObjectStorage.GetIdempotentRequest is expected from 1 to Infinity times
+func (m *mObjectStorageMockGetIdempotentRequest) Expect(p context.Context, p1 core.RecordID, p2 *core.RecordID, p3 string) *mObjectStorageMockGetIdempotentRequest {
+	m.mock.GetIdempotentRequestFunc = nil
+	m.expectationSeries = nil
+
+	if m.mainExpectation == nil {
+		m.mainExpectation = &ObjectStorageMockGetIdempotentRequestExpectation{}
+	}
+	m.mainExpectation.input = &ObjectStorageMockGetIdempotentRequestInput{p, p1, p2, p3}
+	return m
+}
+
+//Return specifies results of invocation of ObjectStorage.GetIdempotentRequest
+func (m *mObjectStorageMockGetIdempotentRequest) Return(r *index.IdempotentRequest, r1 error) *ObjectStorageMock {
+	m.mock.GetIdempotentRequestFunc = nil
+	m.expectationSeries = nil
+
+	if m.mainExpectation == nil {
+		m.mainExpectation = &ObjectStorageMockGetIdempotentRequestExpectation{}
+	}
+	m.mainExpectation.result = &ObjectStorageMockGetIdempotentRequestResult{r, r1}
+	return m.mock
+}
+
+//ExpectOnce specifies that invocation of ObjectStorage.GetIdempotentRequest is expected once
+func (m *mObjectStorageMockGetIdempotentRequest) ExpectOnce(p context.Context, p1 core.RecordID, p2 *core.RecordID, p3 string) *ObjectStorageMockGetIdempotentRequestExpectation {
+	m.mock.GetIdempotentRequestFunc = nil
+	m.mainExpectation = nil
+
+	expectation := &ObjectStorageMockGetIdempotentRequestExpectation{}
+	expectation.input = &ObjectStorageMockGetIdempotentRequestInput{p, p1, p2, p3}
+	m.expectationSeries = append(m.expectationSeries, expectation)
+	return expectation
+}
+
+func (e *ObjectStorageMockGetIdempotentRequestExpectation) Return(r *index.IdempotentRequest, r1 error) {
+	e.result = &ObjectStorageMockGetIdempotentRequestResult{r, r1}
+}
+
+//Set uses given function f as a mock of ObjectStorage.GetIdempotentRequest method
+func (m *mObjectStorageMockGetIdempotentRequest) Set(f func(p context.Context, p1 core.RecordID, p2 *core.RecordID, p3 string) (r *index.IdempotentRequest, r1 error)) *ObjectStorageMock {
+	m.mainExpectation = nil
+	m.expectationSeries = nil
+
+	m.mock.GetIdempotentRequestFunc = f
+	return m.mock
+}
+
+//GetIdempotentRequest implements github.com/insolar/insolar/ledger/storage.ObjectStorage interface
+func (m *ObjectStorageMock) GetIdempotentRequest(p context.Context, p1 core.RecordID, p2 *core.RecordID, p3 string) (r *index.IdempotentRequest, r1 error) {
+	counter := atomic.AddUint64(&m.GetIdempotentRequestPreCounter, 1)
+	defer atomic.AddUint64(&m.GetIdempotentRequestCounter, 1)
+
+	if len(m.GetIdempotentRequestMock.expectationSeries) > 0 {
+		if counter > uint64(len(m.GetIdempotentRequestMock.expectationSeries)) {
+			m.t.Fatalf("Unexpected call to ObjectStorageMock.GetIdempotentRequest. %v %v %v %v", p, p1, p2, p3)
+			return
+		}
+
+		input := m.GetIdempotentRequestMock.expectationSeries[counter-1].input
+		testify_assert.Equal(m.t, *input, ObjectStorageMockGetIdempotentRequestInput{p, p1, p2, p3}, "ObjectStorage.GetIdempotentRequest got unexpected parameters")
+
+		result := m.GetIdempotentRequestMock.expectationSeries[counter-1].result
+		if result == nil {
+			m.t.Fatal("No results are set for the ObjectStorageMock.GetIdempotentRequest")
+			return
+		}
+
+		r = result.r
+		r1 = result.r1
+
+		return
+	}
+
+	if m.GetIdempotentRequestMock.mainExpectation != nil {
+
+		input := m.GetIdempotentRequestMock.mainExpectation.input
+		if input != nil {
+			testify_assert.Equal(m.t, *input, ObjectStorageMockGetIdempotentRequestInput{p, p1, p2, p3}, "ObjectStorage.GetIdempotentRequest got unexpected parameters")
+		}
+
+		result := m.GetIdempotentRequestMock.mainExpectation.result
+		if result == nil {
+			m.t.Fatal("No results are set for the ObjectStorageMock.GetIdempotentRequest")
+		}
+
+		r = result.r
+		r1 = result.r1
+
+		return
+	}
+
+	if m.GetIdempotentRequestFunc == nil {
+		m.t.Fatalf("Unexpected call to ObjectStorageMock.GetIdempotentRequest. %v %v %v %v", p, p1, p2, p3)
+		return
+	}
+
+	return m.GetIdempotentRequestFunc(p, p1, p2, p3)
+}
+
+//GetIdempotentRequestMinimockCounter returns a count of ObjectStorageMock.GetIdempotentRequestFunc invocations
+func (m *ObjectStorageMock) GetIdempotentRequestMinimockCounter() uint64 {
+	return atomic.LoadUint64(&m.GetIdempotentRequestCounter)
+}
+
+//GetIdempotentRequestMinimockPreCounter returns the value of ObjectStorageMock.GetIdempotentRequest invocations
+func (m *ObjectStorageMock) GetIdempotentRequestMinimockPreCounter() uint64 {
+	return atomic.LoadUint64(&m.GetIdempotentRequestPreCounter)
+}
+
+//GetIdempotentRequestFinished returns true if mock invocations count is ok
+func (m *ObjectStorageMock) GetIdempotentRequestFinished() bool {
+	// if expectation series were set then invocations count should be equal to expectations count
+	if len(m.GetIdempotentRequestMock.expectationSeries) > 0 {
+		return atomic.LoadUint64(&m.GetIdempotentRequestCounter) == uint64(len(m.GetIdempotentRequestMock.expectationSeries))
+	}
+
+	// if main expectation was set then invocations count should be greater than zero
+	if m.GetIdempotentRequestMock.mainExpectation != nil {
+		return atomic.LoadUint64(&m.GetIdempotentRequestCounter) > 0
+	}
+
+	// if func was set then invocations count should be greater than zero
+	if m.GetIdempotentRequestFunc != nil {
+		return atomic.LoadUint64(&m.GetIdempotentRequestCounter) > 0
+	}
+
+	return true
+}
+
 type mObjectStorageMockGetObjectIndex struct {
 	mock              *ObjectStorageMock
 	mainExpectation   *ObjectStorageMockGetObjectIndexExpectation
@@ -546,6 +888,156 @@ func (m *ObjectStorageMock) GetRecordFinished() bool {
 	return true
 }
 
+type mObjectStorageMockIterateIdempotentRequests struct {
+	mock              *ObjectStorageMock
+	mainExpectation   *ObjectStorageMockIterateIdempotentRequestsExpectation
+	expectationSeries []*ObjectStorageMockIterateIdempotentRequestsExpectation
+}
+
+type ObjectStorageMockIterateIdempotentRequestsExpectation struct {
+	input  *ObjectStorageMockIterateIdempotentRequestsInput
+	result *ObjectStorageMockIterateIdempotentRequestsResult
+}
+
+type ObjectStorageMockIterateIdempotentRequestsInput struct {
+	p  context.Context
+	p1 core.RecordID
+	p2 *core.RecordID
+	p3 func(p *index.IdempotentRequest) (r error)
+}
+
+type ObjectStorageMockIterateIdempotentRequestsResult struct {
+	r error
+}
+
+//Expect specifies that invocation of ObjectStorage.IterateIdempotentRequests is expected from 1 to Infinity times
+func (m *mObjectStorageMockIterateIdempotentRequests) Expect(p context.Context, p1 core.RecordID, p2 *core.RecordID, p3 func(p *index.IdempotentRequest) (r error)) *mObjectStorageMockIterateIdempotentRequests {
+	m.mock.IterateIdempotentRequestsFunc = nil
+	m.expectationSeries = nil
+
+	if m.mainExpectation == nil {
+		m.mainExpectation = &ObjectStorageMockIterateIdempotentRequestsExpectation{}
+	}
+	m.mainExpectation.input = &ObjectStorageMockIterateIdempotentRequestsInput{p, p1, p2, p3}
+	return m
+}
+
+//Return specifies results of invocation of ObjectStorage.IterateIdempotentRequests
+func (m *mObjectStorageMockIterateIdempotentRequests) Return(r error) *ObjectStorageMock {
+	m.mock.IterateIdempotentRequestsFunc = nil
+	m.expectationSeries = nil
+
+	if m.mainExpectation == nil {
+		m.mainExpectation = &ObjectStorageMockIterateIdempotentRequestsExpectation{}
+	}
+	m.mainExpectation.result = &ObjectStorageMockIterateIdempotentRequestsResult{r}
+	return m.mock
+}
+
+//ExpectOnce specifies that invocation of ObjectStorage.IterateIdempotentRequests is expected once
+func (m *mObjectStorageMockIterateIdempotentRequests) ExpectOnce(p context.Context, p1 core.RecordID, p2 *core.RecordID, p3 func(p *index.IdempotentRequest) (r error)) *ObjectStorageMockIterateIdempotentRequestsExpectation {
+	m.mock.IterateIdempotentRequestsFunc = nil
+	m.mainExpectation = nil
+
+	expectation := &ObjectStorageMockIterateIdempotentRequestsExpectation{}
+	expectation.input = &ObjectStorageMockIterateIdempotentRequestsInput{p, p1, p2, p3}
+	m.expectationSeries = append(m.expectationSeries, expectation)
+	return expectation
+}
+
+func (e *ObjectStorageMockIterateIdempotentRequestsExpectation) Return(r error) {
+	e.result = &ObjectStorageMockIterateIdempotentRequestsResult{r}
+}
+
+//Set uses given function f as a mock of ObjectStorage.IterateIdempotentRequests method
+func (m *mObjectStorageMockIterateIdempotentRequests) Set(f func(p context.Context, p1 core.RecordID, p2 *core.RecordID, p3 func(p *index.IdempotentRequest) (r error)) (r error)) *ObjectStorageMock {
+	m.mainExpectation = nil
+	m.expectationSeries = nil
+
+	m.mock.IterateIdempotentRequestsFunc = f
+	return m.mock
+}
+
+//IterateIdempotentRequests implements github.com/insolar/insolar/ledger/storage.ObjectStorage interface
+func (m *ObjectStorageMock) IterateIdempotentRequests(p context.Context, p1 core.RecordID, p2 *core.RecordID, p3 func(p *index.IdempotentRequest) (r error)) (r error) {
+	counter := atomic.AddUint64(&m.IterateIdempotentRequestsPreCounter, 1)
+	defer atomic.AddUint64(&m.IterateIdempotentRequestsCounter, 1)
+
+	if len(m.IterateIdempotentRequestsMock.expectationSeries) > 0 {
+		if counter > uint64(len(m.IterateIdempotentRequestsMock.expectationSeries)) {
+			m.t.Fatalf("Unexpected call to ObjectStorageMock.IterateIdempotentRequests. %v %v %v %v", p, p1, p2, p3)
+			return
+		}
+
+		input := m.IterateIdempotentRequestsMock.expectationSeries[counter-1].input
+		testify_assert.Equal(m.t, *input, ObjectStorageMockIterateIdempotentRequestsInput{p, p1, p2, p3}, "ObjectStorage.IterateIdempotentRequests got unexpected parameters")
+
+		result := m.IterateIdempotentRequestsMock.expectationSeries[counter-1].result
+		if result == nil {
+			m.t.Fatal("No results are set for the ObjectStorageMock.IterateIdempotentRequests")
+			return
+		}
+
+		r = result.r
+
+		return
+	}
+
+	if m.IterateIdempotentRequestsMock.mainExpectation != nil {
+
+		input := m.IterateIdempotentRequestsMock.mainExpectation.input
+		if input != nil {
+			testify_assert.Equal(m.t, *input, ObjectStorageMockIterateIdempotentRequestsInput{p, p1, p2, p3}, "ObjectStorage.IterateIdempotentRequests got unexpected parameters")
+		}
+
+		result := m.IterateIdempotentRequestsMock.mainExpectation.result
+		if result == nil {
+			m.t.Fatal("No results are set for the ObjectStorageMock.IterateIdempotentRequests")
+		}
+
+		r = result.r
+
+		return
+	}
+
+	if m.IterateIdempotentRequestsFunc == nil {
+		m.t.Fatalf("Unexpected call to ObjectStorageMock.IterateIdempotentRequests. %v %v %v %v", p, p1, p2, p3)
+		return
+	}
+
+	return m.IterateIdempotentRequestsFunc(p, p1, p2, p3)
+}
+
+//IterateIdempotentRequestsMinimockCounter returns a count of ObjectStorageMock.IterateIdempotentRequestsFunc invocations
+func (m *ObjectStorageMock) IterateIdempotentRequestsMinimockCounter() uint64 {
+	return atomic.LoadUint64(&m.IterateIdempotentRequestsCounter)
+}
+
+//IterateIdempotentRequestsMinimockPreCounter returns the value of ObjectStorageMock.IterateIdempotentRequests invocations
+func (m *ObjectStorageMock) IterateIdempotentRequestsMinimockPreCounter() uint64 {
+	return atomic.LoadUint64(&m.IterateIdempotentRequestsPreCounter)
+}
+
+//IterateIdempotentRequestsFinished returns true if mock invocations count is ok
+func (m *ObjectStorageMock) IterateIdempotentRequestsFinished() bool {
+	// if expectation series were set then invocations count should be equal to expectations count
+	if len(m.IterateIdempotentRequestsMock.expectationSeries) > 0 {
+		return atomic.LoadUint64(&m.IterateIdempotentRequestsCounter) == uint64(len(m.IterateIdempotentRequestsMock.expectationSeries))
+	}
+
+	// if main expectation was set then invocations count should be greater than zero
+	if m.IterateIdempotentRequestsMock.mainExpectation != nil {
+		return atomic.LoadUint64(&m.IterateIdempotentRequestsCounter) > 0
+	}
+
+	// if func was set then invocations count should be greater than zero
+	if m.IterateIdempotentRequestsFunc != nil {
+		return atomic.LoadUint64(&m.IterateIdempotentRequestsCounter) > 0
+	}
+
+	return true
+}
+
 type mObjectStorageMockIterateIndexIDs struct {
 	mock              *ObjectStorageMock
 	mainExpectation   *ObjectStorageMockIterateIndexIDsExpectation
@@ -629,9 +1121,158 @@ func (m *ObjectStorageMock) IterateIndexIDs(p context.Context, p1 core.RecordID,
 		input := m.IterateIndexIDsMock.expectationSeries[counter-1].input
 		testify_assert.Equal(m.t, *input, ObjectStorageMockIterateIndexIDsInput{p, p1, p2}, "ObjectStorage.IterateIndexIDs got unexpected parameters")
 
-		result := m.IterateIndexIDsMock.expectationSeries[counter-1].result
+		result := m.IterateIndexIDsMock.expectationSeries[counter-1].result
+		if result == nil {
+			m.t.Fatal("No results are set for the ObjectStorageMock.IterateIndexIDs")
+			return
+		}
+
+		r = result.r
+
+		return
+	}
+
+	if m.IterateIndexIDsMock.mainExpectation != nil {
+
+		input := m.IterateIndexIDsMock.mainExpectation.input
+		if input != nil {
+			testify_assert.Equal(m.t, *input, ObjectStorageMockIterateIndexIDsInput{p, p1, p2}, "ObjectStorage.IterateIndexIDs got unexpected parameters")
+		}
+
+		result := m.IterateIndexIDsMock.mainExpectation.result
+		if result == nil {
+			m.t.Fatal("No results are set for the ObjectStorageMock.IterateIndexIDs")
+		}
+
+		r = result.r
+
+		return
+	}
+
+	if m.IterateIndexIDsFunc == nil {
+		m.t.Fatalf("Unexpected call to ObjectStorageMock.IterateIndexIDs. %v %v %v", p, p1, p2)
+		return
+	}
+
+	return m.IterateIndexIDsFunc(p, p1, p2)
+}
+
+//IterateIndexIDsMinimockCounter returns a count of ObjectStorageMock.IterateIndexIDsFunc invocations
+func (m *ObjectStorageMock) IterateIndexIDsMinimockCounter() uint64 {
+	return atomic.LoadUint64(&m.IterateIndexIDsCounter)
+}
+
+//IterateIndexIDsMinimockPreCounter returns the value of ObjectStorageMock.IterateIndexIDs invocations
+func (m *ObjectStorageMock) IterateIndexIDsMinimockPreCounter() uint64 {
+	return atomic.LoadUint64(&m.IterateIndexIDsPreCounter)
+}
+
+//IterateIndexIDsFinished returns true if mock invocations count is ok
+func (m *ObjectStorageMock) IterateIndexIDsFinished() bool {
+	// if expectation series were set then invocations count should be equal to expectations count
+	if len(m.IterateIndexIDsMock.expectationSeries) > 0 {
+		return atomic.LoadUint64(&m.IterateIndexIDsCounter) == uint64(len(m.IterateIndexIDsMock.expectationSeries))
+	}
+
+	// if main expectation was set then invocations count should be greater than zero
+	if m.IterateIndexIDsMock.mainExpectation != nil {
+		return atomic.LoadUint64(&m.IterateIndexIDsCounter) > 0
+	}
+
+	// if func was set then invocations count should be greater than zero
+	if m.IterateIndexIDsFunc != nil {
+		return atomic.LoadUint64(&m.IterateIndexIDsCounter) > 0
+	}
+
+	return true
+}
+
+type mObjectStorageMockIterateUsedSeeds struct {
+	mock              *ObjectStorageMock
+	mainExpectation   *ObjectStorageMockIterateUsedSeedsExpectation
+	expectationSeries []*ObjectStorageMockIterateUsedSeedsExpectation
+}
+
+type ObjectStorageMockIterateUsedSeedsExpectation struct {
+	input  *ObjectStorageMockIterateUsedSeedsInput
+	result *ObjectStorageMockIterateUsedSeedsResult
+}
+
+type ObjectStorageMockIterateUsedSeedsInput struct {
+	p  context.Context
+	p1 core.RecordID
+	p2 func(p core.RecordID, p1 []byte, p2 core.PulseNumber) (r error)
+}
+
+type ObjectStorageMockIterateUsedSeedsResult struct {
+	r error
+}
+
+//Expect specifies that invocation of ObjectStorage.IterateUsedSeeds is expected from 1 to Infinity times
+func (m *mObjectStorageMockIterateUsedSeeds) Expect(p context.Context, p1 core.RecordID, p2 func(p core.RecordID, p1 []byte, p2 core.PulseNumber) (r error)) *mObjectStorageMockIterateUsedSeeds {
+	m.mock.IterateUsedSeedsFunc = nil
+	m.expectationSeries = nil
+
+	if m.mainExpectation == nil {
+		m.mainExpectation = &ObjectStorageMockIterateUsedSeedsExpectation{}
+	}
+	m.mainExpectation.input = &ObjectStorageMockIterateUsedSeedsInput{p, p1, p2}
+	return m
+}
+
+//Return specifies results of invocation of ObjectStorage.IterateUsedSeeds
+func (m *mObjectStorageMockIterateUsedSeeds) Return(r error) *ObjectStorageMock {
+	m.mock.IterateUsedSeedsFunc = nil
+	m.expectationSeries = nil
+
+	if m.mainExpectation == nil {
+		m.mainExpectation = &ObjectStorageMockIterateUsedSeedsExpectation{}
+	}
+	m.mainExpectation.result = &ObjectStorageMockIterateUsedSeedsResult{r}
+	return m.mock
+}
+
+//ExpectOnce specifies that invocation of ObjectStorage.IterateUsedSeeds is expected once
+func (m *mObjectStorageMockIterateUsedSeeds) ExpectOnce(p context.Context, p1 core.RecordID, p2 func(p core.RecordID, p1 []byte, p2 core.PulseNumber) (r error)) *ObjectStorageMockIterateUsedSeedsExpectation {
+	m.mock.IterateUsedSeedsFunc = nil
+	m.mainExpectation = nil
+
+	expectation := &ObjectStorageMockIterateUsedSeedsExpectation{}
+	expectation.input = &ObjectStorageMockIterateUsedSeedsInput{p, p1, p2}
+	m.expectationSeries = append(m.expectationSeries, expectation)
+	return expectation
+}
+
+func (e *ObjectStorageMockIterateUsedSeedsExpectation) Return(r error) {
+	e.result = &ObjectStorageMockIterateUsedSeedsResult{r}
+}
+
+//Set uses given function f as a mock of ObjectStorage.IterateUsedSeeds method
+func (m *mObjectStorageMockIterateUsedSeeds) Set(f func(p context.Context, p1 core.RecordID, p2 func(p core.RecordID, p1 []byte, p2 core.PulseNumber) (r error)) (r error)) *ObjectStorageMock {
+	m.mainExpectation = nil
+	m.expectationSeries = nil
+
+	m.mock.IterateUsedSeedsFunc = f
+	return m.mock
+}
+
+//IterateUsedSeeds implements github.com/insolar/insolar/ledger/storage.ObjectStorage interface
+func (m *ObjectStorageMock) IterateUsedSeeds(p context.Context, p1 core.RecordID, p2 func(p core.RecordID, p1 []byte, p2 core.PulseNumber) (r error)) (r error) {
+	counter := atomic.AddUint64(&m.IterateUsedSeedsPreCounter, 1)
+	defer atomic.AddUint64(&m.IterateUsedSeedsCounter, 1)
+
+	if len(m.IterateUsedSeedsMock.expectationSeries) > 0 {
+		if counter > uint64(len(m.IterateUsedSeedsMock.expectationSeries)) {
+			m.t.Fatalf("Unexpected call to ObjectStorageMock.IterateUsedSeeds. %v %v %v", p, p1, p2)
+			return
+		}
+
+		input := m.IterateUsedSeedsMock.expectationSeries[counter-1].input
+		testify_assert.Equal(m.t, *input, ObjectStorageMockIterateUsedSeedsInput{p, p1, p2}, "ObjectStorage.IterateUsedSeeds got unexpected parameters")
+
+		result := m.IterateUsedSeedsMock.expectationSeries[counter-1].result
 		if result == nil {
-			m.t.Fatal("No results are set for the ObjectStorageMock.IterateIndexIDs")
+			m.t.Fatal("No results are set for the ObjectStorageMock.IterateUsedSeeds")
 			return
 		}
 
@@ -640,16 +1281,16 @@ func (m *ObjectStorageMock) IterateIndexIDs(p context.Context, p1 core.RecordID,
 		return
 	}
 
-	if m.IterateIndexIDsMock.mainExpectation != nil {
+	if m.IterateUsedSeedsMock.mainExpectation != nil {
 
-		input := m.IterateIndexIDsMock.mainExpectation.input
+		input := m.IterateUsedSeedsMock.mainExpectation.input
 		if input != nil {
-			testify_assert.Equal(m.t, *input, ObjectStorageMockIterateIndexIDsInput{p, p1, p2}, "ObjectStorage.IterateIndexIDs got unexpected parameters")
+			testify_assert.Equal(m.t, *input, ObjectStorageMockIterateUsedSeedsInput{p, p1, p2}, "ObjectStorage.IterateUsedSeeds got unexpected parameters")
 		}
 
-		result := m.IterateIndexIDsMock.mainExpectation.result
+		result := m.IterateUsedSeedsMock.mainExpectation.result
 		if result == nil {
-			m.t.Fatal("No results are set for the ObjectStorageMock.IterateIndexIDs")
+			m.t.Fatal("No results are set for the ObjectStorageMock.IterateUsedSeeds")
 		}
 
 		r = result.r
@@ -657,39 +1298,39 @@ func (m *ObjectStorageMock) IterateIndexIDs(p context.Context, p1 core.RecordID,
 		return
 	}
 
-	if m.IterateIndexIDsFunc == nil {
-		m.t.Fatalf("Unexpected call to ObjectStorageMock.IterateIndexIDs. %v %v %v", p, p1, p2)
+	if m.IterateUsedSeedsFunc == nil {
+		m.t.Fatalf("Unexpected call to ObjectStorageMock.IterateUsedSeeds. %v %v %v", p, p1, p2)
 		return
 	}
 
-	return m.IterateIndexIDsFunc(p, p1, p2)
+	return m.IterateUsedSeedsFunc(p, p1, p2)
 }
 
-//IterateIndexIDsMinimockCounter returns a count of ObjectStorageMock.IterateIndexIDsFunc invocations
-func (m *ObjectStorageMock) IterateIndexIDsMinimockCounter() uint64 {
-	return atomic.LoadUint64(&m.IterateIndexIDsCounter)
+//IterateUsedSeedsMinimockCounter returns a count of ObjectStorageMock.IterateUsedSeedsFunc invocations
+func (m *ObjectStorageMock) IterateUsedSeedsMinimockCounter() uint64 {
+	return atomic.LoadUint64(&m.IterateUsedSeedsCounter)
 }
 
-//IterateIndexIDsMinimockPreCounter returns the value of ObjectStorageMock.IterateIndexIDs invocations
-func (m *ObjectStorageMock) IterateIndexIDsMinimockPreCounter() uint64 {
-	return atomic.LoadUint64(&m.IterateIndexIDsPreCounter)
+//IterateUsedSeedsMinimockPreCounter returns the value of ObjectStorageMock.IterateUsedSeeds invocations
+func (m *ObjectStorageMock) IterateUsedSeedsMinimockPreCounter() uint64 {
+	return atomic.LoadUint64(&m.IterateUsedSeedsPreCounter)
 }
 
-//IterateIndexIDsFinished returns true if mock invocations count is ok
-func (m *ObjectStorageMock) IterateIndexIDsFinished() bool {
+//IterateUsedSeedsFinished returns true if mock invocations count is ok
+func (m *ObjectStorageMock) IterateUsedSeedsFinished() bool {
 	// if expectation series were set then invocations count should be equal to expectations count
-	if len(m.IterateIndexIDsMock.expectationSeries) > 0 {
-		return atomic.LoadUint64(&m.IterateIndexIDsCounter) == uint64(len(m.IterateIndexIDsMock.expectationSeries))
+	if len(m.IterateUsedSeedsMock.expectationSeries) > 0 {
+		return atomic.LoadUint64(&m.IterateUsedSeedsCounter) == uint64(len(m.IterateUsedSeedsMock.expectationSeries))
 	}
 
 	// if main expectation was set then invocations count should be greater than zero
-	if m.IterateIndexIDsMock.mainExpectation != nil {
-		return atomic.LoadUint64(&m.IterateIndexIDsCounter) > 0
+	if m.IterateUsedSeedsMock.mainExpectation != nil {
+		return atomic.LoadUint64(&m.IterateUsedSeedsCounter) > 0
 	}
 
 	// if func was set then invocations count should be greater than zero
-	if m.IterateIndexIDsFunc != nil {
-		return atomic.LoadUint64(&m.IterateIndexIDsCounter) > 0
+	if m.IterateUsedSeedsFunc != nil {
+		return atomic.LoadUint64(&m.IterateUsedSeedsCounter) > 0
 	}
 
 	return true
@@ -997,6 +1638,156 @@ func (m *ObjectStorageMock) SetBlobFinished() bool {
 	return true
 }
 
+type mObjectStorageMockSetIdempotentRequest struct {
+	mock              *ObjectStorageMock
+	mainExpectation   *ObjectStorageMockSetIdempotentRequestExpectation
+	expectationSeries []*ObjectStorageMockSetIdempotentRequestExpectation
+}
+
+type ObjectStorageMockSetIdempotentRequestExpectation struct {
+	input  *ObjectStorageMockSetIdempotentRequestInput
+	result *ObjectStorageMockSetIdempotentRequestResult
+}
+
+type ObjectStorageMockSetIdempotentRequestInput struct {
+	p  context.Context
+	p1 core.RecordID
+	p2 *core.RecordID
+	p3 *index.IdempotentRequest
+}
+
+type ObjectStorageMockSetIdempotentRequestResult struct {
+	r error
+}
+
+//Expect specifies that invocation of ObjectStorage.SetIdempotentRequest is expected from 1 to Infinity times
+func (m *mObjectStorageMockSetIdempotentRequest) Expect(p context.Context, p1 core.RecordID, p2 *core.RecordID, p3 *index.IdempotentRequest) *mObjectStorageMockSetIdempotentRequest {
+	m.mock.SetIdempotentRequestFunc = nil
+	m.expectationSeries = nil
+
+	if m.mainExpectation == nil {
+		m.mainExpectation = &ObjectStorageMockSetIdempotentRequestExpectation{}
+	}
+	m.mainExpectation.input = &ObjectStorageMockSetIdempotentRequestInput{p, p1, p2, p3}
+	return m
+}
+
+//Return specifies results of invocation of ObjectStorage.SetIdempotentRequest
+func (m *mObjectStorageMockSetIdempotentRequest) Return(r error) *ObjectStorageMock {
+	m.mock.SetIdempotentRequestFunc = nil
+	m.expectationSeries = nil
+
+	if m.mainExpectation == nil {
+		m.mainExpectation = &ObjectStorageMockSetIdempotentRequestExpectation{}
+	}
+	m.mainExpectation.result = &ObjectStorageMockSetIdempotentRequestResult{r}
+	return m.mock
+}
+
+//ExpectOnce specifies that invocation of ObjectStorage.SetIdempotentRequest is expected once
+func (m *mObjectStorageMockSetIdempotentRequest) ExpectOnce(p context.Context, p1 core.RecordID, p2 *core.RecordID, p3 *index.IdempotentRequest) *ObjectStorageMockSetIdempotentRequestExpectation {
+	m.mock.SetIdempotentRequestFunc = nil
+	m.mainExpectation = nil
+
+	expectation := &ObjectStorageMockSetIdempotentRequestExpectation{}
+	expectation.input = &ObjectStorageMockSetIdempotentRequestInput{p, p1, p2, p3}
+	m.expectationSeries = append(m.expectationSeries, expectation)
+	return expectation
+}
+
+func (e *ObjectStorageMockSetIdempotentRequestExpectation) Return(r error) {
+	e.result = &ObjectStorageMockSetIdempotentRequestResult{r}
+}
+
+//Set uses given function f as a mock of ObjectStorage.SetIdempotentRequest method
+func (m *mObjectStorageMockSetIdempotentRequest) Set(f func(p context.Context, p1 core.RecordID, p2 *core.RecordID, p3 *index.IdempotentRequest) (r error)) *ObjectStorageMock {
+	m.mainExpectation = nil
+	m.expectationSeries = nil
+
+	m.mock.SetIdempotentRequestFunc = f
+	return m.mock
+}
+
+//SetIdempotentRequest implements github.com/insolar/insolar/ledger/storage.ObjectStorage interface
+func (m *ObjectStorageMock) SetIdempotentRequest(p context.Context, p1 core.RecordID, p2 *core.RecordID, p3 *index.IdempotentRequest) (r error) {
+	counter := atomic.AddUint64(&m.SetIdempotentRequestPreCounter, 1)
+	defer atomic.AddUint64(&m.SetIdempotentRequestCounter, 1)
+
+	if len(m.SetIdempotentRequestMock.expectationSeries) > 0 {
+		if counter > uint64(len(m.SetIdempotentRequestMock.expectationSeries)) {
+			m.t.Fatalf("Unexpected call to ObjectStorageMock.SetIdempotentRequest. %v %v %v %v", p, p1, p2, p3)
+			return
+		}
+
+		input := m.SetIdempotentRequestMock.expectationSeries[counter-1].input
+		testify_assert.Equal(m.t, *input, ObjectStorageMockSetIdempotentRequestInput{p, p1, p2, p3}, "ObjectStorage.SetIdempotentRequest got unexpected parameters")
+
+		result := m.SetIdempotentRequestMock.expectationSeries[counter-1].result
+		if result == nil {
+			m.t.Fatal("No results are set for the ObjectStorageMock.SetIdempotentRequest")
+			return
+		}
+
+		r = result.r
+
+		return
+	}
+
+	if m.SetIdempotentRequestMock.mainExpectation != nil {
+
+		input := m.SetIdempotentRequestMock.mainExpectation.input
+		if input != nil {
+			testify_assert.Equal(m.t, *input, ObjectStorageMockSetIdempotentRequestInput{p, p1, p2, p3}, "ObjectStorage.SetIdempotentRequest got unexpected parameters")
+		}
+
+		result := m.SetIdempotentRequestMock.mainExpectation.result
+		if result == nil {
+			m.t.Fatal("No results are set for the ObjectStorageMock.SetIdempotentRequest")
+		}
+
+		r = result.r
+
+		return
+	}
+
+	if m.SetIdempotentRequestFunc == nil {
+		m.t.Fatalf("Unexpected call to ObjectStorageMock.SetIdempotentRequest. %v %v %v %v", p, p1, p2, p3)
+		return
+	}
+
+	return m.SetIdempotentRequestFunc(p, p1, p2, p3)
+}
+
+//SetIdempotentRequestMinimockCounter returns a count of ObjectStorageMock.SetIdempotentRequestFunc invocations
+func (m *ObjectStorageMock) SetIdempotentRequestMinimockCounter() uint64 {
+	return atomic.LoadUint64(&m.SetIdempotentRequestCounter)
+}
+
+//SetIdempotentRequestMinimockPreCounter returns the value of ObjectStorageMock.SetIdempotentRequest invocations
+func (m *ObjectStorageMock) SetIdempotentRequestMinimockPreCounter() uint64 {
+	return atomic.LoadUint64(&m.SetIdempotentRequestPreCounter)
+}
+
+//SetIdempotentRequestFinished returns true if mock invocations count is ok
+func (m *ObjectStorageMock) SetIdempotentRequestFinished() bool {
+	// if expectation series were set then invocations count should be equal to expectations count
+	if len(m.SetIdempotentRequestMock.expectationSeries) > 0 {
+		return atomic.LoadUint64(&m.SetIdempotentRequestCounter) == uint64(len(m.SetIdempotentRequestMock.expectationSeries))
+	}
+
+	// if main expectation was set then invocations count should be greater than zero
+	if m.SetIdempotentRequestMock.mainExpectation != nil {
+		return atomic.LoadUint64(&m.SetIdempotentRequestCounter) > 0
+	}
+
+	// if func was set then invocations count should be greater than zero
+	if m.SetIdempotentRequestFunc != nil {
+		return atomic.LoadUint64(&m.SetIdempotentRequestCounter) > 0
+	}
+
+	return true
+}
+
 type mObjectStorageMockSetMessage struct {
 	mock              *ObjectStorageMock
 	mainExpectation   *ObjectStorageMockSetMessageExpectation
@@ -1450,6 +2241,157 @@ func (m *ObjectStorageMock) SetRecordFinished() bool {
 	return true
 }
 
+type mObjectStorageMockSetSeedUsed struct {
+	mock              *ObjectStorageMock
+	mainExpectation   *ObjectStorageMockSetSeedUsedExpectation
+	expectationSeries []*ObjectStorageMockSetSeedUsedExpectation
+}
+
+type ObjectStorageMockSetSeedUsedExpectation struct {
+	input  *ObjectStorageMockSetSeedUsedInput
+	result *ObjectStorageMockSetSeedUsedResult
+}
+
+type ObjectStorageMockSetSeedUsedInput struct {
+	p  context.Context
+	p1 core.RecordID
+	p2 *core.RecordID
+	p3 []byte
+	p4 core.PulseNumber
+}
+
+type ObjectStorageMockSetSeedUsedResult struct {
+	r error
+}
+
+//Expect specifies that invocation of ObjectStorage.SetSeedUsed is expected from 1 to Infinity times
+func (m *mObjectStorageMockSetSeedUsed) Expect(p context.Context, p1 core.RecordID, p2 *core.RecordID, p3 []byte, p4 core.PulseNumber) *mObjectStorageMockSetSeedUsed {
+	m.mock.SetSeedUsedFunc = nil
+	m.expectationSeries = nil
+
+	if m.mainExpectation == nil {
+		m.mainExpectation = &ObjectStorageMockSetSeedUsedExpectation{}
+	}
+	m.mainExpectation.input = &ObjectStorageMockSetSeedUsedInput{p, p1, p2, p3, p4}
+	return m
+}
+
+//Return specifies results of invocation of ObjectStorage.SetSeedUsed
+func (m *mObjectStorageMockSetSeedUsed) Return(r error) *ObjectStorageMock {
+	m.mock.SetSeedUsedFunc = nil
+	m.expectationSeries = nil
+
+	if m.mainExpectation == nil {
+		m.mainExpectation = &ObjectStorageMockSetSeedUsedExpectation{}
+	}
+	m.mainExpectation.result = &ObjectStorageMockSetSeedUsedResult{r}
+	return m.mock
+}
+
+//ExpectOnce specifies that invocation of ObjectStorage.SetSeedUsed is expected once
+func (m *mObjectStorageMockSetSeedUsed) ExpectOnce(p context.Context, p1 core.RecordID, p2 *core.RecordID, p3 []byte, p4 core.PulseNumber) *ObjectStorageMockSetSeedUsedExpectation {
+	m.mock.SetSeedUsedFunc = nil
+	m.mainExpectation = nil
+
+	expectation := &ObjectStorageMockSetSeedUsedExpectation{}
+	expectation.input = &ObjectStorageMockSetSeedUsedInput{p, p1, p2, p3, p4}
+	m.expectationSeries = append(m.expectationSeries, expectation)
+	return expectation
+}
+
+func (e *ObjectStorageMockSetSeedUsedExpectation) Return(r error) {
+	e.result = &ObjectStorageMockSetSeedUsedResult{r}
+}
+
+//Set uses given function f as a mock of ObjectStorage.SetSeedUsed method
+func (m *mObjectStorageMockSetSeedUsed) Set(f func(p context.Context, p1 core.RecordID, p2 *core.RecordID, p3 []byte, p4 core.PulseNumber) (r error)) *ObjectStorageMock {
+	m.mainExpectation = nil
+	m.expectationSeries = nil
+
+	m.mock.SetSeedUsedFunc = f
+	return m.mock
+}
+
+//SetSeedUsed implements github.com/insolar/insolar/ledger/storage.ObjectStorage interface
+func (m *ObjectStorageMock) SetSeedUsed(p context.Context, p1 core.RecordID, p2 *core.RecordID, p3 []byte, p4 core.PulseNumber) (r error) {
+	counter := atomic.AddUint64(&m.SetSeedUsedPreCounter, 1)
+	defer atomic.AddUint64(&m.SetSeedUsedCounter, 1)
+
+	if len(m.SetSeedUsedMock.expectationSeries) > 0 {
+		if counter > uint64(len(m.SetSeedUsedMock.expectationSeries)) {
+			m.t.Fatalf("Unexpected call to ObjectStorageMock.SetSeedUsed. %v %v %v %v %v", p, p1, p2, p3, p4)
+			return
+		}
+
+		input := m.SetSeedUsedMock.expectationSeries[counter-1].input
+		testify_assert.Equal(m.t, *input, ObjectStorageMockSetSeedUsedInput{p, p1, p2, p3, p4}, "ObjectStorage.SetSeedUsed got unexpected parameters")
+
+		result := m.SetSeedUsedMock.expectationSeries[counter-1].result
+		if result == nil {
+			m.t.Fatal("No results are set for the ObjectStorageMock.SetSeedUsed")
+			return
+		}
+
+		r = result.r
+
+		return
+	}
+
+	if m.SetSeedUsedMock.mainExpectation != nil {
+
+		input := m.SetSeedUsedMock.mainExpectation.input
+		if input != nil {
+			testify_assert.Equal(m.t, *input, ObjectStorageMockSetSeedUsedInput{p, p1, p2, p3, p4}, "ObjectStorage.SetSeedUsed got unexpected parameters")
+		}
+
+		result := m.SetSeedUsedMock.mainExpectation.result
+		if result == nil {
+			m.t.Fatal("No results are set for the ObjectStorageMock.SetSeedUsed")
+		}
+
+		r = result.r
+
+		return
+	}
+
+	if m.SetSeedUsedFunc == nil {
+		m.t.Fatalf("Unexpected call to ObjectStorageMock.SetSeedUsed. %v %v %v %v %v", p, p1, p2, p3, p4)
+		return
+	}
+
+	return m.SetSeedUsedFunc(p, p1, p2, p3, p4)
+}
+
+//SetSeedUsedMinimockCounter returns a count of ObjectStorageMock.SetSeedUsedFunc invocations
+func (m *ObjectStorageMock) SetSeedUsedMinimockCounter() uint64 {
+	return atomic.LoadUint64(&m.SetSeedUsedCounter)
+}
+
+//SetSeedUsedMinimockPreCounter returns the value of ObjectStorageMock.SetSeedUsed invocations
+func (m *ObjectStorageMock) SetSeedUsedMinimockPreCounter() uint64 {
+	return atomic.LoadUint64(&m.SetSeedUsedPreCounter)
+}
+
+//SetSeedUsedFinished returns true if mock invocations count is ok
+func (m *ObjectStorageMock) SetSeedUsedFinished() bool {
+	// if expectation series were set then invocations count should be equal to expectations count
+	if len(m.SetSeedUsedMock.expectationSeries) > 0 {
+		return atomic.LoadUint64(&m.SetSeedUsedCounter) == uint64(len(m.SetSeedUsedMock.expectationSeries))
+	}
+
+	// if main expectation was set then invocations count should be greater than zero
+	if m.SetSeedUsedMock.mainExpectation != nil {
+		return atomic.LoadUint64(&m.SetSeedUsedCounter) > 0
+	}
+
+	// if func was set then invocations count should be greater than zero
+	if m.SetSeedUsedFunc != nil {
+		return atomic.LoadUint64(&m.SetSeedUsedCounter) > 0
+	}
+
+	return true
+}
+
 //ValidateCallCounters checks that all mocked methods of the interface have been called at least once
 //Deprecated: please use MinimockFinish method or use Finish method of minimock.Controller
 func (m *ObjectStorageMock) ValidateCallCounters() {
@@ -1458,6 +2400,14 @@ func (m *ObjectStorageMock) ValidateCallCounters() {
 		m.t.Fatal("Expected call to ObjectStorageMock.GetBlob")
 	}
 
+	if !m.GetIdempotencyKeyFinished() {
+		m.t.Fatal("Expected call to ObjectStorageMock.GetIdempotencyKey")
+	}
+
+	if !m.GetIdempotentRequestFinished() {
+		m.t.Fatal("Expected call to ObjectStorageMock.GetIdempotentRequest")
+	}
+
 	if !m.GetObjectIndexFinished() {
 		m.t.Fatal("Expected call to ObjectStorageMock.GetObjectIndex")
 	}
@@ -1466,10 +2416,18 @@ func (m *ObjectStorageMock) ValidateCallCounters() {
 		m.t.Fatal("Expected call to ObjectStorageMock.GetRecord")
 	}
 
+	if !m.IterateIdempotentRequestsFinished() {
+		m.t.Fatal("Expected call to ObjectStorageMock.IterateIdempotentRequests")
+	}
+
 	if !m.IterateIndexIDsFinished() {
 		m.t.Fatal("Expected call to ObjectStorageMock.IterateIndexIDs")
 	}
 
+	if !m.IterateUsedSeedsFinished() {
+		m.t.Fatal("Expected call to ObjectStorageMock.IterateUsedSeeds")
+	}
+
 	if !m.RemoveObjectIndexFinished() {
 		m.t.Fatal("Expected call to ObjectStorageMock.RemoveObjectIndex")
 	}
@@ -1478,6 +2436,10 @@ func (m *ObjectStorageMock) ValidateCallCounters() {
 		m.t.Fatal("Expected call to ObjectStorageMock.SetBlob")
 	}
 
+	if !m.SetIdempotentRequestFinished() {
+		m.t.Fatal("Expected call to ObjectStorageMock.SetIdempotentRequest")
+	}
+
 	if !m.SetMessageFinished() {
 		m.t.Fatal("Expected call to ObjectStorageMock.SetMessage")
 	}
@@ -1490,6 +2452,10 @@ func (m *ObjectStorageMock) ValidateCallCounters() {
 		m.t.Fatal("Expected call to ObjectStorageMock.SetRecord")
 	}
 
+	if !m.SetSeedUsedFinished() {
+		m.t.Fatal("Expected call to ObjectStorageMock.SetSeedUsed")
+	}
+
 }
 
 //CheckMocksCalled checks that all mocked methods of the interface have been called at least once
@@ -1511,6 +2477,14 @@ func (m *ObjectStorageMock) MinimockFinish() {
 		m.t.Fatal("Expected call to ObjectStorageMock.GetBlob")
 	}
 
+	if !m.GetIdempotencyKeyFinished() {
+		m.t.Fatal("Expected call to ObjectStorageMock.GetIdempotencyKey")
+	}
+
+	if !m.GetIdempotentRequestFinished() {
+		m.t.Fatal("Expected call to ObjectStorageMock.GetIdempotentRequest")
+	}
+
 	if !m.GetObjectIndexFinished() {
 		m.t.Fatal("Expected call to ObjectStorageMock.GetObjectIndex")
 	}
@@ -1519,10 +2493,18 @@ func (m *ObjectStorageMock) MinimockFinish() {
 		m.t.Fatal("Expected call to ObjectStorageMock.GetRecord")
 	}
 
+	if !m.IterateIdempotentRequestsFinished() {
+		m.t.Fatal("Expected call to ObjectStorageMock.IterateIdempotentRequests")
+	}
+
 	if !m.IterateIndexIDsFinished() {
 		m.t.Fatal("Expected call to ObjectStorageMock.IterateIndexIDs")
 	}
 
+	if !m.IterateUsedSeedsFinished() {
+		m.t.Fatal("Expected call to ObjectStorageMock.IterateUsedSeeds")
+	}
+
 	if !m.RemoveObjectIndexFinished() {
 		m.t.Fatal("Expected call to ObjectStorageMock.RemoveObjectIndex")
 	}
@@ -1531,6 +2513,10 @@ func (m *ObjectStorageMock) MinimockFinish() {
 		m.t.Fatal("Expected call to ObjectStorageMock.SetBlob")
 	}
 
+	if !m.SetIdempotentRequestFinished() {
+		m.t.Fatal("Expected call to ObjectStorageMock.SetIdempotentRequest")
+	}
+
 	if !m.SetMessageFinished() {
 		m.t.Fatal("Expected call to ObjectStorageMock.SetMessage")
 	}
@@ -1543,6 +2529,10 @@ func (m *ObjectStorageMock) MinimockFinish() {
 		m.t.Fatal("Expected call to ObjectStorageMock.SetRecord")
 	}
 
+	if !m.SetSeedUsedFinished() {
+		m.t.Fatal("Expected call to ObjectStorageMock.SetSeedUsed")
+	}
+
 }
 
 //Wait waits for all mocked methods to be called at least once
@@ -1558,14 +2548,20 @@ func (m *ObjectStorageMock) MinimockWait(timeout time.Duration) {
 	for {
 		ok := true
 		ok = ok && m.GetBlobFinished()
+		ok = ok && m.GetIdempotencyKeyFinished()
+		ok = ok && m.GetIdempotentRequestFinished()
 		ok = ok && m.GetObjectIndexFinished()
 		ok = ok && m.GetRecordFinished()
+		ok = ok && m.IterateIdempotentRequestsFinished()
 		ok = ok && m.IterateIndexIDsFinished()
+		ok = ok && m.IterateUsedSeedsFinished()
 		ok = ok && m.RemoveObjectIndexFinished()
 		ok = ok && m.SetBlobFinished()
+		ok = ok && m.SetIdempotentRequestFinished()
 		ok = ok && m.SetMessageFinished()
 		ok = ok && m.SetObjectIndexFinished()
 		ok = ok && m.SetRecordFinished()
+		ok = ok && m.SetSeedUsedFinished()
 
 		if ok {
 			return
@@ -1578,6 +2574,14 @@ func (m *ObjectStorageMock) MinimockWait(timeout time.Duration) {
 				m.t.Error("Expected call to ObjectStorageMock.GetBlob")
 			}
 
+			if !m.GetIdempotencyKeyFinished() {
+				m.t.Error("Expected call to ObjectStorageMock.GetIdempotencyKey")
+			}
+
+			if !m.GetIdempotentRequestFinished() {
+				m.t.Error("Expected call to ObjectStorageMock.GetIdempotentRequest")
+			}
+
 			if !m.GetObjectIndexFinished() {
 				m.t.Error("Expected call to ObjectStorageMock.GetObjectIndex")
 			}
@@ -1586,10 +2590,18 @@ func (m *ObjectStorageMock) MinimockWait(timeout time.Duration) {
 				m.t.Error("Expected call to ObjectStorageMock.GetRecord")
 			}
 
+			if !m.IterateIdempotentRequestsFinished() {
+				m.t.Error("Expected call to ObjectStorageMock.IterateIdempotentRequests")
+			}
+
 			if !m.IterateIndexIDsFinished() {
 				m.t.Error("Expected call to ObjectStorageMock.IterateIndexIDs")
 			}
 
+			if !m.IterateUsedSeedsFinished() {
+				m.t.Error("Expected call to ObjectStorageMock.IterateUsedSeeds")
+			}
+
 			if !m.RemoveObjectIndexFinished() {
 				m.t.Error("Expected call to ObjectStorageMock.RemoveObjectIndex")
 			}
@@ -1598,6 +2610,10 @@ func (m *ObjectStorageMock) MinimockWait(timeout time.Duration) {
 				m.t.Error("Expected call to ObjectStorageMock.SetBlob")
 			}
 
+			if !m.SetIdempotentRequestFinished() {
+				m.t.Error("Expected call to ObjectStorageMock.SetIdempotentRequest")
+			}
+
 			if !m.SetMessageFinished() {
 				m.t.Error("Expected call to ObjectStorageMock.SetMessage")
 			}
@@ -1610,6 +2626,10 @@ func (m *ObjectStorageMock) MinimockWait(timeout time.Duration) {
 				m.t.Error("Expected call to ObjectStorageMock.SetRecord")
 			}
 
+			if !m.SetSeedUsedFinished() {
+				m.t.Error("Expected call to ObjectStorageMock.SetSeedUsed")
+			}
+
 			m.t.Fatalf("Some mocks were not called on time: %s", timeout)
 			return
 		default:
@@ -1626,6 +2646,14 @@ func (m *ObjectStorageMock) AllMocksCalled() bool {
 		return false
 	}
 
+	if !m.GetIdempotencyKeyFinished() {
+		return false
+	}
+
+	if !m.GetIdempotentRequestFinished() {
+		return false
+	}
+
 	if !m.GetObjectIndexFinished() {
 		return false
 	}
@@ -1634,10 +2662,18 @@ func (m *ObjectStorageMock) AllMocksCalled() bool {
 		return false
 	}
 
+	if !m.IterateIdempotentRequestsFinished() {
+		return false
+	}
+
 	if !m.IterateIndexIDsFinished() {
 		return false
 	}
 
+	if !m.IterateUsedSeedsFinished() {
+		return false
+	}
+
 	if !m.RemoveObjectIndexFinished() {
 		return false
 	}
@@ -1646,6 +2682,10 @@ func (m *ObjectStorageMock) AllMocksCalled() bool {
 		return false
 	}
 
+	if !m.SetIdempotentRequestFinished() {
+		return false
+	}
+
 	if !m.SetMessageFinished() {
 		return false
 	}
@@ -1658,5 +2698,9 @@ func (m *ObjectStorageMock) AllMocksCalled() bool {
 		return false
 	}
 
+	if !m.SetSeedUsedFinished() {
+		return false
+	}
+
 	return true
 }
@@ -62,6 +62,48 @@ type ObjectStorage interface {
 		jetID core.RecordID,
 		ref *core.RecordID,
 	) error
+
+	GetIdempotentRequest(
+		ctx context.Context,
+		jetID core.RecordID,
+		object *core.RecordID,
+		key string,
+	) (*index.IdempotentRequest, error)
+
+	GetIdempotencyKey(
+		ctx context.Context,
+		jetID core.RecordID,
+		object *core.RecordID,
+		request *core.RecordID,
+	) (string, error)
+
+	SetIdempotentRequest(
+		ctx context.Context,
+		jetID core.RecordID,
+		object *core.RecordID,
+		req *index.IdempotentRequest,
+	) error
+
+	IterateIdempotentRequests(
+		ctx context.Context,
+		jetID core.RecordID,
+		object *core.RecordID,
+		handler func(req *index.IdempotentRequest) error,
+	) error
+
+	SetSeedUsed(
+		ctx context.Context,
+		jetID core.RecordID,
+		object *core.RecordID,
+		seed []byte,
+		expires core.PulseNumber,
+	) error
+
+	IterateUsedSeeds(
+		ctx context.Context,
+		jetID core.RecordID,
+		handler func(object core.RecordID, seed []byte, expires core.PulseNumber) error,
+	) error
 }
 
 type objectStorage struct {
@@ -220,3 +262,106 @@ func (os *objectStorage) RemoveObjectIndex(
 		return tx.RemoveObjectIndex(ctx, jetID, ref)
 	})
 }
+
+// GetIdempotentRequest wraps matching transaction manager method.
+func (os *objectStorage) GetIdempotentRequest(
+	ctx context.Context,
+	jetID core.RecordID,
+	object *core.RecordID,
+	key string,
+) (*index.IdempotentRequest, error) {
+	var (
+		req *index.IdempotentRequest
+		err error
+	)
+	err = os.DB.View(ctx, func(tx *TransactionManager) error {
+		req, err = tx.GetIdempotentRequest(ctx, jetID, object, key, false)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// GetIdempotencyKey wraps matching transaction manager method.
+func (os *objectStorage) GetIdempotencyKey(
+	ctx context.Context,
+	jetID core.RecordID,
+	object *core.RecordID,
+	request *core.RecordID,
+) (string, error) {
+	var (
+		key string
+		err error
+	)
+	err = os.DB.View(ctx, func(tx *TransactionManager) error {
+		key, err = tx.GetIdempotencyKey(ctx, jetID, object, request)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return key, nil
+}
+
+// SetIdempotentRequest wraps matching transaction manager method.
+func (os *objectStorage) SetIdempotentRequest(
+	ctx context.Context,
+	jetID core.RecordID,
+	object *core.RecordID,
+	req *index.IdempotentRequest,
+) error {
+	return os.DB.Update(ctx, func(tx *TransactionManager) error {
+		return tx.SetIdempotentRequest(ctx, jetID, object, req)
+	})
+}
+
+// IterateIdempotentRequests iterates over requests registered on object with idempotency keys.
+func (os *objectStorage) IterateIdempotentRequests(
+	ctx context.Context,
+	jetID core.RecordID,
+	object *core.RecordID,
+	handler func(req *index.IdempotentRequest) error,
+) error {
+	_, jetPrefix := jet.Jet(jetID)
+	prefix := prefixkey(scopeIDIdempotency, jetPrefix, object[:], []byte{idempotencyByKey})
+
+	return os.DB.iterate(ctx, prefix, func(k, v []byte) error {
+		req, err := index.DecodeIdempotentRequest(v)
+		if err != nil {
+			return err
+		}
+		return handler(req)
+	})
+}
+
+// SetSeedUsed wraps matching transaction manager method.
+func (os *objectStorage) SetSeedUsed(
+	ctx context.Context,
+	jetID core.RecordID,
+	object *core.RecordID,
+	seed []byte,
+	expires core.PulseNumber,
+) error {
+	return os.DB.Update(ctx, func(tx *TransactionManager) error {
+		return tx.SetSeedUsed(ctx, jetID, object, seed, expires)
+	})
+}
+
+// IterateUsedSeeds iterates over seeds used in calls of objects on provided Jet ID.
+func (os *objectStorage) IterateUsedSeeds(
+	ctx context.Context,
+	jetID core.RecordID,
+	handler func(object core.RecordID, seed []byte, expires core.PulseNumber) error,
+) error {
+	_, jetPrefix := jet.Jet(jetID)
+	prefix := prefixkey(scopeIDSeed, jetPrefix)
+
+	return os.DB.iterate(ctx, prefix, func(k, v []byte) error {
+		expires := pulseNumFromKey(0, k)
+		object := core.RecordID{}
+		copy(object[:], k[core.PulseNumberSize:])
+		return handler(object, k[core.PulseNumberSize+core.RecordIDSize:], expires)
+	})
+}
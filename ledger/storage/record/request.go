@@ -34,6 +34,10 @@ type RequestRecord struct {
 	Parcel      []byte
 	MessageHash []byte
 	Object      core.RecordID
+	// Parent is a request that made this request, empty for requests from outside
+	Parent core.RecordRef
+	// IdempotencyKey is a client key of request, empty if client provided none
+	IdempotencyKey string
 }
 
 // WriteHashData writes record data to provided writer. This data is used to calculate record's hash.
@@ -50,3 +54,8 @@ func (r *RequestRecord) GetPayload() []byte {
 func (r *RequestRecord) GetObject() core.RecordID {
 	return r.Object
 }
+
+// GetParent returns request that made this request.
+func (r *RequestRecord) GetParent() core.RecordRef {
+	return r.Parent
+}
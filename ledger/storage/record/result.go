@@ -86,6 +86,7 @@ type CodeRecord struct {
 
 	Code        *core.RecordID
 	MachineType core.MachineType
+	ABI         *core.RecordID
 }
 
 // WriteHashData writes record data to provided writer. This data is used to calculate record's hash.
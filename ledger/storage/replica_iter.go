@@ -87,6 +87,7 @@ func NewReplicaIter(
 			newit(scopeIDRecord, jetID, start, end),
 			newit(scopeIDBlob, jetID, start, end),
 			newit(scopeIDLifeline, jetID, core.FirstPulseNumber, end),
+			newit(scopeIDIdempotency, jetID, core.FirstPulseNumber, end),
 			newit(scopeIDJetDrop, jetID, start, end),
 		},
 	}
@@ -163,6 +163,37 @@ func (s *storageSuite) TestDB_SetObjectIndex_SaveLastUpdate() {
 	assert.Equal(s.T(), 1239, int(idx.LatestUpdate))
 }
 
+func (s *storageSuite) TestDB_SetIdempotentRequest() {
+	object := core.NewRecordID(0, hexhash("10"))
+	req := index.IdempotentRequest{
+		Key:     "key",
+		Request: *core.NewRecordID(0, hexhash("20")),
+	}
+	_, err := s.objectStorage.GetIdempotentRequest(s.ctx, s.jetID, object, "key")
+	assert.Equal(s.T(), storage.ErrNotFound, err)
+
+	err = s.objectStorage.SetIdempotentRequest(s.ctx, s.jetID, object, &req)
+	require.NoError(s.T(), err)
+
+	stored, err := s.objectStorage.GetIdempotentRequest(s.ctx, s.jetID, object, "key")
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), req, *stored)
+	key, err := s.objectStorage.GetIdempotencyKey(s.ctx, s.jetID, object, &req.Request)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), "key", key)
+
+	// Requests of other objects are not iterated.
+	err = s.objectStorage.SetIdempotentRequest(s.ctx, s.jetID, core.NewRecordID(0, hexhash("11")), &req)
+	require.NoError(s.T(), err)
+	var iterated []index.IdempotentRequest
+	err = s.objectStorage.IterateIdempotentRequests(s.ctx, s.jetID, object, func(req *index.IdempotentRequest) error {
+		iterated = append(iterated, *req)
+		return nil
+	})
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), []index.IdempotentRequest{req}, iterated)
+}
+
 func (s *storageSuite) TestDB_GetDrop_ReturnsNotFoundIfNoDrop() {
 	drop, err := s.dropStorage.GetDrop(s.ctx, testutils.RandomJet(), 1)
 	assert.Equal(s.T(), err, storage.ErrNotFound)
@@ -213,6 +213,107 @@ func (m *TransactionManager) RemoveObjectIndex(
 	return m.remove(ctx, k)
 }
 
+// GetIdempotentRequest fetches request registered on object with provided idempotency key.
+//
+// Object is locked until transaction end if forupdate is set.
+func (m *TransactionManager) GetIdempotentRequest(
+	ctx context.Context,
+	jetID core.RecordID,
+	object *core.RecordID,
+	key string,
+	forupdate bool,
+) (*index.IdempotentRequest, error) {
+	if forupdate {
+		m.lockOnID(object)
+	}
+	buf, err := m.get(ctx, m.idempotentRequestKey(jetID, object, key))
+	if err != nil {
+		return nil, err
+	}
+	return index.DecodeIdempotentRequest(buf)
+}
+
+// GetIdempotencyKey fetches idempotency key of request registered on object.
+func (m *TransactionManager) GetIdempotencyKey(
+	ctx context.Context,
+	jetID core.RecordID,
+	object *core.RecordID,
+	request *core.RecordID,
+) (string, error) {
+	_, prefix := jet.Jet(jetID)
+	k := prefixkey(scopeIDIdempotency, prefix, object[:], []byte{idempotencyByRequest}, request[:])
+	buf, err := m.get(ctx, k)
+	if err != nil {
+		return "", err
+	}
+	return string(buf), nil
+}
+
+// SetIdempotentRequest stores request registered on object with idempotency key. Request can be fetched both by its
+// key and its id then.
+func (m *TransactionManager) SetIdempotentRequest(
+	ctx context.Context,
+	jetID core.RecordID,
+	object *core.RecordID,
+	req *index.IdempotentRequest,
+) error {
+	encoded, err := index.EncodeIdempotentRequest(req)
+	if err != nil {
+		return err
+	}
+	err = m.set(ctx, m.idempotentRequestKey(jetID, object, req.Key), encoded)
+	if err != nil {
+		return err
+	}
+	_, prefix := jet.Jet(jetID)
+	k := prefixkey(scopeIDIdempotency, prefix, object[:], []byte{idempotencyByRequest}, req.Request[:])
+	return m.set(ctx, k, []byte(req.Key))
+}
+
+// IsSeedUsed tells if seed is used in call of object.
+//
+// Object is locked until transaction end if forupdate is set.
+func (m *TransactionManager) IsSeedUsed(
+	ctx context.Context,
+	jetID core.RecordID,
+	object *core.RecordID,
+	seed []byte,
+	expires core.PulseNumber,
+	forupdate bool,
+) (bool, error) {
+	if forupdate {
+		m.lockOnID(object)
+	}
+	_, prefix := jet.Jet(jetID)
+	_, err := m.get(ctx, prefixkey(scopeIDSeed, prefix, expires.Bytes(), object[:], seed))
+	if err == ErrNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// SetSeedUsed marks seed as used in call of object until it expires.
+func (m *TransactionManager) SetSeedUsed(
+	ctx context.Context,
+	jetID core.RecordID,
+	object *core.RecordID,
+	seed []byte,
+	expires core.PulseNumber,
+) error {
+	_, prefix := jet.Jet(jetID)
+	return m.set(ctx, prefixkey(scopeIDSeed, prefix, expires.Bytes(), object[:], seed), []byte{})
+}
+
+// idempotentRequestKey uses hash of idempotency key, so keys of any length are stored the same way.
+func (m *TransactionManager) idempotentRequestKey(jetID core.RecordID, object *core.RecordID, key string) []byte {
+	_, prefix := jet.Jet(jetID)
+	hash := m.db.PlatformCryptographyScheme.IntegrityHasher().Hash([]byte(key))
+	return prefixkey(scopeIDIdempotency, prefix, object[:], []byte{idempotencyByKey}, hash)
+}
+
 // set stores value by key.
 func (m *TransactionManager) set(ctx context.Context, key, value []byte) error {
 	m.txupdates[string(key)] = keyval{k: key, v: value}
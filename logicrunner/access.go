@@ -0,0 +1,213 @@
+/*
+ *    Copyright 2019 Insolar Technologies
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package logicrunner
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/core/message"
+	"github.com/insolar/insolar/instrumentation/inslogger"
+)
+
+// CodeABI is a part of code's ABI the logic runner needs to enforce access rules
+// and start transactions
+type CodeABI struct {
+	Contract string `json:"contract"`
+	Methods  []struct {
+		Name          string            `json:"name"`
+		Transactional bool              `json:"transactional"`
+		Access        []core.AccessRule `json:"access"`
+	} `json:"methods"`
+}
+
+// Rules returns access rules of method, nil if method can be called by anyone
+func (ca *CodeABI) Rules(method string) []core.AccessRule {
+	if ca == nil {
+		return nil
+	}
+	for _, m := range ca.Methods {
+		if m.Name == method {
+			return m.Access
+		}
+	}
+	return nil
+}
+
+// Transactional checks if call of method starts transaction
+func (ca *CodeABI) Transactional(method string) bool {
+	if ca == nil {
+		return false
+	}
+	for _, m := range ca.Methods {
+		if m.Name == method {
+			return m.Transactional
+		}
+	}
+	return false
+}
+
+// AccessCache keeps decoded ABI of codes and contract names of prototypes
+type AccessCache struct {
+	sync.RWMutex
+	codes      map[core.RecordRef]*CodeABI
+	prototypes map[core.RecordRef]string
+
+	rootMember atomic.Value
+	resolving  int32
+}
+
+// NewAccessCache creates empty AccessCache
+func NewAccessCache() *AccessCache {
+	return &AccessCache{
+		codes:      make(map[core.RecordRef]*CodeABI),
+		prototypes: make(map[core.RecordRef]string),
+	}
+}
+
+// codeABI returns decoded ABI of code, nil if code was deployed without ABI
+func (lr *LogicRunner) codeABI(codeDesc core.CodeDescriptor) (*CodeABI, error) {
+	ref := *codeDesc.Ref()
+	lr.access.RLock()
+	ca, ok := lr.access.codes[ref]
+	lr.access.RUnlock()
+	if ok {
+		return ca, nil
+	}
+
+	abi, err := codeDesc.ABI()
+	if err != nil {
+		return nil, errors.Wrap(err, "couldn't get ABI of code")
+	}
+	if len(abi) > 0 {
+		ca = &CodeABI{}
+		if err := json.Unmarshal(abi, ca); err != nil {
+			return nil, errors.Wrapf(err, "couldn't decode ABI of code %s", ref)
+		}
+	}
+
+	lr.access.Lock()
+	lr.access.codes[ref] = ca
+	lr.access.Unlock()
+	return ca, nil
+}
+
+// contractName returns name of contract of prototype, empty if it's unknown
+func (lr *LogicRunner) contractName(ctx context.Context, proto core.RecordRef) (string, error) {
+	lr.access.RLock()
+	name, ok := lr.access.prototypes[proto]
+	lr.access.RUnlock()
+	if ok {
+		return name, nil
+	}
+
+	_, codeDesc, err := lr.getDescriptorsByPrototypeRef(ctx, proto)
+	if err != nil {
+		return "", errors.Wrap(err, "couldn't get descriptors of caller's prototype")
+	}
+	ca, err := lr.codeABI(codeDesc)
+	if err != nil {
+		return "", err
+	}
+	if ca != nil {
+		name = ca.Contract
+	}
+
+	lr.access.Lock()
+	lr.access.prototypes[proto] = name
+	lr.access.Unlock()
+	return name, nil
+}
+
+// rootMember returns reference of root member if it's already known
+func (lr *LogicRunner) rootMember() *core.RecordRef {
+	ref, _ := lr.access.rootMember.Load().(*core.RecordRef)
+	return ref
+}
+
+// resolveRootMemberOnPulse asks genesis data provider for root member in background
+// until it's known. It's not done during the check, as the call goes to root domain,
+// that could be the object being executed.
+func (lr *LogicRunner) resolveRootMemberOnPulse(ctx context.Context) {
+	if lr.GenesisDataProvider == nil || lr.rootMember() != nil {
+		return
+	}
+	if !atomic.CompareAndSwapInt32(&lr.access.resolving, 0, 1) {
+		return
+	}
+	go func() {
+		defer atomic.StoreInt32(&lr.access.resolving, 0)
+		ref, err := lr.GenesisDataProvider.GetRootMember(ctx)
+		if err != nil {
+			inslogger.FromContext(ctx).Debug(errors.Wrap(err, "root member isn't resolved yet"))
+			return
+		}
+		lr.access.rootMember.Store(ref)
+	}()
+}
+
+// checkAccess checks caller of the message satisfies at least one of the rules of method
+func (lr *LogicRunner) checkAccess(ctx context.Context, body *ObjectBody, m *message.CallMethod) error {
+	rules := body.abi.Rules(m.Method)
+	if len(rules) == 0 {
+		return nil
+	}
+
+	caller := m.Caller
+	if caller.IsEmpty() {
+		return errors.Errorf("access to method %s denied: caller is unknown", m.Method)
+	}
+
+	for _, rule := range rules {
+		switch rule {
+		case core.AccessParent:
+			if body.Parent != nil && caller.Equal(*body.Parent) {
+				return nil
+			}
+		case core.AccessSelf:
+			if caller.Equal(m.ObjectRef) {
+				return nil
+			}
+		case core.AccessRootMember:
+			root := lr.rootMember()
+			if root == nil {
+				return errors.Errorf("access to method %s denied: root member isn't known yet", m.Method)
+			}
+			if caller.Equal(*root) {
+				return nil
+			}
+		default:
+			contract, ok := rule.Prototype()
+			if !ok || m.CallerPrototype.IsEmpty() {
+				continue
+			}
+			name, err := lr.contractName(ctx, m.CallerPrototype)
+			if err != nil {
+				return errors.Wrapf(err, "access to method %s denied", m.Method)
+			}
+			if name == contract {
+				return nil
+			}
+		}
+	}
+	return errors.Errorf("access to method %s denied", m.Method)
+}
@@ -0,0 +1,93 @@
+/*
+ *    Copyright 2019 Insolar Technologies
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package logicrunner
+
+import (
+	"testing"
+
+	"github.com/gojuno/minimock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/insolar/insolar/configuration"
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/core/message"
+	"github.com/insolar/insolar/instrumentation/inslogger"
+	"github.com/insolar/insolar/logicrunner/goplugin/goplugintestutils"
+	"github.com/insolar/insolar/testutils"
+)
+
+func TestLogicRunner_checkAccess(t *testing.T) {
+	ctx := inslogger.TestContext(t)
+	mc := minimock.NewController(t)
+	defer mc.Finish()
+
+	am := testutils.NewArtifactManagerMock(mc)
+	lr, _ := NewLogicRunner(&configuration.LogicRunner{})
+	lr.ArtifactManager = am
+
+	abi, err := lr.codeABI(&goplugintestutils.TestCodeDescriptor{
+		ARef: testutils.RandomRef(),
+		AABI: []byte(`{
+			"contract": "Wallet",
+			"methods": [
+				{"name": "Balance", "arguments": [], "results": []},
+				{"name": "Transfer", "access": ["parent", "prototype:Wallet"], "arguments": [], "results": []},
+				{"name": "Destroy", "access": ["self", "root_member"], "arguments": [], "results": []}
+			]
+		}`),
+	})
+	require.NoError(t, err)
+
+	object := testutils.RandomRef()
+	parent := testutils.RandomRef()
+	body := &ObjectBody{abi: abi, Parent: &parent}
+
+	// prototype of caller is resolved to contract name through its code
+	walletProto := testutils.RandomRef()
+	walletCode := testutils.RandomRef()
+	protoDesc := testutils.NewObjectDescriptorMock(mc)
+	protoDesc.CodeMock.Return(&walletCode, nil)
+	am.GetObjectMock.Return(protoDesc, nil)
+	am.GetCodeMock.Return(&goplugintestutils.TestCodeDescriptor{
+		ARef: walletCode,
+		AABI: []byte(`{"contract": "Wallet"}`),
+	}, nil)
+
+	call := func(method string, caller, callerProto core.RecordRef) error {
+		return lr.checkAccess(ctx, body, &message.CallMethod{
+			BaseLogicMessage: message.BaseLogicMessage{Caller: caller, CallerPrototype: callerProto},
+			ObjectRef:        object,
+			Method:           method,
+		})
+	}
+
+	require.NoError(t, call("Balance", core.RecordRef{}, core.RecordRef{}))
+	require.NoError(t, call("Unknown", testutils.RandomRef(), core.RecordRef{}))
+
+	require.NoError(t, call("Transfer", parent, core.RecordRef{}))
+	require.NoError(t, call("Transfer", testutils.RandomRef(), walletProto))
+	require.Error(t, call("Transfer", testutils.RandomRef(), core.RecordRef{}))
+	require.Error(t, call("Transfer", core.RecordRef{}, core.RecordRef{}))
+
+	require.NoError(t, call("Destroy", object, core.RecordRef{}))
+	require.Error(t, call("Destroy", testutils.RandomRef(), core.RecordRef{}))
+
+	root := testutils.RandomRef()
+	lr.access.rootMember.Store(&root)
+	require.NoError(t, call("Destroy", root, core.RecordRef{}))
+	require.Error(t, call("Destroy", testutils.RandomRef(), core.RecordRef{}))
+}
@@ -112,6 +112,27 @@ func (st *ObjectState) RefreshConsensus() {
 	st.Consensus = nil
 }
 
+// StartReplay makes es the state calls of replayed request are executed in,
+// replay has its own state, so it doesn't interfere with execution and validation
+func (st *ObjectState) StartReplay(es *ExecutionState) error {
+	st.Lock()
+	defer st.Unlock()
+
+	if st.Replay != nil {
+		return errors.New("object is being replayed right now")
+	}
+	st.Replay = es
+	return nil
+}
+
+// FinishReplay drops state of replay
+func (st *ObjectState) FinishReplay() {
+	st.Lock()
+	defer st.Unlock()
+
+	st.Replay = nil
+}
+
 func (st *ObjectState) StartValidation() *ExecutionState {
 	st.Lock()
 	defer st.Unlock()
@@ -0,0 +1,153 @@
+/*
+ *    Copyright 2019 Insolar Technologies
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package logicrunner
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/core/message"
+	"github.com/insolar/insolar/core/reply"
+	"github.com/insolar/insolar/instrumentation/inslogger"
+)
+
+// callTracesTTL is a number of pulses traces of executed requests are kept
+const callTracesTTL = 100
+
+type callTraceElement struct {
+	trace core.CallTrace
+	pulse core.PulseNumber
+}
+
+// CallTraces keeps traces of requests executed by this node, they are
+// requested by API to build tree of cross-contract calls
+type CallTraces struct {
+	sync.Mutex
+	traces map[Ref]*callTraceElement
+}
+
+// NewCallTraces creates empty storage of traces
+func NewCallTraces() *CallTraces {
+	return &CallTraces{
+		traces: make(map[Ref]*callTraceElement),
+	}
+}
+
+// Add saves trace of executed request
+func (ct *CallTraces) Add(trace core.CallTrace, pulse core.PulseNumber) {
+	ct.Lock()
+	defer ct.Unlock()
+	ct.traces[trace.Request] = &callTraceElement{trace: trace, pulse: pulse}
+}
+
+// Get returns traces of requests, unknown requests are skipped
+func (ct *CallTraces) Get(requests []Ref) []core.CallTrace {
+	ct.Lock()
+	defer ct.Unlock()
+
+	res := make([]core.CallTrace, 0, len(requests))
+	for _, request := range requests {
+		if el, ok := ct.traces[request]; ok {
+			res = append(res, el.trace)
+		}
+	}
+	return res
+}
+
+// OnPulse removes traces that are older than callTracesTTL pulses
+func (ct *CallTraces) OnPulse(pulse core.Pulse) {
+	ct.Lock()
+	defer ct.Unlock()
+
+	for ref, el := range ct.traces {
+		if el.pulse+callTracesTTL < pulse.PulseNumber {
+			delete(ct.traces, ref)
+		}
+	}
+}
+
+// addChild remembers request made during current execution
+func (es *ExecutionState) addChild(request Ref) {
+	es.Lock()
+	defer es.Unlock()
+	if es.Current == nil {
+		return
+	}
+	es.Current.Children = append(es.Current.Children, request)
+}
+
+// traceExecution saves and returns trace of finished execution
+func (lr *LogicRunner) traceExecution(
+	ctx context.Context, es *ExecutionState, qe ExecutionQueueElement, start time.Time, res ExecutionQueueResult,
+) *core.CallTrace {
+	msg, ok := qe.parcel.Message().(message.IBaseLogicMessage)
+	if !ok || qe.request == nil {
+		return nil
+	}
+
+	trace := core.CallTrace{
+		Request:  *qe.request,
+		Parent:   msg.GetBaseLogicMessage().Request,
+		Object:   msg.GetReference(),
+		Start:    start,
+		Duration: time.Since(start),
+	}
+
+	switch m := msg.(type) {
+	case *message.CallMethod:
+		trace.Method = m.Method
+	case *message.CallConstructor:
+		trace.Method = m.Method
+		trace.Prototype = m.PrototypeRef
+	}
+
+	if lr.NodeNetwork != nil {
+		trace.Executor = lr.NodeNetwork.GetOrigin().ID()
+	}
+
+	es.Lock()
+	if es.Current != nil {
+		trace.Children = es.Current.Children
+		if es.Current.LogicContext != nil && es.Current.LogicContext.Prototype != nil {
+			trace.Prototype = *es.Current.LogicContext.Prototype
+		}
+	}
+	es.Unlock()
+
+	if r, ok := res.reply.(*reply.CallMethod); ok {
+		trace.Result = r.Result
+	}
+	if res.err != nil {
+		trace.Error = res.err.Error()
+	}
+
+	inslogger.FromContext(ctx).Debugf("Saving trace of request %s", trace.Request)
+	lr.callTraces.Add(trace, qe.pulse)
+	return &trace
+}
+
+// HandleGetCallTracesMessage returns traces of requests executed by this node
+func (lr *LogicRunner) HandleGetCallTracesMessage(
+	ctx context.Context, parcel core.Parcel,
+) (
+	core.Reply, error,
+) {
+	msg := parcel.Message().(*message.GetCallTraces)
+	return &reply.CallTraces{Traces: lr.callTraces.Get(msg.Requests)}, nil
+}
@@ -0,0 +1,47 @@
+/*
+ *    Copyright 2019 Insolar Technologies
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package logicrunner
+
+import (
+	"testing"
+
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/testutils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallTraces(t *testing.T) {
+	traces := NewCallTraces()
+
+	parent := testutils.RandomRef()
+	child := testutils.RandomRef()
+	unknown := testutils.RandomRef()
+
+	traces.Add(core.CallTrace{Request: parent, Method: "Call", Children: []core.RecordRef{child}}, core.FirstPulseNumber)
+	traces.Add(core.CallTrace{Request: child, Parent: parent, Method: "Transfer"}, core.FirstPulseNumber+10)
+
+	res := traces.Get([]core.RecordRef{parent, unknown, child})
+	require.Len(t, res, 2)
+	require.Equal(t, "Call", res[0].Method)
+	require.Equal(t, []core.RecordRef{child}, res[0].Children)
+	require.Equal(t, parent, res[1].Parent)
+
+	traces.OnPulse(core.Pulse{PulseNumber: core.FirstPulseNumber + callTracesTTL + 1})
+	res = traces.Get([]core.RecordRef{parent, child})
+	require.Len(t, res, 1)
+	require.Equal(t, child, res[0].Request)
+}
@@ -0,0 +1,99 @@
+/*
+ *    Copyright 2019 Insolar Technologies
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package logicrunner
+
+import (
+	"context"
+	"encoding/binary"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/sha3"
+
+	"github.com/insolar/insolar/core"
+)
+
+// Contracts with large memory keep it in chunks, child objects of the contract
+// that have memory and nothing else (see foundation.PagedMap). Memory of the
+// contract refers to exact states of its chunks, so chunks are fetched only when
+// touched and a failed or aborted call leaves the contract with the states it had.
+// Validation saves chunks the same way execution does, ledger replies are played
+// from the tape of the executor, so memory of the contract refers to the same states.
+
+// ChunkPrototype is a prototype of all chunks, there is no code behind it
+var ChunkPrototype = func() core.RecordRef {
+	h := sha3.Sum224([]byte("insolar memory chunk"))
+	return *core.NewRecordRef(core.DomainID, *core.NewRecordID(0, h[:]))
+}()
+
+// chunkRef makes reference of a new chunk, it's derived from the request
+// so validation of the request gets the same references
+func (lr *LogicRunner) chunkRef(es *ExecutionState) Ref {
+	es.Current.Chunks++
+	var n [4]byte
+	binary.BigEndian.PutUint32(n[:], es.Current.Chunks)
+
+	request := *es.Current.Request
+	hash := lr.PlatformCryptographyScheme.ReferenceHasher().Hash(append(request[:], n[:]...))
+	id := core.NewRecordID(request.Record().Pulse(), hash)
+	return *core.NewRecordRef(*request.Domain(), *id)
+}
+
+// GetChunk fetches memory of the chunk at the state
+func (lr *LogicRunner) GetChunk(ctx context.Context, chunk Ref, state core.RecordID) ([]byte, error) {
+	desc, err := lr.ArtifactManager.GetObject(ctx, chunk, &state, false)
+	if err != nil {
+		return nil, errors.Wrap(err, "couldn't get chunk")
+	}
+	return desc.Memory(), nil
+}
+
+// SaveChunk saves data as memory of the chunk of object executed in es, empty chunk is created
+// as a child of the object. In replay nothing is saved and state of chunk is empty.
+func (lr *LogicRunner) SaveChunk(ctx context.Context, es *ExecutionState, chunk Ref, data []byte) (*Ref, *core.RecordID, error) {
+	if es.objectbody == nil || es.objectbody.objDescriptor == nil {
+		return nil, nil, errors.New("chunks can be saved by activated objects only")
+	}
+
+	create := chunk.IsEmpty()
+	if create {
+		chunk = lr.chunkRef(es)
+	}
+	if es.Behaviour.Mode() == "replay" {
+		return &chunk, &core.RecordID{}, nil
+	}
+
+	am := lr.ArtifactManager
+	var desc core.ObjectDescriptor
+	var err error
+	if create {
+		parent := *es.objectbody.objDescriptor.HeadRef()
+		desc, err = am.ActivateObject(ctx, Ref{}, chunk, parent, ChunkPrototype, false, data)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "couldn't create chunk")
+		}
+	} else {
+		prev, err := am.GetObject(ctx, chunk, nil, false)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "couldn't get chunk")
+		}
+		desc, err = am.UpdateObject(ctx, Ref{}, *es.Current.Request, prev, data)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "couldn't update chunk")
+		}
+	}
+	return &chunk, desc.StateID(), nil
+}
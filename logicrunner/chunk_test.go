@@ -0,0 +1,115 @@
+/*
+ *    Copyright 2019 Insolar Technologies
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package logicrunner
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gojuno/minimock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/insolar/insolar/configuration"
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/instrumentation/inslogger"
+	"github.com/insolar/insolar/platformpolicy"
+	"github.com/insolar/insolar/testutils"
+)
+
+func TestLogicRunner_SaveChunk(t *testing.T) {
+	ctx := inslogger.TestContext(t)
+	mc := minimock.NewController(t)
+	defer mc.Finish()
+
+	am := testutils.NewArtifactManagerMock(mc)
+	lr, _ := NewLogicRunner(&configuration.LogicRunner{})
+	lr.ArtifactManager = am
+	lr.PlatformCryptographyScheme = platformpolicy.NewPlatformCryptographyScheme()
+
+	object := testutils.RandomRef()
+	request := testutils.RandomRef()
+	objDesc := testutils.NewObjectDescriptorMock(mc)
+	objDesc.HeadRefMock.Return(&object)
+
+	newState := func() core.ObjectDescriptor {
+		desc := testutils.NewObjectDescriptorMock(mc)
+		state := testutils.RandomID()
+		desc.StateIDMock.Return(&state)
+		return desc
+	}
+
+	es := &ExecutionState{
+		Behaviour:  &ValidationSaver{},
+		objectbody: &ObjectBody{objDescriptor: objDesc},
+		Current: &CurrentExecution{
+			Request:      &request,
+			LogicContext: &core.LogicCallContext{Callee: &object},
+		},
+	}
+
+	var created core.RecordRef
+	am.ActivateObjectMock.Set(func(p context.Context, domain, chunk, parent, prototype core.RecordRef, asDelegate bool, memory []byte) (core.ObjectDescriptor, error) {
+		created = chunk
+		assert.Equal(t, object, parent)
+		assert.Equal(t, ChunkPrototype, prototype)
+		assert.Equal(t, []byte{1}, memory)
+		return newState(), nil
+	})
+	chunk, state, err := lr.SaveChunk(ctx, es, core.RecordRef{}, []byte{1})
+	require.NoError(t, err)
+	assert.Equal(t, created, *chunk)
+	assert.Equal(t, request.Record().Pulse(), chunk.Record().Pulse())
+	assert.NotEqual(t, core.RecordID{}, *state)
+
+	am.GetObjectMock.Return(objDesc, nil)
+	am.UpdateObjectMock.Set(func(p context.Context, domain, req core.RecordRef, od core.ObjectDescriptor, memory []byte) (core.ObjectDescriptor, error) {
+		assert.Equal(t, request, req)
+		assert.Equal(t, []byte{2}, memory)
+		return newState(), nil
+	})
+	updated, _, err := lr.SaveChunk(ctx, es, *chunk, []byte{2})
+	require.NoError(t, err)
+	assert.Equal(t, *chunk, *updated)
+
+	// validation gets same references and states from ledger replies
+	validated := testutils.RandomID()
+	am.ActivateObjectMock.Set(func(p context.Context, domain, chunk, parent, prototype core.RecordRef, asDelegate bool, memory []byte) (core.ObjectDescriptor, error) {
+		assert.Equal(t, created, chunk)
+		desc := testutils.NewObjectDescriptorMock(mc)
+		desc.StateIDMock.Return(&validated)
+		return desc, nil
+	})
+	es.Behaviour = &ValidationChecker{}
+	es.Current.Chunks = 0
+	chunk, state, err = lr.SaveChunk(ctx, es, core.RecordRef{}, []byte{1})
+	require.NoError(t, err)
+	assert.Equal(t, created, *chunk)
+	assert.Equal(t, validated, *state)
+
+	// replay gets same references and doesn't write
+	es.Behaviour = &replayTape{}
+	es.Current.Chunks = 0
+	chunk, state, err = lr.SaveChunk(ctx, es, core.RecordRef{}, []byte{1})
+	require.NoError(t, err)
+	assert.Equal(t, created, *chunk)
+	assert.Equal(t, core.RecordID{}, *state)
+
+	es.objectbody = nil
+	_, _, err = lr.SaveChunk(ctx, es, core.RecordRef{}, []byte{1})
+	require.Error(t, err)
+}
@@ -0,0 +1,661 @@
+/*
+ *    Copyright 2019 Insolar Technologies
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+// Package contracttest allows to unit test smart contracts in-process,
+// without insgorund, compiled plugins and logic runner.
+//
+// Contract code is executed as is, calls between objects go through
+// real generated proxies and are dispatched synchronously by Harness,
+// objects live in an in-memory artifact manager. Access rules declared
+// with INSATTR_<Method>_Access are enforced the same way logic runner does,
+// rules are read from the contract's source file.
+//
+//	h := contracttest.NewHarness()
+//	h.Register(walletproxy.PrototypeReference, &wallet.Wallet{}, wallet.New)
+//	w, err := walletproxy.New(1000).AsChild(h.Root())
+//	...
+//	err = h.State(w.GetReference(), &walletState)
+package contracttest
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tylerb/gls"
+	"github.com/ugorji/go/codec"
+
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/core/message"
+	"github.com/insolar/insolar/logicrunner/goplugin/foundation"
+	"github.com/insolar/insolar/logicrunner/goplugin/goplugintestutils"
+	"github.com/insolar/insolar/logicrunner/goplugin/preprocessor"
+	"github.com/insolar/insolar/logicrunner/goplugin/proxyctx"
+	"github.com/insolar/insolar/testutils"
+)
+
+// PulseDuration is a number of seconds between two pulses of Harness
+const PulseDuration = 10
+
+type contract struct {
+	name         string
+	typ          reflect.Type
+	code         core.RecordRef
+	constructors map[string]reflect.Value
+	access       map[string][]core.AccessRule
+}
+
+// Harness runs contracts in-process and implements proxyctx.ProxyHelper for them.
+// NewHarness replaces proxyctx.Current, so tests using Harness must not run in parallel.
+type Harness struct {
+	AM *goplugintestutils.TestArtifactManager
+
+	root       core.RecordRef
+	rootMember *core.RecordRef
+	contracts  map[core.RecordRef]*contract
+	parents    map[core.RecordRef]core.RecordRef
+	children   map[core.RecordRef][]core.RecordRef
+
+	pulse core.Pulse
+	stack []*core.LogicCallContext
+
+	noWait       []func() error
+	noWaitErrors []error
+	scheduled    []scheduledCall
+	chunks       map[core.RecordID][]byte
+}
+
+type scheduledCall struct {
+	pulse   core.PulseNumber
+	current *core.LogicCallContext
+	object  core.RecordRef
+	method  string
+	args    []byte
+}
+
+// NewHarness creates a new Harness with empty ledger on the genesis pulse
+func NewHarness() *Harness {
+	h := &Harness{
+		AM:        goplugintestutils.NewTestArtifactManager(),
+		root:      testutils.RandomRef(),
+		contracts: make(map[core.RecordRef]*contract),
+		parents:   make(map[core.RecordRef]core.RecordRef),
+		children:  make(map[core.RecordRef][]core.RecordRef),
+		pulse:     *core.GenesisPulse,
+		chunks:    make(map[core.RecordID][]byte),
+	}
+	proxyctx.Current = h
+	return h
+}
+
+// Root returns reference of an object that isn't a contract and could be
+// used as a parent for objects created by test
+func (h *Harness) Root() core.RecordRef {
+	return h.root
+}
+
+// Register makes contract available by its prototype reference, `object` is
+// a pointer to contract's type and `constructors` are its constructor functions
+func (h *Harness) Register(prototype *core.RecordRef, object interface{}, constructors ...interface{}) error {
+	typ := reflect.TypeOf(object)
+	if typ == nil || typ.Kind() != reflect.Ptr || typ.Elem().Kind() != reflect.Struct {
+		return errors.Errorf("[ Register ] contract should be a pointer to struct, got %T", object)
+	}
+
+	c := &contract{
+		name:         typ.Elem().Name(),
+		typ:          typ.Elem(),
+		constructors: make(map[string]reflect.Value),
+	}
+	funcs := make([]reflect.Value, 0, len(constructors)+typ.NumMethod())
+	for _, f := range constructors {
+		fv := reflect.ValueOf(f)
+		if fv.Kind() != reflect.Func || fv.Type().NumOut() != 2 {
+			return errors.Errorf("[ Register ] constructor of %s should be a function returning two values", c.name)
+		}
+		fullName := runtime.FuncForPC(fv.Pointer()).Name()
+		c.constructors[fullName[strings.LastIndex(fullName, ".")+1:]] = fv
+		funcs = append(funcs, fv)
+	}
+	for i := 0; i < typ.NumMethod(); i++ {
+		funcs = append(funcs, typ.Method(i).Func)
+	}
+
+	abi, err := contractABI(funcs)
+	if err != nil {
+		return errors.Wrapf(err, "[ Register ] Can't get ABI of %s", c.name)
+	}
+	c.access = make(map[string][]core.AccessRule)
+	for _, m := range abi.Methods {
+		c.access[m.Name] = m.Access
+	}
+	abiJSON, err := json.Marshal(abi)
+	if err != nil {
+		return errors.Wrap(err, "[ Register ] Can't marshal ABI")
+	}
+
+	ctx := context.TODO()
+	codeID, err := h.AM.DeployCode(ctx, core.RecordRef{}, core.RecordRef{}, []byte(c.name), core.MachineTypeGoPlugin, abiJSON)
+	if err != nil {
+		return errors.Wrap(err, "[ Register ] Can't deploy code")
+	}
+	c.code.SetRecord(*codeID)
+
+	_, err = h.AM.ActivatePrototype(ctx, core.RecordRef{}, *prototype, *h.AM.GenesisRef(), c.code, nil)
+	if err != nil {
+		return errors.Wrap(err, "[ Register ] Can't activate prototype")
+	}
+
+	h.contracts[*prototype] = c
+	return nil
+}
+
+// contractABI parses source file of contract, that is found by location of
+// any of its functions, wrappers of promoted methods are skipped
+func contractABI(funcs []reflect.Value) (*preprocessor.ABI, error) {
+	for _, f := range funcs {
+		file, _ := runtime.FuncForPC(f.Pointer()).FileLine(f.Pointer())
+		if !strings.HasSuffix(file, ".go") {
+			continue
+		}
+		pf, err := preprocessor.ParseFile(file)
+		if err != nil {
+			return nil, err
+		}
+		return pf.ABI(), nil
+	}
+	return nil, errors.New("source file of contract not found")
+}
+
+// SetRootMember makes `ref` a root member for access rules, until it's set
+// methods allowed only to root member can't be called
+func (h *Harness) SetRootMember(ref core.RecordRef) {
+	h.rootMember = &ref
+}
+
+// Pulse returns current pulse
+func (h *Harness) Pulse() core.Pulse {
+	return h.pulse
+}
+
+// NextPulse advances pulse and time seen by contracts by `n` pulses,
+// calls scheduled on passed pulses are made on the way
+func (h *Harness) NextPulse(n int) core.Pulse {
+	for i := 0; i < n; i++ {
+		h.pulse.PrevPulseNumber = h.pulse.PulseNumber
+		h.pulse.PulseNumber += PulseDuration
+		h.pulse.NextPulseNumber = h.pulse.PulseNumber + PulseDuration
+		h.pulse.PulseTimestamp += PulseDuration
+		h.runScheduled()
+	}
+	return h.pulse
+}
+
+func (h *Harness) runScheduled() {
+	var rest []scheduledCall
+	for _, s := range h.scheduled {
+		if s.pulse > h.pulse.PulseNumber {
+			rest = append(rest, s)
+			continue
+		}
+		s := s
+		h.noWait = append(h.noWait, func() error {
+			_, err := h.call(s.current, s.object, s.method, s.args)
+			return err
+		})
+	}
+	h.scheduled = rest
+	h.runNoWait()
+}
+
+// State deserializes current memory of the object into `into`,
+// usually a pointer to contract's type
+func (h *Harness) State(ref core.RecordRef, into interface{}) error {
+	obj, err := h.object(ref)
+	if err != nil {
+		return err
+	}
+	return h.Deserialize(obj.Memory(), into)
+}
+
+// IsActive returns false if object was deactivated or never existed
+func (h *Harness) IsActive(ref core.RecordRef) bool {
+	_, ok := h.AM.Objects[ref]
+	return ok
+}
+
+// Children returns references of active children of the object in order of creation
+func (h *Harness) Children(ref core.RecordRef) []core.RecordRef {
+	var res []core.RecordRef
+	for _, child := range h.children[ref] {
+		if h.IsActive(child) {
+			res = append(res, child)
+		}
+	}
+	return res
+}
+
+// NoWaitErrors returns errors of calls made without waiting for result
+func (h *Harness) NoWaitErrors() []error {
+	return h.noWaitErrors
+}
+
+func (h *Harness) object(ref core.RecordRef) (core.ObjectDescriptor, error) {
+	obj, err := h.AM.GetObject(context.TODO(), ref, nil, false)
+	if err != nil {
+		return nil, errors.Wrapf(err, "object %s is not active", ref)
+	}
+	return obj, nil
+}
+
+func (h *Harness) contractOf(obj core.ObjectDescriptor) (*contract, core.RecordRef, error) {
+	proto, err := obj.Prototype()
+	if err != nil {
+		return nil, core.RecordRef{}, err
+	}
+	c, ok := h.contracts[*proto]
+	if !ok {
+		return nil, core.RecordRef{}, errors.Errorf("prototype %s is not registered", proto)
+	}
+	return c, *proto, nil
+}
+
+func (h *Harness) current() *core.LogicCallContext {
+	if len(h.stack) == 0 {
+		return nil
+	}
+	return h.stack[len(h.stack)-1]
+}
+
+// callContext makes context for a call on `callee` on behalf of `current`,
+// nil `current` means call made by test itself
+func (h *Harness) callContext(current *core.LogicCallContext, callee, prototype, code, parent core.RecordRef) *core.LogicCallContext {
+	caller := &core.RecordRef{}
+	callerPrototype := &core.RecordRef{}
+	if current != nil {
+		caller = current.Callee
+		callerPrototype = current.Prototype
+	}
+
+	request := testutils.RandomRef()
+	return &core.LogicCallContext{
+		Mode:            "execution",
+		Callee:          &callee,
+		Request:         &request,
+		Prototype:       &prototype,
+		Code:            &code,
+		CallerPrototype: callerPrototype,
+		Parent:          &parent,
+		Caller:          caller,
+		Time:            time.Unix(h.pulse.PulseTimestamp, 0),
+		Pulse:           h.pulse,
+	}
+}
+
+// run executes `f` with `callCtx` as current context of the contract, recovers
+// panics and runs postponed calls when the outermost call is finished
+func (h *Harness) run(callCtx *core.LogicCallContext, f func() error) (err error) {
+	for _, c := range h.stack {
+		if c.Callee.Equal(*callCtx.Callee) {
+			return errors.Errorf("reentrant call to object %s", callCtx.Callee)
+		}
+	}
+
+	h.stack = append(h.stack, callCtx)
+	gls.Set("callCtx", callCtx)
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Errorf("panic: %v\n%s", r, debug.Stack())
+		}
+
+		h.stack = h.stack[:len(h.stack)-1]
+		if len(h.stack) > 0 {
+			gls.Set("callCtx", h.stack[len(h.stack)-1])
+			return
+		}
+		gls.Cleanup()
+		h.runNoWait()
+	}()
+
+	return f()
+}
+
+func (h *Harness) runNoWait() {
+	for len(h.noWait) > 0 {
+		call := h.noWait[0]
+		h.noWait = h.noWait[1:]
+		if err := call(); err != nil {
+			h.noWaitErrors = append(h.noWaitErrors, err)
+		}
+	}
+}
+
+// decodeArguments deserializes arguments of function typed `ft` the same way generated wrappers do
+func (h *Harness) decodeArguments(ft reflect.Type, data []byte) ([]reflect.Value, error) {
+	if ft.NumIn() == 0 {
+		return nil, nil
+	}
+
+	args := reflect.New(reflect.ArrayOf(ft.NumIn(), interfaceType)).Elem()
+	for i := 0; i < ft.NumIn(); i++ {
+		args.Index(i).Set(reflect.New(ft.In(i)))
+	}
+	err := h.Deserialize(data, args.Addr().Interface())
+	if err != nil {
+		return nil, errors.Wrap(err, "couldn't deserialize arguments")
+	}
+
+	res := make([]reflect.Value, ft.NumIn())
+	for i := range res {
+		res[i] = args.Index(i).Elem().Elem()
+	}
+	return res, nil
+}
+
+// RouteCall executes method of the object in-process, calls without waiting
+// are postponed until the outermost call is finished
+func (h *Harness) RouteCall(ref core.RecordRef, wait bool, method string, args []byte, proxyPrototype core.RecordRef) ([]byte, error) {
+	current := h.current()
+	if !wait {
+		h.noWait = append(h.noWait, func() error {
+			_, err := h.call(current, ref, method, args)
+			return err
+		})
+		if current == nil {
+			h.runNoWait()
+		}
+		return nil, nil
+	}
+	return h.call(current, ref, method, args)
+}
+
+func (h *Harness) call(current *core.LogicCallContext, ref core.RecordRef, method string, args []byte) ([]byte, error) {
+	obj, err := h.object(ref)
+	if err != nil {
+		return nil, err
+	}
+	c, proto, err := h.contractOf(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []byte
+	callCtx := h.callContext(current, ref, proto, c.code, h.parents[ref])
+	if err := h.checkAccess(c, callCtx, method); err != nil {
+		return nil, errors.Wrapf(err, "[ RouteCall ] %s.%s", c.name, method)
+	}
+	err = h.run(callCtx, func() error {
+		self := reflect.New(c.typ)
+		err := h.Deserialize(obj.Memory(), self.Interface())
+		if err != nil {
+			return errors.Wrapf(err, "couldn't deserialize state of %s", ref)
+		}
+
+		m := self.MethodByName(method)
+		if !m.IsValid() {
+			return errors.Errorf("no method %s in contract %s", method, c.name)
+		}
+
+		in, err := h.decodeArguments(m.Type(), args)
+		if err != nil {
+			return err
+		}
+		out := m.Call(in)
+
+		var state []byte
+		err = h.Serialize(self.Interface(), &state)
+		if err != nil {
+			return err
+		}
+		// object could deactivate itself
+		if h.IsActive(ref) {
+			_, err = h.AM.UpdateObject(context.TODO(), core.RecordRef{}, *callCtx.Request, obj, state)
+			if err != nil {
+				return err
+			}
+		}
+
+		ret := make([]interface{}, len(out))
+		for i, v := range out {
+			ret[i] = v.Interface()
+			if v.Type() == errorType {
+				e, _ := ret[i].(error)
+				ret[i] = h.MakeErrorSerializable(e)
+			}
+		}
+		return h.Serialize(ret, &result)
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "[ RouteCall ] %s.%s", c.name, method)
+	}
+	return result, nil
+}
+
+// checkAccess checks caller satisfies at least one of the access rules of method
+func (h *Harness) checkAccess(c *contract, callCtx *core.LogicCallContext, method string) error {
+	rules := c.access[method]
+	if len(rules) == 0 {
+		return nil
+	}
+
+	caller := *callCtx.Caller
+	if caller.IsEmpty() {
+		return errors.Errorf("access to method %s denied: caller is unknown", method)
+	}
+
+	for _, rule := range rules {
+		switch rule {
+		case core.AccessParent:
+			if caller.Equal(*callCtx.Parent) {
+				return nil
+			}
+		case core.AccessSelf:
+			if caller.Equal(*callCtx.Callee) {
+				return nil
+			}
+		case core.AccessRootMember:
+			if h.rootMember == nil {
+				return errors.Errorf("access to method %s denied: root member isn't known yet", method)
+			}
+			if caller.Equal(*h.rootMember) {
+				return nil
+			}
+		default:
+			contract, ok := rule.Prototype()
+			if !ok {
+				continue
+			}
+			if callerContract, ok := h.contracts[*callCtx.CallerPrototype]; ok && callerContract.name == contract {
+				return nil
+			}
+		}
+	}
+	return errors.Errorf("access to method %s denied", method)
+}
+
+var (
+	errorType     = reflect.TypeOf((*error)(nil)).Elem()
+	interfaceType = reflect.TypeOf((*interface{})(nil)).Elem()
+)
+
+func (h *Harness) construct(parentRef, classRef core.RecordRef, constructorName string, argsSerialized []byte, asDelegate bool) (core.RecordRef, error) {
+	c, ok := h.contracts[classRef]
+	if !ok {
+		return core.RecordRef{}, errors.Errorf("prototype %s is not registered", classRef)
+	}
+	f, ok := c.constructors[constructorName]
+	if !ok {
+		return core.RecordRef{}, errors.Errorf("no constructor %s in contract %s", constructorName, c.name)
+	}
+
+	ctx := context.TODO()
+	reqID, err := h.AM.RegisterRequest(ctx, parentRef, &message.Parcel{Msg: &message.CallConstructor{PrototypeRef: classRef}})
+	if err != nil {
+		return core.RecordRef{}, err
+	}
+	ref := *core.NewRecordRef(core.RecordID{}, *reqID)
+
+	callCtx := h.callContext(h.current(), ref, classRef, c.code, parentRef)
+	err = h.run(callCtx, func() error {
+		in, err := h.decodeArguments(f.Type(), argsSerialized)
+		if err != nil {
+			return err
+		}
+		out := f.Call(in)
+		if !out[1].IsNil() {
+			return out[1].Interface().(error)
+		}
+		if out[0].IsNil() {
+			return errors.New("constructor returns nil")
+		}
+
+		var state []byte
+		err = h.Serialize(out[0].Interface(), &state)
+		if err != nil {
+			return err
+		}
+		_, err = h.AM.ActivateObject(ctx, core.RecordRef{}, ref, parentRef, classRef, asDelegate, state)
+		return err
+	})
+	if err != nil {
+		return core.RecordRef{}, errors.Wrapf(err, "[ %s ] %s", constructorName, c.name)
+	}
+
+	h.parents[ref] = parentRef
+	if !asDelegate {
+		h.children[parentRef] = append(h.children[parentRef], ref)
+	}
+	return ref, nil
+}
+
+// SaveAsChild runs constructor and activates a new object as child of `parentRef`
+func (h *Harness) SaveAsChild(parentRef, classRef core.RecordRef, constructorName string, argsSerialized []byte) (core.RecordRef, error) {
+	return h.construct(parentRef, classRef, constructorName, argsSerialized, false)
+}
+
+// SaveAsDelegate runs constructor and activates a new object as delegate of `intoRef`
+func (h *Harness) SaveAsDelegate(intoRef, classRef core.RecordRef, constructorName string, argsSerialized []byte) (core.RecordRef, error) {
+	if !h.IsActive(intoRef) {
+		return core.RecordRef{}, errors.Errorf("[ SaveAsDelegate ] object %s is not active", intoRef)
+	}
+	return h.construct(intoRef, classRef, constructorName, argsSerialized, true)
+}
+
+// GetObjChildrenIterator returns all active children of the object with given prototype at once
+func (h *Harness) GetObjChildrenIterator(head core.RecordRef, prototype core.RecordRef, iteratorID string) (*proxyctx.ChildrenTypedIterator, error) {
+	var buff []core.RecordRef
+	for _, child := range h.Children(head) {
+		obj, err := h.object(child)
+		if err != nil {
+			return nil, err
+		}
+		proto, err := obj.Prototype()
+		if err != nil {
+			return nil, err
+		}
+		if prototype.IsEmpty() || proto.Equal(prototype) {
+			buff = append(buff, child)
+		}
+	}
+
+	return &proxyctx.ChildrenTypedIterator{
+		Parent:         head,
+		ChildPrototype: prototype,
+		Buff:           buff,
+	}, nil
+}
+
+// GetDelegate returns delegate of the object with given prototype
+func (h *Harness) GetDelegate(object, ofType core.RecordRef) (core.RecordRef, error) {
+	ref, err := h.AM.GetDelegate(context.TODO(), object, ofType)
+	if err != nil {
+		return core.RecordRef{}, errors.Wrapf(err, "[ GetDelegate ] object %s", object)
+	}
+	return *ref, nil
+}
+
+// DeactivateObject deactivates the object
+func (h *Harness) DeactivateObject(object core.RecordRef) error {
+	obj, err := h.object(object)
+	if err != nil {
+		return err
+	}
+	_, err = h.AM.DeactivateObject(context.TODO(), core.RecordRef{}, core.RecordRef{}, obj)
+	return err
+}
+
+// ScheduleCall postpones the call until NextPulse reaches `pulse`
+func (h *Harness) ScheduleCall(object core.RecordRef, pulse core.PulseNumber, method string, args []byte) (core.RecordRef, error) {
+	if pulse <= h.pulse.PulseNumber {
+		return core.RecordRef{}, errors.Errorf("[ ScheduleCall ] pulse %d is not in future", pulse)
+	}
+	h.scheduled = append(h.scheduled, scheduledCall{
+		pulse:   pulse,
+		current: h.current(),
+		object:  object,
+		method:  method,
+		args:    args,
+	})
+	return testutils.RandomRef(), nil
+}
+
+// GetChunk returns memory of the chunk at the state
+func (h *Harness) GetChunk(chunk core.RecordRef, state core.RecordID) ([]byte, error) {
+	data, ok := h.chunks[state]
+	if !ok {
+		return nil, errors.Errorf("[ GetChunk ] state %s of chunk %s not found", state, chunk)
+	}
+	return data, nil
+}
+
+// SaveChunk keeps data as a new state of the chunk
+func (h *Harness) SaveChunk(chunk core.RecordRef, data []byte) (core.RecordRef, core.RecordID, error) {
+	if chunk.IsEmpty() {
+		chunk = testutils.RandomRef()
+	}
+	state := testutils.RandomID()
+	h.chunks[state] = data
+	return chunk, state, nil
+}
+
+// Serialize - CBOR serializer wrapper: `what` -> `to`
+func (h *Harness) Serialize(what interface{}, to *[]byte) error {
+	ch := new(codec.CborHandle)
+	return codec.NewEncoderBytes(to, ch).Encode(what)
+}
+
+// Deserialize - CBOR de-serializer wrapper: `from` -> `into`
+func (h *Harness) Deserialize(from []byte, into interface{}) error {
+	ch := new(codec.CborHandle)
+	return codec.NewDecoderBytes(from, ch).Decode(into)
+}
+
+// MakeErrorSerializable converts errors satisfying error interface to foundation.Error
+func (h *Harness) MakeErrorSerializable(e error) error {
+	if e == nil || e == (*foundation.Error)(nil) {
+		return nil
+	}
+	// typed nil of pointer type is nil error too
+	if v := reflect.ValueOf(e); v.Kind() == reflect.Ptr && v.IsNil() {
+		return nil
+	}
+	return &foundation.Error{S: e.Error()}
+}
@@ -0,0 +1,40 @@
+/*
+ *    Copyright 2019 Insolar Technologies
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package contracttest
+
+import (
+	"testing"
+
+	"github.com/insolar/insolar/logicrunner/goplugin/foundation"
+	"github.com/stretchr/testify/require"
+)
+
+type valueError struct{}
+
+func (valueError) Error() string { return "value error" }
+
+type pointerError struct{}
+
+func (*pointerError) Error() string { return "pointer error" }
+
+func TestHarness_MakeErrorSerializable(t *testing.T) {
+	h := NewHarness()
+	require.Nil(t, h.MakeErrorSerializable(nil))
+	require.Nil(t, h.MakeErrorSerializable((*pointerError)(nil)))
+	require.Equal(t, &foundation.Error{S: "pointer error"}, h.MakeErrorSerializable(&pointerError{}))
+	require.Equal(t, &foundation.Error{S: "value error"}, h.MakeErrorSerializable(valueError{}))
+}
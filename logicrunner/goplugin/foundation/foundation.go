@@ -101,6 +101,26 @@ func (bc *BaseContract) SelfDestruct() {
 	}
 }
 
+// ScheduleCall registers call of the method on the object that will be made
+// without waiting for result on `pulse`, caller of the call is the current contract.
+// Returns reference of the schedule.
+func ScheduleCall(object core.RecordRef, pulse core.PulseNumber, method string, args ...interface{}) (core.RecordRef, error) {
+	if args == nil {
+		args = []interface{}{}
+	}
+	var argsSerialized []byte
+	err := proxyctx.Current.Serialize(args, &argsSerialized)
+	if err != nil {
+		return core.RecordRef{}, err
+	}
+	return proxyctx.Current.ScheduleCall(object, pulse, method, argsSerialized)
+}
+
+// ScheduleCall registers call of own method on `pulse`, see ScheduleCall
+func (bc *BaseContract) ScheduleCall(pulse core.PulseNumber, method string, args ...interface{}) (core.RecordRef, error) {
+	return ScheduleCall(bc.GetReference(), pulse, method, args...)
+}
+
 // Error elementary string based error struct satisfying builtin error interface
 //    foundation.Error{"some err"}
 type Error struct {
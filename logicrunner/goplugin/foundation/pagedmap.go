@@ -0,0 +1,239 @@
+/*
+ *    Copyright 2019 Insolar Technologies
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package foundation
+
+import (
+	"hash/fnv"
+	"sort"
+
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/logicrunner/goplugin/proxyctx"
+)
+
+// DefaultPagedMapPages is a number of pages NewPagedMap spreads keys over by default
+const DefaultPagedMapPages = 64
+
+// PagedMap is a key/value collection for contracts with large memory. Keys are spread
+// over a fixed number of pages, every page is kept in a chunk, a child object of the
+// contract, and memory of the contract keeps pointers to pages only. Page is fetched
+// when any of its keys is touched and saved when the contract is serialized after call,
+// only if it was changed.
+//
+// PagedMap should be a pointer field of the contract created with NewPagedMap, zero PagedMap
+// has DefaultPagedMapPages pages. Chunks belong to the contract, so keys can't be set in constructor.
+type PagedMap struct {
+	pages  []pagePointer
+	loaded map[int][]pageEntry
+	dirty  map[int]bool
+}
+
+type pagePointer struct {
+	Chunk core.RecordRef
+	State core.RecordID
+	Size  int
+}
+
+type pageEntry struct {
+	Key   string
+	Value []byte
+}
+
+// NewPagedMap creates empty PagedMap with the number of pages, DefaultPagedMapPages if it's not positive
+func NewPagedMap(pages int) *PagedMap {
+	if pages <= 0 {
+		pages = DefaultPagedMapPages
+	}
+	return &PagedMap{
+		pages: make([]pagePointer, pages),
+	}
+}
+
+// Len returns number of keys, pages aren't fetched
+func (m *PagedMap) Len() int {
+	res := 0
+	for _, p := range m.pages {
+		res += p.Size
+	}
+	return res
+}
+
+// Pages returns number of pages
+func (m *PagedMap) Pages() int {
+	return len(m.pages)
+}
+
+// Get deserializes value of the key into value, returns false if there is no key
+func (m *PagedMap) Get(key string, value interface{}) (bool, error) {
+	entries, err := m.page(m.pageOf(key))
+	if err != nil {
+		return false, err
+	}
+	i, found := searchEntry(entries, key)
+	if !found {
+		return false, nil
+	}
+	return true, proxyctx.Current.Deserialize(entries[i].Value, value)
+}
+
+// Has checks if there is the key
+func (m *PagedMap) Has(key string) (bool, error) {
+	entries, err := m.page(m.pageOf(key))
+	if err != nil {
+		return false, err
+	}
+	_, found := searchEntry(entries, key)
+	return found, nil
+}
+
+// Set serializes value as value of the key
+func (m *PagedMap) Set(key string, value interface{}) error {
+	var data []byte
+	err := proxyctx.Current.Serialize(value, &data)
+	if err != nil {
+		return err
+	}
+
+	n := m.pageOf(key)
+	entries, err := m.page(n)
+	if err != nil {
+		return err
+	}
+	i, found := searchEntry(entries, key)
+	if found {
+		entries[i].Value = data
+	} else {
+		entries = append(entries, pageEntry{})
+		copy(entries[i+1:], entries[i:])
+		entries[i] = pageEntry{Key: key, Value: data}
+		m.pages[n].Size++
+	}
+	m.update(n, entries)
+	return nil
+}
+
+// Delete removes the key
+func (m *PagedMap) Delete(key string) error {
+	n := m.pageOf(key)
+	entries, err := m.page(n)
+	if err != nil {
+		return err
+	}
+	i, found := searchEntry(entries, key)
+	if !found {
+		return nil
+	}
+	entries = append(entries[:i], entries[i+1:]...)
+	m.pages[n].Size--
+	m.update(n, entries)
+	return nil
+}
+
+// Keys returns sorted keys of the page, so keys of the map can be listed page by page
+func (m *PagedMap) Keys(page int) ([]string, error) {
+	if page < 0 || page >= len(m.pages) {
+		return nil, &Error{S: "page is out of range"}
+	}
+	entries, err := m.page(page)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, len(entries))
+	for i, e := range entries {
+		keys[i] = e.Key
+	}
+	return keys, nil
+}
+
+// MarshalBinary saves changed pages and serializes pointers to pages,
+// it's called when the contract is serialized
+func (m *PagedMap) MarshalBinary() ([]byte, error) {
+	for n := range m.pages {
+		if !m.dirty[n] {
+			continue
+		}
+		var data []byte
+		err := proxyctx.Current.Serialize(m.loaded[n], &data)
+		if err != nil {
+			return nil, err
+		}
+		chunk, state, err := proxyctx.Current.SaveChunk(m.pages[n].Chunk, data)
+		if err != nil {
+			return nil, err
+		}
+		m.pages[n].Chunk = chunk
+		m.pages[n].State = state
+	}
+	m.dirty = nil
+
+	var res []byte
+	err := proxyctx.Current.Serialize(m.pages, &res)
+	return res, err
+}
+
+// UnmarshalBinary deserializes pointers to pages, pages are fetched when touched
+func (m *PagedMap) UnmarshalBinary(data []byte) error {
+	m.loaded = nil
+	m.dirty = nil
+	return proxyctx.Current.Deserialize(data, &m.pages)
+}
+
+func (m *PagedMap) pageOf(key string) int {
+	if len(m.pages) == 0 {
+		m.pages = make([]pagePointer, DefaultPagedMapPages)
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return int(h.Sum32() % uint32(len(m.pages)))
+}
+
+func (m *PagedMap) page(n int) ([]pageEntry, error) {
+	if entries, ok := m.loaded[n]; ok {
+		return entries, nil
+	}
+
+	var entries []pageEntry
+	if p := m.pages[n]; !p.Chunk.IsEmpty() {
+		data, err := proxyctx.Current.GetChunk(p.Chunk, p.State)
+		if err != nil {
+			return nil, err
+		}
+		err = proxyctx.Current.Deserialize(data, &entries)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if m.loaded == nil {
+		m.loaded = make(map[int][]pageEntry)
+	}
+	m.loaded[n] = entries
+	return entries, nil
+}
+
+func (m *PagedMap) update(n int, entries []pageEntry) {
+	m.loaded[n] = entries
+	if m.dirty == nil {
+		m.dirty = make(map[int]bool)
+	}
+	m.dirty[n] = true
+}
+
+// searchEntry finds position of the key in entries sorted by keys
+func searchEntry(entries []pageEntry, key string) (int, bool) {
+	i := sort.Search(len(entries), func(i int) bool { return entries[i].Key >= key })
+	return i, i < len(entries) && entries[i].Key == key
+}
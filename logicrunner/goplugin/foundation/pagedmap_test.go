@@ -0,0 +1,120 @@
+/*
+ *    Copyright 2019 Insolar Technologies
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package foundation
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ugorji/go/codec"
+
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/logicrunner/goplugin/proxyctx"
+	"github.com/insolar/insolar/testutils"
+)
+
+// chunkHelper keeps chunks in memory and counts fetches and saves
+type chunkHelper struct {
+	proxyctx.ProxyHelper
+	chunks  map[core.RecordID][]byte
+	fetched int
+	saved   int
+}
+
+func (h *chunkHelper) GetChunk(chunk core.RecordRef, state core.RecordID) ([]byte, error) {
+	h.fetched++
+	return h.chunks[state], nil
+}
+
+func (h *chunkHelper) SaveChunk(chunk core.RecordRef, data []byte) (core.RecordRef, core.RecordID, error) {
+	h.saved++
+	if chunk.IsEmpty() {
+		chunk = testutils.RandomRef()
+	}
+	state := testutils.RandomID()
+	h.chunks[state] = data
+	return chunk, state, nil
+}
+
+func (h *chunkHelper) Serialize(what interface{}, to *[]byte) error {
+	return codec.NewEncoderBytes(to, new(codec.CborHandle)).Encode(what)
+}
+
+func (h *chunkHelper) Deserialize(from []byte, into interface{}) error {
+	return codec.NewDecoderBytes(from, new(codec.CborHandle)).Decode(into)
+}
+
+type pagedContract struct {
+	Name  string
+	Items *PagedMap
+}
+
+func TestPagedMap(t *testing.T) {
+	helper := &chunkHelper{chunks: make(map[core.RecordID][]byte)}
+	proxyctx.Current = helper
+
+	contract := pagedContract{Name: "test", Items: NewPagedMap(8)}
+	for i := 0; i < 100; i++ {
+		require.NoError(t, contract.Items.Set(fmt.Sprintf("key%d", i), i))
+	}
+	require.NoError(t, contract.Items.Set("key1", 1000))
+	require.NoError(t, contract.Items.Delete("key2"))
+	require.NoError(t, contract.Items.Delete("missing"))
+	assert.Equal(t, 99, contract.Items.Len())
+
+	var memory []byte
+	require.NoError(t, helper.Serialize(contract, &memory))
+	assert.Equal(t, 8, helper.saved)
+	assert.Equal(t, 0, helper.fetched)
+
+	// pages are fetched when touched only
+	restored := pagedContract{}
+	require.NoError(t, helper.Deserialize(memory, &restored))
+	assert.Equal(t, "test", restored.Name)
+	assert.Equal(t, 99, restored.Items.Len())
+	assert.Equal(t, 8, restored.Items.Pages())
+
+	var value int
+	found, err := restored.Items.Get("key1", &value)
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, 1000, value)
+	found, err = restored.Items.Get("key2", &value)
+	require.NoError(t, err)
+	assert.False(t, found)
+	has, err := restored.Items.Has("key50")
+	require.NoError(t, err)
+	assert.True(t, has)
+	assert.True(t, helper.fetched <= 3)
+
+	// only changed page is saved
+	require.NoError(t, restored.Items.Set("key3", 3000))
+	require.NoError(t, helper.Serialize(restored, &memory))
+	assert.Equal(t, 9, helper.saved)
+
+	var keys []string
+	for page := 0; page < restored.Items.Pages(); page++ {
+		pageKeys, err := restored.Items.Keys(page)
+		require.NoError(t, err)
+		keys = append(keys, pageKeys...)
+	}
+	assert.Len(t, keys, 99)
+	_, err = restored.Items.Keys(8)
+	assert.Error(t, err)
+}
@@ -17,9 +17,9 @@
 package ginsider
 
 import (
+	"bytes"
 	"context"
 	"fmt"
-	"io/ioutil"
 	"net/rpc"
 	"os"
 	"path/filepath"
@@ -60,6 +60,8 @@ type GoInsider struct {
 
 	plugins      map[core.RecordRef]*pluginRec
 	pluginsMutex sync.Mutex
+
+	manifest *Manifest
 }
 
 // NewGoInsider creates a new GoInsider instance validating arguments
@@ -67,10 +69,27 @@ func NewGoInsider(path, network, address string) *GoInsider {
 	//TODO: check that path exist, it's a directory and writable
 	res := GoInsider{dir: path, upstreamProtocol: network, upstreamAddress: address}
 	res.plugins = make(map[core.RecordRef]*pluginRec)
+
+	manifest, err := LoadManifest(path)
+	if err != nil {
+		log.Errorf("Couldn't load manifest of plugins, all plugins will be fetched again: %s", err)
+		manifest = NewManifest(path)
+	}
+	res.manifest = manifest
+
 	proxyctx.Current = &res
 	return &res
 }
 
+// CollectGarbage removes plugin files that weren't verified and leftovers of unfinished writes
+func (gi *GoInsider) CollectGarbage(ctx context.Context) error {
+	removed, err := gi.manifest.CollectGarbage()
+	for _, name := range removed {
+		inslogger.FromContext(ctx).Infof("Removed orphaned plugin file %q", name)
+	}
+	return err
+}
+
 // RPC struct with methods representing RPC interface of this code runner
 type RPC struct {
 	GI *GoInsider
@@ -204,27 +223,68 @@ func (gi *GoInsider) Upstream() (*rpc.Client, error) {
 }
 
 // ObtainCode returns path on the file system to the plugin, fetches it from a provider
-// if it's not in the storage
+// if it's not in the storage. Plugin file is checked against hash of code record
+// from ledger, manifest only tells which files were verified before
 func (gi *GoInsider) ObtainCode(ctx context.Context, ref core.RecordRef) (string, error) {
 	path := filepath.Join(gi.dir, ref.String())
-	_, err := os.Stat(path)
 
-	if err == nil {
-		return path, nil
-	} else if !os.IsNotExist(err) {
-		return "", errors.Wrap(err, "file !notexists()")
+	if verified, ok := gi.manifest.Hash(ref); ok {
+		res, err := gi.getCode(ctx, ref, true)
+		if err != nil {
+			return "", err
+		}
+		if bytes.Equal(verified, res.Hash) {
+			err := verifyFile(path, res.Hash)
+			if err == nil {
+				return path, nil
+			}
+			if err == ErrCodeHashMismatch {
+				return "", errors.Errorf("[ ObtainCode ] plugin file %q of code %s is modified, refusing to load it", path, ref)
+			}
+			if !os.IsNotExist(err) {
+				return "", errors.Wrap(err, "[ ObtainCode ] couldn't verify plugin file")
+			}
+		} else {
+			inslogger.FromContext(ctx).Warnf("manifest has wrong hash of code %s, fetching it again", ref)
+		}
 	}
 
-	client, err := gi.Upstream()
+	res, err := gi.getCode(ctx, ref, false)
 	if err != nil {
 		return "", err
 	}
 
+	err = verifyCode(res.Code, res.Hash)
+	if err != nil {
+		return "", errors.Wrapf(err, "[ ObtainCode ] code %s is rejected", ref)
+	}
+
+	err = writeFileAtomic(gi.dir, ref.String(), res.Code)
+	if err != nil {
+		return "", errors.Wrap(err, "[ ObtainCode ] on writing file down")
+	}
+
+	err = gi.manifest.Add(ref, res.Hash)
+	if err != nil {
+		return "", errors.Wrap(err, "[ ObtainCode ] on updating manifest")
+	}
+
+	return path, nil
+}
+
+// getCode fetches code and its hash from code record, only hash is fetched if hashOnly is set
+func (gi *GoInsider) getCode(ctx context.Context, ref core.RecordRef, hashOnly bool) (*rpctypes.UpGetCodeResp, error) {
+	client, err := gi.Upstream()
+	if err != nil {
+		return nil, err
+	}
+
 	inslogger.FromContext(ctx).Debugf("obtaining code %q", ref)
 	req := rpctypes.UpGetCodeReq{
 		UpBaseReq: MakeUpBaseReq(),
 		Code:      ref,
 		MType:     core.MachineTypeGoPlugin,
+		HashOnly:  hashOnly,
 	}
 	res := rpctypes.UpGetCodeResp{}
 	err = client.Call("RPC.GetCode", req, &res)
@@ -233,15 +293,9 @@ func (gi *GoInsider) ObtainCode(ctx context.Context, ref core.RecordRef) (string
 			log.Error("Insgorund can't connect to Insolard")
 			os.Exit(0)
 		}
-		return "", errors.Wrap(err, "[ ObtainCode ] on calling main API")
-	}
-
-	err = ioutil.WriteFile(path, res.Code, 0666)
-	if err != nil {
-		return "", errors.Wrap(err, "[ ObtainCode ] on writing file down")
+		return nil, errors.Wrap(err, "[ ObtainCode ] on calling main API")
 	}
-
-	return path, nil
+	return &res, nil
 }
 
 // Plugin loads Go plugin by reference and returns `*plugin.Plugin`
@@ -468,6 +522,86 @@ func (gi *GoInsider) DeactivateObject(object core.RecordRef) error {
 	return nil
 }
 
+// ScheduleCall ...
+func (gi *GoInsider) ScheduleCall(object core.RecordRef, pulse core.PulseNumber, method string, args []byte) (core.RecordRef, error) {
+	client, err := gi.Upstream()
+	if err != nil {
+		return core.RecordRef{}, err
+	}
+
+	req := rpctypes.UpScheduleCallReq{
+		UpBaseReq: MakeUpBaseReq(),
+		Object:    object,
+		Pulse:     pulse,
+		Method:    method,
+		Arguments: args,
+	}
+
+	res := rpctypes.UpScheduleCallResp{}
+	err = client.Call("RPC.ScheduleCall", req, &res)
+	if err != nil {
+		if err == rpc.ErrShutdown {
+			log.Error("Insgorund can't connect to Insolard")
+			os.Exit(0)
+		}
+		return core.RecordRef{}, errors.Wrap(err, "[ ScheduleCall ] on calling main API")
+	}
+
+	return res.Schedule, nil
+}
+
+// GetChunk fetches memory of the chunk at the given state
+func (gi *GoInsider) GetChunk(chunk core.RecordRef, state core.RecordID) ([]byte, error) {
+	client, err := gi.Upstream()
+	if err != nil {
+		return nil, err
+	}
+
+	req := rpctypes.UpGetChunkReq{
+		UpBaseReq: MakeUpBaseReq(),
+		Chunk:     chunk,
+		State:     state,
+	}
+
+	res := rpctypes.UpGetChunkResp{}
+	err = client.Call("RPC.GetChunk", req, &res)
+	if err != nil {
+		if err == rpc.ErrShutdown {
+			log.Error("Insgorund can't connect to Insolard")
+			os.Exit(0)
+		}
+		return nil, errors.Wrap(err, "[ GetChunk ] on calling main API")
+	}
+
+	return res.Data, nil
+}
+
+// SaveChunk saves data as new memory of the chunk, empty chunk is created as child of current object
+func (gi *GoInsider) SaveChunk(chunk core.RecordRef, data []byte) (core.RecordRef, core.RecordID, error) {
+	client, err := gi.Upstream()
+	if err != nil {
+		return core.RecordRef{}, core.RecordID{}, err
+	}
+
+	req := rpctypes.UpSaveChunkReq{
+		UpBaseReq: MakeUpBaseReq(),
+		Chunk:     chunk,
+		Data:      data,
+	}
+
+	res := rpctypes.UpSaveChunkResp{}
+	err = client.Call("RPC.SaveChunk", req, &res)
+	if err != nil {
+		if err == rpc.ErrShutdown {
+			log.Error("Insgorund can't connect to Insolard")
+			os.Exit(0)
+		}
+		return core.RecordRef{}, core.RecordID{}, errors.Wrap(err, "[ SaveChunk ] on calling main API")
+	}
+
+	return res.Chunk, res.State, nil
+}
+
 // Serialize - CBOR serializer wrapper: `what` -> `to`
 func (gi *GoInsider) Serialize(what interface{}, to *[]byte) error {
 	ch := new(codec.CborHandle)
@@ -0,0 +1,190 @@
+/*
+ *    Copyright 2019 Insolar Technologies
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package ginsider
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/platformpolicy"
+)
+
+const (
+	// manifestFile is a name of file in plugins directory with hashes of verified plugins
+	manifestFile = "manifest.json"
+	// tmpFilePrefix is a prefix of files that are being written
+	tmpFilePrefix = ".tmp-"
+	// pluginFileMode restricts access to plugins by owner of the process
+	pluginFileMode = 0600
+)
+
+// ErrCodeHashMismatch is returned when code doesn't match hash from code record
+var ErrCodeHashMismatch = errors.New("code doesn't match hash of code record")
+
+// Manifest keeps hashes of plugins that were verified against code records,
+// plugin file is loaded only if its content matches the hash from manifest
+type Manifest struct {
+	mutex  sync.Mutex
+	dir    string
+	hashes map[string]string
+}
+
+// NewManifest creates empty manifest for plugins directory
+func NewManifest(dir string) *Manifest {
+	return &Manifest{
+		dir:    dir,
+		hashes: make(map[string]string),
+	}
+}
+
+// LoadManifest reads manifest from plugins directory, missing manifest is considered empty
+func LoadManifest(dir string) (*Manifest, error) {
+	m := NewManifest(dir)
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, manifestFile))
+	if os.IsNotExist(err) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "couldn't read manifest")
+	}
+	if err := json.Unmarshal(data, &m.hashes); err != nil {
+		return nil, errors.Wrap(err, "couldn't parse manifest")
+	}
+	return m, nil
+}
+
+// Hash returns verified hash of the plugin
+func (m *Manifest) Hash(ref core.RecordRef) ([]byte, bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	h, ok := m.hashes[ref.String()]
+	if !ok {
+		return nil, false
+	}
+	hash, err := hex.DecodeString(h)
+	if err != nil {
+		return nil, false
+	}
+	return hash, true
+}
+
+// Add stores hash of verified plugin and saves manifest
+func (m *Manifest) Add(ref core.RecordRef, hash []byte) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.hashes[ref.String()] = hex.EncodeToString(hash)
+	data, err := json.MarshalIndent(m.hashes, "", "    ")
+	if err != nil {
+		return errors.Wrap(err, "couldn't marshal manifest")
+	}
+	return writeFileAtomic(m.dir, manifestFile, data)
+}
+
+// CollectGarbage removes plugin files that are not in manifest and unfinished writes
+func (m *Manifest) CollectGarbage() ([]string, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	files, err := ioutil.ReadDir(m.dir)
+	if err != nil {
+		return nil, errors.Wrap(err, "couldn't read plugins directory")
+	}
+
+	var removed []string
+	for _, f := range files {
+		name := f.Name()
+		if f.IsDir() || name == manifestFile {
+			continue
+		}
+		if !strings.HasPrefix(name, tmpFilePrefix) {
+			if _, err := core.NewRefFromBase58(name); err != nil {
+				// not a plugin file
+				continue
+			}
+			if _, ok := m.hashes[name]; ok {
+				continue
+			}
+		}
+		if err := os.Remove(filepath.Join(m.dir, name)); err != nil {
+			return removed, errors.Wrapf(err, "couldn't remove orphaned file %s", name)
+		}
+		removed = append(removed, name)
+	}
+	return removed, nil
+}
+
+// codeHash calculates hash of code the same way ledger does for code records,
+// it's a hash part of id of the code blob
+func codeHash(code []byte) []byte {
+	hash := platformpolicy.NewPlatformCryptographyScheme().IntegrityHasher().Hash(code)
+	return core.NewRecordID(0, hash).Hash()
+}
+
+// verifyCode checks code against expected hash
+func verifyCode(code []byte, hash []byte) error {
+	if len(hash) == 0 || !bytes.Equal(codeHash(code), hash) {
+		return ErrCodeHashMismatch
+	}
+	return nil
+}
+
+// verifyFile checks content of file against expected hash
+func verifyFile(path string, hash []byte) error {
+	code, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return verifyCode(code, hash)
+}
+
+// writeFileAtomic writes data into temporary file and renames it,
+// so readers never see partially written file
+func writeFileAtomic(dir string, name string, data []byte) error {
+	tmp, err := ioutil.TempFile(dir, tmpFilePrefix+name)
+	if err != nil {
+		return errors.Wrap(err, "couldn't create temporary file")
+	}
+	defer os.Remove(tmp.Name()) // nolint: errcheck
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close() // nolint: errcheck
+		return errors.Wrap(err, "couldn't write temporary file")
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close() // nolint: errcheck
+		return errors.Wrap(err, "couldn't sync temporary file")
+	}
+	if err := tmp.Close(); err != nil {
+		return errors.Wrap(err, "couldn't close temporary file")
+	}
+	if err := os.Chmod(tmp.Name(), pluginFileMode); err != nil {
+		return errors.Wrap(err, "couldn't set permissions")
+	}
+	return os.Rename(tmp.Name(), filepath.Join(dir, name))
+}
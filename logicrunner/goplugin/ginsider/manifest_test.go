@@ -0,0 +1,151 @@
+/*
+ *    Copyright 2019 Insolar Technologies
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package ginsider
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"net/rpc"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/ledger/storage/record"
+	"github.com/insolar/insolar/logicrunner/goplugin/rpctypes"
+	"github.com/insolar/insolar/platformpolicy"
+	"github.com/insolar/insolar/testutils"
+	"github.com/stretchr/testify/require"
+	"github.com/tylerb/gls"
+)
+
+func TestManifest(t *testing.T) {
+	dir, err := ioutil.TempDir("", "contractcache-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint: errcheck
+
+	code := []byte("plugin code")
+	verified := testutils.RandomRef()
+	orphaned := testutils.RandomRef()
+
+	m, err := LoadManifest(dir)
+	require.NoError(t, err)
+	_, ok := m.Hash(verified)
+	require.False(t, ok)
+
+	require.NoError(t, writeFileAtomic(dir, verified.String(), code))
+	require.NoError(t, m.Add(verified, codeHash(code)))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, orphaned.String()), code, 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, tmpFilePrefix+"unfinished"), code, 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "main.so"), code, 0644))
+
+	info, err := os.Stat(filepath.Join(dir, verified.String()))
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(pluginFileMode), info.Mode().Perm())
+
+	// manifest survives restart
+	m, err = LoadManifest(dir)
+	require.NoError(t, err)
+	hash, ok := m.Hash(verified)
+	require.True(t, ok)
+	require.NoError(t, verifyFile(filepath.Join(dir, verified.String()), hash))
+
+	removed, err := m.CollectGarbage()
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{orphaned.String(), tmpFilePrefix + "unfinished"}, removed)
+	_, err = os.Stat(filepath.Join(dir, "main.so"))
+	require.NoError(t, err)
+
+	// tampered file is rejected
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, verified.String()), []byte("evil code"), 0600))
+	require.Equal(t, ErrCodeHashMismatch, verifyFile(filepath.Join(dir, verified.String()), hash))
+	require.Equal(t, ErrCodeHashMismatch, verifyCode(code, nil))
+}
+
+func TestCodeHash(t *testing.T) {
+	code := []byte("plugin code")
+	id := record.CalculateIDForBlob(platformpolicy.NewPlatformCryptographyScheme(), core.FirstPulseNumber, code)
+	require.Equal(t, id.Hash(), codeHash(code))
+	require.NoError(t, verifyCode(code, id.Hash()))
+}
+
+// upstreamMock serves code from ledger to GoInsider
+type upstreamMock struct {
+	code map[core.RecordRef][]byte
+}
+
+func (u *upstreamMock) GetCode(req rpctypes.UpGetCodeReq, reply *rpctypes.UpGetCodeResp) error {
+	code := u.code[req.Code]
+	reply.Hash = codeHash(code)
+	if !req.HashOnly {
+		reply.Code = code
+	}
+	return nil
+}
+
+func newUpstream(t *testing.T, gi *GoInsider, upstream *upstreamMock) {
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("RPC", upstream))
+	client, conn := net.Pipe()
+	go server.ServeConn(conn)
+	gi.UpstreamClient = rpc.NewClient(client)
+}
+
+func TestGoInsider_ObtainCode(t *testing.T) {
+	ctx := context.Background()
+	dir, err := ioutil.TempDir("", "contractcache-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint: errcheck
+
+	ref := testutils.RandomRef()
+	code := []byte("plugin code")
+	upstream := &upstreamMock{code: map[core.RecordRef][]byte{ref: code}}
+	gls.Set("callCtx", &core.LogicCallContext{Callee: &ref, Prototype: &ref, Request: &ref})
+	defer gls.Cleanup()
+
+	gi := NewGoInsider(dir, "unix", "")
+	newUpstream(t, gi, upstream)
+	path, err := gi.ObtainCode(ctx, ref)
+	require.NoError(t, err)
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, code, data)
+
+	// file from previous run is checked against ledger
+	gi = NewGoInsider(dir, "unix", "")
+	newUpstream(t, gi, upstream)
+	_, err = gi.ObtainCode(ctx, ref)
+	require.NoError(t, err)
+
+	// manifest rewritten together with the file doesn't let modified file in
+	evil := []byte("evil code")
+	require.NoError(t, writeFileAtomic(dir, ref.String(), evil))
+	require.NoError(t, gi.manifest.Add(ref, codeHash(evil)))
+	gi = NewGoInsider(dir, "unix", "")
+	newUpstream(t, gi, upstream)
+	path, err = gi.ObtainCode(ctx, ref)
+	require.NoError(t, err)
+	data, err = ioutil.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, code, data)
+
+	// file modified after verification is refused
+	require.NoError(t, ioutil.WriteFile(path, evil, 0600))
+	_, err = gi.ObtainCode(ctx, ref)
+	require.Error(t, err)
+}
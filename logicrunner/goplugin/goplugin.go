@@ -25,12 +25,14 @@ import (
 	"time"
 
 	"go.opencensus.io/stats"
+	"go.opencensus.io/tag"
 
 	"github.com/pkg/errors"
 
 	"github.com/insolar/insolar/configuration"
 	"github.com/insolar/insolar/core"
 	"github.com/insolar/insolar/instrumentation/inslogger"
+	"github.com/insolar/insolar/instrumentation/insmetrics"
 	"github.com/insolar/insolar/logicrunner/goplugin/rpctypes"
 )
 
@@ -57,6 +59,9 @@ type GoPlugin struct {
 
 	clientMutex sync.Mutex
 	client      *rpc.Client
+
+	// pool of supervised insgorund processes, nil if node uses external insgorund
+	pool *workerPool
 }
 
 // NewGoPlugin returns a new started GoPlugin
@@ -67,11 +72,23 @@ func NewGoPlugin(conf *configuration.LogicRunner, eb core.MessageBus, am core.Ar
 		ArtifactManager: am,
 	}
 
+	if conf.GoPlugin.RunnerPath != "" {
+		pool, err := newWorkerPool(conf)
+		if err != nil {
+			return nil, errors.Wrap(err, "couldn't create pool of insgorund workers")
+		}
+		pool.Start(context.Background())
+		gp.pool = pool
+	}
+
 	return &gp, nil
 }
 
 // Stop stops runner(s) and RPC service
 func (gp *GoPlugin) Stop() error {
+	if gp.pool != nil {
+		gp.pool.Stop()
+	}
 	return nil
 }
 
@@ -134,6 +151,19 @@ func (gp *GoPlugin) callClientWithReconnect(ctx context.Context, method string,
 	return err
 }
 
+// call sends request to insgorund serving the callee
+func (gp *GoPlugin) call(ctx context.Context, callContext *core.LogicCallContext, method string, req interface{}, res interface{}) error {
+	if gp.pool == nil {
+		return gp.callClientWithReconnect(ctx, method, req, res)
+	}
+
+	var callee *core.RecordRef
+	if callContext != nil {
+		callee = callContext.Callee
+	}
+	return gp.pool.Call(ctx, callee, method, req, res)
+}
+
 type CallMethodResult struct {
 	Response rpctypes.DownCallMethodResp
 	Error    error
@@ -142,7 +172,7 @@ type CallMethodResult struct {
 func (gp *GoPlugin) CallMethodRPC(ctx context.Context, req rpctypes.DownCallMethodReq, res rpctypes.DownCallMethodResp, resultChan chan CallMethodResult) {
 	inslogger.FromContext(ctx).Debug("GoPlugin.CallMethodRPC starts ...")
 	method := "RPC.CallMethod"
-	callClientError := gp.callClientWithReconnect(ctx, method, req, &res)
+	callClientError := gp.call(ctx, req.Context, method, req, &res)
 	resultChan <- CallMethodResult{Response: res, Error: callClientError}
 }
 
@@ -155,6 +185,8 @@ func (gp *GoPlugin) CallMethod(
 	[]byte, core.Arguments, error,
 ) {
 	inslogger.FromContext(ctx).Debug("GoPlugin.CallMethod starts")
+	ctx = withContractTags(ctx, callContext, method)
+	stats.Record(ctx, statGopluginContractArgumentsSize.M(int64(len(args))))
 	start := time.Now()
 	defer func() {
 		stats.Record(ctx, statGopluginContractMethodTime.M(
@@ -179,6 +211,11 @@ func (gp *GoPlugin) CallMethod(
 		if callResult.Error != nil {
 			return nil, nil, errors.Wrap(callResult.Error, "problem with API call")
 		}
+		stats.Record(
+			ctx,
+			statGopluginContractResultSize.M(int64(len(callResult.Response.Ret))),
+			statGopluginContractStateSize.M(int64(len(callResult.Response.Data))),
+		)
 		return callResult.Response.Data, callResult.Response.Ret, nil
 	case <-time.After(timeout):
 		return nil, nil, errors.New("logicrunner execution timeout")
@@ -192,7 +229,7 @@ type CallConstructorResult struct {
 
 func (gp *GoPlugin) CallConstructorRPC(ctx context.Context, req rpctypes.DownCallConstructorReq, res rpctypes.DownCallConstructorResp, resultChan chan CallConstructorResult) {
 	method := "RPC.CallConstructor"
-	callClientError := gp.callClientWithReconnect(ctx, method, req, &res)
+	callClientError := gp.call(ctx, req.Context, method, req, &res)
 	resultChan <- CallConstructorResult{Response: res, Error: callClientError}
 }
 
@@ -203,6 +240,14 @@ func (gp *GoPlugin) CallConstructor(
 ) (
 	[]byte, error,
 ) {
+	ctx = withContractTags(ctx, callContext, name)
+	stats.Record(ctx, statGopluginContractArgumentsSize.M(int64(len(args))))
+	start := time.Now()
+	defer func() {
+		stats.Record(ctx, statGopluginContractConstructorTime.M(
+			float64(time.Since(start).Nanoseconds())/1e6,
+		))
+	}()
 
 	res := rpctypes.DownCallConstructorResp{}
 	req := rpctypes.DownCallConstructorReq{
@@ -220,8 +265,22 @@ func (gp *GoPlugin) CallConstructor(
 		if callResult.Error != nil {
 			return nil, errors.Wrap(callResult.Error, "problem with API call")
 		}
+		stats.Record(ctx, statGopluginContractStateSize.M(int64(len(callResult.Response.Ret))))
 		return callResult.Response.Ret, nil
 	case <-time.After(timeout):
 		return nil, errors.New("logicrunner execution timeout")
 	}
 }
+
+// withContractTags adds prototype and method of the call to metrics tags
+func withContractTags(ctx context.Context, callContext *core.LogicCallContext, method string) context.Context {
+	prototype := ""
+	if callContext != nil && callContext.Prototype != nil {
+		prototype = callContext.Prototype.String()
+	}
+	return insmetrics.ChangeTags(
+		ctx,
+		tag.Insert(tagPrototype, prototype),
+		tag.Insert(tagMethodName, method),
+	)
+}
@@ -29,6 +29,7 @@ import (
 	"github.com/insolar/insolar/core"
 	"github.com/insolar/insolar/core/message"
 	"github.com/insolar/insolar/log"
+	"github.com/insolar/insolar/platformpolicy"
 	"github.com/insolar/insolar/testutils"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
@@ -60,6 +61,7 @@ func WriteFile(dir string, name string, text string) error {
 type TestCodeDescriptor struct {
 	ARef         core.RecordRef
 	ACode        []byte
+	AABI         []byte
 	AMachineType core.MachineType
 }
 
@@ -78,6 +80,17 @@ func (t *TestCodeDescriptor) Code() ([]byte, error) {
 	return t.ACode, nil
 }
 
+// ABI implementation for tests
+func (t *TestCodeDescriptor) ABI() ([]byte, error) {
+	return t.AABI, nil
+}
+
+// Hash implementation for tests
+func (t *TestCodeDescriptor) Hash() []byte {
+	hash := platformpolicy.NewPlatformCryptographyScheme().IntegrityHasher().Hash(t.ACode)
+	return core.NewRecordID(0, hash).Hash()
+}
+
 // TestObjectDescriptor implementation for tests
 type TestObjectDescriptor struct {
 	AM                *TestArtifactManager
@@ -160,6 +173,18 @@ func (t *TestArtifactManager) HasPendingRequests(ctx context.Context, object cor
 	panic("implement me")
 }
 
+func (t *TestArtifactManager) GetIdempotentRequest(ctx context.Context, object core.RecordRef, key string) (*core.IdempotentRequest, error) {
+	panic("implement me")
+}
+
+func (t *TestArtifactManager) GetRequestResult(ctx context.Context, object, request core.RecordRef) (*core.IdempotentRequest, error) {
+	panic("implement me")
+}
+
+func (t *TestArtifactManager) RegisterSeed(ctx context.Context, object core.RecordRef, seed []byte, expires core.PulseNumber) error {
+	panic("implement me")
+}
+
 // State implementation for tests
 func (t *TestArtifactManager) State() ([]byte, error) {
 	panic("implement me")
@@ -225,12 +250,13 @@ func (t *TestArtifactManager) DeclareType(ctx context.Context, domain core.Recor
 }
 
 // DeployCode implementation for tests
-func (t *TestArtifactManager) DeployCode(ctx context.Context, domain core.RecordRef, request core.RecordRef, code []byte, mt core.MachineType) (*core.RecordID, error) {
+func (t *TestArtifactManager) DeployCode(ctx context.Context, domain core.RecordRef, request core.RecordRef, code []byte, mt core.MachineType, abi []byte) (*core.RecordID, error) {
 	ref := testutils.RandomRef()
 
 	t.Codes[ref] = &TestCodeDescriptor{
 		ARef:         ref,
 		ACode:        code,
+		AABI:         abi,
 		AMachineType: core.MachineTypeGoPlugin,
 	}
 	id := ref.Record()
@@ -300,7 +326,14 @@ func (t *TestArtifactManager) DeactivateObject(
 	ctx context.Context,
 	domain core.RecordRef, request core.RecordRef, obj core.ObjectDescriptor,
 ) (*core.RecordID, error) {
-	panic("not implemented")
+	_, ok := t.Objects[*obj.HeadRef()]
+	if !ok {
+		return nil, errors.New("No object to deactivate")
+	}
+	delete(t.Objects, *obj.HeadRef())
+
+	id := testutils.RandomID()
+	return &id, nil
 }
 
 // UpdatePrototype implementation for tests
@@ -391,7 +424,7 @@ func AMPublishCode(
 ) {
 	ctx := context.TODO()
 	codeID, err := am.DeployCode(
-		ctx, domain, request, code, mtype,
+		ctx, domain, request, code, mtype, nil,
 	)
 	assert.NoError(t, err, "create code on ledger")
 	codeRef = &core.RecordRef{}
@@ -500,11 +533,16 @@ func (cb *ContractsBuilder) Build(contracts map[string]string) error {
 			return errors.Wrap(err, "[ Build ] Can't RegisterRequest")
 		}
 
+		abi, err := cb.abi(name)
+		if err != nil {
+			return errors.Wrap(err, "[ Build ] Can't call abi")
+		}
+
 		log.Debugf("Deploying code for contract %q", name)
 		codeID, err := cb.ArtifactManager.DeployCode(
 			ctx,
 			core.RecordRef{}, *core.NewRecordRef(core.RecordID{}, *codeReq),
-			pluginBinary, core.MachineTypeGoPlugin,
+			pluginBinary, core.MachineTypeGoPlugin, abi,
 		)
 		codeRef := &core.RecordRef{}
 		codeRef.SetRecord(*codeID)
@@ -564,6 +602,18 @@ func (cb *ContractsBuilder) wrapper(name string) error {
 	return nil
 }
 
+// abi generates ABI of contract, logic runner enforces access rules of methods from it
+func (cb *ContractsBuilder) abi(name string) ([]byte, error) {
+	contractPath := filepath.Join(cb.root, "src/contract", name, "main.go")
+	abiPath := filepath.Join(cb.root, "src/contract", name, "abi.json")
+
+	out, err := exec.Command(cb.IccPath, "abi", "-o", abiPath, contractPath).CombinedOutput()
+	if err != nil {
+		return nil, errors.Wrap(err, "can't generate ABI for contract '"+name+"': "+string(out))
+	}
+	return ioutil.ReadFile(abiPath)
+}
+
 // Plugin ...
 func (cb *ContractsBuilder) plugin(name string) error {
 	dstDir := filepath.Join(cb.root, "plugins")
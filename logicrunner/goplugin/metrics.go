@@ -26,6 +26,8 @@ import (
 
 var (
 	tagMethodName = insmetrics.MustTagKey("methodName")
+	tagPrototype  = insmetrics.MustTagKey("prototype")
+	tagWorker     = insmetrics.MustTagKey("worker")
 )
 
 var (
@@ -34,6 +36,47 @@ var (
 		"time spent on execution contract, measured in goplugin",
 		stats.UnitMilliseconds,
 	)
+	statGopluginContractConstructorTime = stats.Float64(
+		"goplugin/contract/constructor/time",
+		"time spent on execution of contract's constructor, measured in goplugin",
+		stats.UnitMilliseconds,
+	)
+	statGopluginContractArgumentsSize = stats.Int64(
+		"goplugin/contract/arguments/size",
+		"size of serialized arguments passed to contract",
+		stats.UnitBytes,
+	)
+	statGopluginContractResultSize = stats.Int64(
+		"goplugin/contract/result/size",
+		"size of serialized results returned by contract",
+		stats.UnitBytes,
+	)
+	statGopluginContractStateSize = stats.Int64(
+		"goplugin/contract/state/size",
+		"size of object's memory after execution",
+		stats.UnitBytes,
+	)
+
+	statGopluginWorkerUp = stats.Int64(
+		"goplugin/worker/up",
+		"1 if insgorund worker is running, 0 otherwise",
+		stats.UnitDimensionless,
+	)
+	statGopluginWorkerRestarts = stats.Int64(
+		"goplugin/worker/restarts",
+		"number of restarts of insgorund worker",
+		stats.UnitDimensionless,
+	)
+	statGopluginWorkerCallsInFlight = stats.Int64(
+		"goplugin/worker/calls/inflight",
+		"number of calls executing in insgorund worker",
+		stats.UnitDimensionless,
+	)
+	statGopluginWorkerFailedCalls = stats.Int64(
+		"goplugin/worker/calls/failed",
+		"number of calls failed because insgorund worker crashed",
+		stats.UnitDimensionless,
+	)
 )
 
 func init() {
@@ -41,7 +84,47 @@ func init() {
 		&view.View{
 			Measure:     statGopluginContractMethodTime,
 			Aggregation: view.Distribution(0.001, 0.01, 0.1, 1, 10, 100, 1000, 5000, 10000, 20000),
-			TagKeys:     []tag.Key{tagMethodName},
+			TagKeys:     []tag.Key{tagPrototype, tagMethodName},
+		},
+		&view.View{
+			Measure:     statGopluginContractConstructorTime,
+			Aggregation: view.Distribution(0.001, 0.01, 0.1, 1, 10, 100, 1000, 5000, 10000, 20000),
+			TagKeys:     []tag.Key{tagPrototype, tagMethodName},
+		},
+		&view.View{
+			Measure:     statGopluginContractArgumentsSize,
+			Aggregation: view.Distribution(64, 256, 1024, 4096, 16384, 65536, 262144, 1048576),
+			TagKeys:     []tag.Key{tagPrototype, tagMethodName},
+		},
+		&view.View{
+			Measure:     statGopluginContractResultSize,
+			Aggregation: view.Distribution(64, 256, 1024, 4096, 16384, 65536, 262144, 1048576),
+			TagKeys:     []tag.Key{tagPrototype, tagMethodName},
+		},
+		&view.View{
+			Measure:     statGopluginContractStateSize,
+			Aggregation: view.Distribution(64, 256, 1024, 4096, 16384, 65536, 262144, 1048576),
+			TagKeys:     []tag.Key{tagPrototype, tagMethodName},
+		},
+		&view.View{
+			Measure:     statGopluginWorkerUp,
+			Aggregation: view.LastValue(),
+			TagKeys:     []tag.Key{tagWorker},
+		},
+		&view.View{
+			Measure:     statGopluginWorkerRestarts,
+			Aggregation: view.Sum(),
+			TagKeys:     []tag.Key{tagWorker},
+		},
+		&view.View{
+			Measure:     statGopluginWorkerCallsInFlight,
+			Aggregation: view.LastValue(),
+			TagKeys:     []tag.Key{tagWorker},
+		},
+		&view.View{
+			Measure:     statGopluginWorkerFailedCalls,
+			Aggregation: view.Sum(),
+			TagKeys:     []tag.Key{tagWorker},
 		},
 	)
 	if err != nil {
@@ -0,0 +1,296 @@
+/*
+ *    Copyright 2019 Insolar Technologies
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package goplugin
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"net"
+	"net/rpc"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.opencensus.io/stats"
+
+	"github.com/insolar/insolar/configuration"
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/instrumentation/inslogger"
+	"github.com/insolar/insolar/instrumentation/insmetrics"
+)
+
+const (
+	// workerDialAttempts and workerDialInterval limit time we wait for starting worker
+	workerDialAttempts = 50
+	workerDialInterval = 100 * time.Millisecond
+)
+
+// workerPool is a pool of insgorund processes spawned and supervised by the node.
+// Calls are dispatched by callee, so calls of one object always go to the same
+// worker and keep their order
+type workerPool struct {
+	cfg     *configuration.LogicRunner
+	workers []*worker
+
+	stopped chan struct{}
+	wg      sync.WaitGroup
+}
+
+// worker is one supervised insgorund process
+type worker struct {
+	id       int
+	protocol string
+	listen   string
+
+	mu     sync.Mutex
+	cmd    *exec.Cmd
+	client *rpc.Client
+	alive  bool
+	// crashed is closed when current process exits, calls in flight are failed
+	crashed chan struct{}
+
+	inFlight int64
+}
+
+func newWorkerPool(cfg *configuration.LogicRunner) (*workerPool, error) {
+	count := cfg.GoPlugin.RunnerCount
+	if count <= 0 {
+		count = 1
+	}
+
+	p := &workerPool{
+		cfg:     cfg,
+		workers: make([]*worker, count),
+		stopped: make(chan struct{}),
+	}
+	for i := range p.workers {
+		listen, err := workerAddress(cfg.GoPlugin.RunnerProtocol, cfg.GoPlugin.RunnerListen, i)
+		if err != nil {
+			return nil, errors.Wrapf(err, "couldn't build address of insgorund worker %d", i)
+		}
+		p.workers[i] = &worker{
+			id:       i,
+			protocol: cfg.GoPlugin.RunnerProtocol,
+			listen:   listen,
+		}
+	}
+	return p, nil
+}
+
+// workerAddress returns address of N-th worker, port of base address
+// is increased by N for network protocols and suffix .N is added for unix sockets
+func workerAddress(protocol string, base string, n int) (string, error) {
+	if protocol == "unix" || protocol == "unixpacket" {
+		return fmt.Sprintf("%s.%d", base, n), nil
+	}
+
+	host, port, err := net.SplitHostPort(base)
+	if err != nil {
+		return "", err
+	}
+	portNum, err := strconv.Atoi(port)
+	if err != nil {
+		return "", errors.Wrap(err, "port is not a number")
+	}
+	return net.JoinHostPort(host, strconv.Itoa(portNum+n)), nil
+}
+
+// index returns number of worker that serves the object
+func (p *workerPool) index(callee *core.RecordRef) int {
+	if callee == nil || len(p.workers) == 1 {
+		return 0
+	}
+	h := fnv.New32a()
+	_, _ = h.Write(callee[:])
+	return int(h.Sum32() % uint32(len(p.workers)))
+}
+
+// Start spawns all workers
+func (p *workerPool) Start(ctx context.Context) {
+	for _, w := range p.workers {
+		p.wg.Add(1)
+		go p.supervise(ctx, w)
+	}
+}
+
+// Stop kills all workers and waits for supervisors to finish
+func (p *workerPool) Stop() {
+	close(p.stopped)
+	for _, w := range p.workers {
+		w.mu.Lock()
+		if w.cmd != nil && w.cmd.Process != nil {
+			_ = w.cmd.Process.Kill()
+		}
+		w.mu.Unlock()
+	}
+	p.wg.Wait()
+}
+
+func (p *workerPool) supervise(ctx context.Context, w *worker) {
+	defer p.wg.Done()
+
+	logger := inslogger.FromContext(ctx)
+	ctx = insmetrics.InsertTag(ctx, tagWorker, strconv.Itoa(w.id))
+	delay := p.cfg.GoPlugin.RunnerRestartDelay
+	if delay <= 0 {
+		delay = time.Second
+	}
+
+	for {
+		cmd, err := p.spawn(w)
+		if err != nil {
+			logger.Errorf("Couldn't start insgorund worker %d: %s", w.id, err)
+		} else {
+			logger.Infof("insgorund worker %d started, listens %s", w.id, w.listen)
+			stats.Record(ctx, statGopluginWorkerUp.M(1))
+
+			err = cmd.Wait()
+			w.down()
+			stats.Record(ctx, statGopluginWorkerUp.M(0))
+		}
+
+		select {
+		case <-p.stopped:
+			logger.Infof("insgorund worker %d stopped", w.id)
+			return
+		default:
+		}
+
+		logger.Errorf("insgorund worker %d exited: %v, restarting in %s", w.id, err, delay)
+		stats.Record(ctx, statGopluginWorkerRestarts.M(1))
+
+		select {
+		case <-p.stopped:
+			return
+		case <-time.After(delay):
+		}
+	}
+}
+
+func (p *workerPool) spawn(w *worker) (*exec.Cmd, error) {
+	args := []string{
+		"-l", w.listen,
+		"--proto", w.protocol,
+		"--rpc", p.cfg.RPCListen,
+		"--rpc-proto", p.cfg.RPCProtocol,
+	}
+	if p.cfg.GoPlugin.RunnerCodePath != "" {
+		dir := filepath.Join(p.cfg.GoPlugin.RunnerCodePath, strconv.Itoa(w.id))
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return nil, errors.Wrap(err, "couldn't create code directory")
+		}
+		args = append(args, "-d", dir)
+	}
+
+	cmd := exec.Command(p.cfg.GoPlugin.RunnerPath, args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.cmd = cmd
+	w.alive = true
+	w.crashed = make(chan struct{})
+	return cmd, nil
+}
+
+// down marks worker as not running and fails calls in flight
+func (w *worker) down() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.alive = false
+	if w.client != nil {
+		_ = w.client.Close()
+		w.client = nil
+	}
+	if w.crashed != nil {
+		close(w.crashed)
+		w.crashed = nil
+	}
+}
+
+// downstream returns connection to running worker
+func (w *worker) downstream() (*rpc.Client, chan struct{}, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if !w.alive {
+		return nil, nil, errors.New("worker is not running")
+	}
+	if w.client == nil {
+		client, err := rpc.Dial(w.protocol, w.listen)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "couldn't dial '%s' over %s", w.listen, w.protocol)
+		}
+		w.client = client
+	}
+	return w.client, w.crashed, nil
+}
+
+// Call dispatches call to the worker serving callee, the call fails
+// if the worker crashes while executing it
+func (p *workerPool) Call(
+	ctx context.Context, callee *core.RecordRef, method string, req interface{}, res interface{},
+) error {
+	w := p.workers[p.index(callee)]
+	ctx = insmetrics.InsertTag(ctx, tagWorker, strconv.Itoa(w.id))
+
+	var client *rpc.Client
+	var crashed chan struct{}
+	var err error
+	for attempt := 1; ; attempt++ {
+		client, crashed, err = w.downstream()
+		if err == nil {
+			break
+		}
+		if attempt >= workerDialAttempts {
+			return errors.Wrapf(err, "insgorund worker %d is not available", w.id)
+		}
+		inslogger.FromContext(ctx).Debugf("insgorund worker %d is not ready: %s", w.id, err)
+		time.Sleep(workerDialInterval)
+	}
+
+	stats.Record(ctx, statGopluginWorkerCallsInFlight.M(atomic.AddInt64(&w.inFlight, 1)))
+	defer func() {
+		stats.Record(ctx, statGopluginWorkerCallsInFlight.M(atomic.AddInt64(&w.inFlight, -1)))
+	}()
+
+	call := client.Go(method, req, res, nil)
+	select {
+	case <-call.Done:
+		err = call.Error
+	case <-crashed:
+		err = rpc.ErrShutdown
+	}
+
+	if err == rpc.ErrShutdown || err == io.ErrUnexpectedEOF {
+		stats.Record(ctx, statGopluginWorkerFailedCalls.M(1))
+		return errors.Errorf("insgorund worker %d crashed while executing call", w.id)
+	}
+	return err
+}
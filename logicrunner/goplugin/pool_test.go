@@ -0,0 +1,58 @@
+/*
+ *    Copyright 2019 Insolar Technologies
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package goplugin
+
+import (
+	"testing"
+
+	"github.com/insolar/insolar/configuration"
+	"github.com/insolar/insolar/testutils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkerAddress(t *testing.T) {
+	addr, err := workerAddress("tcp", "127.0.0.1:7777", 3)
+	require.NoError(t, err)
+	require.Equal(t, "127.0.0.1:7780", addr)
+
+	addr, err = workerAddress("unix", "/tmp/insgorund.sock", 2)
+	require.NoError(t, err)
+	require.Equal(t, "/tmp/insgorund.sock.2", addr)
+
+	_, err = workerAddress("tcp", "127.0.0.1", 1)
+	require.Error(t, err)
+}
+
+func TestWorkerPool_Index(t *testing.T) {
+	cfg := configuration.NewLogicRunner()
+	cfg.GoPlugin.RunnerPath = "insgorund"
+	cfg.GoPlugin.RunnerCount = 4
+
+	pool, err := newWorkerPool(&cfg)
+	require.NoError(t, err)
+	require.Len(t, pool.workers, 4)
+	require.Equal(t, "127.0.0.1:7780", pool.workers[3].listen)
+
+	for i := 0; i < 100; i++ {
+		ref := testutils.RandomRef()
+		idx := pool.index(&ref)
+		require.True(t, idx >= 0 && idx < 4)
+		// calls of the same object always go to the same worker
+		require.Equal(t, idx, pool.index(&ref))
+	}
+	require.Equal(t, 0, pool.index(nil))
+}
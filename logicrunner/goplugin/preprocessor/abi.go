@@ -0,0 +1,226 @@
+/*
+ *    Copyright 2019 Insolar Technologies
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package preprocessor
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/token"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/insolar/insolar/core"
+)
+
+// ABI is a machine readable description of contract's interface
+type ABI struct {
+	Contract     string        `json:"contract"`
+	Constructors []FunctionABI `json:"constructors"`
+	Methods      []FunctionABI `json:"methods"`
+	Types        []TypeABI     `json:"types,omitempty"`
+}
+
+// FunctionABI describes constructor or method of a contract
+type FunctionABI struct {
+	Name string `json:"name"`
+	// API is true if method could be called from outside, see INSATTR_<Method>_API
+	API bool `json:"api,omitempty"`
+	// Transactional is true if call of method starts transaction, see INSATTR_<Method>_Transactional
+	Transactional bool `json:"transactional,omitempty"`
+	// Access lists rules caller must satisfy, see INSATTR_<Method>_Access
+	Access    []core.AccessRule `json:"access,omitempty"`
+	Arguments []FieldABI        `json:"arguments"`
+	Results   []FieldABI        `json:"results"`
+}
+
+// TypeABI describes type declared in contract's file and used in arguments or results
+type TypeABI struct {
+	Name   string     `json:"name"`
+	Type   string     `json:"type"`
+	Fields []FieldABI `json:"fields,omitempty"`
+}
+
+// FieldABI describes argument, result or field of struct
+type FieldABI struct {
+	Name string `json:"name,omitempty"`
+	Type string `json:"type"`
+}
+
+// ABI returns description of contract's interface
+func (pf *ParsedFile) ABI() *ABI {
+	res := &ABI{
+		Contract:     pf.contract,
+		Constructors: pf.functionsABI(pf.constructors[pf.contract]),
+		Methods:      pf.functionsABI(pf.methods[pf.contract]),
+	}
+
+	api := pf.flagAttributes("API")
+	transactional := pf.flagAttributes("Transactional")
+	for i := range res.Methods {
+		method := &res.Methods[i]
+		method.API = api[method.Name]
+		method.Transactional = transactional[method.Name]
+		method.Access = pf.access[method.Name]
+	}
+
+	names := make([]string, 0, len(pf.types))
+	for name := range pf.types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		typeSpec := pf.types[name]
+		t := TypeABI{
+			Name: name,
+			Type: pf.codeOfNode(typeSpec.Type),
+		}
+		if st, ok := typeSpec.Type.(*ast.StructType); ok {
+			t.Type = "struct"
+			t.Fields = pf.fieldsABI(st.Fields)
+		}
+		res.Types = append(res.Types, t)
+	}
+
+	return res
+}
+
+// WriteABI writes into `out` contract's ABI in JSON
+func (pf *ParsedFile) WriteABI(out io.Writer) error {
+	data, err := json.MarshalIndent(pf.ABI(), "", "    ")
+	if err != nil {
+		return errors.Wrap(err, "couldn't marshal ABI")
+	}
+	_, err = out.Write(data)
+	if err != nil {
+		return errors.Wrap(err, "couldn't write ABI to output")
+	}
+	return nil
+}
+
+// flagAttributes returns set of methods marked with INSATTR_<Method>_<attr> = true
+func (pf *ParsedFile) flagAttributes(attr string) map[string]bool {
+	res := make(map[string]bool)
+	for _, decl := range pf.node.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.VAR {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			vs := spec.(*ast.ValueSpec)
+			for i, name := range vs.Names {
+				method, ok := attributeMethod(name.Name, attr)
+				if !ok || i >= len(vs.Values) {
+					continue
+				}
+				if value, ok := vs.Values[i].(*ast.Ident); ok && value.Name == "true" {
+					res[method] = true
+				}
+			}
+		}
+	}
+	return res
+}
+
+// parseAccessAttributes collects access rules of methods declared
+// with INSATTR_<Method>_Access = "rule, rule"
+func (pf *ParsedFile) parseAccessAttributes() error {
+	pf.access = make(map[string][]core.AccessRule)
+	methods := make(map[string]bool)
+	for _, method := range pf.methods[pf.contract] {
+		methods[method.Name.Name] = true
+	}
+
+	for _, decl := range pf.node.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.VAR {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			vs := spec.(*ast.ValueSpec)
+			for i, name := range vs.Names {
+				method, ok := attributeMethod(name.Name, "Access")
+				if !ok {
+					continue
+				}
+				if !methods[method] {
+					return errors.Errorf("%s: contract %s has no method %s", name.Name, pf.contract, method)
+				}
+				if i >= len(vs.Values) {
+					return errors.Errorf("%s: access rules must be set", name.Name)
+				}
+				lit, ok := vs.Values[i].(*ast.BasicLit)
+				if !ok || lit.Kind != token.STRING {
+					return errors.Errorf("%s: access rules must be a string literal", name.Name)
+				}
+				value, err := strconv.Unquote(lit.Value)
+				if err != nil {
+					return errors.Wrapf(err, "%s: bad string literal", name.Name)
+				}
+				rules, err := core.ParseAccessRules(value)
+				if err != nil {
+					return errors.Wrap(err, name.Name)
+				}
+				pf.access[method] = rules
+			}
+		}
+	}
+	return nil
+}
+
+// attributeMethod extracts method name from attribute variable INSATTR_<Method>_<attr>
+func attributeMethod(name string, attr string) (string, bool) {
+	suffix := "_" + attr
+	if !isAttributeName(name) || !strings.HasSuffix(name, suffix) {
+		return "", false
+	}
+	method := strings.TrimSuffix(strings.TrimPrefix(name, "INSATTR_"), suffix)
+	return method, method != ""
+}
+
+func (pf *ParsedFile) functionsABI(list []*ast.FuncDecl) []FunctionABI {
+	res := make([]FunctionABI, 0, len(list))
+	for _, fun := range list {
+		res = append(res, FunctionABI{
+			Name:      fun.Name.Name,
+			Arguments: pf.fieldsABI(fun.Type.Params),
+			Results:   pf.fieldsABI(fun.Type.Results),
+		})
+	}
+	return res
+}
+
+func (pf *ParsedFile) fieldsABI(list *ast.FieldList) []FieldABI {
+	res := []FieldABI{}
+	if list == nil {
+		return res
+	}
+	for _, field := range list.List {
+		t := pf.codeOfNode(field.Type)
+		if len(field.Names) == 0 {
+			res = append(res, FieldABI{Type: t})
+			continue
+		}
+		for _, name := range field.Names {
+			res = append(res, FieldABI{Name: name.Name, Type: t})
+		}
+	}
+	return res
+}
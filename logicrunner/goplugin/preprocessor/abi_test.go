@@ -0,0 +1,159 @@
+/*
+ *    Copyright 2019 Insolar Technologies
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package preprocessor
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/logicrunner/goplugin/goplugintestutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestABI(t *testing.T) {
+	t.Parallel()
+	tmpDir, err := ioutil.TempDir("", "test-")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpDir) // nolint: errcheck
+
+	code := `
+package main
+
+import (
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/logicrunner/goplugin/foundation"
+)
+
+type Info struct {
+	Name, Role string
+}
+
+type One struct {
+	foundation.BaseContract
+}
+
+func New(name string) (*One, error) {
+	return &One{}, nil
+}
+
+var INSATTR_Get_API = true
+
+func (o *One) Get(ref *core.RecordRef, n int) (*Info, error) {
+	return nil, nil
+}
+
+var INSATTR_Set_Access = "parent, prototype:Two"
+var INSATTR_Set_Transactional = true
+
+func (o *One) Set() error {
+	return nil
+}
+`
+	err = goplugintestutils.WriteFile(tmpDir, "main.go", code)
+	require.NoError(t, err)
+
+	parsed, err := ParseFile(filepath.Join(tmpDir, "main.go"))
+	require.NoError(t, err)
+
+	expected := &ABI{
+		Contract: "One",
+		Constructors: []FunctionABI{
+			{
+				Name:      "New",
+				Arguments: []FieldABI{{Name: "name", Type: "string"}},
+				Results:   []FieldABI{{Type: "*One"}, {Type: "error"}},
+			},
+		},
+		Methods: []FunctionABI{
+			{
+				Name:      "Get",
+				API:       true,
+				Arguments: []FieldABI{{Name: "ref", Type: "*core.RecordRef"}, {Name: "n", Type: "int"}},
+				Results:   []FieldABI{{Type: "*Info"}, {Type: "error"}},
+			},
+			{
+				Name:          "Set",
+				Transactional: true,
+				Access:        []core.AccessRule{core.AccessParent, "prototype:Two"},
+				Arguments:     []FieldABI{},
+				Results:       []FieldABI{{Type: "error"}},
+			},
+		},
+		Types: []TypeABI{
+			{
+				Name:   "Info",
+				Type:   "struct",
+				Fields: []FieldABI{{Name: "Name", Type: "string"}, {Name: "Role", Type: "string"}},
+			},
+		},
+	}
+	assert.Equal(t, expected, parsed.ABI())
+
+	var buf bytes.Buffer
+	err = parsed.WriteABI(&buf)
+	require.NoError(t, err)
+
+	var decoded ABI
+	err = json.Unmarshal(buf.Bytes(), &decoded)
+	require.NoError(t, err)
+	assert.Equal(t, expected, &decoded)
+}
+
+func TestABI_AccessErrors(t *testing.T) {
+	t.Parallel()
+	tmpDir, err := ioutil.TempDir("", "test-")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpDir) // nolint: errcheck
+
+	code := `
+package main
+
+import (
+	"github.com/insolar/insolar/logicrunner/goplugin/foundation"
+)
+
+type One struct {
+	foundation.BaseContract
+}
+
+%s
+
+func (o *One) Set() error {
+	return nil
+}
+`
+	for _, attr := range []string{
+		`var INSATTR_Set_Access = "everyone"`,
+		`var INSATTR_Set_Access = "parent,"`,
+		`var INSATTR_Set_Access = "prototype:"`,
+		`var INSATTR_Set_Access = true`,
+		`var INSATTR_Get_Access = "self"`,
+	} {
+		err = goplugintestutils.WriteFile(tmpDir, "main.go", fmt.Sprintf(code, attr))
+		require.NoError(t, err)
+
+		_, err = ParseFile(filepath.Join(tmpDir, "main.go"))
+		require.Error(t, err, attr)
+	}
+}
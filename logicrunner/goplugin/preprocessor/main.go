@@ -58,6 +58,7 @@ type ParsedFile struct {
 	methods      map[string][]*ast.FuncDecl
 	constructors map[string][]*ast.FuncDecl
 	contract     string
+	access       map[string][]core.AccessRule
 }
 
 // ParseFile parses a file as Go source code of a smart contract
@@ -92,6 +93,11 @@ func ParseFile(fileName string) (*ParsedFile, error) {
 		return nil, errors.New("Only one smart contract must exist")
 	}
 
+	err = res.parseAccessAttributes()
+	if err != nil {
+		return nil, errors.Wrap(err, "Bad access rules")
+	}
+
 	return res, nil
 }
 
@@ -0,0 +1,302 @@
+/*
+ *    Copyright 2019 Insolar Technologies
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package preprocessor
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strconv"
+	"strings"
+)
+
+// allowedImports is a list of standard packages a contract may import,
+// everything else is forbidden unless it matches allowedImportPrefixes
+var allowedImports = map[string]bool{
+	"bytes":           true,
+	"encoding/base64": true,
+	"encoding/hex":    true,
+	"encoding/json":   true,
+	"errors":          true,
+	"fmt":             true,
+	"math":            true,
+	"math/big":        true,
+	"sort":            true,
+	"strconv":         true,
+	"strings":         true,
+	"time":            true,
+	"unicode":         true,
+	"unicode/utf8":    true,
+
+	corePath:       true,
+	foundationPath: true,
+}
+
+// allowedImportPrefixes is a list of import path prefixes a contract may import
+var allowedImportPrefixes = []string{
+	"github.com/insolar/insolar/application/",
+}
+
+// forbiddenCalls lists functions of allowed packages that are not deterministic
+var forbiddenCalls = map[string]map[string]bool{
+	"time": {
+		"Now":       true,
+		"Since":     true,
+		"Until":     true,
+		"Sleep":     true,
+		"After":     true,
+		"AfterFunc": true,
+		"Tick":      true,
+		"NewTicker": true,
+		"NewTimer":  true,
+	},
+}
+
+// SandboxViolation describes place in a contract's code that breaks determinism
+type SandboxViolation struct {
+	Position token.Position
+	Message  string
+}
+
+func (v SandboxViolation) String() string {
+	return fmt.Sprintf("%s: %s", v.Position, v.Message)
+}
+
+// CheckSandbox statically analyses contract's code and returns list
+// of constructions that could make execution non deterministic:
+// forbidden imports, goroutines, global mutable state and
+// iteration over maps
+func (pf *ParsedFile) CheckSandbox() []SandboxViolation {
+	sc := &sandboxChecker{
+		pf:        pf,
+		imports:   make(map[string]string),
+		mapFields: make(map[string]bool),
+	}
+
+	sc.checkImports()
+	sc.checkGlobals()
+	sc.collectMapFields()
+
+	for _, decl := range pf.node.Decls {
+		fd, ok := decl.(*ast.FuncDecl)
+		if !ok || fd.Body == nil {
+			continue
+		}
+		sc.checkFunc(fd)
+	}
+
+	return sc.violations
+}
+
+type sandboxChecker struct {
+	pf         *ParsedFile
+	violations []SandboxViolation
+
+	// imports maps local package name to import path
+	imports map[string]string
+	// mapFields holds names of struct fields with map type
+	mapFields map[string]bool
+}
+
+func (sc *sandboxChecker) report(pos token.Pos, format string, args ...interface{}) {
+	sc.violations = append(sc.violations, SandboxViolation{
+		Position: sc.pf.fileSet.Position(pos),
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (sc *sandboxChecker) checkImports() {
+	for _, imp := range sc.pf.node.Imports {
+		impPath, err := strconv.Unquote(imp.Path.Value)
+		if err != nil {
+			sc.report(imp.Pos(), "malformed import %s", imp.Path.Value)
+			continue
+		}
+
+		if !isImportAllowed(impPath) {
+			sc.report(imp.Pos(), "import of %q is not allowed in contracts", impPath)
+		}
+
+		name := impPath[strings.LastIndex(impPath, "/")+1:]
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+		// dot import hides package of identifiers, so forbidden
+		// functions can't be found by import path
+		if name == "." {
+			sc.report(imp.Pos(), "dot import of %q is not allowed in contracts", impPath)
+			continue
+		}
+		sc.imports[name] = impPath
+	}
+}
+
+func isImportAllowed(impPath string) bool {
+	if allowedImports[impPath] {
+		return true
+	}
+	for _, prefix := range allowedImportPrefixes {
+		if strings.HasPrefix(impPath, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (sc *sandboxChecker) checkGlobals() {
+	for _, decl := range sc.pf.node.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.VAR {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			for _, name := range spec.(*ast.ValueSpec).Names {
+				if name.Name == "_" || isAttributeName(name.Name) {
+					continue
+				}
+				sc.report(name.Pos(), "global variable %q is not allowed in contracts, use constants or contract fields", name.Name)
+			}
+		}
+	}
+}
+
+// isAttributeName checks if global variable is a contract annotation like INSATTR_Call_API
+func isAttributeName(name string) bool {
+	return strings.HasPrefix(name, "INSATTR_")
+}
+
+func (sc *sandboxChecker) collectMapFields() {
+	for _, decl := range sc.pf.node.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			st, ok := spec.(*ast.TypeSpec).Type.(*ast.StructType)
+			if !ok || st.Fields == nil {
+				continue
+			}
+			for _, field := range st.Fields.List {
+				if _, ok := field.Type.(*ast.MapType); !ok {
+					continue
+				}
+				for _, name := range field.Names {
+					sc.mapFields[name.Name] = true
+				}
+			}
+		}
+	}
+}
+
+func (sc *sandboxChecker) checkFunc(fd *ast.FuncDecl) {
+	// local variables with map type, scoping is ignored on purpose,
+	// false positives are better than missed iterations
+	mapVars := make(map[string]bool)
+	for _, list := range []*ast.FieldList{fd.Recv, fd.Type.Params} {
+		if list == nil {
+			continue
+		}
+		for _, field := range list.List {
+			if _, ok := field.Type.(*ast.MapType); !ok {
+				continue
+			}
+			for _, name := range field.Names {
+				mapVars[name.Name] = true
+			}
+		}
+	}
+
+	ast.Inspect(fd.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.GoStmt:
+			sc.report(node.Pos(), "goroutines are not allowed in contracts")
+		case *ast.ValueSpec:
+			if isMapExpr(node.Type) {
+				for _, name := range node.Names {
+					mapVars[name.Name] = true
+				}
+			}
+			for i, value := range node.Values {
+				if i < len(node.Names) && isMapExpr(value) {
+					mapVars[node.Names[i].Name] = true
+				}
+			}
+		case *ast.AssignStmt:
+			if len(node.Lhs) != len(node.Rhs) {
+				break
+			}
+			for i, value := range node.Rhs {
+				ident, ok := node.Lhs[i].(*ast.Ident)
+				if ok && isMapExpr(value) {
+					mapVars[ident.Name] = true
+				}
+			}
+		case *ast.RangeStmt:
+			if sc.isMapRange(node.X, mapVars) {
+				sc.report(node.Pos(), "iteration over map has random order and is not allowed in contracts, sort keys first")
+			}
+		case *ast.SelectorExpr:
+			sc.checkSelector(node)
+		}
+		return true
+	})
+}
+
+func (sc *sandboxChecker) isMapRange(x ast.Expr, mapVars map[string]bool) bool {
+	switch expr := x.(type) {
+	case *ast.Ident:
+		return mapVars[expr.Name]
+	case *ast.SelectorExpr:
+		return sc.mapFields[expr.Sel.Name]
+	case *ast.ParenExpr:
+		return sc.isMapRange(expr.X, mapVars)
+	}
+	return isMapExpr(x)
+}
+
+// checkSelector resolves package of selector by import path, so aliased imports
+// are checked too, any reference is reported, not only calls, as function value
+// could be called later
+func (sc *sandboxChecker) checkSelector(sel *ast.SelectorExpr) {
+	pkg, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return
+	}
+	impPath, ok := sc.imports[pkg.Name]
+	if !ok {
+		return
+	}
+	if forbiddenCalls[impPath][sel.Sel.Name] {
+		sc.report(sel.Pos(), "call of %s.%s is not deterministic and is not allowed in contracts", impPath, sel.Sel.Name)
+	}
+}
+
+// isMapExpr checks if expression is a map type, map literal or make of a map
+func isMapExpr(expr ast.Expr) bool {
+	switch e := expr.(type) {
+	case *ast.MapType:
+		return true
+	case *ast.CompositeLit:
+		return isMapExpr(e.Type)
+	case *ast.CallExpr:
+		fun, ok := e.Fun.(*ast.Ident)
+		if ok && fun.Name == "make" && len(e.Args) > 0 {
+			return isMapExpr(e.Args[0])
+		}
+	}
+	return false
+}
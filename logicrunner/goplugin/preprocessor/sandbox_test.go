@@ -0,0 +1,145 @@
+/*
+ *    Copyright 2019 Insolar Technologies
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package preprocessor
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/insolar/insolar/logicrunner/goplugin/goplugintestutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckSandbox(t *testing.T) {
+	t.Parallel()
+	tmpDir, err := ioutil.TempDir("", "test-")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpDir) // nolint: errcheck
+
+	code := `
+package main
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/insolar/insolar/logicrunner/goplugin/foundation"
+)
+
+var counter int
+
+var INSATTR_Get_API = true
+
+type One struct {
+	foundation.BaseContract
+	Index map[string]int
+}
+
+func (o *One) Get() (int, error) {
+	go func() {}()
+
+	res := 0
+	for _, v := range o.Index {
+		res += v
+	}
+
+	local := make(map[string]int)
+	for k := range local {
+		_ = k
+	}
+
+	_ = time.Now()
+	_ = time.Unix(0, 0)
+	return res + rand.Int(), nil
+}
+`
+	err = goplugintestutils.WriteFile(tmpDir, "main.go", code)
+	require.NoError(t, err)
+
+	parsed, err := ParseFile(filepath.Join(tmpDir, "main.go"))
+	require.NoError(t, err)
+
+	violations := parsed.CheckSandbox()
+	lines := make([]int, 0, len(violations))
+	for _, v := range violations {
+		lines = append(lines, v.Position.Line)
+	}
+	// import, global var, goroutine, field map range, local map range, time.Now
+	assert.Equal(t, []int{5, 11, 21, 24, 29, 33}, lines)
+}
+
+func TestCheckSandbox_ImportAliases(t *testing.T) {
+	t.Parallel()
+	tmpDir, err := ioutil.TempDir("", "test-")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpDir) // nolint: errcheck
+
+	code := `
+package main
+
+import (
+	. "strings"
+	clock "time"
+
+	"github.com/insolar/insolar/logicrunner/goplugin/foundation"
+)
+
+type One struct {
+	foundation.BaseContract
+}
+
+func (o *One) Get() (string, error) {
+	now := clock.Now
+	_ = now()
+	_ = clock.Unix(0, 0)
+	return ToUpper("a"), nil
+}
+`
+	err = goplugintestutils.WriteFile(tmpDir, "main.go", code)
+	require.NoError(t, err)
+
+	parsed, err := ParseFile(filepath.Join(tmpDir, "main.go"))
+	require.NoError(t, err)
+
+	violations := parsed.CheckSandbox()
+	lines := make([]int, 0, len(violations))
+	for _, v := range violations {
+		lines = append(lines, v.Position.Line)
+	}
+	// dot import, reference to aliased time.Now
+	assert.Equal(t, []int{5, 16}, lines)
+}
+
+func TestCheckSandboxForRealSmartContracts(t *testing.T) {
+	t.Parallel()
+	contractNames, err := GetRealContractsNames()
+	assert.NoError(t, err)
+	contractsDir, err := GetRealApplicationDir("contract")
+	assert.NoError(t, err)
+	for _, name := range contractNames {
+		file := contractPath(name, contractsDir)
+		t.Run(file, func(t *testing.T) {
+			t.Parallel()
+			parsed, err := ParseFile(file)
+			require.NoError(t, err)
+			assert.Empty(t, parsed.CheckSandbox())
+		})
+	}
+}
@@ -28,6 +28,9 @@ type ProxyHelper interface {
 	SaveAsDelegate(parentRef, classRef core.RecordRef, constructorName string, argsSerialized []byte) (core.RecordRef, error)
 	GetDelegate(object, ofType core.RecordRef) (core.RecordRef, error)
 	DeactivateObject(object core.RecordRef) error
+	ScheduleCall(object core.RecordRef, pulse core.PulseNumber, method string, args []byte) (core.RecordRef, error)
+	GetChunk(chunk core.RecordRef, state core.RecordID) ([]byte, error)
+	SaveChunk(chunk core.RecordRef, data []byte) (core.RecordRef, core.RecordID, error)
 	Serialize(what interface{}, to *[]byte) error
 	Deserialize(from []byte, into interface{}) error
 	MakeErrorSerializable(error) error
@@ -69,11 +69,15 @@ type UpGetCodeReq struct {
 	UpBaseReq
 	MType core.MachineType
 	Code  core.RecordRef
+	// HashOnly asks for hash of the code without the code
+	HashOnly bool
 }
 
 // UpGetCodeResp is response from GetCode RPC in goplugin
 type UpGetCodeResp struct {
 	Code []byte
+	// Hash of the code from code record, runner checks code against it
+	Hash []byte
 }
 
 // UpRouteReq is a set of arguments for Send RPC in goplugin
@@ -159,3 +163,42 @@ type UpDeactivateObjectReq struct {
 // UpDeactivateObjectResp is response from DeactivateObject RPC in goplugin
 type UpDeactivateObjectResp struct {
 }
+
+// UpScheduleCallReq is a set of arguments for ScheduleCall RPC in goplugin
+type UpScheduleCallReq struct {
+	UpBaseReq
+	Object    core.RecordRef
+	Pulse     core.PulseNumber
+	Method    string
+	Arguments core.Arguments
+}
+
+// UpScheduleCallResp is response from ScheduleCall RPC in goplugin
+type UpScheduleCallResp struct {
+	Schedule core.RecordRef
+}
+
+// UpGetChunkReq is a set of arguments for GetChunk RPC in goplugin
+type UpGetChunkReq struct {
+	UpBaseReq
+	Chunk core.RecordRef
+	State core.RecordID
+}
+
+// UpGetChunkResp is response from GetChunk RPC in goplugin
+type UpGetChunkResp struct {
+	Data []byte
+}
+
+// UpSaveChunkReq is a set of arguments for SaveChunk RPC in goplugin
+type UpSaveChunkReq struct {
+	UpBaseReq
+	Chunk core.RecordRef
+	Data  []byte
+}
+
+// UpSaveChunkResp is response from SaveChunk RPC in goplugin
+type UpSaveChunkResp struct {
+	Chunk core.RecordRef
+	State core.RecordID
+}
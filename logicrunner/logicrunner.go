@@ -39,6 +39,7 @@ import (
 	"github.com/insolar/insolar/instrumentation/inslogger"
 	"github.com/insolar/insolar/logicrunner/builtin"
 	"github.com/insolar/insolar/logicrunner/goplugin"
+	"github.com/insolar/insolar/logicrunner/wasm"
 )
 
 const maxQueueLength = 10
@@ -52,6 +53,7 @@ type ObjectState struct {
 
 	ExecutionState *ExecutionState
 	Validation     *ExecutionState
+	Replay         *ExecutionState
 	Consensus      *Consensus
 }
 
@@ -60,9 +62,10 @@ type ExecutionState struct {
 
 	ArtifactManager core.ArtifactManager
 
-	objectbody *ObjectBody
-	deactivate bool
-	nonce      uint64
+	objectbody  *ObjectBody
+	deactivate  bool
+	nonce       uint64
+	transaction *stagedTransaction // transaction object waits decision on
 
 	Behaviour ValidationBehaviour
 
@@ -82,10 +85,20 @@ type CurrentExecution struct {
 	Context       context.Context
 	LogicContext  *core.LogicCallContext
 	Request       *Ref
+	Method        string
+	Started       time.Time
 	Sequence      uint64
 	RequesterNode *Ref
 	ReturnMode    message.MethodReturnMode
 	SentResult    bool
+	Children      []Ref         // requests made during execution
+	ExecutorTime  time.Duration // time spent in executor of the machine type
+	Chunks        uint32        // chunks of memory created during execution
+
+	Transaction       Ref                 // transaction the execution belongs to
+	Coordinator       Ref                 // object that started the transaction
+	Participants      []reply.Participant // objects that staged changes in calls made during execution
+	TransactionFailed bool                // a call made in transaction failed
 }
 
 type ExecutionQueueResult struct {
@@ -100,6 +113,7 @@ type ExecutionQueueElement struct {
 	request    *Ref
 	pulse      core.PulseNumber
 	fromLedger bool
+	queued     time.Time // when element was added to the queue
 }
 
 type Error struct {
@@ -132,6 +146,8 @@ func (st *ObjectState) MustModeState(mode string) (res *ExecutionState) {
 		res = st.ExecutionState
 	case "validation":
 		res = st.Validation
+	case "replay":
+		res = st.Replay
 	default:
 		panic("'" + mode + "' is unknown object processing mode")
 	}
@@ -217,6 +233,7 @@ type LogicRunner struct {
 	PulseStorage               core.PulseStorage               `inject:""`
 	ArtifactManager            core.ArtifactManager            `inject:""`
 	JetCoordinator             core.JetCoordinator             `inject:""`
+	GenesisDataProvider        core.GenesisDataProvider        `inject:""`
 
 	Executors    [core.MachineTypesLastID]core.MachineLogicExecutor
 	machinePrefs []core.MachineType
@@ -225,6 +242,11 @@ type LogicRunner struct {
 	state      map[Ref]*ObjectState // if object exists, we are validating or executing it right now
 	stateMutex sync.RWMutex
 
+	callTraces *CallTraces
+	schedules  *Schedules
+	access     *AccessCache
+	queueDepth *queueDepth
+
 	sock net.Listener
 }
 
@@ -234,8 +256,12 @@ func NewLogicRunner(cfg *configuration.LogicRunner) (*LogicRunner, error) {
 		return nil, errors.New("LogicRunner have nil configuration")
 	}
 	res := LogicRunner{
-		Cfg:   cfg,
-		state: make(map[Ref]*ObjectState),
+		Cfg:        cfg,
+		state:      make(map[Ref]*ObjectState),
+		callTraces: NewCallTraces(),
+		schedules:  NewSchedules(),
+		access:     NewAccessCache(),
+		queueDepth: newQueueDepth(),
 	}
 	return &res, nil
 }
@@ -265,6 +291,14 @@ func (lr *LogicRunner) Start(ctx context.Context) error {
 		lr.machinePrefs = append(lr.machinePrefs, core.MachineTypeGoPlugin)
 	}
 
+	if lr.Cfg.Wasm != nil {
+		w := wasm.NewWasm(lr.Cfg.Wasm, lr.ArtifactManager, &RPC{lr: lr, ps: lr.PulseStorage})
+		if err := lr.RegisterExecutor(core.MachineTypeWasm, w); err != nil {
+			return err
+		}
+		lr.machinePrefs = append(lr.machinePrefs, core.MachineTypeWasm)
+	}
+
 	lr.RegisterHandlers()
 
 	return nil
@@ -279,6 +313,11 @@ func (lr *LogicRunner) RegisterHandlers() {
 	lr.MessageBus.MustRegister(core.TypePendingFinished, lr.HandlePendingFinishedMessage)
 	lr.MessageBus.MustRegister(core.TypeStillExecuting, lr.HandleStillExecutingMessage)
 	lr.MessageBus.MustRegister(core.TypeAbandonedRequestsNotification, lr.HandleAbandonedRequestsNotificationMessage)
+	lr.MessageBus.MustRegister(core.TypeGetCallTraces, lr.HandleGetCallTracesMessage)
+	lr.MessageBus.MustRegister(core.TypeTransactionDecision, lr.HandleTransactionDecisionMessage)
+	lr.MessageBus.MustRegister(core.TypeReplayRequest, lr.HandleReplayRequestMessage)
+	lr.MessageBus.MustRegister(core.TypeGetExecutionQueues, lr.HandleGetExecutionQueuesMessage)
+	lr.MessageBus.MustRegister(core.TypeCancelQueuedRequest, lr.HandleCancelQueuedRequestMessage)
 }
 
 // Stop stops logic runner component and its executors
@@ -409,6 +448,7 @@ func (lr *LogicRunner) executeActual(ctx context.Context, parcel core.Parcel, ms
 		parcel:  parcel,
 		request: request,
 		pulse:   lr.pulse(ctx).PulseNumber,
+		queued:  time.Now(),
 	}
 
 	es.Queue = append(es.Queue, qElement)
@@ -565,7 +605,18 @@ func (lr *LogicRunner) ProcessExecutionQueue(ctx context.Context, es *ExecutionS
 		}
 
 		var qe ExecutionQueueElement
-		if es.LedgerQueueElement != nil {
+		if es.transaction != nil {
+			var ok bool
+			qe, ok = es.popTransactionCall()
+			if !ok {
+				// other calls wait for decision on transaction
+				inslogger.FromContext(ctx).Debug("Quiting queue processing, object is in transaction")
+				es.QueueProcessorActive = false
+				es.Current = nil
+				es.Unlock()
+				return
+			}
+		} else if es.LedgerQueueElement != nil {
 			qe = *es.LedgerQueueElement
 			es.LedgerQueueElement = nil
 		} else {
@@ -575,6 +626,8 @@ func (lr *LogicRunner) ProcessExecutionQueue(ctx context.Context, es *ExecutionS
 		sender := qe.parcel.GetSender()
 		current := CurrentExecution{
 			Request:       qe.request,
+			Method:        requestMethod(qe.parcel.Message()),
+			Started:       time.Now(),
 			RequesterNode: &sender,
 		}
 		es.Current = &current
@@ -607,7 +660,10 @@ func (lr *LogicRunner) ProcessExecutionQueue(ctx context.Context, es *ExecutionS
 		}
 		es.Behaviour.(*ValidationSaver).NewRequest(qe.parcel, *qe.request, recordingBus)
 
+		start := time.Now()
 		res.reply, res.err = lr.executeOrValidate(current.Context, es, qe.parcel)
+		trace := lr.traceExecution(qe.ctx, es, qe, start, res)
+		lr.profileExecution(qe.ctx, qe, trace, current.ExecutorTime)
 
 		if qe.fromLedger {
 			go lr.getLedgerPendingRequest(ctx, es, *qe.parcel.DefaultTarget().Record())
@@ -630,7 +686,8 @@ func (lr *LogicRunner) finishPendingIfNeeded(ctx context.Context, es *ExecutionS
 	es.Lock()
 	defer es.Unlock()
 
-	if es.pending != message.InPending {
+	// object with staged transaction finishes pending when decision is applied
+	if es.pending != message.InPending || es.transaction != nil {
 		return
 	}
 
@@ -805,6 +862,7 @@ func (lr *LogicRunner) unsafeGetLedgerPendingRequest(ctx context.Context, es *Ex
 // make it private again when we start it serialize before sending
 type ObjectBody struct {
 	objDescriptor   core.ObjectDescriptor
+	abi             *CodeABI
 	Object          []byte
 	Prototype       *Ref
 	CodeMachineType core.MachineType
@@ -886,8 +944,13 @@ func (lr *LogicRunner) executeMethodCall(ctx context.Context, es *ExecutionState
 		if err != nil {
 			return nil, errors.Wrap(err, "couldn't get descriptors by object reference")
 		}
+		abi, err := lr.codeABI(codeDesc)
+		if err != nil {
+			return nil, errors.Wrap(err, "couldn't get ABI of code")
+		}
 		es.objectbody = &ObjectBody{
 			objDescriptor:   objDesc,
+			abi:             abi,
 			Object:          objDesc.Memory(),
 			Prototype:       protoDesc.HeadRef(),
 			CodeMachineType: codeDesc.MachineType(),
@@ -906,20 +969,48 @@ func (lr *LogicRunner) executeMethodCall(ctx context.Context, es *ExecutionState
 		return nil, errors.New("proxy call error: try to call method of prototype as method of another prototype")
 	}
 
+	if err := lr.checkAccess(ctx, es.objectbody, m); err != nil {
+		return nil, es.WrapError(err, "access check failed")
+	}
+
+	tx, txCoordinator := m.Transaction, m.Coordinator
+	coordinator := tx.IsEmpty() && es.objectbody.abi.Transactional(m.Method)
+	if coordinator {
+		tx, txCoordinator = *current.Request, m.ObjectRef
+	}
+	es.Lock()
+	staged := es.transaction
+	es.Current.Transaction = tx
+	es.Current.Coordinator = txCoordinator
+	es.Unlock()
+	if staged != nil && !staged.transaction.Equal(tx) {
+		return nil, es.WrapError(nil, "object is in another transaction")
+	}
+
 	executor, err := lr.GetExecutor(es.objectbody.CodeMachineType)
 	if err != nil {
 		return nil, es.WrapError(err, "no executor registered")
 	}
 
+	start := time.Now()
 	newData, result, err := executor.CallMethod(
 		ctx, current.LogicContext, *es.objectbody.CodeRef, es.objectbody.Object, m.Method, m.Arguments,
 	)
+	executorTime := time.Since(start)
+	es.Lock()
+	es.Current.ExecutorTime = executorTime
+	es.Unlock()
 	if err != nil {
 		return nil, es.WrapError(err, "executor error")
 	}
 
+	// object that isn't changed in transaction doesn't take part in it
+	staging := !tx.IsEmpty() && (staged != nil || es.deactivate || !bytes.Equal(es.objectbody.Object, newData))
+
 	am := lr.ArtifactManager
-	if es.deactivate {
+	if staging {
+		lr.stageTransaction(ctx, es, m.ObjectRef, tx, txCoordinator, *current.Request, result)
+	} else if es.deactivate {
 		_, err := am.DeactivateObject(
 			ctx, Ref{}, *current.Request, es.objectbody.objDescriptor,
 		)
@@ -936,14 +1027,59 @@ func (lr *LogicRunner) executeMethodCall(ctx context.Context, es *ExecutionState
 		}
 		es.objectbody.objDescriptor = od
 	}
-	_, err = am.RegisterResult(ctx, m.ObjectRef, *current.Request, result)
-	if err != nil {
-		return nil, es.WrapError(err, "couldn't save results")
+	// results of calls made in transaction are registered when transaction is decided
+	if tx.IsEmpty() {
+		_, err = am.RegisterResult(ctx, m.ObjectRef, *current.Request, result)
+		if err != nil {
+			return nil, es.WrapError(err, "couldn't save results")
+		}
 	}
 
 	es.objectbody.Object = newData
 
-	return &reply.CallMethod{Result: result, Request: *current.Request}, nil
+	re := &reply.CallMethod{Result: result, Request: *current.Request}
+	if tx.IsEmpty() {
+		return re, nil
+	}
+
+	es.Lock()
+	participants := es.Current.Participants
+	failed := es.Current.TransactionFailed
+	es.Unlock()
+	if staging {
+		participants = append(participants, reply.Participant{
+			Object: m.ObjectRef,
+			Node:   lr.NodeNetwork.GetOrigin().ID(),
+		})
+	}
+
+	if !coordinator {
+		if !staging {
+			// object took no part in transaction, so its result doesn't depend on decision
+			_, err = am.RegisterResult(ctx, m.ObjectRef, *current.Request, result)
+			if err != nil {
+				return nil, es.WrapError(err, "couldn't save results")
+			}
+		}
+		re.Participants = participants
+		return re, nil
+	}
+
+	contractFailed := resultHasError(result)
+	commit := !failed && !contractFailed && time.Since(start) < lr.transactionTimeout()/2
+	committed := lr.finishTransaction(ctx, es, tx, m.ObjectRef, participants, commit)
+	if !staging {
+		// result of staged call is registered by the decision itself
+		err = lr.registerTransactionResult(ctx, m.ObjectRef, *current.Request, result, committed)
+		if err != nil {
+			return nil, es.WrapError(err, "couldn't save results")
+		}
+	}
+	if !committed && !contractFailed {
+		// error returned by contract explains abort itself
+		return nil, es.WrapError(nil, "transaction aborted")
+	}
+	return re, nil
 }
 
 func (lr *LogicRunner) getDescriptorsByPrototypeRef(
@@ -1017,7 +1153,12 @@ func (lr *LogicRunner) executeConstructorCall(
 		return nil, es.WrapError(err, "no executer registered")
 	}
 
+	start := time.Now()
 	newData, err := executor.CallConstructor(ctx, current.LogicContext, *codeDesc.Ref(), m.Method, m.Arguments)
+	executorTime := time.Since(start)
+	es.Lock()
+	es.Current.ExecutorTime = executorTime
+	es.Unlock()
 	if err != nil {
 		return nil, es.WrapError(err, "executer error")
 	}
@@ -1065,7 +1206,7 @@ func (lr *LogicRunner) OnPulse(ctx context.Context, pulse core.Pulse) error {
 			if !meNext {
 				sendExecResults := false
 
-				if es.Current != nil {
+				if es.Current != nil || es.transaction != nil {
 					es.pending = message.InPending
 					sendExecResults = true
 
@@ -1137,7 +1278,7 @@ func (lr *LogicRunner) OnPulse(ctx context.Context, pulse core.Pulse) error {
 			es.Unlock()
 		}
 
-		if state.ExecutionState == nil && state.Validation == nil && state.Consensus == nil {
+		if state.ExecutionState == nil && state.Validation == nil && state.Replay == nil && state.Consensus == nil {
 			delete(lr.state, ref)
 		}
 
@@ -1147,6 +1288,11 @@ func (lr *LogicRunner) OnPulse(ctx context.Context, pulse core.Pulse) error {
 
 	lr.stateMutex.Unlock()
 
+	lr.callTraces.OnPulse(pulse)
+	lr.recordQueueDepth(ctx)
+	lr.dispatchSchedulesOnPulse(ctx, pulse)
+	lr.resolveRootMemberOnPulse(ctx)
+
 	if len(messages) > 0 {
 		go lr.sendOnPulseMessagesAsync(ctx, messages)
 	}
@@ -1203,6 +1349,19 @@ func (lr *LogicRunner) HandleAbandonedRequestsNotificationMessage(
 	inslogger.FromContext(ctx).Debug("LogicRunner.HandleAbandonedRequestsNotificationMessage starts ...")
 
 	msg := parcel.Message().(*message.AbandonedRequestsNotification)
+	if IsScheduleBucket(msg.Object) {
+		// bucket wasn't dispatched on its pulse, e.g. executor was down
+		// or there was no pulse with such number
+		if msg.Object.Pulse() <= lr.pulse(ctx).PulseNumber {
+			go lr.dispatchSchedules(ctx, msg.Object)
+		}
+		return &reply.OK{}, nil
+	}
+	if IsTransactionBucket(msg.Object) {
+		go lr.closeTransactionDecisions(ctx, msg.Object)
+		return &reply.OK{}, nil
+	}
+
 	ref := msg.DefaultTarget()
 	os := lr.UpsertObjectState(*ref)
 
@@ -27,6 +27,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/pkg/errors"
+
 	"github.com/insolar/insolar/testutils/terminationhandler"
 
 	"github.com/insolar/insolar/ledger/storage/jet"
@@ -67,6 +69,15 @@ import (
 	"github.com/ugorji/go/codec"
 )
 
+// genesisDataProviderStub doesn't know root member, genesis isn't made in these tests
+type genesisDataProviderStub struct {
+	core.GenesisDataProvider
+}
+
+func (*genesisDataProviderStub) GetRootMember(ctx context.Context) (*core.RecordRef, error) {
+	return nil, errors.New("no root member in tests")
+}
+
 var icc = ""
 var runnerbin = ""
 var parallel = false
@@ -153,7 +164,10 @@ func PrepareLrAmCbPm(t *testing.T) (core.LogicRunner, core.ArtifactManager, *gop
 	pulseStorage := l.PulseManager.(*pulsemanager.PulseManager).PulseStorage
 	nth := terminationhandler.NewTestHandler()
 
-	cm.Inject(db, pulseStorage, nk, providerMock, l, lr, nw, mb, cr, delegationTokenFactory, parcelFactory, nth, mock)
+	cm.Inject(
+		db, pulseStorage, nk, providerMock, l, lr, nw, mb, cr, delegationTokenFactory, parcelFactory, nth, mock,
+		&genesisDataProviderStub{},
+	)
 	err = cm.Init(ctx)
 	assert.NoError(t, err)
 	err = cm.Start(ctx)
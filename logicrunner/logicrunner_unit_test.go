@@ -570,6 +570,9 @@ func (s *LogicRunnerOnPulseTestSuite) AfterTest(suiteName, testName string) {
 
 // Empty state, expecting no error
 func (s *LogicRunnerOnPulseTestSuite) TestEmptyLR() {
+	s.jc.MeMock.Return(core.RecordRef{})
+	s.jc.IsAuthorizedMock.Return(false, nil)
+
 	err := s.lr.OnPulse(s.ctx, s.pulse)
 	s.Require().NoError(err)
 }
@@ -676,6 +679,9 @@ func (s *LogicRunnerOnPulseTestSuite) TestExecutorSameNode() {
 	s.jc.MeMock.Return(core.RecordRef{})
 	s.jc.IsAuthorizedMock.Return(true, nil)
 
+	// we are executor of schedule bucket too
+	s.am.GetPendingRequestMock.Return(nil, core.ErrNoPendingRequest)
+
 	s.lr.state[s.objectRef] = &ObjectState{
 		ExecutionState: &ExecutionState{
 			Behaviour: &ValidationSaver{},
@@ -696,6 +702,9 @@ func (s *LogicRunnerOnPulseTestSuite) TestStateTransfer1() {
 	s.jc.MeMock.Return(core.RecordRef{})
 	s.jc.IsAuthorizedMock.Return(true, nil)
 
+	// we are executor of schedule bucket too
+	s.am.GetPendingRequestMock.Return(nil, core.ErrNoPendingRequest)
+
 	s.lr.state[s.objectRef] = &ObjectState{
 		ExecutionState: &ExecutionState{
 			Behaviour: &ValidationSaver{},
@@ -740,6 +749,9 @@ func (s *LogicRunnerOnPulseTestSuite) TestStateTransfer3() {
 	s.jc.MeMock.Return(core.RecordRef{})
 	s.jc.IsAuthorizedMock.Return(true, nil)
 
+	// we are executor of schedule bucket too
+	s.am.GetPendingRequestMock.Return(nil, core.ErrNoPendingRequest)
+
 	s.lr.state[s.objectRef] = &ObjectState{
 		ExecutionState: &ExecutionState{
 			Behaviour:        &ValidationSaver{},
@@ -0,0 +1,141 @@
+/*
+ *    Copyright 2019 Insolar Technologies
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package logicrunner
+
+import (
+	"context"
+	"time"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/instrumentation/inslogger"
+	"github.com/insolar/insolar/instrumentation/insmetrics"
+)
+
+var (
+	tagPrototype = insmetrics.MustTagKey("prototype")
+	tagMethod    = insmetrics.MustTagKey("method")
+)
+
+var (
+	statQueueTime = stats.Float64(
+		"logicrunner/request/queue/time",
+		"time request spent in execution queue",
+		stats.UnitMilliseconds,
+	)
+	statExecutionTime = stats.Float64(
+		"logicrunner/request/execution/time",
+		"time spent on execution of request, including registration of results",
+		stats.UnitMilliseconds,
+	)
+	statOutgoingCalls = stats.Int64(
+		"logicrunner/request/outgoing/count",
+		"number of calls made by contract while executing request",
+		stats.UnitDimensionless,
+	)
+	statSlowCalls = stats.Int64(
+		"logicrunner/request/slow/count",
+		"number of requests that exceeded slow call threshold",
+		stats.UnitDimensionless,
+	)
+	statQueueDepth = stats.Int64(
+		"logicrunner/queue/depth",
+		"number of requests in execution queues of objects",
+		stats.UnitDimensionless,
+	)
+)
+
+func init() {
+	err := view.Register(
+		&view.View{
+			Measure:     statQueueTime,
+			Aggregation: view.Distribution(0.001, 0.01, 0.1, 1, 10, 100, 1000, 5000, 10000, 20000),
+			TagKeys:     []tag.Key{tagPrototype, tagMethod},
+		},
+		&view.View{
+			Measure:     statExecutionTime,
+			Aggregation: view.Distribution(0.001, 0.01, 0.1, 1, 10, 100, 1000, 5000, 10000, 20000),
+			TagKeys:     []tag.Key{tagPrototype, tagMethod},
+		},
+		&view.View{
+			Name:        "logicrunner/request/outgoing/sum",
+			Measure:     statOutgoingCalls,
+			Aggregation: view.Sum(),
+			TagKeys:     []tag.Key{tagPrototype, tagMethod},
+		},
+		&view.View{
+			Name:        "logicrunner/request/outgoing/distribution",
+			Measure:     statOutgoingCalls,
+			Aggregation: view.Distribution(0, 1, 2, 5, 10, 20, 50, 100),
+			TagKeys:     []tag.Key{tagPrototype, tagMethod},
+		},
+		&view.View{
+			Measure:     statSlowCalls,
+			Aggregation: view.Sum(),
+			TagKeys:     []tag.Key{tagPrototype, tagMethod},
+		},
+		&view.View{
+			Measure:     statQueueDepth,
+			Aggregation: view.LastValue(),
+			TagKeys:     []tag.Key{tagPrototype},
+		},
+	)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// profileExecution records metrics of finished execution and reports slow calls
+func (lr *LogicRunner) profileExecution(
+	ctx context.Context, qe ExecutionQueueElement, trace *core.CallTrace, executorTime time.Duration,
+) {
+	if trace == nil {
+		return
+	}
+
+	queueTime := time.Duration(0)
+	if !qe.queued.IsZero() {
+		queueTime = trace.Start.Sub(qe.queued)
+	}
+
+	ctx = insmetrics.ChangeTags(
+		ctx,
+		tag.Insert(tagPrototype, trace.Prototype.String()),
+		tag.Insert(tagMethod, trace.Method),
+	)
+	stats.Record(
+		ctx,
+		statQueueTime.M(float64(queueTime.Nanoseconds())/1e6),
+		statExecutionTime.M(float64(trace.Duration.Nanoseconds())/1e6),
+		statOutgoingCalls.M(int64(len(trace.Children))),
+	)
+
+	threshold := lr.Cfg.SlowCallThreshold
+	if threshold <= 0 || queueTime+trace.Duration < threshold {
+		return
+	}
+
+	stats.Record(ctx, statSlowCalls.M(1))
+	inslogger.FromContext(ctx).Warnf(
+		"Slow call of %s on object %s (prototype %s), request %s: queued %s, executing %s (in executor %s), outgoing calls %d",
+		trace.Method, trace.Object, trace.Prototype, trace.Request,
+		queueTime, trace.Duration, executorTime, len(trace.Children),
+	)
+}
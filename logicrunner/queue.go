@@ -0,0 +1,278 @@
+/*
+ *    Copyright 2019 Insolar Technologies
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package logicrunner
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.opencensus.io/stats"
+
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/core/message"
+	"github.com/insolar/insolar/core/reply"
+	"github.com/insolar/insolar/instrumentation/inslogger"
+	"github.com/insolar/insolar/instrumentation/insmetrics"
+	"github.com/insolar/insolar/logicrunner/goplugin/foundation"
+)
+
+// Operators see execution queues of objects and may cancel a queued request
+// that blocks the object or isn't needed anymore. Canceled request gets error
+// result on ledger, so it isn't fetched as pending again, and the caller that
+// waits for result gets the error.
+
+// unknownPrototype tags queue depth of objects executor hasn't fetched yet
+const unknownPrototype = "unknown"
+
+// requestMethod returns name of method or constructor called by the message
+func requestMethod(msg core.Message) string {
+	switch m := msg.(type) {
+	case *message.CallMethod:
+		return m.Method
+	case *message.CallConstructor:
+		return m.Method
+	}
+	return ""
+}
+
+func queuedRequest(qe ExecutionQueueElement, now time.Time) core.QueuedRequest {
+	res := core.QueuedRequest{
+		Request:    *qe.request,
+		Method:     requestMethod(qe.parcel.Message()),
+		FromLedger: qe.fromLedger,
+	}
+	if caller := qe.parcel.GetCaller(); caller != nil {
+		res.Caller = *caller
+	}
+	if !qe.queued.IsZero() {
+		res.Age = now.Sub(qe.queued)
+	}
+	return res
+}
+
+// ExecutionQueues returns queues of objects that have requests to execute, sorted by objects
+func (lr *LogicRunner) ExecutionQueues() []core.ExecutionQueue {
+	lr.stateMutex.RLock()
+	states := make([]*ObjectState, 0, len(lr.state))
+	for _, os := range lr.state {
+		states = append(states, os)
+	}
+	lr.stateMutex.RUnlock()
+
+	now := time.Now()
+	res := make([]core.ExecutionQueue, 0)
+	for _, os := range states {
+		os.Lock()
+		es := os.ExecutionState
+		os.Unlock()
+		if es == nil {
+			continue
+		}
+
+		es.Lock()
+		if es.Current == nil && !es.haveSomeToProcess() {
+			es.Unlock()
+			continue
+		}
+		queue := core.ExecutionQueue{
+			Object:                *os.Ref,
+			Queue:                 make([]core.QueuedRequest, 0, len(es.Queue)),
+			Pending:               es.pending == message.InPending,
+			InTransaction:         es.transaction != nil,
+			LedgerHasMoreRequests: es.LedgerHasMoreRequests,
+		}
+		if es.objectbody != nil && es.objectbody.Prototype != nil {
+			prototype := *es.objectbody.Prototype
+			queue.Prototype = &prototype
+		}
+		if current := es.Current; current != nil && current.Request != nil {
+			queue.Current = &core.QueuedRequest{
+				Request: *current.Request,
+				Method:  current.Method,
+				Age:     now.Sub(current.Started),
+			}
+			if current.LogicContext != nil && current.LogicContext.Caller != nil {
+				queue.Current.Caller = *current.LogicContext.Caller
+			}
+		}
+		if es.LedgerQueueElement != nil {
+			queue.Queue = append(queue.Queue, queuedRequest(*es.LedgerQueueElement, now))
+		}
+		for _, qe := range es.Queue {
+			queue.Queue = append(queue.Queue, queuedRequest(qe, now))
+		}
+		es.Unlock()
+
+		res = append(res, queue)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Object.Compare(res[j].Object) < 0
+	})
+	return res
+}
+
+// CancelQueuedRequest removes request from execution queue of the object, registers
+// error result of the request and sends the error to the caller if it waits for result
+func (lr *LogicRunner) CancelQueuedRequest(ctx context.Context, object, request Ref, reason string) error {
+	os := lr.GetObjectState(object)
+	if os == nil {
+		return errors.New("object has no execution queue")
+	}
+	os.Lock()
+	es := os.ExecutionState
+	os.Unlock()
+	if es == nil {
+		return errors.New("object has no execution queue")
+	}
+
+	es.Lock()
+	if es.Current != nil && es.Current.Request != nil && es.Current.Request.Equal(request) {
+		es.Unlock()
+		return errors.New("request is executing right now, it can't be canceled")
+	}
+	var qe *ExecutionQueueElement
+	if es.LedgerQueueElement != nil && es.LedgerQueueElement.request.Equal(request) {
+		qe = es.LedgerQueueElement
+		es.LedgerQueueElement = nil
+	} else {
+		for i := range es.Queue {
+			if es.Queue[i].request.Equal(request) {
+				element := es.Queue[i]
+				qe = &element
+				queue := make([]ExecutionQueueElement, 0, len(es.Queue)-1)
+				es.Queue = append(append(queue, es.Queue[:i]...), es.Queue[i+1:]...)
+				break
+			}
+		}
+	}
+	es.Unlock()
+	if qe == nil {
+		return errors.New("request isn't in execution queue")
+	}
+
+	errstr := "request canceled"
+	if reason != "" {
+		errstr += ": " + reason
+	}
+	inslogger.FromContext(ctx).Warnf("Request %s on object %s is canceled: %s", request, object, reason)
+
+	result, err := core.MarshalArgs(nil, &foundation.Error{S: errstr})
+	if err != nil {
+		return errors.Wrap(err, "couldn't serialize result")
+	}
+	_, err = lr.ArtifactManager.RegisterResult(ctx, object, request, result)
+	if err != nil {
+		return errors.Wrap(err, "couldn't save result")
+	}
+
+	msg, ok := qe.parcel.Message().(*message.CallMethod)
+	if !ok || msg.ReturnMode != message.ReturnResult {
+		return nil
+	}
+	target := qe.parcel.GetSender()
+	_, err = lr.MessageBus.Send(
+		ctx,
+		&message.ReturnResults{
+			Caller:   lr.NodeNetwork.GetOrigin().ID(),
+			Target:   target,
+			Sequence: msg.Sequence,
+			Error:    errstr,
+		},
+		&core.MessageSendOptions{
+			Receiver: &target,
+		},
+	)
+	if err != nil {
+		inslogger.FromContext(ctx).Error("couldn't deliver result of canceled request: ", err)
+	}
+	return nil
+}
+
+// queueDepth remembers prototypes depth was recorded for, so drained queues are recorded as empty
+type queueDepth struct {
+	sync.Mutex
+	recorded map[string]bool
+}
+
+func newQueueDepth() *queueDepth {
+	return &queueDepth{recorded: make(map[string]bool)}
+}
+
+// recordQueueDepth records number of queued requests per prototype
+func (lr *LogicRunner) recordQueueDepth(ctx context.Context) {
+	depth := make(map[string]int64)
+	for _, queue := range lr.ExecutionQueues() {
+		prototype := unknownPrototype
+		if queue.Prototype != nil {
+			prototype = queue.Prototype.String()
+		}
+		depth[prototype] += int64(len(queue.Queue))
+	}
+
+	lr.queueDepth.Lock()
+	defer lr.queueDepth.Unlock()
+	for prototype := range lr.queueDepth.recorded {
+		if _, ok := depth[prototype]; !ok {
+			depth[prototype] = 0
+		}
+	}
+	for prototype, n := range depth {
+		if n > 0 {
+			lr.queueDepth.recorded[prototype] = true
+		} else {
+			delete(lr.queueDepth.recorded, prototype)
+		}
+		stats.Record(
+			insmetrics.InsertTag(ctx, tagPrototype, prototype),
+			statQueueDepth.M(n),
+		)
+	}
+}
+
+// HandleGetExecutionQueuesMessage replies with execution queues of objects
+func (lr *LogicRunner) HandleGetExecutionQueuesMessage(
+	ctx context.Context, parcel core.Parcel,
+) (
+	core.Reply, error,
+) {
+	return &reply.ExecutionQueues{
+		Node:   lr.JetCoordinator.Me(),
+		Queues: lr.ExecutionQueues(),
+	}, nil
+}
+
+// HandleCancelQueuedRequestMessage cancels queued request, it's sent by admin API of executor itself
+func (lr *LogicRunner) HandleCancelQueuedRequestMessage(
+	ctx context.Context, parcel core.Parcel,
+) (
+	core.Reply, error,
+) {
+	msg := parcel.Message().(*message.CancelQueuedRequest)
+	err := lr.checkExecutorSender(ctx, parcel, msg.Object)
+	if err != nil {
+		return nil, errors.Wrap(err, "[ HandleCancelQueuedRequestMessage ] can't cancel request")
+	}
+	err = lr.CancelQueuedRequest(ctx, msg.Object, msg.Request, msg.Reason)
+	if err != nil {
+		return nil, err
+	}
+	return &reply.OK{}, nil
+}
@@ -0,0 +1,188 @@
+/*
+ *    Copyright 2019 Insolar Technologies
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package logicrunner
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gojuno/minimock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/insolar/insolar/configuration"
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/core/message"
+	"github.com/insolar/insolar/core/reply"
+	"github.com/insolar/insolar/instrumentation/inslogger"
+	"github.com/insolar/insolar/testutils"
+	"github.com/insolar/insolar/testutils/network"
+)
+
+func TestLogicRunner_ExecutionQueues(t *testing.T) {
+	ctx := inslogger.TestContext(t)
+	mc := minimock.NewController(t)
+	defer mc.Finish()
+
+	am := testutils.NewArtifactManagerMock(mc)
+	mb := testutils.NewMessageBusMock(mc)
+	nn := network.NewNodeNetworkMock(mc)
+	origin := network.NewNodeMock(mc)
+	me := testutils.RandomRef()
+	origin.IDMock.Return(me)
+	nn.GetOriginMock.Return(origin)
+
+	lr, _ := NewLogicRunner(&configuration.LogicRunner{})
+	lr.ArtifactManager = am
+	lr.MessageBus = mb
+	lr.NodeNetwork = nn
+
+	object := testutils.RandomRef()
+	prototype := testutils.RandomRef()
+	caller := testutils.RandomRef()
+	sender := testutils.RandomRef()
+	element := func(method string, mode message.MethodReturnMode) ExecutionQueueElement {
+		request := testutils.RandomRef()
+		return ExecutionQueueElement{
+			parcel: &message.Parcel{
+				Sender: sender,
+				Msg: &message.CallMethod{
+					BaseLogicMessage: message.BaseLogicMessage{Caller: caller, Sequence: 7},
+					ObjectRef:        object,
+					Method:           method,
+					ReturnMode:       mode,
+				},
+			},
+			request: &request,
+			queued:  time.Now().Add(-time.Minute),
+		}
+	}
+
+	current := testutils.RandomRef()
+	queued := []ExecutionQueueElement{
+		element("First", message.ReturnNoWait),
+		element("Second", message.ReturnResult),
+	}
+	es := &ExecutionState{
+		objectbody: &ObjectBody{Prototype: &prototype},
+		Current: &CurrentExecution{
+			Request: &current,
+			Method:  "Current",
+			Started: time.Now(),
+		},
+		Queue: queued,
+	}
+	lr.UpsertObjectState(object).ExecutionState = es
+	lr.UpsertObjectState(testutils.RandomRef()).ExecutionState = &ExecutionState{}
+
+	queues := lr.ExecutionQueues()
+	require.Len(t, queues, 1)
+	assert.Equal(t, object, queues[0].Object)
+	assert.Equal(t, &prototype, queues[0].Prototype)
+	assert.Equal(t, "Current", queues[0].Current.Method)
+	require.Len(t, queues[0].Queue, 2)
+	assert.Equal(t, "First", queues[0].Queue[0].Method)
+	assert.Equal(t, caller, queues[0].Queue[0].Caller)
+	assert.True(t, queues[0].Queue[0].Age >= time.Minute)
+
+	lr.recordQueueDepth(ctx)
+	assert.True(t, lr.queueDepth.recorded[prototype.String()])
+
+	err := lr.CancelQueuedRequest(ctx, object, current, "")
+	require.Error(t, err)
+	err = lr.CancelQueuedRequest(ctx, object, testutils.RandomRef(), "")
+	require.Error(t, err)
+
+	am.RegisterResultMock.Set(func(p context.Context, obj, req core.RecordRef, payload []byte) (*core.RecordID, error) {
+		assert.Equal(t, object, obj)
+		assert.Equal(t, *queued[1].request, req)
+		assert.NotEmpty(t, payload)
+		return &core.RecordID{}, nil
+	})
+	mb.SendMock.Set(func(p context.Context, m core.Message, o *core.MessageSendOptions) (core.Reply, error) {
+		results := m.(*message.ReturnResults)
+		assert.Equal(t, sender, *o.Receiver)
+		assert.Equal(t, me, results.Caller)
+		assert.Equal(t, uint64(7), results.Sequence)
+		assert.Equal(t, "request canceled: stuck", results.Error)
+		return &reply.OK{}, nil
+	})
+	err = lr.CancelQueuedRequest(ctx, object, *queued[1].request, "stuck")
+	require.NoError(t, err)
+	require.Len(t, es.Queue, 1)
+	assert.Equal(t, queued[0].request, es.Queue[0].request)
+
+	// caller doesn't wait for result of the call
+	am.RegisterResultMock.Set(func(p context.Context, obj, req core.RecordRef, payload []byte) (*core.RecordID, error) {
+		assert.Equal(t, *queued[0].request, req)
+		return &core.RecordID{}, nil
+	})
+	err = lr.CancelQueuedRequest(ctx, object, *queued[0].request, "")
+	require.NoError(t, err)
+	assert.Empty(t, es.Queue)
+	assert.Equal(t, uint64(1), mb.SendCounter)
+
+	// drained queue is recorded as empty
+	lr.recordQueueDepth(ctx)
+	assert.False(t, lr.queueDepth.recorded[prototype.String()])
+}
+
+func TestLogicRunner_HandleCancelQueuedRequestMessage(t *testing.T) {
+	ctx := inslogger.TestContext(t)
+	mc := minimock.NewController(t)
+	defer mc.Finish()
+
+	object := testutils.RandomRef()
+	request := testutils.RandomRef()
+	me := testutils.RandomRef()
+
+	am := testutils.NewArtifactManagerMock(mc)
+	am.RegisterResultMock.Return(&core.RecordID{}, nil)
+	jc := testutils.NewJetCoordinatorMock(mc)
+	jc.IsAuthorizedFunc = func(p context.Context, role core.DynamicRole, obj core.RecordID, pulse core.PulseNumber, node core.RecordRef) (bool, error) {
+		assert.Equal(t, core.DynamicRoleVirtualExecutor, role)
+		return obj == *object.Record() && node == me, nil
+	}
+
+	lr, _ := NewLogicRunner(&configuration.LogicRunner{})
+	lr.ArtifactManager = am
+	lr.JetCoordinator = jc
+	es := &ExecutionState{
+		Queue: []ExecutionQueueElement{{
+			parcel:  &message.Parcel{Msg: &message.CallMethod{ObjectRef: object, ReturnMode: message.ReturnNoWait}},
+			request: &request,
+		}},
+	}
+	lr.UpsertObjectState(object).ExecutionState = es
+
+	handle := func(sender core.RecordRef) error {
+		_, err := lr.HandleCancelQueuedRequestMessage(ctx, &message.Parcel{
+			Msg:         &message.CancelQueuedRequest{Object: object, Request: request},
+			Sender:      sender,
+			PulseNumber: core.FirstPulseNumber,
+		})
+		return err
+	}
+
+	err := handle(testutils.RandomRef())
+	require.Contains(t, err.Error(), "sender isn't executor of object")
+	require.Len(t, es.Queue, 1)
+
+	require.NoError(t, handle(me))
+	assert.Empty(t, es.Queue)
+}
@@ -0,0 +1,363 @@
+/*
+ *    Copyright 2019 Insolar Technologies
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package logicrunner
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/core/message"
+	"github.com/insolar/insolar/core/reply"
+	"github.com/insolar/insolar/instrumentation/inslogger"
+	"github.com/insolar/insolar/instrumentation/instracer"
+)
+
+// Replay of a request made in the past. Heavy gives the request with its result,
+// state of object it was executed on and requests registered while executing it.
+// Method runs again on that state in replay mode of the object, so calls executor
+// makes back to us touch neither current execution nor validation. Calls to other objects
+// are played from the tape of registered requests and their results instead of
+// being sent, nothing is written on ledger.
+//
+// Replay is best effort: contracts reading current data of ledger (children,
+// delegates) or depending on time and entropy of pulse may diverge.
+
+// replayTape answers calls replayed request makes with requests it registered originally
+type replayTape struct {
+	sync.Mutex
+	calls []*replayCall
+	diff  []string
+}
+
+type replayCall struct {
+	ref       Ref
+	msg       core.Message
+	scheduled bool
+	result    []byte
+	finished  bool
+	played    bool
+}
+
+func newReplayTape(children []reply.TracedRequest) (*replayTape, error) {
+	tape := &replayTape{}
+	for _, child := range children {
+		parcel, err := message.DeserializeParcel(bytes.NewBuffer(child.Parcel))
+		if err != nil {
+			return nil, errors.Wrapf(err, "can't deserialize request %s", child.ID)
+		}
+		msg, ok := parcel.Message().(message.IBaseLogicMessage)
+		if !ok {
+			continue
+		}
+
+		call := &replayCall{
+			msg:       msg,
+			scheduled: IsScheduleBucket(child.Object),
+			result:    child.Result,
+			finished:  child.Finished,
+		}
+		if call.scheduled {
+			call.ref = *core.NewRecordRef(core.DomainID, child.ID)
+		} else {
+			call.ref = msg.GetReference()
+			call.ref.SetRecord(child.ID)
+		}
+		tape.calls = append(tape.calls, call)
+	}
+	return tape, nil
+}
+
+func (t *replayTape) Mode() string {
+	return "replay"
+}
+
+func (t *replayTape) Result(reply core.Reply, err error) error {
+	return nil
+}
+
+// play finds a call that isn't played yet and matches
+func (t *replayTape) play(what string, match func(c *replayCall) bool) (*replayCall, error) {
+	t.Lock()
+	defer t.Unlock()
+	for _, c := range t.calls {
+		if !c.played && match(c) {
+			c.played = true
+			return c, nil
+		}
+	}
+	t.diff = append(t.diff, "unexpected "+what)
+	return nil, errors.Errorf("%s wasn't made by original request", what)
+}
+
+// unplayed returns differences for calls original request made and replay didn't
+func (t *replayTape) unplayed() []string {
+	t.Lock()
+	defer t.Unlock()
+	res := t.diff
+	for _, c := range t.calls {
+		if c.played {
+			continue
+		}
+		switch m := c.msg.(type) {
+		case *message.CallMethod:
+			res = append(res, fmt.Sprintf("missing call of %s on %s", m.Method, m.ObjectRef))
+		case *message.CallConstructor:
+			res = append(res, fmt.Sprintf("missing call of constructor %s of %s", m.Method, m.PrototypeRef))
+		}
+	}
+	return res
+}
+
+// SendRequest is not used by executors
+func (t *replayTape) SendRequest(context.Context, *Ref, string, []interface{}) (core.Reply, error) {
+	return nil, errors.New("SendRequest isn't supported in replay")
+}
+
+// CallMethod plays call of method
+func (t *replayTape) CallMethod(
+	ctx context.Context, base core.Message, async bool, ref *Ref, method string, args core.Arguments, mustPrototype *Ref,
+) (core.Reply, error) {
+	what := fmt.Sprintf("call of %s on %s", method, ref)
+	c, err := t.play(what, func(c *replayCall) bool {
+		m, ok := c.msg.(*message.CallMethod)
+		return ok && !c.scheduled && m.ObjectRef.Equal(*ref) && m.Method == method &&
+			bytes.Equal(m.Arguments, args) && (m.ReturnMode == message.ReturnNoWait) == async
+	})
+	if err != nil {
+		return nil, err
+	}
+	if async {
+		return &reply.RegisterRequest{Request: c.ref}, nil
+	}
+	if !c.finished {
+		return nil, errors.Errorf("%s didn't finish originally", what)
+	}
+	return &reply.CallMethod{Request: c.ref, Result: c.result}, nil
+}
+
+// CallConstructor plays call of constructor
+func (t *replayTape) CallConstructor(
+	ctx context.Context, base core.Message, async bool, prototype *Ref, to *Ref, method string, args core.Arguments, saveAs int,
+) (*Ref, error) {
+	what := fmt.Sprintf("call of constructor %s of %s", method, prototype)
+	c, err := t.play(what, func(c *replayCall) bool {
+		m, ok := c.msg.(*message.CallConstructor)
+		return ok && m.PrototypeRef.Equal(*prototype) && m.ParentRef.Equal(*to) && m.Method == method &&
+			bytes.Equal(m.Arguments, args) && m.SaveAs == message.SaveAs(saveAs)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !async && !c.finished {
+		return nil, errors.Errorf("%s didn't finish originally", what)
+	}
+	return &c.ref, nil
+}
+
+// ScheduleCall plays scheduling of call
+func (t *replayTape) ScheduleCall(msg *message.CallMethod) (*Ref, error) {
+	what := fmt.Sprintf("scheduling of %s on %s", msg.Method, msg.ObjectRef)
+	c, err := t.play(what, func(c *replayCall) bool {
+		m, ok := c.msg.(*message.CallMethod)
+		return ok && c.scheduled && m.ObjectRef.Equal(msg.ObjectRef) && m.Method == msg.Method &&
+			bytes.Equal(m.Arguments, msg.Arguments)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &c.ref, nil
+}
+
+// requester returns contract requester calls made in execution go through,
+// calls made while replaying request are played from its tape
+func (lr *LogicRunner) requester(es *ExecutionState) core.ContractRequester {
+	if tape, ok := es.Behaviour.(*replayTape); ok {
+		return tape
+	}
+	return lr.ContractRequester
+}
+
+// getRequestTrace fetches from heavy request with everything needed to replay it
+func (lr *LogicRunner) getRequestTrace(ctx context.Context, request core.RecordID) (*reply.RequestTrace, error) {
+	heavy, err := lr.JetCoordinator.Heavy(ctx, lr.pulse(ctx).PulseNumber)
+	if err != nil {
+		return nil, errors.Wrap(err, "can't calculate heavy")
+	}
+	rep, err := lr.MessageBus.Send(ctx, &message.GetRequestTrace{Request: request}, &core.MessageSendOptions{
+		Receiver: heavy,
+	})
+	if err != nil {
+		return nil, err
+	}
+	switch r := rep.(type) {
+	case *reply.RequestTrace:
+		return r, nil
+	case *reply.Error:
+		return nil, r.Error()
+	}
+	return nil, errors.Errorf("unexpected reply: %#v", rep)
+}
+
+// Replay executes request made in the past again and compares its outcome with the stored one
+func (lr *LogicRunner) Replay(ctx context.Context, request Ref) (*reply.Replay, error) {
+	ctx, span := instracer.StartSpan(ctx, "LogicRunner.Replay")
+	defer span.End()
+
+	trace, err := lr.getRequestTrace(ctx, *request.Record())
+	if err != nil {
+		return nil, errors.Wrap(err, "can't get request from ledger")
+	}
+	parcel, err := message.DeserializeParcel(bytes.NewBuffer(trace.Parcel))
+	if err != nil {
+		return nil, errors.Wrap(err, "can't deserialize request")
+	}
+	msg, ok := parcel.Message().(*message.CallMethod)
+	if !ok {
+		return nil, errors.Errorf("only method calls can be replayed, request is %s", parcel.Message().Type())
+	}
+	if trace.Before == nil {
+		return nil, errors.New("state of object request was executed on isn't found")
+	}
+
+	objDesc, err := lr.ArtifactManager.GetObject(ctx, msg.ObjectRef, trace.Before, false)
+	if err != nil {
+		return nil, errors.Wrap(err, "couldn't get object")
+	}
+	protoRef, err := objDesc.Prototype()
+	if err != nil {
+		return nil, errors.Wrap(err, "couldn't get prototype reference")
+	}
+	protoDesc, codeDesc, err := lr.getDescriptorsByPrototypeRef(ctx, *protoRef)
+	if err != nil {
+		return nil, errors.Wrap(err, "couldn't resolve prototype reference to descriptors")
+	}
+
+	res := &reply.Replay{
+		Object:            msg.ObjectRef,
+		Method:            msg.Method,
+		StoredState:       objDesc.Memory(),
+		StoredDeactivated: trace.Deactivated,
+		StoredResult:      trace.Result,
+	}
+	if trace.After != nil && !trace.Deactivated {
+		after, err := lr.ArtifactManager.GetObject(ctx, msg.ObjectRef, trace.After, false)
+		if err != nil {
+			return nil, errors.Wrap(err, "couldn't get state made by request")
+		}
+		res.StoredState = after.Memory()
+	}
+
+	tape, err := newReplayTape(trace.Children)
+	if err != nil {
+		return nil, err
+	}
+
+	vs := &ExecutionState{
+		ArtifactManager: lr.ArtifactManager,
+		Behaviour:       tape,
+	}
+	os := lr.UpsertObjectState(msg.ObjectRef)
+	if err := os.StartReplay(vs); err != nil {
+		return nil, err
+	}
+	defer os.FinishReplay()
+
+	sender := parcel.GetSender()
+	vs.objectbody = &ObjectBody{
+		objDescriptor:   objDesc,
+		Object:          objDesc.Memory(),
+		Prototype:       protoDesc.HeadRef(),
+		CodeMachineType: codeDesc.MachineType(),
+		CodeRef:         codeDesc.Ref(),
+		Parent:          objDesc.Parent(),
+	}
+	vs.Current = &CurrentExecution{
+		Context:       ctx,
+		Request:       &request,
+		RequesterNode: &sender,
+		ReturnMode:    message.ReturnNoWait,
+		LogicContext: &core.LogicCallContext{
+			Mode:            tape.Mode(),
+			Caller:          msg.GetCaller(),
+			Callee:          &msg.ObjectRef,
+			Request:         &request,
+			Prototype:       vs.objectbody.Prototype,
+			Code:            vs.objectbody.CodeRef,
+			Parent:          vs.objectbody.Parent,
+			Time:            time.Now(),
+			Pulse:           core.Pulse{PulseNumber: request.Record().Pulse()},
+			TraceID:         inslogger.TraceID(ctx),
+			CallerPrototype: msg.GetCallerPrototype(),
+		},
+	}
+
+	executor, err := lr.GetExecutor(vs.objectbody.CodeMachineType)
+	if err != nil {
+		return nil, errors.Wrap(err, "no executor registered")
+	}
+	newData, result, err := executor.CallMethod(
+		ctx, vs.Current.LogicContext, *vs.objectbody.CodeRef, vs.objectbody.Object, msg.Method, msg.Arguments,
+	)
+	if err != nil {
+		res.Error = err.Error()
+	}
+	res.State = newData
+	res.Result = result
+	res.Deactivated = vs.deactivate
+
+	res.Diff = replayDiff(res, trace.Finished)
+	res.Diff = append(res.Diff, tape.unplayed()...)
+	return res, nil
+}
+
+// replayDiff lists differences between outcome of replay and the stored one
+func replayDiff(res *reply.Replay, finished bool) []string {
+	var diff []string
+	if res.Error != "" {
+		return append(diff, "execution failed: "+res.Error)
+	}
+	if !finished {
+		diff = append(diff, "stored result not found")
+	} else if !bytes.Equal(res.Result, res.StoredResult) {
+		diff = append(diff, "result differs")
+	}
+	if res.Deactivated != res.StoredDeactivated {
+		diff = append(diff, fmt.Sprintf("deactivation differs: replay %t, stored %t", res.Deactivated, res.StoredDeactivated))
+	} else if !res.Deactivated && !bytes.Equal(res.State, res.StoredState) {
+		diff = append(diff, "state differs")
+	}
+	return diff
+}
+
+// HandleReplayRequestMessage replays request and replies with comparison of outcomes
+func (lr *LogicRunner) HandleReplayRequestMessage(ctx context.Context, parcel core.Parcel) (core.Reply, error) {
+	msg, ok := parcel.Message().(*message.ReplayRequest)
+	if !ok {
+		return nil, errors.New("HandleReplayRequestMessage( ! message.ReplayRequest )")
+	}
+	res, err := lr.Replay(ctx, msg.Request)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
@@ -0,0 +1,184 @@
+/*
+ *    Copyright 2019 Insolar Technologies
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package logicrunner
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/core/message"
+	"github.com/insolar/insolar/core/reply"
+	"github.com/insolar/insolar/instrumentation/inslogger"
+	"github.com/insolar/insolar/testutils"
+)
+
+func tracedRequest(object core.RecordID, msg core.Message, result []byte) reply.TracedRequest {
+	return reply.TracedRequest{
+		ID:       testutils.RandomID(),
+		Object:   object,
+		Parcel:   message.ParcelToBytes(&message.Parcel{Msg: msg}),
+		Result:   result,
+		Finished: result != nil,
+	}
+}
+
+func TestReplayTape_CallMethod(t *testing.T) {
+	ctx := inslogger.TestContext(t)
+
+	objRef := testutils.RandomRef()
+	call := &message.CallMethod{
+		ObjectRef: objRef,
+		Method:    "Transfer",
+		Arguments: core.Arguments{1, 2, 3},
+	}
+	notify := &message.CallMethod{
+		ObjectRef:  objRef,
+		Method:     "Notify",
+		ReturnMode: message.ReturnNoWait,
+	}
+	children := []reply.TracedRequest{
+		tracedRequest(*objRef.Record(), call, []byte{42}),
+		tracedRequest(*objRef.Record(), notify, nil),
+	}
+	tape, err := newReplayTape(children)
+	require.NoError(t, err)
+
+	rep, err := tape.CallMethod(ctx, nil, false, &objRef, "Transfer", core.Arguments{1, 2, 3}, nil)
+	require.NoError(t, err)
+	require.Equal(t, []byte{42}, rep.(*reply.CallMethod).Result)
+	require.Equal(t, children[0].ID, *rep.(*reply.CallMethod).Request.Record())
+
+	// call is played only once
+	_, err = tape.CallMethod(ctx, nil, false, &objRef, "Transfer", core.Arguments{1, 2, 3}, nil)
+	require.Error(t, err)
+
+	rep, err = tape.CallMethod(ctx, nil, true, &objRef, "Notify", nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, children[1].ID, *rep.(*reply.RegisterRequest).Request.Record())
+
+	assert.Equal(t, []string{"unexpected call of Transfer on " + objRef.String()}, tape.unplayed())
+}
+
+func TestReplayTape_CallConstructorAndSchedule(t *testing.T) {
+	ctx := inslogger.TestContext(t)
+
+	parent := testutils.RandomRef()
+	proto := testutils.RandomRef()
+	ctor := &message.CallConstructor{
+		ParentRef:    parent,
+		PrototypeRef: proto,
+		Method:       "New",
+		SaveAs:       message.Child,
+	}
+	scheduled := &message.CallMethod{
+		ObjectRef: parent,
+		Method:    "Expire",
+	}
+	missed := &message.CallMethod{
+		ObjectRef: testutils.RandomRef(),
+		Method:    "Missed",
+	}
+	children := []reply.TracedRequest{
+		tracedRequest(testutils.RandomID(), ctor, []byte{}),
+		tracedRequest(*ScheduleBucket(core.FirstPulseNumber + 10), scheduled, nil),
+		tracedRequest(*missed.ObjectRef.Record(), missed, nil),
+	}
+	tape, err := newReplayTape(children)
+	require.NoError(t, err)
+
+	_, err = tape.CallConstructor(ctx, nil, false, &proto, &parent, "New", nil, int(message.Delegate))
+	require.Error(t, err)
+	ref, err := tape.CallConstructor(ctx, nil, false, &proto, &parent, "New", nil, int(message.Child))
+	require.NoError(t, err)
+	require.Equal(t, children[0].ID, *ref.Record())
+
+	// scheduled call is not played as regular one
+	_, err = tape.CallMethod(ctx, nil, false, &parent, "Expire", nil, nil)
+	require.Error(t, err)
+	ref, err = tape.ScheduleCall(&message.CallMethod{ObjectRef: parent, Method: "Expire"})
+	require.NoError(t, err)
+	require.Equal(t, *core.NewRecordRef(core.DomainID, children[1].ID), *ref)
+
+	assert.Equal(t, []string{
+		"unexpected call of constructor New of " + proto.String(),
+		"unexpected call of Expire on " + parent.String(),
+		"missing call of Missed on " + missed.ObjectRef.String(),
+	}, tape.unplayed())
+}
+
+func TestReplayDiff(t *testing.T) {
+	table := []struct {
+		name     string
+		res      reply.Replay
+		finished bool
+		diff     []string
+	}{
+		{
+			name:     "same",
+			res:      reply.Replay{State: []byte{1}, StoredState: []byte{1}, Result: []byte{2}, StoredResult: []byte{2}},
+			finished: true,
+		},
+		{
+			name:     "failed",
+			res:      reply.Replay{Error: "panic"},
+			finished: true,
+			diff:     []string{"execution failed: panic"},
+		},
+		{
+			name:     "differs",
+			res:      reply.Replay{State: []byte{1}, StoredState: []byte{2}, Result: []byte{2}, StoredResult: []byte{3}},
+			finished: true,
+			diff:     []string{"result differs", "state differs"},
+		},
+		{
+			name: "not finished",
+			res:  reply.Replay{Deactivated: true},
+			diff: []string{"stored result not found", "deactivation differs: replay true, stored false"},
+		},
+		{
+			name:     "both deactivated",
+			res:      reply.Replay{Deactivated: true, StoredDeactivated: true, State: []byte{1}},
+			finished: true,
+		},
+	}
+
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.diff, replayDiff(&test.res, test.finished))
+		})
+	}
+}
+
+func TestObjectState_Replay(t *testing.T) {
+	os := &ObjectState{}
+	replay := &ExecutionState{Behaviour: &replayTape{}}
+	require.NoError(t, os.StartReplay(replay))
+	require.Error(t, os.StartReplay(&ExecutionState{}))
+
+	// validation goes on while object is replayed
+	validation := os.StartValidation()
+	assert.Equal(t, replay, os.MustModeState("replay"))
+	assert.Equal(t, validation, os.MustModeState("validation"))
+
+	os.FinishReplay()
+	assert.Nil(t, os.Replay)
+	assert.Equal(t, validation, os.Validation)
+	assert.Panics(t, func() { os.MustModeState("replay") })
+}
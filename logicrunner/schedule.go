@@ -0,0 +1,218 @@
+/*
+ *    Copyright 2019 Insolar Technologies
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package logicrunner
+
+import (
+	"bytes"
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/sha3"
+
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/core/message"
+	"github.com/insolar/insolar/core/reply"
+	"github.com/insolar/insolar/instrumentation/inslogger"
+	"github.com/insolar/insolar/instrumentation/instracer"
+)
+
+// Scheduled calls are kept on ledger as requests registered on a schedule bucket,
+// a virtual object every pulse has. Ledger treats them like any other pending
+// requests, so they survive restarts and move between light executors with hot data.
+//
+// Executor of the bucket dispatches its requests on the pulse (or when ledger
+// notifies about abandoned requests of a past bucket) and closes every schedule
+// with a result record holding the dispatched request.
+
+// scheduleBucketHash is a hash part of ids of all schedule buckets
+var scheduleBucketHash = func() []byte {
+	h := sha3.Sum224([]byte("insolar schedule bucket"))
+	return h[:]
+}()
+
+// ScheduleBucket returns id of the object calls scheduled on pulse are registered on
+func ScheduleBucket(pulse core.PulseNumber) *core.RecordID {
+	return core.NewRecordID(pulse, scheduleBucketHash)
+}
+
+// IsScheduleBucket checks if id is an id of a schedule bucket
+func IsScheduleBucket(id core.RecordID) bool {
+	return bytes.Equal(id[core.PulseNumberSize:], scheduleBucketHash)
+}
+
+// Schedules keeps buckets this node dispatches right now,
+// so OnPulse and notifications from ledger don't dispatch same bucket twice
+type Schedules struct {
+	sync.Mutex
+	active map[core.RecordID]bool
+}
+
+// NewSchedules creates empty Schedules
+func NewSchedules() *Schedules {
+	return &Schedules{
+		active: make(map[core.RecordID]bool),
+	}
+}
+
+func (s *Schedules) acquire(bucket core.RecordID) bool {
+	s.Lock()
+	defer s.Unlock()
+	if s.active[bucket] {
+		return false
+	}
+	s.active[bucket] = true
+	return true
+}
+
+func (s *Schedules) release(bucket core.RecordID) {
+	s.Lock()
+	defer s.Unlock()
+	delete(s.active, bucket)
+}
+
+// requestID calculates id of request record ledger makes for parcel
+func (lr *LogicRunner) requestID(parcel core.Parcel) core.RecordID {
+	msgHash := lr.PlatformCryptographyScheme.IntegrityHasher().Hash(message.MustSerializeBytes(parcel.Message()))
+	hash := lr.PlatformCryptographyScheme.ReferenceHasher().Hash(msgHash)
+	return *core.NewRecordID(parcel.Pulse(), hash)
+}
+
+// scheduleKey is an idempotency key of call dispatched by schedule
+func scheduleKey(schedule core.RecordID) string {
+	return "schedule:" + schedule.String()
+}
+
+// ScheduleCall registers on ledger a call that will be sent without waiting
+// for result on pulse, returns reference of the schedule
+func (lr *LogicRunner) ScheduleCall(ctx context.Context, msg *message.CallMethod, pulse core.PulseNumber) (*core.RecordRef, error) {
+	ctx, span := instracer.StartSpan(ctx, "LogicRunner.ScheduleCall")
+	defer span.End()
+
+	current := lr.pulse(ctx)
+	if pulse <= current.PulseNumber {
+		return nil, errors.Errorf("can't schedule call on pulse %d, current pulse is %d", pulse, current.PulseNumber)
+	}
+
+	msg.ReturnMode = message.ReturnNoWait
+	parcel, err := lr.ParcelFactory.Create(ctx, msg, lr.NodeNetwork.GetOrigin().ID(), nil, *current)
+	if err != nil {
+		return nil, errors.Wrap(err, "can't create parcel")
+	}
+
+	bucket := core.NewRecordRef(core.DomainID, *ScheduleBucket(pulse))
+	id, err := lr.ArtifactManager.RegisterRequest(ctx, *bucket, parcel)
+	if err != nil {
+		return nil, errors.Wrap(err, "can't register schedule")
+	}
+	ref := core.NewRecordRef(core.DomainID, *id)
+
+	// pulse changed while we were registering, schedule couldn't be closed
+	// by dispatcher, so we close it right away
+	if *id != lr.requestID(parcel) {
+		_, err := lr.ArtifactManager.RegisterResult(ctx, *bucket, *ref, nil)
+		if err != nil {
+			inslogger.FromContext(ctx).Error(errors.Wrap(err, "can't cancel schedule"))
+		}
+		return nil, errors.New("pulse changed while scheduling call, try again")
+	}
+
+	return ref, nil
+}
+
+// dispatchSchedules sends calls scheduled in the bucket to their objects,
+// every schedule is closed after dispatch. If node fails between sending a call
+// and closing its schedule, call is sent again by the next executor, schedule id
+// is the idempotency key of the call, so object registers it only once.
+func (lr *LogicRunner) dispatchSchedules(ctx context.Context, bucket core.RecordID) {
+	if !lr.schedules.acquire(bucket) {
+		return
+	}
+	defer lr.schedules.release(bucket)
+
+	ctx, span := instracer.StartSpan(ctx, "LogicRunner.dispatchSchedules")
+	defer span.End()
+
+	logger := inslogger.FromContext(ctx)
+	bucketRef := core.NewRecordRef(core.DomainID, bucket)
+	dispatched := make(map[core.RecordID]bool)
+	for {
+		parcel, err := lr.ArtifactManager.GetPendingRequest(ctx, bucket)
+		if err == core.ErrNoPendingRequest {
+			return
+		}
+		if err != nil {
+			logger.Error(errors.Wrap(err, "can't get scheduled call"))
+			return
+		}
+
+		id := lr.requestID(parcel)
+		if dispatched[id] {
+			logger.Errorf("scheduled call %s is still pending after dispatch", id)
+			return
+		}
+		dispatched[id] = true
+
+		var result []byte
+		msg, ok := parcel.Message().(*message.CallMethod)
+		if !ok {
+			logger.Errorf("unexpected message %T in schedule %s", parcel.Message(), id)
+		} else {
+			call := *msg
+			if call.IdempotencyKey == "" {
+				call.IdempotencyKey = scheduleKey(id)
+			}
+			rep, err := lr.MessageBus.Send(ctx, &call, nil)
+			if err != nil {
+				// call could be sent before, object has it registered with the key then
+				sent, findErr := lr.ArtifactManager.GetIdempotentRequest(ctx, call.ObjectRef, call.IdempotencyKey)
+				if findErr != nil || sent == nil {
+					logger.Error(errors.Wrapf(err, "can't dispatch scheduled call %s", id))
+					return
+				}
+				result = sent.Request[:]
+			} else if r, ok := rep.(*reply.RegisterRequest); ok {
+				result = r.Request[:]
+			}
+		}
+
+		_, err = lr.ArtifactManager.RegisterResult(ctx, *bucketRef, *core.NewRecordRef(core.DomainID, id), result)
+		if err != nil {
+			logger.Error(errors.Wrapf(err, "can't close schedule %s", id))
+			return
+		}
+	}
+}
+
+// dispatchSchedulesOnPulse starts dispatch of the bucket of new pulse if we are its executor,
+// first pulse is set before components are injected, nothing is dispatched then
+func (lr *LogicRunner) dispatchSchedulesOnPulse(ctx context.Context, pulse core.Pulse) {
+	if lr.JetCoordinator == nil || lr.ArtifactManager == nil || lr.MessageBus == nil {
+		return
+	}
+	bucket := ScheduleBucket(pulse.PulseNumber)
+	meExecutor, err := lr.JetCoordinator.IsAuthorized(
+		ctx, core.DynamicRoleVirtualExecutor, *bucket, pulse.PulseNumber, lr.JetCoordinator.Me(),
+	)
+	if err != nil {
+		inslogger.FromContext(ctx).Error(errors.Wrap(err, "authorization failed for schedule bucket"))
+		return
+	}
+	if meExecutor {
+		go lr.dispatchSchedules(ctx, *bucket)
+	}
+}
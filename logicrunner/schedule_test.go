@@ -0,0 +1,156 @@
+/*
+ *    Copyright 2019 Insolar Technologies
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package logicrunner
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gojuno/minimock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/insolar/insolar/configuration"
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/core/message"
+	"github.com/insolar/insolar/core/reply"
+	"github.com/insolar/insolar/instrumentation/inslogger"
+	"github.com/insolar/insolar/platformpolicy"
+	"github.com/insolar/insolar/testutils"
+)
+
+func TestScheduleBucket(t *testing.T) {
+	pulse := core.PulseNumber(core.FirstPulseNumber + 100)
+	bucket := ScheduleBucket(pulse)
+
+	assert.Equal(t, pulse, bucket.Pulse())
+	assert.True(t, IsScheduleBucket(*bucket))
+	next := pulse + 1
+	assert.NotEqual(t, *bucket, *ScheduleBucket(next))
+	assert.False(t, IsScheduleBucket(testutils.RandomID()))
+}
+
+func TestLogicRunner_ScheduleCall_PastPulse(t *testing.T) {
+	ctx := inslogger.TestContext(t)
+	mc := minimock.NewController(t)
+	defer mc.Finish()
+
+	ps := testutils.NewPulseStorageMock(mc)
+	ps.CurrentMock.Return(&core.Pulse{PulseNumber: core.FirstPulseNumber + 10}, nil)
+
+	lr, _ := NewLogicRunner(&configuration.LogicRunner{})
+	lr.PulseStorage = ps
+
+	_, err := lr.ScheduleCall(ctx, &message.CallMethod{}, core.FirstPulseNumber+10)
+	require.Error(t, err)
+}
+
+func TestLogicRunner_dispatchSchedules(t *testing.T) {
+	ctx := inslogger.TestContext(t)
+	mc := minimock.NewController(t)
+	defer mc.Finish()
+
+	am := testutils.NewArtifactManagerMock(mc)
+	mb := testutils.NewMessageBusMock(mc)
+
+	lr, _ := NewLogicRunner(&configuration.LogicRunner{})
+	lr.ArtifactManager = am
+	lr.MessageBus = mb
+	lr.PlatformCryptographyScheme = platformpolicy.NewPlatformCryptographyScheme()
+
+	bucket := *ScheduleBucket(core.FirstPulseNumber + 10)
+	msg := &message.CallMethod{
+		BaseLogicMessage: message.BaseLogicMessage{Caller: testutils.RandomRef(), Nonce: 1},
+		ReturnMode:       message.ReturnNoWait,
+		ObjectRef:        testutils.RandomRef(),
+		Method:           "GetExpiredBalance",
+	}
+	parcel := &message.Parcel{Msg: msg, PulseNumber: core.FirstPulseNumber}
+
+	var pending []core.Parcel
+	am.GetPendingRequestMock.Set(func(p context.Context, id core.RecordID) (core.Parcel, error) {
+		require.Equal(t, bucket, id)
+		if len(pending) == 0 {
+			return nil, core.ErrNoPendingRequest
+		}
+		return pending[0], nil
+	})
+
+	request := testutils.RandomRef()
+	mb.SendMock.Set(func(p context.Context, m core.Message, o *core.MessageSendOptions) (core.Reply, error) {
+		call := m.(*message.CallMethod)
+		require.Equal(t, msg.ObjectRef, call.ObjectRef)
+		require.Equal(t, msg.Method, call.Method)
+		require.Equal(t, scheduleKey(lr.requestID(parcel)), call.IdempotencyKey)
+		return &reply.RegisterRequest{Request: request}, nil
+	})
+
+	am.RegisterResultMock.Set(func(p context.Context, object, req core.RecordRef, payload []byte) (*core.RecordID, error) {
+		require.Equal(t, bucket, *object.Record())
+		require.Equal(t, lr.requestID(parcel), *req.Record())
+		require.Equal(t, request[:], payload)
+		pending = pending[1:]
+		return &core.RecordID{}, nil
+	})
+
+	pending = []core.Parcel{parcel}
+	lr.dispatchSchedules(ctx, bucket)
+	require.Equal(t, uint64(1), mb.SendCounter)
+	require.Empty(t, pending)
+
+	// schedule that ledger still reports after it's closed isn't dispatched twice
+	am.RegisterResultMock.Set(func(p context.Context, object, req core.RecordRef, payload []byte) (*core.RecordID, error) {
+		return &core.RecordID{}, nil
+	})
+	pending = []core.Parcel{parcel}
+	lr.dispatchSchedules(ctx, bucket)
+	require.Equal(t, uint64(2), mb.SendCounter)
+
+	// call sent before by failed executor is found by schedule key and isn't sent again
+	mb.SendMock.Set(func(p context.Context, m core.Message, o *core.MessageSendOptions) (core.Reply, error) {
+		return nil, errors.New("request with the same idempotency key is already registered")
+	})
+	am.GetIdempotentRequestMock.Set(func(p context.Context, object core.RecordRef, key string) (*core.IdempotentRequest, error) {
+		require.Equal(t, msg.ObjectRef, object)
+		require.Equal(t, scheduleKey(lr.requestID(parcel)), key)
+		return &core.IdempotentRequest{Request: request}, nil
+	})
+	am.RegisterResultMock.Set(func(p context.Context, object, req core.RecordRef, payload []byte) (*core.RecordID, error) {
+		require.Equal(t, request[:], payload)
+		pending = pending[1:]
+		return &core.RecordID{}, nil
+	})
+	pending = []core.Parcel{parcel}
+	lr.dispatchSchedules(ctx, bucket)
+	require.Empty(t, pending)
+
+	// bucket that is being dispatched is skipped
+	require.True(t, lr.schedules.acquire(bucket))
+	lr.dispatchSchedules(ctx, bucket)
+	require.Equal(t, uint64(3), mb.SendCounter)
+}
+
+func TestLogicRunner_OnPulse_NotInjected(t *testing.T) {
+	ctx := inslogger.TestContext(t)
+
+	lr, err := NewLogicRunner(&configuration.LogicRunner{})
+	require.NoError(t, err)
+
+	err = lr.OnPulse(ctx, core.Pulse{PulseNumber: core.FirstPulseNumber})
+	require.NoError(t, err)
+}
@@ -98,11 +98,12 @@ func (gpr *RPC) GetCode(req rpctypes.UpGetCodeReq, reply *rpctypes.UpGetCodeResp
 	if err != nil {
 		return err
 	}
-	reply.Code, err = codeDescriptor.Code()
-	if err != nil {
-		return err
+	reply.Hash = codeDescriptor.Hash()
+	if req.HashOnly {
+		return nil
 	}
-	return nil
+	reply.Code, err = codeDescriptor.Code()
+	return err
 }
 
 // MakeBaseMessage makes base of logicrunner event from base of up request
@@ -125,7 +126,12 @@ func (gpr *RPC) RouteCall(req rpctypes.UpRouteReq, rep *rpctypes.UpRouteResp) (e
 	ctx := es.Current.Context
 
 	bm := MakeBaseMessage(req.UpBaseReq, es)
-	res, err := gpr.lr.ContractRequester.CallMethod(ctx,
+	if req.Wait {
+		// calls without waiting for result aren't part of transaction
+		bm.Transaction = es.Current.Transaction
+		bm.Coordinator = es.Current.Coordinator
+	}
+	res, err := gpr.lr.requester(es).CallMethod(ctx,
 		&bm,
 		!req.Wait,
 		&req.Object,
@@ -133,12 +139,18 @@ func (gpr *RPC) RouteCall(req rpctypes.UpRouteReq, rep *rpctypes.UpRouteResp) (e
 		req.Arguments,
 		&req.ProxyPrototype,
 	)
+	if req.Wait {
+		es.joinTransaction(res, err)
+	}
 	if err != nil {
 		return err
 	}
 
 	if req.Wait {
 		rep.Result = res.(*reply.CallMethod).Result
+		es.addChild(res.(*reply.CallMethod).Request)
+	} else if r, ok := res.(*reply.RegisterRequest); ok {
+		es.addChild(r.Request)
 	}
 
 	return nil
@@ -153,7 +165,10 @@ func (gpr *RPC) SaveAsChild(req rpctypes.UpSaveAsChildReq, rep *rpctypes.UpSaveA
 	ctx := es.Current.Context
 
 	bm := MakeBaseMessage(req.UpBaseReq, es)
-	ref, err := gpr.lr.ContractRequester.CallConstructor(ctx, &bm, false, &req.Prototype, &req.Parent, req.ConstructorName, req.ArgsSerialized, int(message.Child))
+	ref, err := gpr.lr.requester(es).CallConstructor(ctx, &bm, false, &req.Prototype, &req.Parent, req.ConstructorName, req.ArgsSerialized, int(message.Child))
+	if ref != nil {
+		es.addChild(*ref)
+	}
 
 	rep.Reference = ref
 
@@ -169,7 +184,10 @@ func (gpr *RPC) SaveAsDelegate(req rpctypes.UpSaveAsDelegateReq, rep *rpctypes.U
 	ctx := es.Current.Context
 
 	bm := MakeBaseMessage(req.UpBaseReq, es)
-	ref, err := gpr.lr.ContractRequester.CallConstructor(ctx, &bm, false, &req.Prototype, &req.Into, req.ConstructorName, req.ArgsSerialized, int(message.Delegate))
+	ref, err := gpr.lr.requester(es).CallConstructor(ctx, &bm, false, &req.Prototype, &req.Into, req.ConstructorName, req.ArgsSerialized, int(message.Delegate))
+	if ref != nil {
+		es.addChild(*ref)
+	}
 
 	rep.Reference = ref
 	return err
@@ -286,6 +304,64 @@ func (gpr *RPC) DeactivateObject(req rpctypes.UpDeactivateObjectReq, rep *rpctyp
 	return nil
 }
 
+// ScheduleCall is an RPC registering a call that is made on a future pulse
+func (gpr *RPC) ScheduleCall(req rpctypes.UpScheduleCallReq, rep *rpctypes.UpScheduleCallResp) (err error) {
+	defer recoverRPC(&err)
+
+	os := gpr.lr.MustObjectState(req.Callee)
+	es := os.MustModeState(req.Mode)
+	ctx := es.Current.Context
+
+	msg := &message.CallMethod{
+		BaseLogicMessage: MakeBaseMessage(req.UpBaseReq, es),
+		ObjectRef:        req.Object,
+		Method:           req.Method,
+		Arguments:        req.Arguments,
+	}
+	var ref *core.RecordRef
+	if tape, ok := es.Behaviour.(*replayTape); ok {
+		ref, err = tape.ScheduleCall(msg)
+	} else {
+		ref, err = gpr.lr.ScheduleCall(ctx, msg, req.Pulse)
+	}
+	if err != nil {
+		return err
+	}
+
+	rep.Schedule = *ref
+	return nil
+}
+
+// GetChunk is an RPC fetching memory of a chunk of the contract
+func (gpr *RPC) GetChunk(req rpctypes.UpGetChunkReq, rep *rpctypes.UpGetChunkResp) (err error) {
+	defer recoverRPC(&err)
+
+	os := gpr.lr.MustObjectState(req.Callee)
+	es := os.MustModeState(req.Mode)
+	ctx := es.Current.Context
+
+	rep.Data, err = gpr.lr.GetChunk(ctx, req.Chunk, req.State)
+	return err
+}
+
+// SaveChunk is an RPC saving memory of a chunk of the contract
+func (gpr *RPC) SaveChunk(req rpctypes.UpSaveChunkReq, rep *rpctypes.UpSaveChunkResp) (err error) {
+	defer recoverRPC(&err)
+
+	os := gpr.lr.MustObjectState(req.Callee)
+	es := os.MustModeState(req.Mode)
+	ctx := es.Current.Context
+
+	chunk, state, err := gpr.lr.SaveChunk(ctx, es, req.Chunk, req.Data)
+	if err != nil {
+		return err
+	}
+
+	rep.Chunk = *chunk
+	rep.State = *state
+	return nil
+}
+
 // atomicLoadAndIncrementUint64 performs CAS loop, increments counter and returns old value.
 func atomicLoadAndIncrementUint64(addr *uint64) uint64 {
 	for {
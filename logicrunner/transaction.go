@@ -0,0 +1,488 @@
+/*
+ *    Copyright 2019 Insolar Technologies
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package logicrunner
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/sha3"
+
+	"github.com/insolar/insolar/configuration"
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/core/message"
+	"github.com/insolar/insolar/core/reply"
+	"github.com/insolar/insolar/instrumentation/inslogger"
+	"github.com/insolar/insolar/instrumentation/instracer"
+	"github.com/insolar/insolar/logicrunner/goplugin/foundation"
+)
+
+// Transaction groups changes of objects made by a call of method marked with
+// INSATTR_<Method>_Transactional and by all calls it waits for. Reference of
+// the root request is the reference of transaction.
+//
+// Object called in transaction doesn't update its state on ledger, it keeps the
+// new state staged and doesn't process calls from outside of the transaction until
+// decision. Reply of the call lists objects with staged changes, so executor of the
+// root call (coordinator) knows all participants when the root call finishes.
+//
+// Coordinator commits if the root call returned no error, none of waited calls failed
+// and the root call took less than half of the timeout. Decision is stored on ledger
+// in transaction bucket and then sent to participants, which check it against the record,
+// update their objects on commit and drop staged changes on abort. Participant that
+// doesn't get decision within the timeout reads it from ledger, and aborts its changes
+// if there is no decision yet. Results of staged calls are registered on decision,
+// successful results of aborted calls are replaced with error.
+//
+// Calls that don't wait for result and constructors aren't part of transaction.
+
+// transactionBucketHash is a hash part of ids of all transaction buckets
+var transactionBucketHash = func() []byte {
+	h := sha3.Sum224([]byte("insolar transaction bucket"))
+	return h[:]
+}()
+
+// transactionBucketPrefix is a part of hash that tells transaction bucket from other objects,
+// the rest of hash is taken from hash of transaction
+const transactionBucketPrefix = 8
+
+// TransactionBucket returns id of the object decision on transaction is registered on,
+// bucket is on the pulse of the request that started transaction
+func TransactionBucket(tx core.RecordRef) *core.RecordID {
+	h := sha3.Sum224(tx[:])
+	hash := append(transactionBucketHash[:transactionBucketPrefix:transactionBucketPrefix], h[transactionBucketPrefix:]...)
+	return core.NewRecordID(tx.Record().Pulse(), hash)
+}
+
+// IsTransactionBucket checks if id is an id of a transaction bucket
+func IsTransactionBucket(id core.RecordID) bool {
+	return bytes.Equal(id[core.PulseNumberSize:core.PulseNumberSize+transactionBucketPrefix], transactionBucketHash[:transactionBucketPrefix])
+}
+
+// stagedTransaction is a transaction object has changes staged in
+type stagedTransaction struct {
+	transaction Ref
+	coordinator Ref
+	request     Ref // last request of the transaction on the object
+	results     []stagedResult
+	timer       *time.Timer
+	decided     bool
+}
+
+// stagedResult is a result of call made in transaction, it's registered on decision
+type stagedResult struct {
+	request Ref
+	result  []byte
+}
+
+func (lr *LogicRunner) transactionTimeout() time.Duration {
+	if lr.Cfg.TransactionTimeout > 0 {
+		return lr.Cfg.TransactionTimeout
+	}
+	return configuration.NewLogicRunner().TransactionTimeout
+}
+
+// popTransactionCall takes from the queue next call of staged transaction, must be called under es.Lock
+func (es *ExecutionState) popTransactionCall() (ExecutionQueueElement, bool) {
+	for i, qe := range es.Queue {
+		msg, ok := qe.parcel.Message().(message.IBaseLogicMessage)
+		if ok && msg.GetBaseLogicMessage().Transaction.Equal(es.transaction.transaction) {
+			es.Queue = append(es.Queue[:i:i], es.Queue[i+1:]...)
+			return qe, true
+		}
+	}
+	return ExecutionQueueElement{}, false
+}
+
+// joinTransaction remembers outcome of a call current execution made in transaction
+func (es *ExecutionState) joinTransaction(rep core.Reply, err error) {
+	es.Lock()
+	defer es.Unlock()
+	if es.Current == nil || es.Current.Transaction.IsEmpty() {
+		return
+	}
+	if err != nil {
+		es.Current.TransactionFailed = true
+		return
+	}
+	if r, ok := rep.(*reply.CallMethod); ok {
+		es.Current.Participants = append(es.Current.Participants, r.Participants...)
+	}
+}
+
+// stageTransaction makes object wait for decision on transaction, changes of the object
+// are kept in its body and written on ledger on commit together with results of calls
+func (lr *LogicRunner) stageTransaction(
+	ctx context.Context, es *ExecutionState, object Ref, tx Ref, coordinator Ref, request Ref, result []byte,
+) {
+	es.Lock()
+	defer es.Unlock()
+
+	if es.transaction == nil {
+		es.transaction = &stagedTransaction{transaction: tx, coordinator: coordinator}
+		es.transaction.timer = time.AfterFunc(lr.transactionTimeout(), func() {
+			lr.timeoutTransaction(ctx, es, object, tx)
+		})
+	}
+	es.transaction.request = request
+	es.transaction.results = append(es.transaction.results, stagedResult{request: request, result: result})
+}
+
+// timeoutTransaction applies decision recorded on ledger if coordinator didn't send it in time,
+// changes are aborted if there is no decision
+func (lr *LogicRunner) timeoutTransaction(ctx context.Context, es *ExecutionState, object Ref, tx Ref) {
+	logger := inslogger.FromContext(ctx)
+	commit := false
+	decision, err := lr.recordedDecision(ctx, tx)
+	if err == nil && decision != nil {
+		err = es.checkDecision(object, decision)
+	}
+	if err != nil {
+		logger.Error(errors.Wrapf(err, "couldn't read decision on transaction %s", tx))
+	} else if decision != nil {
+		commit = decision.Commit
+	}
+
+	if lr.applyTransaction(ctx, es, object, tx, commit) != nil {
+		// decision was applied already
+		return
+	}
+	if !commit {
+		logger.Warnf("transaction %s timed out, changes of object %s aborted", tx, object)
+	}
+}
+
+// decideStaged commits or aborts changes of object staged in transaction and registers
+// results of staged calls, returns false if the transaction isn't staged on the object
+func (lr *LogicRunner) decideStaged(ctx context.Context, es *ExecutionState, object Ref, tx Ref, commit bool) (bool, error) {
+	es.Lock()
+	staged := es.transaction
+	if staged == nil || staged.decided || !staged.transaction.Equal(tx) {
+		es.Unlock()
+		return false, nil
+	}
+	staged.decided = true
+	staged.timer.Stop()
+	body := es.objectbody
+	deactivate := es.deactivate
+	es.Unlock()
+
+	var err error
+	if commit {
+		err = lr.commitStaged(ctx, body, staged.request, deactivate)
+	}
+	committed := commit && err == nil
+	for _, r := range staged.results {
+		resErr := lr.registerTransactionResult(ctx, object, r.request, r.result, committed)
+		if resErr != nil && err == nil {
+			err = resErr
+		}
+	}
+
+	es.Lock()
+	defer es.Unlock()
+	es.transaction = nil
+	if !commit || err != nil {
+		// next call reads state from ledger
+		es.objectbody = nil
+		es.deactivate = false
+	}
+	return true, err
+}
+
+func (lr *LogicRunner) commitStaged(ctx context.Context, body *ObjectBody, request Ref, deactivate bool) error {
+	if body == nil {
+		return errors.New("staged state of object is lost")
+	}
+
+	am := lr.ArtifactManager
+	if deactivate {
+		_, err := am.DeactivateObject(ctx, Ref{}, request, body.objDescriptor)
+		return errors.Wrap(err, "couldn't deactivate object")
+	}
+	if bytes.Equal(body.objDescriptor.Memory(), body.Object) {
+		return nil
+	}
+	od, err := am.UpdateObject(ctx, Ref{}, request, body.objDescriptor, body.Object)
+	if err != nil {
+		return errors.Wrap(err, "couldn't update object")
+	}
+	body.objDescriptor = od
+	return nil
+}
+
+// registerTransactionResult registers result of call made in transaction, successful result
+// of aborted transaction is replaced with error
+func (lr *LogicRunner) registerTransactionResult(ctx context.Context, object, request Ref, result []byte, committed bool) error {
+	if !committed && !resultHasError(result) {
+		var err error
+		result, err = core.MarshalArgs(nil, &foundation.Error{S: "transaction aborted"})
+		if err != nil {
+			return errors.Wrap(err, "couldn't serialize result")
+		}
+	}
+	_, err := lr.ArtifactManager.RegisterResult(ctx, object, request, result)
+	return errors.Wrapf(err, "couldn't save result of request %s", request)
+}
+
+// applyTransaction decides on changes staged on object and lets the object process other calls
+func (lr *LogicRunner) applyTransaction(ctx context.Context, es *ExecutionState, object Ref, tx Ref, commit bool) error {
+	decided, err := lr.decideStaged(ctx, es, object, tx, commit)
+	if !decided {
+		return errors.Errorf("transaction %s isn't staged on object %s", tx, object)
+	}
+	if err != nil {
+		inslogger.FromContext(ctx).Error(errors.Wrapf(err, "couldn't commit transaction %s on object %s", tx, object))
+	}
+
+	es.Lock()
+	processing := es.QueueProcessorActive
+	es.Unlock()
+	if processing {
+		// processor finishes current call of the transaction and goes on
+		return nil
+	}
+
+	lr.finishPendingIfNeeded(ctx, es, object)
+	startErr := lr.StartQueueProcessorIfNeeded(ctx, es, &message.PendingFinished{Reference: object})
+	if startErr != nil {
+		inslogger.FromContext(ctx).Error(errors.Wrap(startErr, "couldn't start queue processor"))
+	}
+	return nil
+}
+
+// finishTransaction stores decision on transaction on ledger and sends it to participants,
+// returns true if transaction was committed
+func (lr *LogicRunner) finishTransaction(
+	ctx context.Context, es *ExecutionState, tx Ref, self Ref, participants []reply.Participant, commit bool,
+) bool {
+	ctx, span := instracer.StartSpan(ctx, "LogicRunner.finishTransaction")
+	defer span.End()
+	logger := inslogger.FromContext(ctx)
+
+	unique := make([]reply.Participant, 0, len(participants))
+	objects := make([]core.RecordRef, 0, len(participants))
+	seen := make(map[Ref]bool)
+	for _, p := range participants {
+		if seen[p.Object] {
+			continue
+		}
+		seen[p.Object] = true
+		unique = append(unique, p)
+		objects = append(objects, p.Object)
+	}
+
+	if len(unique) == 0 {
+		return commit
+	}
+
+	decision := &message.TransactionDecision{Transaction: tx, Coordinator: self, Commit: commit, Participants: objects}
+	if err := lr.recordTransactionDecision(ctx, decision); err != nil {
+		logger.Error(errors.Wrapf(err, "couldn't record decision on transaction %s", tx))
+		commit = false
+	}
+
+	var wg sync.WaitGroup
+	for _, p := range unique {
+		if p.Object.Equal(self) {
+			if _, err := lr.decideStaged(ctx, es, self, tx, commit); err != nil {
+				logger.Error(errors.Wrapf(err, "couldn't apply transaction %s on object %s", tx, self))
+			}
+			continue
+		}
+
+		wg.Add(1)
+		go func(p reply.Participant) {
+			defer wg.Done()
+			msg := &message.TransactionDecision{Transaction: tx, Coordinator: self, Object: p.Object, Commit: commit}
+			_, err := lr.MessageBus.Send(ctx, msg, &core.MessageSendOptions{Receiver: &p.Node})
+			if err != nil {
+				logger.Error(errors.Wrapf(err, "couldn't send decision on transaction %s to object %s", tx, p.Object))
+			}
+		}(p)
+	}
+	wg.Wait()
+
+	return commit
+}
+
+// recordTransactionDecision stores decision in bucket of the transaction. Record is left open,
+// so participants could read it as pending request of the bucket, bucket executor closes it later.
+func (lr *LogicRunner) recordTransactionDecision(ctx context.Context, decision *message.TransactionDecision) error {
+	current := lr.pulse(ctx)
+	parcel, err := lr.ParcelFactory.Create(ctx, decision, lr.NodeNetwork.GetOrigin().ID(), nil, *current)
+	if err != nil {
+		return errors.Wrap(err, "can't create parcel")
+	}
+
+	bucket := core.NewRecordRef(core.DomainID, *TransactionBucket(decision.Transaction))
+	_, err = lr.ArtifactManager.RegisterRequest(ctx, *bucket, parcel)
+	if err != nil {
+		return errors.Wrap(err, "can't register decision")
+	}
+	return nil
+}
+
+// recordedDecision reads decision on transaction from ledger, nil is returned if there is no decision.
+// Decision must be recorded by executor of coordinator.
+func (lr *LogicRunner) recordedDecision(ctx context.Context, tx Ref) (*message.TransactionDecision, error) {
+	parcel, err := lr.ArtifactManager.GetPendingRequest(ctx, *TransactionBucket(tx))
+	if err == core.ErrNoPendingRequest {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "can't get decision record")
+	}
+
+	decision, ok := parcel.Message().(*message.TransactionDecision)
+	if !ok || !decision.Transaction.Equal(tx) {
+		return nil, errors.Errorf("transaction bucket has unexpected record %T", parcel.Message())
+	}
+	err = lr.checkExecutorSender(ctx, parcel, decision.Coordinator)
+	if err != nil {
+		return nil, errors.Wrap(err, "decision isn't recorded by coordinator")
+	}
+	return decision, nil
+}
+
+// checkDecision checks that decision is made by coordinator of transaction staged on
+// the object and the object takes part in it
+func (es *ExecutionState) checkDecision(object Ref, decision *message.TransactionDecision) error {
+	es.Lock()
+	staged := es.transaction
+	es.Unlock()
+	if staged == nil || !staged.transaction.Equal(decision.Transaction) {
+		return errors.Errorf("transaction %s isn't staged on object %s", decision.Transaction, object)
+	}
+	if !staged.coordinator.Equal(decision.Coordinator) {
+		return errors.Errorf("object %s isn't coordinator of transaction %s", decision.Coordinator, decision.Transaction)
+	}
+	for _, p := range decision.Participants {
+		if p.Equal(object) {
+			return nil
+		}
+	}
+	return errors.Errorf("object %s isn't participant of transaction %s", object, decision.Transaction)
+}
+
+// checkExecutorSender checks that parcel is sent by executor of the object on pulse of the parcel
+func (lr *LogicRunner) checkExecutorSender(ctx context.Context, parcel core.Parcel, object Ref) error {
+	authorized, err := lr.JetCoordinator.IsAuthorized(
+		ctx, core.DynamicRoleVirtualExecutor, *object.Record(), parcel.Pulse(), parcel.GetSender(),
+	)
+	if err != nil {
+		return errors.Wrap(err, "authorization failed with error")
+	}
+	if !authorized {
+		return errors.Errorf("sender isn't executor of object %s", object)
+	}
+	return nil
+}
+
+// closeTransactionDecisions closes decision records left open in transaction bucket,
+// records are kept open until participants time out and could read them
+func (lr *LogicRunner) closeTransactionDecisions(ctx context.Context, bucket core.RecordID) {
+	// pulse numbers are seconds
+	keep := core.PulseNumber(2 * lr.transactionTimeout() / time.Second)
+	if lr.pulse(ctx).PulseNumber < bucket.Pulse()+keep {
+		return
+	}
+
+	logger := inslogger.FromContext(ctx)
+	bucketRef := core.NewRecordRef(core.DomainID, bucket)
+	closed := make(map[core.RecordID]bool)
+	for {
+		parcel, err := lr.ArtifactManager.GetPendingRequest(ctx, bucket)
+		if err == core.ErrNoPendingRequest {
+			return
+		}
+		if err != nil {
+			logger.Error(errors.Wrap(err, "can't get decision record"))
+			return
+		}
+
+		id := lr.requestID(parcel)
+		if closed[id] {
+			logger.Errorf("decision record %s is still open after close", id)
+			return
+		}
+		closed[id] = true
+
+		_, err = lr.ArtifactManager.RegisterResult(ctx, *bucketRef, *core.NewRecordRef(core.DomainID, id), nil)
+		if err != nil {
+			logger.Error(errors.Wrapf(err, "can't close decision record %s", id))
+			return
+		}
+	}
+}
+
+// HandleTransactionDecisionMessage applies decision of coordinator on changes staged by object
+func (lr *LogicRunner) HandleTransactionDecisionMessage(
+	ctx context.Context, parcel core.Parcel,
+) (
+	core.Reply, error,
+) {
+	ctx = loggerWithTargetID(ctx, parcel)
+	msg := parcel.Message().(*message.TransactionDecision)
+
+	if err := lr.checkExecutorSender(ctx, parcel, msg.Coordinator); err != nil {
+		return nil, errors.Wrap(err, "decision isn't sent by coordinator")
+	}
+
+	var es *ExecutionState
+	if os := lr.GetObjectState(msg.Object); os != nil {
+		os.Lock()
+		es = os.ExecutionState
+		os.Unlock()
+	}
+	if es == nil {
+		return nil, errors.Errorf("transaction %s isn't staged on object %s", msg.Transaction, msg.Object)
+	}
+
+	decision, err := lr.recordedDecision(ctx, msg.Transaction)
+	if err != nil {
+		return nil, err
+	}
+	if decision == nil {
+		return nil, errors.Errorf("decision on transaction %s isn't recorded", msg.Transaction)
+	}
+	if decision.Commit != msg.Commit || !decision.Coordinator.Equal(msg.Coordinator) {
+		return nil, errors.Errorf("decision on transaction %s doesn't match the record", msg.Transaction)
+	}
+	if err := es.checkDecision(msg.Object, decision); err != nil {
+		return nil, err
+	}
+
+	err = lr.applyTransaction(ctx, es, msg.Object, msg.Transaction, decision.Commit)
+	if err != nil {
+		return nil, err
+	}
+	return &reply.OK{}, nil
+}
+
+// resultHasError checks if method returned error, results are serialized
+// as a list with error in the end
+func resultHasError(result []byte) bool {
+	var values []interface{}
+	if err := core.Deserialize(result, &values); err != nil || len(values) == 0 {
+		return false
+	}
+	return values[len(values)-1] != nil
+}
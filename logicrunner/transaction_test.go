@@ -0,0 +1,361 @@
+/*
+ *    Copyright 2019 Insolar Technologies
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package logicrunner
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gojuno/minimock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/insolar/insolar/component"
+	"github.com/insolar/insolar/configuration"
+	"github.com/insolar/insolar/contractrequester"
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/core/delegationtoken"
+	"github.com/insolar/insolar/core/message"
+	"github.com/insolar/insolar/core/reply"
+	"github.com/insolar/insolar/instrumentation/inslogger"
+	"github.com/insolar/insolar/logicrunner/goplugin/foundation"
+	"github.com/insolar/insolar/logicrunner/goplugin/rpctypes"
+	"github.com/insolar/insolar/messagebus"
+	"github.com/insolar/insolar/platformpolicy"
+	"github.com/insolar/insolar/testutils"
+	"github.com/insolar/insolar/testutils/network"
+)
+
+func TestTransactionBucket(t *testing.T) {
+	tx := testutils.RandomRef()
+	bucket := TransactionBucket(tx)
+
+	assert.Equal(t, tx.Record().Pulse(), bucket.Pulse())
+	assert.Equal(t, bucket, TransactionBucket(tx))
+	assert.NotEqual(t, bucket, TransactionBucket(testutils.RandomRef()))
+	assert.True(t, IsTransactionBucket(*bucket))
+	assert.False(t, IsScheduleBucket(*bucket))
+	assert.False(t, IsTransactionBucket(*ScheduleBucket(bucket.Pulse())))
+	assert.False(t, IsTransactionBucket(*tx.Record()))
+}
+
+func TestResultHasError(t *testing.T) {
+	ok, err := core.Serialize([]interface{}{1, nil})
+	require.NoError(t, err)
+	assert.False(t, resultHasError(ok))
+
+	failed, err := core.Serialize([]interface{}{nil, &foundation.Error{S: "not enough balance"}})
+	require.NoError(t, err)
+	assert.True(t, resultHasError(failed))
+
+	assert.False(t, resultHasError([]byte("not a list")))
+}
+
+func TestExecutionState_popTransactionCall(t *testing.T) {
+	tx := testutils.RandomRef()
+	call := func(tx core.RecordRef) ExecutionQueueElement {
+		return ExecutionQueueElement{parcel: &message.Parcel{Msg: &message.CallMethod{
+			BaseLogicMessage: message.BaseLogicMessage{Transaction: tx},
+		}}}
+	}
+
+	es := &ExecutionState{
+		Queue:       []ExecutionQueueElement{call(core.RecordRef{}), call(tx), call(testutils.RandomRef())},
+		transaction: &stagedTransaction{transaction: tx},
+	}
+
+	qe, ok := es.popTransactionCall()
+	require.True(t, ok)
+	assert.Equal(t, call(tx), qe)
+	assert.Len(t, es.Queue, 2)
+
+	_, ok = es.popTransactionCall()
+	require.False(t, ok)
+}
+
+func TestLogicRunner_applyTransaction(t *testing.T) {
+	ctx := inslogger.TestContext(t)
+	mc := minimock.NewController(t)
+	defer mc.Finish()
+
+	am := testutils.NewArtifactManagerMock(mc)
+	lr, _ := NewLogicRunner(&configuration.LogicRunner{TransactionTimeout: time.Minute})
+	lr.ArtifactManager = am
+
+	object := testutils.RandomRef()
+	coordinator := testutils.RandomRef()
+	tx := testutils.RandomRef()
+	request := testutils.RandomRef()
+	result, err := core.MarshalArgs(1, nil)
+	require.NoError(t, err)
+	aborted, err := core.MarshalArgs(nil, &foundation.Error{S: "transaction aborted"})
+	require.NoError(t, err)
+
+	desc := testutils.NewObjectDescriptorMock(mc)
+	desc.MemoryMock.Return([]byte("old"))
+	newDesc := testutils.NewObjectDescriptorMock(mc)
+	es := &ExecutionState{objectbody: &ObjectBody{objDescriptor: desc, Object: []byte("new")}}
+
+	// state and results are written on ledger only on decision
+	am.UpdateObjectMock.Set(func(p context.Context, domain, req core.RecordRef, od core.ObjectDescriptor, memory []byte) (core.ObjectDescriptor, error) {
+		require.Equal(t, request, req)
+		require.Equal(t, desc, od)
+		require.Equal(t, []byte("new"), memory)
+		return newDesc, nil
+	})
+	var registered [][]byte
+	am.RegisterResultMock.Set(func(p context.Context, obj, req core.RecordRef, payload []byte) (*core.RecordID, error) {
+		require.Equal(t, object, obj)
+		require.Equal(t, request, req)
+		registered = append(registered, payload)
+		return &core.RecordID{}, nil
+	})
+	lr.stageTransaction(ctx, es, object, tx, coordinator, request, result)
+	require.NotNil(t, es.transaction)
+	require.Empty(t, registered)
+
+	require.Error(t, lr.applyTransaction(ctx, es, object, testutils.RandomRef(), true))
+	require.NoError(t, lr.applyTransaction(ctx, es, object, tx, true))
+	require.Nil(t, es.transaction)
+	require.Equal(t, newDesc, es.objectbody.objDescriptor)
+	require.Equal(t, uint64(1), am.UpdateObjectCounter)
+	require.Equal(t, [][]byte{result}, registered)
+
+	// decision can't be applied twice
+	require.Error(t, lr.applyTransaction(ctx, es, object, tx, false))
+
+	// abort drops staged state and replaces result with error
+	lr.stageTransaction(ctx, es, object, tx, coordinator, request, result)
+	require.NoError(t, lr.applyTransaction(ctx, es, object, tx, false))
+	require.Nil(t, es.objectbody)
+	require.Equal(t, uint64(1), am.UpdateObjectCounter)
+	require.Equal(t, [][]byte{result, aborted}, registered)
+}
+
+// decisionParcel makes record of decision on transaction sent by node
+func decisionParcel(decision *message.TransactionDecision, sender core.RecordRef) core.Parcel {
+	return &message.Parcel{Msg: decision, Sender: sender, PulseNumber: core.FirstPulseNumber}
+}
+
+func waitTransactionDecided(t *testing.T, es *ExecutionState) {
+	for i := 0; i < 100; i++ {
+		es.Lock()
+		decided := es.transaction == nil
+		es.Unlock()
+		if decided {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("transaction isn't decided")
+}
+
+func TestLogicRunner_stageTransaction_Timeout(t *testing.T) {
+	ctx := inslogger.TestContext(t)
+	mc := minimock.NewController(t)
+	defer mc.Finish()
+
+	am := testutils.NewArtifactManagerMock(mc)
+	am.GetPendingRequestMock.Return(nil, core.ErrNoPendingRequest)
+	am.RegisterResultMock.Return(&core.RecordID{}, nil)
+
+	lr, _ := NewLogicRunner(&configuration.LogicRunner{TransactionTimeout: 10 * time.Millisecond})
+	lr.ArtifactManager = am
+	es := &ExecutionState{objectbody: &ObjectBody{Object: []byte("new")}}
+	lr.stageTransaction(ctx, es, testutils.RandomRef(), testutils.RandomRef(), testutils.RandomRef(), testutils.RandomRef(), nil)
+
+	// there is no decision on ledger, changes are aborted
+	waitTransactionDecided(t, es)
+	require.Nil(t, es.objectbody)
+	require.Equal(t, uint64(1), am.RegisterResultCounter)
+}
+
+func TestLogicRunner_stageTransaction_TimeoutRecordedCommit(t *testing.T) {
+	ctx := inslogger.TestContext(t)
+	mc := minimock.NewController(t)
+	defer mc.Finish()
+
+	object := testutils.RandomRef()
+	coordinator := testutils.RandomRef()
+	coordinatorNode := testutils.RandomRef()
+	tx := testutils.RandomRef()
+
+	desc := testutils.NewObjectDescriptorMock(mc)
+	desc.MemoryMock.Return([]byte("old"))
+	am := testutils.NewArtifactManagerMock(mc)
+	am.GetPendingRequestFunc = func(p context.Context, bucket core.RecordID) (core.Parcel, error) {
+		require.Equal(t, *TransactionBucket(tx), bucket)
+		return decisionParcel(&message.TransactionDecision{
+			Transaction: tx, Coordinator: coordinator, Commit: true, Participants: []core.RecordRef{object},
+		}, coordinatorNode), nil
+	}
+	am.UpdateObjectMock.Return(desc, nil)
+	am.RegisterResultMock.Return(&core.RecordID{}, nil)
+	jc := testutils.NewJetCoordinatorMock(mc)
+	jc.IsAuthorizedFunc = func(p context.Context, role core.DynamicRole, obj core.RecordID, pulse core.PulseNumber, node core.RecordRef) (bool, error) {
+		return obj == *coordinator.Record() && node == coordinatorNode, nil
+	}
+
+	lr, _ := NewLogicRunner(&configuration.LogicRunner{TransactionTimeout: 10 * time.Millisecond})
+	lr.ArtifactManager = am
+	lr.JetCoordinator = jc
+	es := &ExecutionState{objectbody: &ObjectBody{objDescriptor: desc, Object: []byte("new")}}
+	lr.stageTransaction(ctx, es, object, tx, coordinator, testutils.RandomRef(), nil)
+
+	// coordinator recorded commit but the message was lost
+	waitTransactionDecided(t, es)
+	require.NotNil(t, es.objectbody)
+	require.Equal(t, uint64(1), am.UpdateObjectCounter)
+}
+
+func TestLogicRunner_HandleTransactionDecisionMessage(t *testing.T) {
+	ctx := inslogger.TestContext(t)
+	mc := minimock.NewController(t)
+	defer mc.Finish()
+
+	object := testutils.RandomRef()
+	coordinator := testutils.RandomRef()
+	coordinatorNode := testutils.RandomRef()
+	tx := testutils.RandomRef()
+
+	recorded := &message.TransactionDecision{
+		Transaction: tx, Coordinator: coordinator, Commit: false, Participants: []core.RecordRef{object},
+	}
+	am := testutils.NewArtifactManagerMock(mc)
+	am.GetPendingRequestFunc = func(p context.Context, bucket core.RecordID) (core.Parcel, error) {
+		return decisionParcel(recorded, coordinatorNode), nil
+	}
+	am.RegisterResultMock.Return(&core.RecordID{}, nil)
+	jc := testutils.NewJetCoordinatorMock(mc)
+	jc.IsAuthorizedFunc = func(p context.Context, role core.DynamicRole, obj core.RecordID, pulse core.PulseNumber, node core.RecordRef) (bool, error) {
+		return obj == *coordinator.Record() && node == coordinatorNode, nil
+	}
+
+	lr, _ := NewLogicRunner(&configuration.LogicRunner{TransactionTimeout: time.Minute})
+	lr.ArtifactManager = am
+	lr.JetCoordinator = jc
+	es := &ExecutionState{objectbody: &ObjectBody{Object: []byte("new")}}
+	lr.state[object] = &ObjectState{ExecutionState: es}
+	lr.stageTransaction(ctx, es, object, tx, coordinator, testutils.RandomRef(), nil)
+
+	handle := func(sender core.RecordRef, commit bool) error {
+		_, err := lr.HandleTransactionDecisionMessage(ctx, decisionParcel(&message.TransactionDecision{
+			Transaction: tx, Coordinator: coordinator, Object: object, Commit: commit,
+		}, sender))
+		return err
+	}
+
+	err := handle(testutils.RandomRef(), false)
+	require.Contains(t, err.Error(), "decision isn't sent by coordinator")
+	err = handle(coordinatorNode, true)
+	require.Contains(t, err.Error(), "doesn't match the record")
+	require.NotNil(t, es.transaction)
+
+	require.NoError(t, handle(coordinatorNode, false))
+	require.Nil(t, es.transaction)
+	require.Nil(t, es.objectbody)
+}
+
+func TestRPC_RouteCall_Transaction(t *testing.T) {
+	ctx := inslogger.TestContext(t)
+	mc := minimock.NewController(t)
+	defer mc.Finish()
+
+	me := testutils.RandomRef()
+	node := network.NewNodeMock(mc)
+	node.IDMock.Return(me)
+	nn := network.NewNodeNetworkMock(mc)
+	nn.GetOriginMock.Return(node)
+
+	ps := testutils.NewPulseStorageMock(mc)
+	ps.CurrentMock.Return(core.GenesisPulse, nil)
+
+	cs := testutils.NewCryptographyServiceMock(mc)
+	cs.SignMock.Return(&core.Signature{}, nil)
+	pf := messagebus.NewParcelFactory()
+	cm := &component.Manager{}
+	cm.Register(platformpolicy.NewPlatformCryptographyScheme())
+	cm.Inject(delegationtoken.NewDelegationTokenFactory(), pf, cs)
+
+	am := testutils.NewArtifactManagerMock(mc)
+	am.RegisterRequestMock.Return(&core.RecordID{}, nil)
+
+	mb := testutils.NewMessageBusMock(mc)
+	cr, err := contractrequester.New()
+	require.NoError(t, err)
+	cr.MessageBus = mb
+
+	lr, _ := NewLogicRunner(&configuration.LogicRunner{TransactionTimeout: time.Minute})
+	lr.ArtifactManager = am
+	lr.MessageBus = mb
+	lr.ContractRequester = cr
+	lr.ParcelFactory = pf
+	lr.NodeNetwork = nn
+	lr.PulseStorage = ps
+
+	self := testutils.RandomRef()
+	callee := testutils.RandomRef()
+	tx := testutils.RandomRef()
+	participant := reply.Participant{Object: callee, Node: testutils.RandomRef()}
+
+	es := &ExecutionState{
+		Behaviour: &ValidationSaver{},
+		Current:   &CurrentExecution{Context: ctx, Transaction: tx, Coordinator: self},
+	}
+	lr.state[self] = &ObjectState{ExecutionState: es}
+
+	var decision *message.TransactionDecision
+	mb.SendMock.Set(func(p context.Context, m core.Message, o *core.MessageSendOptions) (core.Reply, error) {
+		switch msg := m.(type) {
+		case *message.CallMethod:
+			require.Equal(t, tx, msg.Transaction)
+			require.Equal(t, self, msg.Coordinator)
+			go func() {
+				_, err := cr.ReceiveResult(ctx, &message.Parcel{Msg: &message.ReturnResults{
+					Sequence: msg.Sequence,
+					Reply:    &reply.CallMethod{Participants: []reply.Participant{participant}},
+				}})
+				require.NoError(t, err)
+			}()
+			return &reply.RegisterRequest{Request: testutils.RandomRef()}, nil
+		case *message.TransactionDecision:
+			require.Equal(t, participant.Node, *o.Receiver)
+			decision = msg
+			return &reply.OK{}, nil
+		}
+		t.Fatalf("unexpected message %T", m)
+		return nil, nil
+	})
+
+	rpc := &RPC{lr: lr}
+	err = rpc.RouteCall(rpctypes.UpRouteReq{
+		UpBaseReq: rpctypes.UpBaseReq{Mode: "execution", Callee: self},
+		Wait:      true,
+		Object:    callee,
+		Method:    "Transfer",
+	}, &rpctypes.UpRouteResp{})
+	require.NoError(t, err)
+	require.Equal(t, []reply.Participant{participant}, es.Current.Participants)
+	require.False(t, es.Current.TransactionFailed)
+
+	require.True(t, lr.finishTransaction(ctx, es, tx, self, es.Current.Participants, true))
+	require.NotNil(t, decision)
+	require.True(t, decision.Commit)
+	require.Equal(t, callee, decision.Object)
+	require.Equal(t, self, decision.Coordinator)
+}
@@ -0,0 +1,341 @@
+/*
+ *    Copyright 2019 Insolar Technologies
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package wasm
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/instrumentation/inslogger"
+	"github.com/insolar/insolar/logicrunner/goplugin/rpctypes"
+)
+
+// hostModule is a name of module contracts import host functions from
+const hostModule = "insolar"
+
+// hostCallFailed is returned by host functions when call failed, it's -1 as i32,
+// text of error is put into buffer
+const hostCallFailed uint32 = 0xFFFFFFFF
+
+// Upstream is a logic runner side of host API, it's the same service
+// Go plugins call through insgorund (see proxyctx.ProxyHelper)
+type Upstream interface {
+	RouteCall(req rpctypes.UpRouteReq, rep *rpctypes.UpRouteResp) error
+	SaveAsChild(req rpctypes.UpSaveAsChildReq, rep *rpctypes.UpSaveAsChildResp) error
+	SaveAsDelegate(req rpctypes.UpSaveAsDelegateReq, rep *rpctypes.UpSaveAsDelegateResp) error
+	GetDelegate(req rpctypes.UpGetDelegateReq, rep *rpctypes.UpGetDelegateResp) error
+	DeactivateObject(req rpctypes.UpDeactivateObjectReq, rep *rpctypes.UpDeactivateObjectResp) error
+	ScheduleCall(req rpctypes.UpScheduleCallReq, rep *rpctypes.UpScheduleCallResp) error
+}
+
+// call is a state of one contract call shared by host functions.
+//
+// Host functions get and return pointers into memory of contract. Functions that
+// produce data of variable size put it into buffer and return its size, contract
+// allocates memory and copies buffer with read_buffer.
+type call struct {
+	ctx     context.Context
+	callCtx *core.LogicCallContext
+	up      Upstream
+
+	state  []byte
+	result []byte
+	err    []byte
+	buffer []byte
+}
+
+func newCall(ctx context.Context, callCtx *core.LogicCallContext, up Upstream) *call {
+	return &call{ctx: ctx, callCtx: callCtx, up: up}
+}
+
+func (c *call) upBaseReq() rpctypes.UpBaseReq {
+	return rpctypes.UpBaseReq{
+		Mode:      c.callCtx.Mode,
+		Callee:    refOrEmpty(c.callCtx.Callee),
+		Prototype: refOrEmpty(c.callCtx.Prototype),
+		Request:   refOrEmpty(c.callCtx.Request),
+	}
+}
+
+func refOrEmpty(ref *core.RecordRef) core.RecordRef {
+	if ref == nil {
+		return core.RecordRef{}
+	}
+	return *ref
+}
+
+func (c *call) imports() imports {
+	i32 := valueTypeI32
+	fn := func(params []valueType, results []valueType, f func(*instance, []uint64) ([]uint64, error)) hostFunction {
+		return hostFunction{typ: funcType{params: params, results: results}, call: f}
+	}
+	ptrLen := []valueType{i32, i32}
+	ptr := []valueType{i32}
+	status := []valueType{i32}
+
+	return imports{
+		hostModule: {
+			"set_state":   fn(ptrLen, nil, c.setState),
+			"set_result":  fn(ptrLen, nil, c.setResult),
+			"set_error":   fn(ptrLen, nil, c.setError),
+			"read_buffer": fn(ptr, nil, c.readBuffer),
+			"log":         fn(ptrLen, nil, c.log),
+
+			"get_callee":           fn(ptr, nil, c.getRef(func(cc *core.LogicCallContext) *core.RecordRef { return cc.Callee })),
+			"get_caller":           fn(ptr, nil, c.getRef(func(cc *core.LogicCallContext) *core.RecordRef { return cc.Caller })),
+			"get_caller_prototype": fn(ptr, nil, c.getRef(func(cc *core.LogicCallContext) *core.RecordRef { return cc.CallerPrototype })),
+			"get_prototype":        fn(ptr, nil, c.getRef(func(cc *core.LogicCallContext) *core.RecordRef { return cc.Prototype })),
+			"get_parent":           fn(ptr, nil, c.getRef(func(cc *core.LogicCallContext) *core.RecordRef { return cc.Parent })),
+			"get_request":          fn(ptr, nil, c.getRef(func(cc *core.LogicCallContext) *core.RecordRef { return cc.Request })),
+			"get_pulse":            fn(nil, []valueType{valueTypeI64}, c.getPulse),
+
+			// object, proxy prototype, method, arguments, wait
+			"route_call": fn([]valueType{i32, i32, i32, i32, i32, i32, i32}, status, c.routeCall),
+			// parent, prototype, constructor, arguments
+			"save_as_child": fn([]valueType{i32, i32, i32, i32, i32, i32}, status, c.saveAsChild),
+			// object, prototype, constructor, arguments
+			"save_as_delegate": fn([]valueType{i32, i32, i32, i32, i32, i32}, status, c.saveAsDelegate),
+			// object, type of delegate
+			"get_delegate":      fn([]valueType{i32, i32}, status, c.getDelegate),
+			"deactivate_object": fn(nil, status, c.deactivateObject),
+			// object, pulse, method, arguments
+			"schedule_call": fn([]valueType{i32, valueTypeI64, i32, i32, i32, i32}, status, c.scheduleCall),
+		},
+	}
+}
+
+// readRef reads reference from memory of contract
+func readRef(inst *instance, ptr uint64) (core.RecordRef, error) {
+	var ref core.RecordRef
+	data, err := inst.read(uint32(ptr), core.RecordRefSize)
+	if err != nil {
+		return ref, err
+	}
+	copy(ref[:], data)
+	return ref, nil
+}
+
+// done puts result of host call into buffer and returns its size or failure status
+func (c *call) done(data []byte, err error) ([]uint64, error) {
+	if err != nil {
+		c.buffer = []byte(err.Error())
+		return []uint64{uint64(hostCallFailed)}, nil
+	}
+	c.buffer = data
+	return []uint64{uint64(len(data))}, nil
+}
+
+func (c *call) setState(inst *instance, args []uint64) ([]uint64, error) {
+	data, err := inst.read(uint32(args[0]), uint32(args[1]))
+	c.state = data
+	return nil, err
+}
+
+func (c *call) setResult(inst *instance, args []uint64) ([]uint64, error) {
+	data, err := inst.read(uint32(args[0]), uint32(args[1]))
+	c.result = data
+	return nil, err
+}
+
+func (c *call) setError(inst *instance, args []uint64) ([]uint64, error) {
+	data, err := inst.read(uint32(args[0]), uint32(args[1]))
+	c.err = data
+	return nil, err
+}
+
+func (c *call) readBuffer(inst *instance, args []uint64) ([]uint64, error) {
+	return nil, inst.write(uint32(args[0]), c.buffer)
+}
+
+func (c *call) log(inst *instance, args []uint64) ([]uint64, error) {
+	data, err := inst.read(uint32(args[0]), uint32(args[1]))
+	if err != nil {
+		return nil, err
+	}
+	inslogger.FromContext(c.ctx).Debugf("wasm contract %s: %s", refOrEmpty(c.callCtx.Callee), data)
+	return nil, nil
+}
+
+func (c *call) getRef(
+	field func(*core.LogicCallContext) *core.RecordRef,
+) func(*instance, []uint64) ([]uint64, error) {
+	return func(inst *instance, args []uint64) ([]uint64, error) {
+		ref := refOrEmpty(field(c.callCtx))
+		return nil, inst.write(uint32(args[0]), ref[:])
+	}
+}
+
+func (c *call) getPulse(inst *instance, args []uint64) ([]uint64, error) {
+	return []uint64{uint64(c.callCtx.Pulse.PulseNumber)}, nil
+}
+
+func (c *call) routeCall(inst *instance, args []uint64) ([]uint64, error) {
+	object, err := readRef(inst, args[0])
+	if err != nil {
+		return nil, err
+	}
+	proxyPrototype, err := readRef(inst, args[1])
+	if err != nil {
+		return nil, err
+	}
+	method, err := inst.read(uint32(args[2]), uint32(args[3]))
+	if err != nil {
+		return nil, err
+	}
+	arguments, err := inst.read(uint32(args[4]), uint32(args[5]))
+	if err != nil {
+		return nil, err
+	}
+
+	req := rpctypes.UpRouteReq{
+		UpBaseReq:      c.upBaseReq(),
+		Wait:           uint32(args[6]) != 0,
+		Object:         object,
+		Method:         string(method),
+		Arguments:      arguments,
+		ProxyPrototype: proxyPrototype,
+	}
+	res := rpctypes.UpRouteResp{}
+	err = c.up.RouteCall(req, &res)
+	return c.done(res.Result, errors.Wrap(err, "[ RouteCall ] on calling main API"))
+}
+
+func (c *call) saveAsChild(inst *instance, args []uint64) ([]uint64, error) {
+	parent, prototype, constructor, arguments, err := readConstructorArgs(inst, args)
+	if err != nil {
+		return nil, err
+	}
+
+	req := rpctypes.UpSaveAsChildReq{
+		UpBaseReq:       c.upBaseReq(),
+		Parent:          parent,
+		Prototype:       prototype,
+		ConstructorName: constructor,
+		ArgsSerialized:  arguments,
+	}
+	res := rpctypes.UpSaveAsChildResp{}
+	err = c.up.SaveAsChild(req, &res)
+	if err == nil && res.Reference == nil {
+		err = errors.New("no reference returned")
+	}
+	if err != nil {
+		return c.done(nil, errors.Wrap(err, "[ SaveAsChild ] on calling main API"))
+	}
+	return c.done(res.Reference[:], nil)
+}
+
+func (c *call) saveAsDelegate(inst *instance, args []uint64) ([]uint64, error) {
+	into, prototype, constructor, arguments, err := readConstructorArgs(inst, args)
+	if err != nil {
+		return nil, err
+	}
+
+	req := rpctypes.UpSaveAsDelegateReq{
+		UpBaseReq:       c.upBaseReq(),
+		Into:            into,
+		Prototype:       prototype,
+		ConstructorName: constructor,
+		ArgsSerialized:  arguments,
+	}
+	res := rpctypes.UpSaveAsDelegateResp{}
+	err = c.up.SaveAsDelegate(req, &res)
+	if err == nil && res.Reference == nil {
+		err = errors.New("no reference returned")
+	}
+	if err != nil {
+		return c.done(nil, errors.Wrap(err, "[ SaveAsDelegate ] on calling main API"))
+	}
+	return c.done(res.Reference[:], nil)
+}
+
+func readConstructorArgs(inst *instance, args []uint64) (core.RecordRef, core.RecordRef, string, []byte, error) {
+	object, err := readRef(inst, args[0])
+	if err != nil {
+		return object, core.RecordRef{}, "", nil, err
+	}
+	prototype, err := readRef(inst, args[1])
+	if err != nil {
+		return object, prototype, "", nil, err
+	}
+	constructor, err := inst.read(uint32(args[2]), uint32(args[3]))
+	if err != nil {
+		return object, prototype, "", nil, err
+	}
+	arguments, err := inst.read(uint32(args[4]), uint32(args[5]))
+	return object, prototype, string(constructor), arguments, err
+}
+
+func (c *call) getDelegate(inst *instance, args []uint64) ([]uint64, error) {
+	object, err := readRef(inst, args[0])
+	if err != nil {
+		return nil, err
+	}
+	ofType, err := readRef(inst, args[1])
+	if err != nil {
+		return nil, err
+	}
+
+	req := rpctypes.UpGetDelegateReq{
+		UpBaseReq: c.upBaseReq(),
+		Object:    object,
+		OfType:    ofType,
+	}
+	res := rpctypes.UpGetDelegateResp{}
+	if err := c.up.GetDelegate(req, &res); err != nil {
+		return c.done(nil, errors.Wrap(err, "[ GetDelegate ] on calling main API"))
+	}
+	return c.done(res.Object[:], nil)
+}
+
+func (c *call) deactivateObject(inst *instance, args []uint64) ([]uint64, error) {
+	req := rpctypes.UpDeactivateObjectReq{
+		UpBaseReq: c.upBaseReq(),
+	}
+	res := rpctypes.UpDeactivateObjectResp{}
+	err := c.up.DeactivateObject(req, &res)
+	return c.done(nil, errors.Wrap(err, "[ DeactivateObject ] on calling main API"))
+}
+
+func (c *call) scheduleCall(inst *instance, args []uint64) ([]uint64, error) {
+	object, err := readRef(inst, args[0])
+	if err != nil {
+		return nil, err
+	}
+	method, err := inst.read(uint32(args[2]), uint32(args[3]))
+	if err != nil {
+		return nil, err
+	}
+	arguments, err := inst.read(uint32(args[4]), uint32(args[5]))
+	if err != nil {
+		return nil, err
+	}
+
+	req := rpctypes.UpScheduleCallReq{
+		UpBaseReq: c.upBaseReq(),
+		Object:    object,
+		Pulse:     core.PulseNumber(args[1]),
+		Method:    string(method),
+		Arguments: arguments,
+	}
+	res := rpctypes.UpScheduleCallResp{}
+	if err := c.up.ScheduleCall(req, &res); err != nil {
+		return c.done(nil, errors.Wrap(err, "[ ScheduleCall ] on calling main API"))
+	}
+	return c.done(res.Schedule[:], nil)
+}
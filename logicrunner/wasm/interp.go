@@ -0,0 +1,761 @@
+/*
+ *    Copyright 2019 Insolar Technologies
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package wasm
+
+import (
+	"encoding/binary"
+	"math"
+	"math/bits"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// maxCallDepth limits recursion of contract functions
+	maxCallDepth = 1024
+	// maxStackSize limits number of values on operand stack
+	maxStackSize = 1 << 20
+	// bulkMemoryCost is number of bytes copied or filled for one unit of fuel
+	bulkMemoryCost = 64
+)
+
+// ErrOutOfFuel is returned when contract exceeds limit of executed instructions
+var ErrOutOfFuel = errors.New("contract exceeded instruction limit")
+
+// hostFunction is a function provided to module by executor
+type hostFunction struct {
+	typ  funcType
+	call func(inst *instance, args []uint64) ([]uint64, error)
+}
+
+// imports is a set of host functions by module and name
+type imports map[string]map[string]hostFunction
+
+// trap aborts execution of module, it's raised as panic and recovered on call boundary
+type trap struct {
+	err error
+}
+
+type label struct {
+	arity  int
+	height int
+	target int
+	loop   bool
+}
+
+// instance is a module instantiated for one call, execution is single threaded
+// and depends only on module, arguments and results of host functions
+type instance struct {
+	module   *Module
+	host     []hostFunction
+	memory   []byte
+	maxPages uint32
+	globals  []uint64
+	table    []int64
+	stack    []uint64
+	fuel     uint64
+	depth    int
+}
+
+func instantiate(m *Module, imp imports, fuel uint64, maxPages uint32) (*instance, error) {
+	inst := &instance{
+		module:   m,
+		fuel:     fuel,
+		maxPages: maxPages,
+	}
+
+	for _, i := range m.imports {
+		f, ok := imp[i.module][i.name]
+		if !ok {
+			return nil, errors.Errorf("unknown import %s.%s", i.module, i.name)
+		}
+		if !f.typ.equal(m.types[i.typeIndex]) {
+			return nil, errors.Errorf("import %s.%s has wrong signature", i.module, i.name)
+		}
+		inst.host = append(inst.host, f)
+	}
+
+	if m.memory != nil {
+		if m.memory.hasMax && m.memory.max < inst.maxPages {
+			inst.maxPages = m.memory.max
+		}
+		if m.memory.min > inst.maxPages {
+			return nil, errors.Errorf("module requires %d pages of memory, limit is %d", m.memory.min, inst.maxPages)
+		}
+		inst.memory = make([]byte, int(m.memory.min)*pageSize)
+	}
+	for _, seg := range m.data {
+		if uint64(seg.offset)+uint64(len(seg.data)) > uint64(len(inst.memory)) {
+			return nil, errors.New("data segment doesn't fit into memory")
+		}
+		copy(inst.memory[seg.offset:], seg.data)
+	}
+
+	inst.globals = make([]uint64, len(m.globals))
+	for i, g := range m.globals {
+		inst.globals[i] = g.init
+	}
+
+	if m.table != nil {
+		inst.table = make([]int64, m.table.min)
+		for i := range inst.table {
+			inst.table[i] = -1
+		}
+		for _, el := range m.elements {
+			if uint64(el.offset)+uint64(len(el.funcs)) > uint64(len(inst.table)) {
+				return nil, errors.New("element segment doesn't fit into table")
+			}
+			for i, idx := range el.funcs {
+				inst.table[int(el.offset)+i] = int64(idx)
+			}
+		}
+	}
+
+	if m.start != nil {
+		if err := inst.run(func() { inst.invoke(*m.start) }); err != nil {
+			return nil, errors.Wrap(err, "start function failed")
+		}
+	}
+	return inst, nil
+}
+
+// Call calls exported function with arguments and returns its results
+func (inst *instance) Call(name string, args ...uint64) ([]uint64, error) {
+	exp, ok := inst.module.exports[name]
+	if !ok || exp.kind != externalFunction {
+		return nil, errors.Errorf("function %s is not exported", name)
+	}
+	t := inst.module.funcType(exp.index)
+	if len(t.params) != len(args) {
+		return nil, errors.Errorf("function %s expects %d arguments, got %d", name, len(t.params), len(args))
+	}
+
+	inst.stack = inst.stack[:0]
+	inst.depth = 0
+	for i, a := range args {
+		if t.params[i] == valueTypeI32 {
+			a = uint64(uint32(a))
+		}
+		inst.push(a)
+	}
+
+	var res []uint64
+	err := inst.run(func() {
+		inst.invoke(exp.index)
+		res = append(res, inst.popN(len(t.results))...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Global returns value of exported global
+func (inst *instance) Global(name string) (uint64, bool) {
+	exp, ok := inst.module.exports[name]
+	if !ok || exp.kind != externalGlobal {
+		return 0, false
+	}
+	return inst.globals[exp.index], true
+}
+
+func (inst *instance) run(f func()) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			t, ok := r.(trap)
+			if !ok {
+				panic(r)
+			}
+			err = t.err
+		}
+	}()
+	f()
+	return nil
+}
+
+func (inst *instance) trap(err error) {
+	panic(trap{err: err})
+}
+
+func (inst *instance) trapf(format string, args ...interface{}) {
+	inst.trap(errors.Errorf(format, args...))
+}
+
+func (inst *instance) consume(n uint64) {
+	if inst.fuel < n {
+		inst.fuel = 0
+		inst.trap(ErrOutOfFuel)
+	}
+	inst.fuel -= n
+}
+
+func (inst *instance) push(v uint64) {
+	if len(inst.stack) >= maxStackSize {
+		inst.trapf("operand stack overflow")
+	}
+	inst.stack = append(inst.stack, v)
+}
+
+func (inst *instance) pop() uint64 {
+	if len(inst.stack) == 0 {
+		inst.trapf("operand stack underflow")
+	}
+	v := inst.stack[len(inst.stack)-1]
+	inst.stack = inst.stack[:len(inst.stack)-1]
+	return v
+}
+
+func (inst *instance) popN(n int) []uint64 {
+	if len(inst.stack) < n {
+		inst.trapf("operand stack underflow")
+	}
+	res := make([]uint64, n)
+	copy(res, inst.stack[len(inst.stack)-n:])
+	inst.stack = inst.stack[:len(inst.stack)-n]
+	return res
+}
+
+func (inst *instance) pushI32(v uint32) { inst.push(uint64(v)) }
+func (inst *instance) popI32() uint32   { return uint32(inst.pop()) }
+
+func (inst *instance) pushBool(v bool) {
+	if v {
+		inst.push(1)
+	} else {
+		inst.push(0)
+	}
+}
+
+// unwind leaves top arity values above height on stack
+func (inst *instance) unwind(height int, arity int) {
+	if len(inst.stack) < height+arity {
+		inst.trapf("operand stack underflow")
+	}
+	copy(inst.stack[height:], inst.stack[len(inst.stack)-arity:])
+	inst.stack = inst.stack[:height+arity]
+}
+
+// address checks memory access and returns effective address
+func (inst *instance) address(base uint32, offset uint32, size int) int {
+	ea := uint64(base) + uint64(offset)
+	if ea+uint64(size) > uint64(len(inst.memory)) {
+		inst.trapf("out of bounds memory access")
+	}
+	return int(ea)
+}
+
+// read returns copy of memory region
+func (inst *instance) read(ptr uint32, size uint32) ([]byte, error) {
+	if uint64(ptr)+uint64(size) > uint64(len(inst.memory)) {
+		return nil, errors.New("out of bounds memory access")
+	}
+	res := make([]byte, size)
+	copy(res, inst.memory[ptr:])
+	return res, nil
+}
+
+// write copies data into memory region
+func (inst *instance) write(ptr uint32, data []byte) error {
+	if uint64(ptr)+uint64(len(data)) > uint64(len(inst.memory)) {
+		return errors.New("out of bounds memory access")
+	}
+	copy(inst.memory[ptr:], data)
+	return nil
+}
+
+func (inst *instance) grow(delta uint32) int32 {
+	pages := uint32(len(inst.memory) / pageSize)
+	if uint64(pages)+uint64(delta) > uint64(inst.maxPages) {
+		return -1
+	}
+	inst.consume(uint64(delta) * pageSize / bulkMemoryCost)
+	inst.memory = append(inst.memory, make([]byte, int(delta)*pageSize)...)
+	return int32(pages)
+}
+
+func (inst *instance) invoke(index uint32) {
+	m := inst.module
+	t := m.funcType(index)
+
+	if int(index) < len(inst.host) {
+		args := inst.popN(len(t.params))
+		res, err := inst.host[index].call(inst, args)
+		if err != nil {
+			imp := m.imports[index]
+			inst.trap(errors.Wrapf(err, "%s.%s failed", imp.module, imp.name))
+		}
+		if len(res) != len(t.results) {
+			inst.trapf("host function returned %d values, expected %d", len(res), len(t.results))
+		}
+		for _, v := range res {
+			inst.push(v)
+		}
+		return
+	}
+
+	inst.depth++
+	if inst.depth > maxCallDepth {
+		inst.trapf("call stack exhausted")
+	}
+	f := m.funcs[int(index)-len(inst.host)]
+	locals := make([]uint64, len(t.params)+len(f.locals))
+	copy(locals, inst.popN(len(t.params)))
+	inst.execute(f, locals, len(t.results))
+	inst.depth--
+}
+
+// execute interprets body of function, arguments are already in locals
+func (inst *instance) execute(f *function, locals []uint64, arity int) {
+	m := inst.module
+	body := f.body
+	labels := make([]label, 1, 16)
+	labels[0] = label{arity: arity, height: len(inst.stack), target: len(body)}
+
+	pc := 0
+	br := func(depth uint32) {
+		l := labels[len(labels)-1-int(depth)]
+		inst.unwind(l.height, l.arity)
+		if l.loop {
+			labels = labels[:len(labels)-int(depth)]
+		} else {
+			labels = labels[:len(labels)-1-int(depth)]
+		}
+		pc = l.target
+	}
+	u32 := func() uint32 {
+		v, next, err := readU32(body, pc)
+		if err != nil {
+			inst.trap(err)
+		}
+		pc = next
+		return v
+	}
+	memarg := func() uint32 {
+		u32() // alignment is a hint only
+		return u32()
+	}
+
+	for pc < len(body) {
+		inst.consume(1)
+		start := pc
+		op := body[pc]
+		pc++
+
+		switch op {
+		case opUnreachable:
+			inst.trapf("unreachable executed")
+		case opNop:
+
+		case opBlock, opLoop, opIf:
+			params, results, next, err := m.blockType(body, pc)
+			if err != nil {
+				inst.trap(err)
+			}
+			pc = next
+			if op == opIf && inst.popI32() == 0 {
+				e, ok := f.elses[start]
+				if !ok {
+					pc = f.blocks[start] + 1
+					break
+				}
+				pc = e + 1
+			}
+			l := label{arity: results, height: len(inst.stack) - params, target: f.blocks[start] + 1}
+			if op == opLoop {
+				l = label{arity: params, height: len(inst.stack) - params, target: pc, loop: true}
+			}
+			if l.height < 0 {
+				inst.trapf("operand stack underflow")
+			}
+			labels = append(labels, l)
+		case opElse:
+			// end of then branch, skip else branch
+			labels = labels[:len(labels)-1]
+			pc = f.blocks[start] + 1
+		case opEnd:
+			labels = labels[:len(labels)-1]
+		case opBr:
+			br(u32())
+		case opBrIf:
+			depth := u32()
+			if inst.popI32() != 0 {
+				br(depth)
+			}
+		case opBrTable:
+			n := u32()
+			targets := make([]uint32, n+1)
+			for i := range targets {
+				targets[i] = u32()
+			}
+			i := inst.popI32()
+			if i >= n {
+				i = n
+			}
+			br(targets[i])
+		case opReturn:
+			br(uint32(len(labels) - 1))
+
+		case opCall:
+			inst.invoke(u32())
+		case opCallIndirect:
+			typ := m.types[u32()]
+			pc++ // table index
+			i := inst.popI32()
+			if uint64(i) >= uint64(len(inst.table)) {
+				inst.trapf("undefined element %d", i)
+			}
+			index := inst.table[i]
+			if index < 0 {
+				inst.trapf("uninitialized element %d", i)
+			}
+			if !m.funcType(uint32(index)).equal(typ) {
+				inst.trapf("indirect call type mismatch")
+			}
+			inst.invoke(uint32(index))
+
+		case opDrop:
+			inst.pop()
+		case opSelect:
+			c := inst.popI32()
+			b, a := inst.pop(), inst.pop()
+			if c != 0 {
+				inst.push(a)
+			} else {
+				inst.push(b)
+			}
+
+		case opLocalGet:
+			inst.push(locals[u32()])
+		case opLocalSet:
+			idx := u32()
+			locals[idx] = inst.pop()
+		case opLocalTee:
+			idx := u32()
+			v := inst.pop()
+			locals[idx] = v
+			inst.push(v)
+		case opGlobalGet:
+			inst.push(inst.globals[u32()])
+		case opGlobalSet:
+			idx := u32()
+			inst.globals[idx] = inst.pop()
+
+		case opI32Load:
+			a := inst.address(inst.popI32(), memarg(), 4)
+			inst.pushI32(binary.LittleEndian.Uint32(inst.memory[a:]))
+		case opI64Load:
+			a := inst.address(inst.popI32(), memarg(), 8)
+			inst.push(binary.LittleEndian.Uint64(inst.memory[a:]))
+		case opI32Load8S:
+			a := inst.address(inst.popI32(), memarg(), 1)
+			inst.pushI32(uint32(int8(inst.memory[a])))
+		case opI32Load8U:
+			a := inst.address(inst.popI32(), memarg(), 1)
+			inst.pushI32(uint32(inst.memory[a]))
+		case opI32Load16S:
+			a := inst.address(inst.popI32(), memarg(), 2)
+			inst.pushI32(uint32(int16(binary.LittleEndian.Uint16(inst.memory[a:]))))
+		case opI32Load16U:
+			a := inst.address(inst.popI32(), memarg(), 2)
+			inst.pushI32(uint32(binary.LittleEndian.Uint16(inst.memory[a:])))
+		case opI64Load8S:
+			a := inst.address(inst.popI32(), memarg(), 1)
+			inst.push(uint64(int8(inst.memory[a])))
+		case opI64Load8U:
+			a := inst.address(inst.popI32(), memarg(), 1)
+			inst.push(uint64(inst.memory[a]))
+		case opI64Load16S:
+			a := inst.address(inst.popI32(), memarg(), 2)
+			inst.push(uint64(int16(binary.LittleEndian.Uint16(inst.memory[a:]))))
+		case opI64Load16U:
+			a := inst.address(inst.popI32(), memarg(), 2)
+			inst.push(uint64(binary.LittleEndian.Uint16(inst.memory[a:])))
+		case opI64Load32S:
+			a := inst.address(inst.popI32(), memarg(), 4)
+			inst.push(uint64(int32(binary.LittleEndian.Uint32(inst.memory[a:]))))
+		case opI64Load32U:
+			a := inst.address(inst.popI32(), memarg(), 4)
+			inst.push(uint64(binary.LittleEndian.Uint32(inst.memory[a:])))
+
+		case opI32Store:
+			v := inst.popI32()
+			a := inst.address(inst.popI32(), memarg(), 4)
+			binary.LittleEndian.PutUint32(inst.memory[a:], v)
+		case opI64Store:
+			v := inst.pop()
+			a := inst.address(inst.popI32(), memarg(), 8)
+			binary.LittleEndian.PutUint64(inst.memory[a:], v)
+		case opI32Store8, opI64Store8:
+			v := inst.pop()
+			a := inst.address(inst.popI32(), memarg(), 1)
+			inst.memory[a] = byte(v)
+		case opI32Store16, opI64Store16:
+			v := inst.pop()
+			a := inst.address(inst.popI32(), memarg(), 2)
+			binary.LittleEndian.PutUint16(inst.memory[a:], uint16(v))
+		case opI64Store32:
+			v := inst.pop()
+			a := inst.address(inst.popI32(), memarg(), 4)
+			binary.LittleEndian.PutUint32(inst.memory[a:], uint32(v))
+
+		case opMemorySize:
+			pc++
+			inst.pushI32(uint32(len(inst.memory) / pageSize))
+		case opMemoryGrow:
+			pc++
+			inst.pushI32(uint32(inst.grow(inst.popI32())))
+
+		case opI32Const:
+			v, next, err := readSLEB(body, pc, 32)
+			if err != nil {
+				inst.trap(err)
+			}
+			pc = next
+			inst.pushI32(uint32(v))
+		case opI64Const:
+			v, next, err := readSLEB(body, pc, 64)
+			if err != nil {
+				inst.trap(err)
+			}
+			pc = next
+			inst.push(uint64(v))
+
+		case opI32Eqz:
+			inst.pushBool(inst.popI32() == 0)
+		case opI64Eqz:
+			inst.pushBool(inst.pop() == 0)
+		case opI32Eq, opI32Ne, opI32LtS, opI32LtU, opI32GtS, opI32GtU, opI32LeS, opI32LeU, opI32GeS, opI32GeU:
+			b, a := inst.popI32(), inst.popI32()
+			inst.pushBool(compareI32(op, a, b))
+		case opI64Eq, opI64Ne, opI64LtS, opI64LtU, opI64GtS, opI64GtU, opI64LeS, opI64LeU, opI64GeS, opI64GeU:
+			b, a := inst.pop(), inst.pop()
+			inst.pushBool(compareI64(op, a, b))
+
+		case opI32Clz:
+			inst.pushI32(uint32(bits.LeadingZeros32(inst.popI32())))
+		case opI32Ctz:
+			inst.pushI32(uint32(bits.TrailingZeros32(inst.popI32())))
+		case opI32Popcnt:
+			inst.pushI32(uint32(bits.OnesCount32(inst.popI32())))
+		case opI64Clz:
+			inst.push(uint64(bits.LeadingZeros64(inst.pop())))
+		case opI64Ctz:
+			inst.push(uint64(bits.TrailingZeros64(inst.pop())))
+		case opI64Popcnt:
+			inst.push(uint64(bits.OnesCount64(inst.pop())))
+
+		case opI32DivS, opI32DivU, opI32RemS, opI32RemU:
+			b, a := inst.popI32(), inst.popI32()
+			inst.pushI32(inst.divI32(op, a, b))
+		case opI64DivS, opI64DivU, opI64RemS, opI64RemU:
+			b, a := inst.pop(), inst.pop()
+			inst.push(inst.divI64(op, a, b))
+		case opI32Add, opI32Sub, opI32Mul, opI32And, opI32Or, opI32Xor,
+			opI32Shl, opI32ShrS, opI32ShrU, opI32Rotl, opI32Rotr:
+			b, a := inst.popI32(), inst.popI32()
+			inst.pushI32(arithI32(op, a, b))
+		case opI64Add, opI64Sub, opI64Mul, opI64And, opI64Or, opI64Xor,
+			opI64Shl, opI64ShrS, opI64ShrU, opI64Rotl, opI64Rotr:
+			b, a := inst.pop(), inst.pop()
+			inst.push(arithI64(op, a, b))
+
+		case opI32WrapI64:
+			inst.pushI32(uint32(inst.pop()))
+		case opI64ExtendI32S:
+			inst.push(uint64(int32(inst.popI32())))
+		case opI64ExtendI32U:
+			inst.push(uint64(inst.popI32()))
+		case opI32Extend8S:
+			inst.pushI32(uint32(int8(inst.popI32())))
+		case opI32Extend16S:
+			inst.pushI32(uint32(int16(inst.popI32())))
+		case opI64Extend8S:
+			inst.push(uint64(int8(inst.pop())))
+		case opI64Extend16S:
+			inst.push(uint64(int16(inst.pop())))
+		case opI64Extend32S:
+			inst.push(uint64(int32(inst.pop())))
+
+		case opPrefixMisc:
+			switch u32() {
+			case opMiscMemoryCopy:
+				pc += 2
+				n, src, dst := inst.popI32(), inst.popI32(), inst.popI32()
+				inst.consume(uint64(n) / bulkMemoryCost)
+				s := inst.address(src, 0, int(n))
+				d := inst.address(dst, 0, int(n))
+				copy(inst.memory[d:d+int(n)], inst.memory[s:s+int(n)])
+			case opMiscMemoryFill:
+				pc++
+				n, v, dst := inst.popI32(), inst.popI32(), inst.popI32()
+				inst.consume(uint64(n) / bulkMemoryCost)
+				d := inst.address(dst, 0, int(n))
+				region := inst.memory[d : d+int(n)]
+				for i := range region {
+					region[i] = byte(v)
+				}
+			default:
+				inst.trapf("unsupported instruction at %d", start)
+			}
+
+		default:
+			inst.trapf("unsupported instruction 0x%x at %d", op, start)
+		}
+	}
+}
+
+func compareI32(op byte, a, b uint32) bool {
+	switch op {
+	case opI32Eq:
+		return a == b
+	case opI32Ne:
+		return a != b
+	case opI32LtS:
+		return int32(a) < int32(b)
+	case opI32LtU:
+		return a < b
+	case opI32GtS:
+		return int32(a) > int32(b)
+	case opI32GtU:
+		return a > b
+	case opI32LeS:
+		return int32(a) <= int32(b)
+	case opI32LeU:
+		return a <= b
+	case opI32GeS:
+		return int32(a) >= int32(b)
+	}
+	return a >= b
+}
+
+func compareI64(op byte, a, b uint64) bool {
+	switch op {
+	case opI64Eq:
+		return a == b
+	case opI64Ne:
+		return a != b
+	case opI64LtS:
+		return int64(a) < int64(b)
+	case opI64LtU:
+		return a < b
+	case opI64GtS:
+		return int64(a) > int64(b)
+	case opI64GtU:
+		return a > b
+	case opI64LeS:
+		return int64(a) <= int64(b)
+	case opI64LeU:
+		return a <= b
+	case opI64GeS:
+		return int64(a) >= int64(b)
+	}
+	return a >= b
+}
+
+func arithI32(op byte, a, b uint32) uint32 {
+	switch op {
+	case opI32Add:
+		return a + b
+	case opI32Sub:
+		return a - b
+	case opI32Mul:
+		return a * b
+	case opI32And:
+		return a & b
+	case opI32Or:
+		return a | b
+	case opI32Xor:
+		return a ^ b
+	case opI32Shl:
+		return a << (b % 32)
+	case opI32ShrS:
+		return uint32(int32(a) >> (b % 32))
+	case opI32ShrU:
+		return a >> (b % 32)
+	case opI32Rotl:
+		return bits.RotateLeft32(a, int(b%32))
+	}
+	return bits.RotateLeft32(a, -int(b%32))
+}
+
+func arithI64(op byte, a, b uint64) uint64 {
+	switch op {
+	case opI64Add:
+		return a + b
+	case opI64Sub:
+		return a - b
+	case opI64Mul:
+		return a * b
+	case opI64And:
+		return a & b
+	case opI64Or:
+		return a | b
+	case opI64Xor:
+		return a ^ b
+	case opI64Shl:
+		return a << (b % 64)
+	case opI64ShrS:
+		return uint64(int64(a) >> (b % 64))
+	case opI64ShrU:
+		return a >> (b % 64)
+	case opI64Rotl:
+		return bits.RotateLeft64(a, int(b%64))
+	}
+	return bits.RotateLeft64(a, -int(b%64))
+}
+
+func (inst *instance) divI32(op byte, a, b uint32) uint32 {
+	if b == 0 {
+		inst.trapf("integer divide by zero")
+	}
+	switch op {
+	case opI32DivS:
+		if int32(a) == math.MinInt32 && int32(b) == -1 {
+			inst.trapf("integer overflow")
+		}
+		return uint32(int32(a) / int32(b))
+	case opI32DivU:
+		return a / b
+	case opI32RemS:
+		if int32(b) == -1 {
+			return 0
+		}
+		return uint32(int32(a) % int32(b))
+	}
+	return a % b
+}
+
+func (inst *instance) divI64(op byte, a, b uint64) uint64 {
+	if b == 0 {
+		inst.trapf("integer divide by zero")
+	}
+	switch op {
+	case opI64DivS:
+		if int64(a) == math.MinInt64 && int64(b) == -1 {
+			inst.trapf("integer overflow")
+		}
+		return uint64(int64(a) / int64(b))
+	case opI64DivU:
+		return a / b
+	case opI64RemS:
+		if int64(b) == -1 {
+			return 0
+		}
+		return uint64(int64(a) % int64(b))
+	}
+	return a % b
+}
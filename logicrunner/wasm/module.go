@@ -0,0 +1,927 @@
+/*
+ *    Copyright 2019 Insolar Technologies
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package wasm
+
+import (
+	"encoding/binary"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	wasmMagic   = 0x6d736100 // "\0asm"
+	wasmVersion = 1
+	pageSize    = 65536
+
+	// maxLocals limits number of locals of one function
+	maxLocals = 50000
+)
+
+// section ids
+const (
+	sectionCustom = iota
+	sectionType
+	sectionImport
+	sectionFunction
+	sectionTable
+	sectionMemory
+	sectionGlobal
+	sectionExport
+	sectionStart
+	sectionElement
+	sectionCode
+	sectionData
+	sectionDataCount
+)
+
+// kinds of imports and exports
+const (
+	externalFunction = iota
+	externalTable
+	externalMemory
+	externalGlobal
+)
+
+type valueType byte
+
+const (
+	valueTypeI32 valueType = 0x7f
+	valueTypeI64 valueType = 0x7e
+	valueTypeF32 valueType = 0x7d
+	valueTypeF64 valueType = 0x7c
+
+	blockTypeEmpty = 0x40
+	funcTypeForm   = 0x60
+	elemTypeFunc   = 0x70
+)
+
+// ErrFloatingPoint is returned for modules that use floating point types or instructions,
+// they are rejected because results of floating point operations may differ between platforms
+var ErrFloatingPoint = errors.New("floating point types and instructions are not supported")
+
+type funcType struct {
+	params  []valueType
+	results []valueType
+}
+
+func (t funcType) equal(other funcType) bool {
+	if len(t.params) != len(other.params) || len(t.results) != len(other.results) {
+		return false
+	}
+	for i := range t.params {
+		if t.params[i] != other.params[i] {
+			return false
+		}
+	}
+	for i := range t.results {
+		if t.results[i] != other.results[i] {
+			return false
+		}
+	}
+	return true
+}
+
+type limits struct {
+	min    uint32
+	max    uint32
+	hasMax bool
+}
+
+type importEntry struct {
+	module    string
+	name      string
+	typeIndex uint32
+}
+
+type globalEntry struct {
+	typ     valueType
+	mutable bool
+	init    uint64
+}
+
+type exportEntry struct {
+	kind  byte
+	index uint32
+}
+
+type elementSegment struct {
+	offset uint32
+	funcs  []uint32
+}
+
+type dataSegment struct {
+	offset uint32
+	data   []byte
+}
+
+type function struct {
+	typeIndex uint32
+	locals    []valueType
+	body      []byte
+	// blocks maps position of block, loop, if and else instructions to position of matching end
+	blocks map[int]int
+	// elses maps position of if instruction to position of its else
+	elses map[int]int
+}
+
+// Module is a decoded and validated WebAssembly module. It's immutable,
+// every call instantiates it with fresh memory and globals.
+type Module struct {
+	types    []funcType
+	imports  []importEntry
+	funcs    []*function
+	table    *limits
+	elements []elementSegment
+	memory   *limits
+	globals  []globalEntry
+	exports  map[string]exportEntry
+	start    *uint32
+	data     []dataSegment
+}
+
+// Decode parses binary WebAssembly module and prepares it for execution
+func Decode(code []byte) (*Module, error) {
+	r := &reader{buf: code}
+	header, err := r.bytes(8)
+	if err != nil {
+		return nil, errors.Wrap(err, "couldn't read header")
+	}
+	if binary.LittleEndian.Uint32(header) != wasmMagic {
+		return nil, errors.New("not a WebAssembly module")
+	}
+	if binary.LittleEndian.Uint32(header[4:]) != wasmVersion {
+		return nil, errors.New("unsupported version of WebAssembly module")
+	}
+
+	m := &Module{exports: make(map[string]exportEntry)}
+	var funcTypes []uint32
+	seen := make(map[byte]bool)
+	for !r.eof() {
+		id, err := r.byte()
+		if err != nil {
+			return nil, err
+		}
+		size, err := r.u32()
+		if err != nil {
+			return nil, errors.Wrapf(err, "couldn't read size of section %d", id)
+		}
+		payload, err := r.bytes(int(size))
+		if err != nil {
+			return nil, errors.Wrapf(err, "couldn't read section %d", id)
+		}
+		if id == sectionCustom {
+			continue
+		}
+		if seen[id] {
+			return nil, errors.Errorf("duplicate section %d", id)
+		}
+		seen[id] = true
+
+		sr := &reader{buf: payload}
+		switch id {
+		case sectionType:
+			err = m.decodeTypes(sr)
+		case sectionImport:
+			err = m.decodeImports(sr)
+		case sectionFunction:
+			funcTypes, err = sr.u32s()
+		case sectionTable:
+			err = m.decodeTable(sr)
+		case sectionMemory:
+			err = m.decodeMemory(sr)
+		case sectionGlobal:
+			err = m.decodeGlobals(sr)
+		case sectionExport:
+			err = m.decodeExports(sr)
+		case sectionStart:
+			var start uint32
+			start, err = sr.u32()
+			m.start = &start
+		case sectionElement:
+			err = m.decodeElements(sr)
+		case sectionCode:
+			err = m.decodeCode(sr, funcTypes)
+		case sectionData:
+			err = m.decodeData(sr)
+		case sectionDataCount:
+			_, err = sr.u32()
+		default:
+			err = errors.Errorf("unknown section %d", id)
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "couldn't decode section %d", id)
+		}
+		if !sr.eof() {
+			return nil, errors.Errorf("section %d has trailing bytes", id)
+		}
+	}
+
+	if len(funcTypes) != len(m.funcs) {
+		return nil, errors.New("function and code sections don't match")
+	}
+	if err := m.validate(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (m *Module) decodeTypes(r *reader) error {
+	count, err := r.u32()
+	if err != nil {
+		return err
+	}
+	for i := uint32(0); i < count; i++ {
+		form, err := r.byte()
+		if err != nil {
+			return err
+		}
+		if form != funcTypeForm {
+			return errors.Errorf("unknown type form 0x%x", form)
+		}
+		params, err := r.valueTypes()
+		if err != nil {
+			return err
+		}
+		results, err := r.valueTypes()
+		if err != nil {
+			return err
+		}
+		m.types = append(m.types, funcType{params: params, results: results})
+	}
+	return nil
+}
+
+func (m *Module) decodeImports(r *reader) error {
+	count, err := r.u32()
+	if err != nil {
+		return err
+	}
+	for i := uint32(0); i < count; i++ {
+		module, err := r.name()
+		if err != nil {
+			return err
+		}
+		name, err := r.name()
+		if err != nil {
+			return err
+		}
+		kind, err := r.byte()
+		if err != nil {
+			return err
+		}
+		if kind != externalFunction {
+			return errors.Errorf("import %s.%s: only functions can be imported", module, name)
+		}
+		typeIndex, err := r.u32()
+		if err != nil {
+			return err
+		}
+		m.imports = append(m.imports, importEntry{module: module, name: name, typeIndex: typeIndex})
+	}
+	return nil
+}
+
+func (m *Module) decodeTable(r *reader) error {
+	count, err := r.u32()
+	if err != nil {
+		return err
+	}
+	if count > 1 {
+		return errors.New("only one table is supported")
+	}
+	if count == 0 {
+		return nil
+	}
+	elemType, err := r.byte()
+	if err != nil {
+		return err
+	}
+	if elemType != elemTypeFunc {
+		return errors.Errorf("unsupported table element type 0x%x", elemType)
+	}
+	l, err := r.limits()
+	if err != nil {
+		return err
+	}
+	m.table = &l
+	return nil
+}
+
+func (m *Module) decodeMemory(r *reader) error {
+	count, err := r.u32()
+	if err != nil {
+		return err
+	}
+	if count > 1 {
+		return errors.New("only one memory is supported")
+	}
+	if count == 0 {
+		return nil
+	}
+	l, err := r.limits()
+	if err != nil {
+		return err
+	}
+	m.memory = &l
+	return nil
+}
+
+func (m *Module) decodeGlobals(r *reader) error {
+	count, err := r.u32()
+	if err != nil {
+		return err
+	}
+	for i := uint32(0); i < count; i++ {
+		typ, err := r.valueType()
+		if err != nil {
+			return err
+		}
+		mut, err := r.byte()
+		if err != nil {
+			return err
+		}
+		init, err := r.constExpr(typ)
+		if err != nil {
+			return err
+		}
+		m.globals = append(m.globals, globalEntry{typ: typ, mutable: mut == 1, init: init})
+	}
+	return nil
+}
+
+func (m *Module) decodeExports(r *reader) error {
+	count, err := r.u32()
+	if err != nil {
+		return err
+	}
+	for i := uint32(0); i < count; i++ {
+		name, err := r.name()
+		if err != nil {
+			return err
+		}
+		kind, err := r.byte()
+		if err != nil {
+			return err
+		}
+		index, err := r.u32()
+		if err != nil {
+			return err
+		}
+		if _, ok := m.exports[name]; ok {
+			return errors.Errorf("duplicate export %s", name)
+		}
+		m.exports[name] = exportEntry{kind: kind, index: index}
+	}
+	return nil
+}
+
+func (m *Module) decodeElements(r *reader) error {
+	count, err := r.u32()
+	if err != nil {
+		return err
+	}
+	for i := uint32(0); i < count; i++ {
+		flags, err := r.u32()
+		if err != nil {
+			return err
+		}
+		if flags != 0 {
+			return errors.New("only active element segments of table 0 are supported")
+		}
+		offset, err := r.constExpr(valueTypeI32)
+		if err != nil {
+			return err
+		}
+		funcs, err := r.u32s()
+		if err != nil {
+			return err
+		}
+		m.elements = append(m.elements, elementSegment{offset: uint32(offset), funcs: funcs})
+	}
+	return nil
+}
+
+func (m *Module) decodeCode(r *reader, funcTypes []uint32) error {
+	count, err := r.u32()
+	if err != nil {
+		return err
+	}
+	if int(count) != len(funcTypes) {
+		return errors.New("function and code sections don't match")
+	}
+	for i := uint32(0); i < count; i++ {
+		size, err := r.u32()
+		if err != nil {
+			return err
+		}
+		code, err := r.bytes(int(size))
+		if err != nil {
+			return err
+		}
+
+		cr := &reader{buf: code}
+		groups, err := cr.u32()
+		if err != nil {
+			return err
+		}
+		f := &function{typeIndex: funcTypes[i]}
+		for g := uint32(0); g < groups; g++ {
+			n, err := cr.u32()
+			if err != nil {
+				return err
+			}
+			typ, err := cr.valueType()
+			if err != nil {
+				return err
+			}
+			if len(f.locals)+int(n) > maxLocals {
+				return errors.Errorf("function %d has too many locals", i)
+			}
+			for j := uint32(0); j < n; j++ {
+				f.locals = append(f.locals, typ)
+			}
+		}
+		f.body = code[cr.pos:]
+		m.funcs = append(m.funcs, f)
+	}
+	return nil
+}
+
+func (m *Module) decodeData(r *reader) error {
+	count, err := r.u32()
+	if err != nil {
+		return err
+	}
+	for i := uint32(0); i < count; i++ {
+		flags, err := r.u32()
+		if err != nil {
+			return err
+		}
+		if flags != 0 {
+			return errors.New("only active data segments of memory 0 are supported")
+		}
+		offset, err := r.constExpr(valueTypeI32)
+		if err != nil {
+			return err
+		}
+		size, err := r.u32()
+		if err != nil {
+			return err
+		}
+		data, err := r.bytes(int(size))
+		if err != nil {
+			return err
+		}
+		m.data = append(m.data, dataSegment{offset: uint32(offset), data: data})
+	}
+	return nil
+}
+
+// validate checks indexes used by module and prepares function bodies for execution
+func (m *Module) validate() error {
+	for _, imp := range m.imports {
+		if int(imp.typeIndex) >= len(m.types) {
+			return errors.Errorf("import %s.%s has unknown type", imp.module, imp.name)
+		}
+	}
+	for i, f := range m.funcs {
+		if int(f.typeIndex) >= len(m.types) {
+			return errors.Errorf("function %d has unknown type", i)
+		}
+	}
+	for name, exp := range m.exports {
+		var ok bool
+		switch exp.kind {
+		case externalFunction:
+			ok = int(exp.index) < m.funcCount()
+		case externalTable:
+			ok = exp.index == 0 && m.table != nil
+		case externalMemory:
+			ok = exp.index == 0 && m.memory != nil
+		case externalGlobal:
+			ok = int(exp.index) < len(m.globals)
+		}
+		if !ok {
+			return errors.Errorf("export %s refers to unknown item", name)
+		}
+	}
+	if m.start != nil {
+		if int(*m.start) >= m.funcCount() {
+			return errors.New("unknown start function")
+		}
+		t := m.funcType(*m.start)
+		if len(t.params) != 0 || len(t.results) != 0 {
+			return errors.New("start function must have no params and results")
+		}
+	}
+	for _, el := range m.elements {
+		if m.table == nil {
+			return errors.New("element segment without table")
+		}
+		for _, idx := range el.funcs {
+			if int(idx) >= m.funcCount() {
+				return errors.New("element segment refers to unknown function")
+			}
+		}
+	}
+	if len(m.data) > 0 && m.memory == nil {
+		return errors.New("data segment without memory")
+	}
+
+	for i, f := range m.funcs {
+		if err := m.compile(f); err != nil {
+			return errors.Wrapf(err, "invalid function %d", i+len(m.imports))
+		}
+	}
+	return nil
+}
+
+func (m *Module) funcCount() int {
+	return len(m.imports) + len(m.funcs)
+}
+
+// funcType returns type of function by index in function space, imports go first
+func (m *Module) funcType(index uint32) funcType {
+	if int(index) < len(m.imports) {
+		return m.types[m.imports[index].typeIndex]
+	}
+	return m.types[m.funcs[int(index)-len(m.imports)].typeIndex]
+}
+
+// blockType reads type of block at pos and returns number of its params and results
+func (m *Module) blockType(body []byte, pos int) (int, int, int, error) {
+	if pos >= len(body) {
+		return 0, 0, 0, errUnexpectedEnd
+	}
+	switch valueType(body[pos]) {
+	case blockTypeEmpty:
+		return 0, 0, pos + 1, nil
+	case valueTypeI32, valueTypeI64:
+		return 0, 1, pos + 1, nil
+	case valueTypeF32, valueTypeF64:
+		return 0, 0, 0, ErrFloatingPoint
+	}
+	idx, next, err := readSLEB(body, pos, 33)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	if idx < 0 || int(idx) >= len(m.types) {
+		return 0, 0, 0, errors.New("unknown block type")
+	}
+	t := m.types[idx]
+	return len(t.params), len(t.results), next, nil
+}
+
+// compile checks instructions of function and finds ends of blocks,
+// so interpreter doesn't scan code on branches
+func (m *Module) compile(f *function) error {
+	f.blocks = make(map[int]int)
+	f.elses = make(map[int]int)
+
+	body := f.body
+	localCount := len(m.types[f.typeIndex].params) + len(f.locals)
+	var open []int
+	pos := 0
+	var err error
+	for pos < len(body) {
+		start := pos
+		op := body[pos]
+		pos++
+
+		var idx uint32
+		switch op {
+		case opBlock, opLoop, opIf:
+			_, _, pos, err = m.blockType(body, pos)
+			open = append(open, start)
+
+		case opElse:
+			if len(open) == 0 || body[open[len(open)-1]] != opIf {
+				return errors.Errorf("else without if at %d", start)
+			}
+			if _, ok := f.elses[open[len(open)-1]]; ok {
+				return errors.Errorf("duplicate else at %d", start)
+			}
+			f.elses[open[len(open)-1]] = start
+
+		case opEnd:
+			if len(open) == 0 {
+				if pos != len(body) {
+					return errors.Errorf("unexpected end at %d", start)
+				}
+				return nil
+			}
+			top := open[len(open)-1]
+			open = open[:len(open)-1]
+			f.blocks[top] = start
+			if e, ok := f.elses[top]; ok {
+				f.blocks[e] = start
+			}
+
+		case opBr, opBrIf:
+			idx, pos, err = readU32(body, pos)
+			if err == nil && int(idx) > len(open) {
+				err = errors.New("unknown label")
+			}
+
+		case opBrTable:
+			var n uint32
+			n, pos, err = readU32(body, pos)
+			for i := uint32(0); err == nil && i <= n; i++ {
+				idx, pos, err = readU32(body, pos)
+				if err == nil && int(idx) > len(open) {
+					err = errors.New("unknown label")
+				}
+			}
+
+		case opCall:
+			idx, pos, err = readU32(body, pos)
+			if err == nil && int(idx) >= m.funcCount() {
+				err = errors.New("unknown function")
+			}
+
+		case opCallIndirect:
+			idx, pos, err = readU32(body, pos)
+			if err == nil && (int(idx) >= len(m.types) || m.table == nil) {
+				err = errors.New("unknown type or table of indirect call")
+			}
+			pos++
+
+		case opLocalGet, opLocalSet, opLocalTee:
+			idx, pos, err = readU32(body, pos)
+			if err == nil && int(idx) >= localCount {
+				err = errors.New("unknown local")
+			}
+
+		case opGlobalGet, opGlobalSet:
+			idx, pos, err = readU32(body, pos)
+			if err == nil && int(idx) >= len(m.globals) {
+				err = errors.New("unknown global")
+			}
+			if err == nil && op == opGlobalSet && !m.globals[idx].mutable {
+				err = errors.New("global is immutable")
+			}
+
+		case opI32Load, opI64Load,
+			opI32Load8S, opI32Load8U, opI32Load16S, opI32Load16U,
+			opI64Load8S, opI64Load8U, opI64Load16S, opI64Load16U, opI64Load32S, opI64Load32U,
+			opI32Store, opI64Store, opI32Store8, opI32Store16, opI64Store8, opI64Store16, opI64Store32:
+			if m.memory == nil {
+				return errors.New("memory instruction without memory")
+			}
+			_, pos, err = readU32(body, pos)
+			if err == nil {
+				_, pos, err = readU32(body, pos)
+			}
+
+		case opMemorySize, opMemoryGrow:
+			if m.memory == nil {
+				return errors.New("memory instruction without memory")
+			}
+			pos++
+
+		case opI32Const:
+			_, pos, err = readSLEB(body, pos, 32)
+
+		case opI64Const:
+			_, pos, err = readSLEB(body, pos, 64)
+
+		case opPrefixMisc:
+			idx, pos, err = readU32(body, pos)
+			switch {
+			case err != nil:
+			case m.memory == nil:
+				err = errors.New("memory instruction without memory")
+			case idx == opMiscMemoryCopy:
+				pos += 2
+			case idx == opMiscMemoryFill:
+				pos++
+			case idx <= 7:
+				err = ErrFloatingPoint
+			default:
+				err = errors.Errorf("unsupported instruction 0xfc %d", idx)
+			}
+
+		default:
+			if isFloatOpcode(op) {
+				return ErrFloatingPoint
+			}
+			if !isSimpleOpcode(op) {
+				return errors.Errorf("unsupported instruction 0x%x at %d", op, start)
+			}
+		}
+		if err != nil {
+			return errors.Wrapf(err, "instruction 0x%x at %d", op, start)
+		}
+	}
+	return errUnexpectedEnd
+}
+
+var errUnexpectedEnd = errors.New("unexpected end of code")
+
+type reader struct {
+	buf []byte
+	pos int
+}
+
+func (r *reader) eof() bool {
+	return r.pos >= len(r.buf)
+}
+
+func (r *reader) byte() (byte, error) {
+	if r.eof() {
+		return 0, errUnexpectedEnd
+	}
+	b := r.buf[r.pos]
+	r.pos++
+	return b, nil
+}
+
+func (r *reader) bytes(n int) ([]byte, error) {
+	if n < 0 || r.pos+n > len(r.buf) {
+		return nil, errUnexpectedEnd
+	}
+	b := r.buf[r.pos : r.pos+n]
+	r.pos += n
+	return b, nil
+}
+
+func (r *reader) u32() (uint32, error) {
+	v, pos, err := readU32(r.buf, r.pos)
+	if err != nil {
+		return 0, err
+	}
+	r.pos = pos
+	return v, nil
+}
+
+func (r *reader) u32s() ([]uint32, error) {
+	count, err := r.u32()
+	if err != nil {
+		return nil, err
+	}
+	if int(count) > len(r.buf)-r.pos {
+		return nil, errUnexpectedEnd
+	}
+	res := make([]uint32, count)
+	for i := range res {
+		if res[i], err = r.u32(); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+func (r *reader) name() (string, error) {
+	size, err := r.u32()
+	if err != nil {
+		return "", err
+	}
+	b, err := r.bytes(int(size))
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func (r *reader) valueType() (valueType, error) {
+	b, err := r.byte()
+	if err != nil {
+		return 0, err
+	}
+	switch valueType(b) {
+	case valueTypeI32, valueTypeI64:
+		return valueType(b), nil
+	case valueTypeF32, valueTypeF64:
+		return 0, ErrFloatingPoint
+	}
+	return 0, errors.Errorf("unknown value type 0x%x", b)
+}
+
+func (r *reader) valueTypes() ([]valueType, error) {
+	count, err := r.u32()
+	if err != nil {
+		return nil, err
+	}
+	if int(count) > len(r.buf)-r.pos {
+		return nil, errUnexpectedEnd
+	}
+	res := make([]valueType, count)
+	for i := range res {
+		if res[i], err = r.valueType(); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+func (r *reader) limits() (limits, error) {
+	flags, err := r.byte()
+	if err != nil {
+		return limits{}, err
+	}
+	var l limits
+	if l.min, err = r.u32(); err != nil {
+		return limits{}, err
+	}
+	if flags&1 != 0 {
+		l.hasMax = true
+		if l.max, err = r.u32(); err != nil {
+			return limits{}, err
+		}
+		if l.max < l.min {
+			return limits{}, errors.New("maximum is less than minimum")
+		}
+	}
+	return l, nil
+}
+
+// constExpr reads initializer of global or offset of segment,
+// only constants are supported as there are no imported globals
+func (r *reader) constExpr(typ valueType) (uint64, error) {
+	op, err := r.byte()
+	if err != nil {
+		return 0, err
+	}
+	var v int64
+	switch {
+	case op == opI32Const && typ == valueTypeI32:
+		v, r.pos, err = readSLEB(r.buf, r.pos, 32)
+		v = int64(uint32(v))
+	case op == opI64Const && typ == valueTypeI64:
+		v, r.pos, err = readSLEB(r.buf, r.pos, 64)
+	default:
+		return 0, errors.Errorf("unsupported constant expression 0x%x", op)
+	}
+	if err != nil {
+		return 0, err
+	}
+	end, err := r.byte()
+	if err != nil {
+		return 0, err
+	}
+	if end != opEnd {
+		return 0, errors.New("constant expression must be a single instruction")
+	}
+	return uint64(v), nil
+}
+
+// readU32 reads unsigned LEB128 number
+func readU32(buf []byte, pos int) (uint32, int, error) {
+	var res uint32
+	var shift uint
+	for i := 0; i < 5; i++ {
+		if pos >= len(buf) {
+			return 0, 0, errUnexpectedEnd
+		}
+		b := buf[pos]
+		pos++
+		if i == 4 && b&0xf0 != 0 {
+			return 0, 0, errors.New("integer is too large")
+		}
+		res |= uint32(b&0x7f) << shift
+		if b&0x80 == 0 {
+			return res, pos, nil
+		}
+		shift += 7
+	}
+	return 0, 0, errors.New("integer representation is too long")
+}
+
+// readSLEB reads signed LEB128 number of given size in bits
+func readSLEB(buf []byte, pos int, size uint) (int64, int, error) {
+	var res int64
+	var shift uint
+	for i := uint(0); i < (size+6)/7; i++ {
+		if pos >= len(buf) {
+			return 0, 0, errUnexpectedEnd
+		}
+		b := buf[pos]
+		pos++
+		res |= int64(b&0x7f) << shift
+		shift += 7
+		if b&0x80 == 0 {
+			if shift < 64 && b&0x40 != 0 {
+				res |= -1 << shift
+			}
+			if size < 64 && (res < -(1<<(size-1)) || res >= 1<<(size-1)) {
+				return 0, 0, errors.New("integer is too large")
+			}
+			return res, pos, nil
+		}
+	}
+	return 0, 0, errors.New("integer representation is too long")
+}
@@ -0,0 +1,220 @@
+/*
+ *    Copyright 2019 Insolar Technologies
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+// Package wasm is an executor of contracts compiled to WebAssembly.
+//
+// Contracts are run by pure Go interpreter, it supports integer subset of WebAssembly
+// and rejects floating point, so execution is deterministic on every node. Number of
+// executed instructions and memory are limited by configuration.
+//
+// Contract module exports:
+//
+//   memory                    - linear memory
+//   alloc(size i32) i32       - allocates memory for data passed by executor
+//   INSMETHOD_<Name>(statePtr, stateLen, argsPtr, argsLen i32)
+//   INSCONSTRUCTOR_<Name>(argsPtr, argsLen i32)
+//   INSATTR_<Name>_API        - global (non zero) or function, marks method callable from API
+//
+// and imports host functions of module "insolar" mirroring proxyctx.ProxyHelper,
+// see call.imports for the list.
+package wasm
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/insolar/insolar/configuration"
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/instrumentation/inslogger"
+	"github.com/insolar/insolar/instrumentation/instracer"
+)
+
+// Wasm is a contract runner engine based on WebAssembly interpreter
+type Wasm struct {
+	Cfg      *configuration.Wasm
+	AM       core.ArtifactManager
+	Upstream Upstream
+
+	mu      sync.Mutex
+	modules map[core.RecordRef]*Module
+}
+
+// NewWasm is an constructor
+func NewWasm(cfg *configuration.Wasm, am core.ArtifactManager, up Upstream) *Wasm {
+	return &Wasm{
+		Cfg:      cfg,
+		AM:       am,
+		Upstream: up,
+		modules:  make(map[core.RecordRef]*Module),
+	}
+}
+
+// Stop is a no-op, nothing runs outside of calls
+func (w *Wasm) Stop() error {
+	return nil
+}
+
+// module returns decoded module of code, modules are cached by code reference
+func (w *Wasm) module(ctx context.Context, codeRef core.RecordRef) (*Module, error) {
+	w.mu.Lock()
+	m, ok := w.modules[codeRef]
+	w.mu.Unlock()
+	if ok {
+		return m, nil
+	}
+
+	codeDescriptor, err := w.AM.GetCode(ctx, codeRef)
+	if err != nil {
+		return nil, errors.Wrap(err, "Can't find code")
+	}
+	code, err := codeDescriptor.Code()
+	if err != nil {
+		return nil, errors.Wrap(err, "Can't get code")
+	}
+	m, err = Decode(code)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Invalid WebAssembly module %s", codeRef)
+	}
+
+	w.mu.Lock()
+	w.modules[codeRef] = m
+	w.mu.Unlock()
+	return m, nil
+}
+
+// instantiate prepares module for one call and copies data into its memory
+func (w *Wasm) instantiate(
+	ctx context.Context, callCtx *core.LogicCallContext, codeRef core.RecordRef, data ...[]byte,
+) (
+	*instance, *call, []uint64, error,
+) {
+	m, err := w.module(ctx, codeRef)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	c := newCall(ctx, callCtx, w.Upstream)
+	inst, err := instantiate(m, c.imports(), w.Cfg.Fuel, w.Cfg.MaxMemoryPages)
+	if err != nil {
+		return nil, nil, nil, errors.Wrap(err, "Can't instantiate module")
+	}
+
+	var args []uint64
+	for _, d := range data {
+		ptr, err := inst.alloc(d)
+		if err != nil {
+			return nil, nil, nil, errors.Wrap(err, "Can't pass data to contract")
+		}
+		args = append(args, uint64(ptr), uint64(len(d)))
+	}
+	return inst, c, args, nil
+}
+
+// alloc allocates memory with exported alloc function and copies data there
+func (inst *instance) alloc(data []byte) (uint32, error) {
+	if len(data) == 0 {
+		return 0, nil
+	}
+	res, err := inst.Call("alloc", uint64(len(data)))
+	if err != nil {
+		return 0, err
+	}
+	if len(res) != 1 {
+		return 0, errors.New("alloc must return pointer")
+	}
+	ptr := uint32(res[0])
+	return ptr, inst.write(ptr, data)
+}
+
+// CallMethod runs a method on contract
+func (w *Wasm) CallMethod(
+	ctx context.Context, callCtx *core.LogicCallContext, codeRef core.RecordRef,
+	data []byte, method string, args core.Arguments,
+) (
+	[]byte, core.Arguments, error,
+) {
+	ctx, span := instracer.StartSpan(ctx, "wasm.CallMethod")
+	defer span.End()
+	inslogger.FromContext(ctx).Debugf("Calling method %q on object %q", method, refOrEmpty(callCtx.Callee))
+
+	inst, c, params, err := w.instantiate(ctx, callCtx, codeRef, data, args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if callCtx.Caller == nil || callCtx.Caller.IsEmpty() {
+		if !inst.isAPI(method) {
+			return nil, nil, errors.Errorf("Calling non INSATTRAPI method %s (code ref: %s)", method, codeRef)
+		}
+	}
+
+	if _, err := inst.Call("INSMETHOD_"+method, params...); err != nil {
+		return nil, nil, errors.Wrapf(err, "Method %s failed (code ref: %s)", method, codeRef)
+	}
+	if c.err != nil {
+		return nil, nil, errors.Errorf("Method call returned error: %s", c.err)
+	}
+
+	state := c.state
+	if state == nil {
+		state = data
+	}
+	return state, c.result, nil
+}
+
+// CallConstructor runs a constructor of contract and returns memory of new object
+func (w *Wasm) CallConstructor(
+	ctx context.Context, callCtx *core.LogicCallContext, codeRef core.RecordRef,
+	name string, args core.Arguments,
+) (
+	[]byte, error,
+) {
+	ctx, span := instracer.StartSpan(ctx, "wasm.CallConstructor")
+	defer span.End()
+	inslogger.FromContext(ctx).Debugf("Calling constructor %q of prototype %q", name, refOrEmpty(callCtx.Prototype))
+
+	inst, c, params, err := w.instantiate(ctx, callCtx, codeRef, args)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := inst.Call("INSCONSTRUCTOR_"+name, params...); err != nil {
+		return nil, errors.Wrapf(err, "Constructor %s failed (code ref: %s)", name, codeRef)
+	}
+	if c.err != nil {
+		return nil, errors.Errorf("Constructor returned error: %s", c.err)
+	}
+	if c.state == nil {
+		return nil, errors.Errorf("Constructor %s didn't set state of object", name)
+	}
+	return c.state, nil
+}
+
+// isAPI checks INSATTR_<Method>_API export of module
+func (inst *instance) isAPI(method string) bool {
+	name := "INSATTR_" + method + "_API"
+	exp, ok := inst.module.exports[name]
+	if !ok {
+		return false
+	}
+	if exp.kind == externalGlobal {
+		v, _ := inst.Global(name)
+		return v != 0
+	}
+	return exp.kind == externalFunction
+}
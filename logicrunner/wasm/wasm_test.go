@@ -0,0 +1,293 @@
+/*
+ *    Copyright 2019 Insolar Technologies
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package wasm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/insolar/insolar/configuration"
+	"github.com/insolar/insolar/core"
+	"github.com/insolar/insolar/logicrunner/goplugin/goplugintestutils"
+	"github.com/insolar/insolar/logicrunner/goplugin/rpctypes"
+	"github.com/insolar/insolar/testutils"
+)
+
+// helpers to assemble binary modules
+
+func uleb(v uint64) []byte {
+	var res []byte
+	for {
+		b := byte(v & 0x7f)
+		v >>= 7
+		if v == 0 {
+			return append(res, b)
+		}
+		res = append(res, b|0x80)
+	}
+}
+
+func cat(parts ...[]byte) []byte {
+	var res []byte
+	for _, p := range parts {
+		res = append(res, p...)
+	}
+	return res
+}
+
+func vec(items ...[]byte) []byte {
+	return cat(uleb(uint64(len(items))), cat(items...))
+}
+
+func str(s string) []byte {
+	return cat(uleb(uint64(len(s))), []byte(s))
+}
+
+func section(id byte, items ...[]byte) []byte {
+	payload := vec(items...)
+	return cat([]byte{id}, uleb(uint64(len(payload))), payload)
+}
+
+func body(locals []byte, code ...byte) []byte {
+	b := cat(locals, code)
+	return cat(uleb(uint64(len(b))), b)
+}
+
+func module(sections ...[]byte) []byte {
+	return cat([]byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}, cat(sections...))
+}
+
+func exportFunc(name string, idx byte) []byte {
+	return cat(str(name), []byte{externalFunction, idx})
+}
+
+var noLocals = []byte{0x00}
+
+func testModule() []byte {
+	return module(
+		section(sectionType,
+			[]byte{0x60, 0x01, 0x7e, 0x01, 0x7e}, // (i64) -> i64
+			[]byte{0x60, 0x01, 0x7f, 0x01, 0x7f}, // (i32) -> i32
+			[]byte{0x60, 0x00, 0x00},             // () -> ()
+		),
+		section(sectionFunction, []byte{0}, []byte{1}, []byte{1}, []byte{2}, []byte{1}),
+		section(sectionExport,
+			exportFunc("fac", 0),
+			exportFunc("sum", 1),
+			exportFunc("switch", 2),
+			exportFunc("loop", 3),
+			exportFunc("div", 4),
+		),
+		section(sectionCode,
+			// recursive factorial
+			body(noLocals,
+				0x20, 0x00, 0x50, 0x04, 0x7e, 0x42, 0x01, 0x05,
+				0x20, 0x00, 0x20, 0x00, 0x42, 0x01, 0x7d, 0x10, 0x00, 0x7e, 0x0b, 0x0b,
+			),
+			// sum of 1..n in loop
+			body([]byte{0x01, 0x01, 0x7f},
+				0x02, 0x40, 0x03, 0x40, 0x20, 0x00, 0x45, 0x0d, 0x01,
+				0x20, 0x01, 0x20, 0x00, 0x6a, 0x21, 0x01,
+				0x20, 0x00, 0x41, 0x01, 0x6b, 0x21, 0x00, 0x0c, 0x00, 0x0b, 0x0b,
+				0x20, 0x01, 0x0b,
+			),
+			// br_table: 0 -> 10, 1 -> 20, otherwise 30
+			body(noLocals,
+				0x02, 0x40, 0x02, 0x40, 0x02, 0x40, 0x20, 0x00, 0x0e, 0x02, 0x00, 0x01, 0x02, 0x0b,
+				0x41, 0x0a, 0x0f, 0x0b, 0x41, 0x14, 0x0f, 0x0b, 0x41, 0x1e, 0x0b,
+			),
+			// infinite loop
+			body(noLocals, 0x03, 0x40, 0x0c, 0x00, 0x0b, 0x0b),
+			// 1 / n
+			body(noLocals, 0x41, 0x01, 0x20, 0x00, 0x6e, 0x0b),
+		),
+	)
+}
+
+func TestInstance_Call(t *testing.T) {
+	m, err := Decode(testModule())
+	require.NoError(t, err)
+
+	inst, err := instantiate(m, nil, 100000, 1)
+	require.NoError(t, err)
+
+	res, err := inst.Call("fac", 20)
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{2432902008176640000}, res)
+
+	res, err = inst.Call("sum", 100)
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{5050}, res)
+
+	for arg, expected := range map[uint64]uint64{0: 10, 1: 20, 2: 30, 100: 30} {
+		res, err = inst.Call("switch", arg)
+		require.NoError(t, err)
+		assert.Equal(t, []uint64{expected}, res, "argument %d", arg)
+	}
+
+	_, err = inst.Call("div", 0)
+	require.EqualError(t, err, "integer divide by zero")
+
+	_, err = inst.Call("loop")
+	require.Equal(t, ErrOutOfFuel, err)
+
+	_, err = inst.Call("unknown")
+	require.Error(t, err)
+}
+
+func TestDecode_Errors(t *testing.T) {
+	_, err := Decode([]byte("not a module"))
+	require.Error(t, err)
+
+	// f32 in signature
+	_, err = Decode(module(section(sectionType, []byte{0x60, 0x01, 0x7d, 0x00})))
+	require.Equal(t, ErrFloatingPoint, errors.Cause(err))
+
+	// f32.const in code
+	_, err = Decode(module(
+		section(sectionType, []byte{0x60, 0x00, 0x00}),
+		section(sectionFunction, []byte{0}),
+		section(sectionCode, body(noLocals, 0x43, 0x00, 0x00, 0x00, 0x00, 0x1a, 0x0b)),
+	))
+	require.Equal(t, ErrFloatingPoint, errors.Cause(err))
+
+	// missing end
+	_, err = Decode(module(
+		section(sectionType, []byte{0x60, 0x00, 0x00}),
+		section(sectionFunction, []byte{0}),
+		section(sectionCode, body(noLocals, 0x02, 0x40, 0x0b)),
+	))
+	require.Error(t, err)
+}
+
+// contractModule is a contract with methods:
+//
+//   Inc - increments first byte of state and returns arguments
+//   Call - calls method Get on object from arguments and returns its result
+//
+// and constructor New that makes state from arguments
+func contractModule() []byte {
+	i32 := byte(0x7f)
+	return module(
+		section(sectionType,
+			[]byte{0x60, 0x02, i32, i32, 0x00},                               // set_state, set_result, New
+			[]byte{0x60, 0x07, i32, i32, i32, i32, i32, i32, i32, 0x01, i32}, // route_call
+			[]byte{0x60, 0x01, i32, 0x00},                                    // read_buffer
+			[]byte{0x60, 0x01, i32, 0x01, i32},                               // alloc
+			[]byte{0x60, 0x04, i32, i32, i32, i32, 0x00},                     // methods
+		),
+		section(sectionImport,
+			cat(str(hostModule), str("set_state"), []byte{externalFunction, 0}),
+			cat(str(hostModule), str("set_result"), []byte{externalFunction, 0}),
+			cat(str(hostModule), str("route_call"), []byte{externalFunction, 1}),
+			cat(str(hostModule), str("read_buffer"), []byte{externalFunction, 2}),
+		),
+		section(sectionFunction, []byte{3}, []byte{4}, []byte{4}, []byte{0}),
+		section(sectionMemory, []byte{0x00, 0x01}),
+		section(sectionGlobal,
+			[]byte{i32, 0x01, 0x41, 0x80, 0x08, 0x0b}, // heap pointer, 1024
+			[]byte{i32, 0x00, 0x41, 0x01, 0x0b},       // API flag of Inc
+		),
+		section(sectionExport,
+			cat(str("memory"), []byte{externalMemory, 0}),
+			exportFunc("alloc", 4),
+			exportFunc("INSMETHOD_Inc", 5),
+			exportFunc("INSMETHOD_Call", 6),
+			exportFunc("INSCONSTRUCTOR_New", 7),
+			cat(str("INSATTR_Inc_API"), []byte{externalGlobal, 1}),
+		),
+		section(sectionCode,
+			body(noLocals, 0x23, 0x00, 0x23, 0x00, 0x20, 0x00, 0x6a, 0x24, 0x00, 0x0b),
+			body(noLocals,
+				0x20, 0x00, 0x20, 0x00, 0x2d, 0x00, 0x00, 0x41, 0x01, 0x6a, 0x3a, 0x00, 0x00,
+				0x20, 0x00, 0x20, 0x01, 0x10, 0x00,
+				0x20, 0x02, 0x20, 0x03, 0x10, 0x01,
+				0x0b,
+			),
+			body([]byte{0x01, 0x01, i32},
+				// route_call(args, 600, 512, 3, 0, 0, 1)
+				0x20, 0x02, 0x41, 0xd8, 0x04, 0x41, 0x80, 0x04, 0x41, 0x03,
+				0x41, 0x00, 0x41, 0x00, 0x41, 0x01, 0x10, 0x02, 0x21, 0x04,
+				0x41, 0x80, 0x10, 0x10, 0x03,
+				0x41, 0x80, 0x10, 0x20, 0x04, 0x10, 0x01,
+				0x0b,
+			),
+			body(noLocals, 0x20, 0x00, 0x20, 0x01, 0x10, 0x00, 0x0b),
+		),
+		section(sectionData, cat([]byte{0x00, 0x41, 0x80, 0x04, 0x0b}, str("Get"))),
+	)
+}
+
+type testUpstream struct {
+	Upstream
+	routed []rpctypes.UpRouteReq
+}
+
+func (u *testUpstream) RouteCall(req rpctypes.UpRouteReq, rep *rpctypes.UpRouteResp) error {
+	u.routed = append(u.routed, req)
+	rep.Result = []byte("result of " + req.Method)
+	return nil
+}
+
+func TestWasm_CallMethod(t *testing.T) {
+	ctx := context.Background()
+	codeRef := testutils.RandomRef()
+
+	am := testutils.NewArtifactManagerMock(t)
+	am.GetCodeMock.Return(&goplugintestutils.TestCodeDescriptor{
+		ARef:         codeRef,
+		ACode:        contractModule(),
+		AMachineType: core.MachineTypeWasm,
+	}, nil)
+
+	up := &testUpstream{}
+	w := NewWasm(&configuration.Wasm{Fuel: 100000, MaxMemoryPages: 4}, am, up)
+
+	callee := testutils.RandomRef()
+	caller := testutils.RandomRef()
+	callCtx := &core.LogicCallContext{Mode: "execution", Callee: &callee, Caller: &caller}
+
+	state, err := w.CallConstructor(ctx, callCtx, codeRef, "New", []byte{5})
+	require.NoError(t, err)
+	assert.Equal(t, []byte{5}, state)
+
+	state, res, err := w.CallMethod(ctx, callCtx, codeRef, state, "Inc", []byte("hello"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte{6}, state)
+	assert.Equal(t, core.Arguments("hello"), res)
+
+	object := testutils.RandomRef()
+	state, res, err = w.CallMethod(ctx, callCtx, codeRef, state, "Call", object[:])
+	require.NoError(t, err)
+	assert.Equal(t, []byte{6}, state)
+	assert.Equal(t, core.Arguments("result of Get"), res)
+	require.Len(t, up.routed, 1)
+	assert.Equal(t, object, up.routed[0].Object)
+	assert.Equal(t, callee, up.routed[0].Callee)
+	assert.True(t, up.routed[0].Wait)
+
+	// only methods marked as API can be called from outside
+	apiCtx := &core.LogicCallContext{Mode: "execution", Callee: &callee}
+	_, _, err = w.CallMethod(ctx, apiCtx, codeRef, state, "Inc", nil)
+	require.NoError(t, err)
+	_, _, err = w.CallMethod(ctx, apiCtx, codeRef, state, "Call", object[:])
+	require.Error(t, err)
+}
@@ -25,3 +25,10 @@ var APIContractExecutionTime = prometheus.NewSummaryVec(prometheus.SummaryOpts{
 	Subsystem:  "API",
 	Objectives: map[float64]float64{0.5: 0.05, 0.9: 0.01, 0.95: 0.005, 0.99: 0.001},
 }, []string{"method", "success"})
+
+var APIRateLimitExceeded = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name:      "rate_limit_exceeded_total",
+	Help:      "Number of API requests rejected by rate limits",
+	Namespace: insolarNamespace,
+	Subsystem: "API",
+}, []string{"limit"})
@@ -41,6 +41,7 @@ func GetInsolarRegistry() *prometheus.Registry {
 	registry.MustRegister(NetworkRecvSize)
 
 	registry.MustRegister(APIContractExecutionTime)
+	registry.MustRegister(APIRateLimitExceeded)
 
 	return registry
 }
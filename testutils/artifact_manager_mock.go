@@ -40,7 +40,7 @@ type ArtifactManagerMock struct {
 	DeclareTypePreCounter uint64
 	DeclareTypeMock       mArtifactManagerMockDeclareType
 
-	DeployCodeFunc       func(p context.Context, p1 core.RecordRef, p2 core.RecordRef, p3 []byte, p4 core.MachineType) (r *core.RecordID, r1 error)
+	DeployCodeFunc       func(p context.Context, p1 core.RecordRef, p2 core.RecordRef, p3 []byte, p4 core.MachineType, p5 []byte) (r *core.RecordID, r1 error)
 	DeployCodeCounter    uint64
 	DeployCodePreCounter uint64
 	DeployCodeMock       mArtifactManagerMockDeployCode
@@ -65,6 +65,11 @@ type ArtifactManagerMock struct {
 	GetDelegatePreCounter uint64
 	GetDelegateMock       mArtifactManagerMockGetDelegate
 
+	GetIdempotentRequestFunc       func(p context.Context, p1 core.RecordRef, p2 string) (r *core.IdempotentRequest, r1 error)
+	GetIdempotentRequestCounter    uint64
+	GetIdempotentRequestPreCounter uint64
+	GetIdempotentRequestMock       mArtifactManagerMockGetIdempotentRequest
+
 	GetObjectFunc       func(p context.Context, p1 core.RecordRef, p2 *core.RecordID, p3 bool) (r core.ObjectDescriptor, r1 error)
 	GetObjectCounter    uint64
 	GetObjectPreCounter uint64
@@ -75,6 +80,11 @@ type ArtifactManagerMock struct {
 	GetPendingRequestPreCounter uint64
 	GetPendingRequestMock       mArtifactManagerMockGetPendingRequest
 
+	GetRequestResultFunc       func(p context.Context, p1 core.RecordRef, p2 core.RecordRef) (r *core.IdempotentRequest, r1 error)
+	GetRequestResultCounter    uint64
+	GetRequestResultPreCounter uint64
+	GetRequestResultMock       mArtifactManagerMockGetRequestResult
+
 	HasPendingRequestsFunc       func(p context.Context, p1 core.RecordRef) (r bool, r1 error)
 	HasPendingRequestsCounter    uint64
 	HasPendingRequestsPreCounter uint64
@@ -90,6 +100,11 @@ type ArtifactManagerMock struct {
 	RegisterResultPreCounter uint64
 	RegisterResultMock       mArtifactManagerMockRegisterResult
 
+	RegisterSeedFunc       func(p context.Context, p1 core.RecordRef, p2 []byte, p3 core.PulseNumber) (r error)
+	RegisterSeedCounter    uint64
+	RegisterSeedPreCounter uint64
+	RegisterSeedMock       mArtifactManagerMockRegisterSeed
+
 	RegisterValidationFunc       func(p context.Context, p1 core.RecordRef, p2 core.RecordID, p3 bool, p4 []core.Message) (r error)
 	RegisterValidationCounter    uint64
 	RegisterValidationPreCounter uint64
@@ -128,11 +143,14 @@ func NewArtifactManagerMock(t minimock.Tester) *ArtifactManagerMock {
 	m.GetChildrenMock = mArtifactManagerMockGetChildren{mock: m}
 	m.GetCodeMock = mArtifactManagerMockGetCode{mock: m}
 	m.GetDelegateMock = mArtifactManagerMockGetDelegate{mock: m}
+	m.GetIdempotentRequestMock = mArtifactManagerMockGetIdempotentRequest{mock: m}
 	m.GetObjectMock = mArtifactManagerMockGetObject{mock: m}
 	m.GetPendingRequestMock = mArtifactManagerMockGetPendingRequest{mock: m}
+	m.GetRequestResultMock = mArtifactManagerMockGetRequestResult{mock: m}
 	m.HasPendingRequestsMock = mArtifactManagerMockHasPendingRequests{mock: m}
 	m.RegisterRequestMock = mArtifactManagerMockRegisterRequest{mock: m}
 	m.RegisterResultMock = mArtifactManagerMockRegisterResult{mock: m}
+	m.RegisterSeedMock = mArtifactManagerMockRegisterSeed{mock: m}
 	m.RegisterValidationMock = mArtifactManagerMockRegisterValidation{mock: m}
 	m.StateMock = mArtifactManagerMockState{mock: m}
 	m.UpdateObjectMock = mArtifactManagerMockUpdateObject{mock: m}
@@ -775,6 +793,7 @@ type ArtifactManagerMockDeployCodeInput struct {
 	p2 core.RecordRef
 	p3 []byte
 	p4 core.MachineType
+	p5 []byte
 }
 
 type ArtifactManagerMockDeployCodeResult struct {
@@ -783,14 +802,14 @@ type ArtifactManagerMockDeployCodeResult struct {
 }
 
 //Expect specifies that invocation of ArtifactManager.DeployCode is expected from 1 to Infinity times
-func (m *mArtifactManagerMockDeployCode) Expect(p context.Context, p1 core.RecordRef, p2 core.RecordRef, p3 []byte, p4 core.MachineType) *mArtifactManagerMockDeployCode {
+func (m *mArtifactManagerMockDeployCode) Expect(p context.Context, p1 core.RecordRef, p2 core.RecordRef, p3 []byte, p4 core.MachineType, p5 []byte) *mArtifactManagerMockDeployCode {
 	m.mock.DeployCodeFunc = nil
 	m.expectationSeries = nil
 
 	if m.mainExpectation == nil {
 		m.mainExpectation = &ArtifactManagerMockDeployCodeExpectation{}
 	}
-	m.mainExpectation.input = &ArtifactManagerMockDeployCodeInput{p, p1, p2, p3, p4}
+	m.mainExpectation.input = &ArtifactManagerMockDeployCodeInput{p, p1, p2, p3, p4, p5}
 	return m
 }
 
@@ -807,12 +826,12 @@ func (m *mArtifactManagerMockDeployCode) Return(r *core.RecordID, r1 error) *Art
 }
 
 //ExpectOnce specifies that invocation of ArtifactManager.DeployCode is expected once
-func (m *mArtifactManagerMockDeployCode) ExpectOnce(p context.Context, p1 core.RecordRef, p2 core.RecordRef, p3 []byte, p4 core.MachineType) *ArtifactManagerMockDeployCodeExpectation {
+func (m *mArtifactManagerMockDeployCode) ExpectOnce(p context.Context, p1 core.RecordRef, p2 core.RecordRef, p3 []byte, p4 core.MachineType, p5 []byte) *ArtifactManagerMockDeployCodeExpectation {
 	m.mock.DeployCodeFunc = nil
 	m.mainExpectation = nil
 
 	expectation := &ArtifactManagerMockDeployCodeExpectation{}
-	expectation.input = &ArtifactManagerMockDeployCodeInput{p, p1, p2, p3, p4}
+	expectation.input = &ArtifactManagerMockDeployCodeInput{p, p1, p2, p3, p4, p5}
 	m.expectationSeries = append(m.expectationSeries, expectation)
 	return expectation
 }
@@ -822,7 +841,7 @@ func (e *ArtifactManagerMockDeployCodeExpectation) Return(r *core.RecordID, r1 e
 }
 
 //Set uses given function f as a mock of ArtifactManager.DeployCode method
-func (m *mArtifactManagerMockDeployCode) Set(f func(p context.Context, p1 core.RecordRef, p2 core.RecordRef, p3 []byte, p4 core.MachineType) (r *core.RecordID, r1 error)) *ArtifactManagerMock {
+func (m *mArtifactManagerMockDeployCode) Set(f func(p context.Context, p1 core.RecordRef, p2 core.RecordRef, p3 []byte, p4 core.MachineType, p5 []byte) (r *core.RecordID, r1 error)) *ArtifactManagerMock {
 	m.mainExpectation = nil
 	m.expectationSeries = nil
 
@@ -831,18 +850,18 @@ func (m *mArtifactManagerMockDeployCode) Set(f func(p context.Context, p1 core.R
 }
 
 //DeployCode implements github.com/insolar/insolar/core.ArtifactManager interface
-func (m *ArtifactManagerMock) DeployCode(p context.Context, p1 core.RecordRef, p2 core.RecordRef, p3 []byte, p4 core.MachineType) (r *core.RecordID, r1 error) {
+func (m *ArtifactManagerMock) DeployCode(p context.Context, p1 core.RecordRef, p2 core.RecordRef, p3 []byte, p4 core.MachineType, p5 []byte) (r *core.RecordID, r1 error) {
 	counter := atomic.AddUint64(&m.DeployCodePreCounter, 1)
 	defer atomic.AddUint64(&m.DeployCodeCounter, 1)
 
 	if len(m.DeployCodeMock.expectationSeries) > 0 {
 		if counter > uint64(len(m.DeployCodeMock.expectationSeries)) {
-			m.t.Fatalf("Unexpected call to ArtifactManagerMock.DeployCode. %v %v %v %v %v", p, p1, p2, p3, p4)
+			m.t.Fatalf("Unexpected call to ArtifactManagerMock.DeployCode. %v %v %v %v %v %v", p, p1, p2, p3, p4, p5)
 			return
 		}
 
 		input := m.DeployCodeMock.expectationSeries[counter-1].input
-		testify_assert.Equal(m.t, *input, ArtifactManagerMockDeployCodeInput{p, p1, p2, p3, p4}, "ArtifactManager.DeployCode got unexpected parameters")
+		testify_assert.Equal(m.t, *input, ArtifactManagerMockDeployCodeInput{p, p1, p2, p3, p4, p5}, "ArtifactManager.DeployCode got unexpected parameters")
 
 		result := m.DeployCodeMock.expectationSeries[counter-1].result
 		if result == nil {
@@ -860,7 +879,7 @@ func (m *ArtifactManagerMock) DeployCode(p context.Context, p1 core.RecordRef, p
 
 		input := m.DeployCodeMock.mainExpectation.input
 		if input != nil {
-			testify_assert.Equal(m.t, *input, ArtifactManagerMockDeployCodeInput{p, p1, p2, p3, p4}, "ArtifactManager.DeployCode got unexpected parameters")
+			testify_assert.Equal(m.t, *input, ArtifactManagerMockDeployCodeInput{p, p1, p2, p3, p4, p5}, "ArtifactManager.DeployCode got unexpected parameters")
 		}
 
 		result := m.DeployCodeMock.mainExpectation.result
@@ -875,11 +894,11 @@ func (m *ArtifactManagerMock) DeployCode(p context.Context, p1 core.RecordRef, p
 	}
 
 	if m.DeployCodeFunc == nil {
-		m.t.Fatalf("Unexpected call to ArtifactManagerMock.DeployCode. %v %v %v %v %v", p, p1, p2, p3, p4)
+		m.t.Fatalf("Unexpected call to ArtifactManagerMock.DeployCode. %v %v %v %v %v %v", p, p1, p2, p3, p4, p5)
 		return
 	}
 
-	return m.DeployCodeFunc(p, p1, p2, p3, p4)
+	return m.DeployCodeFunc(p, p1, p2, p3, p4, p5)
 }
 
 //DeployCodeMinimockCounter returns a count of ArtifactManagerMock.DeployCodeFunc invocations
@@ -1501,6 +1520,158 @@ func (m *ArtifactManagerMock) GetDelegateFinished() bool {
 	return true
 }
 
+type mArtifactManagerMockGetIdempotentRequest struct {
+	mock              *ArtifactManagerMock
+	mainExpectation   *ArtifactManagerMockGetIdempotentRequestExpectation
+	expectationSeries []*ArtifactManagerMockGetIdempotentRequestExpectation
+}
+
+type ArtifactManagerMockGetIdempotentRequestExpectation struct {
+	input  *ArtifactManagerMockGetIdempotentRequestInput
+	result *ArtifactManagerMockGetIdempotentRequestResult
+}
+
+type ArtifactManagerMockGetIdempotentRequestInput struct {
+	p  context.Context
+	p1 core.RecordRef
+	p2 string
+}
+
+type ArtifactManagerMockGetIdempotentRequestResult struct {
+	r  *core.IdempotentRequest
+	r1 error
+}
+
+//Expect specifies that invocation of ArtifactManager.GetIdempotentRequest is expected from 1 to Infinity times
+func (m *mArtifactManagerMockGetIdempotentRequest) Expect(p context.Context, p1 core.RecordRef, p2 string) *mArtifactManagerMockGetIdempotentRequest {
+	m.mock.GetIdempotentRequestFunc = nil
+	m.expectationSeries = nil
+
+	if m.mainExpectation == nil {
+		m.mainExpectation = &ArtifactManagerMockGetIdempotentRequestExpectation{}
+	}
+	m.mainExpectation.input = &ArtifactManagerMockGetIdempotentRequestInput{p, p1, p2}
+	return m
+}
+
+//Return specifies results of invocation of ArtifactManager.GetIdempotentRequest
+func (m *mArtifactManagerMockGetIdempotentRequest) Return(r *core.IdempotentRequest, r1 error) *ArtifactManagerMock {
+	m.mock.GetIdempotentRequestFunc = nil
+	m.expectationSeries = nil
+
+	if m.mainExpectation == nil {
+		m.mainExpectation = &ArtifactManagerMockGetIdempotentRequestExpectation{}
+	}
+	m.mainExpectation.result = &ArtifactManagerMockGetIdempotentRequestResult{r, r1}
+	return m.mock
+}
+
+//ExpectOnce specifies that invocation of ArtifactManager.GetIdempotentRequest is expected once
+func (m *mArtifactManagerMockGetIdempotentRequest) ExpectOnce(p context.Context, p1 core.RecordRef, p2 string) *ArtifactManagerMockGetIdempotentRequestExpectation {
+	m.mock.GetIdempotentRequestFunc = nil
+	m.mainExpectation = nil
+
+	expectation := &ArtifactManagerMockGetIdempotentRequestExpectation{}
+	expectation.input = &ArtifactManagerMockGetIdempotentRequestInput{p, p1, p2}
+	m.expectationSeries = append(m.expectationSeries, expectation)
+	return expectation
+}
+
+func (e *ArtifactManagerMockGetIdempotentRequestExpectation) Return(r *core.IdempotentRequest, r1 error) {
+	e.result = &ArtifactManagerMockGetIdempotentRequestResult{r, r1}
+}
+
+//Set uses given function f as a mock of ArtifactManager.GetIdempotentRequest method
+func (m *mArtifactManagerMockGetIdempotentRequest) Set(f func(p context.Context, p1 core.RecordRef, p2 string) (r *core.IdempotentRequest, r1 error)) *ArtifactManagerMock {
+	m.mainExpectation = nil
+	m.expectationSeries = nil
+
+	m.mock.GetIdempotentRequestFunc = f
+	return m.mock
+}
+
+//GetIdempotentRequest implements github.com/insolar/insolar/core.ArtifactManager interface
+func (m *ArtifactManagerMock) GetIdempotentRequest(p context.Context, p1 core.RecordRef, p2 string) (r *core.IdempotentRequest, r1 error) {
+	counter := atomic.AddUint64(&m.GetIdempotentRequestPreCounter, 1)
+	defer atomic.AddUint64(&m.GetIdempotentRequestCounter, 1)
+
+	if len(m.GetIdempotentRequestMock.expectationSeries) > 0 {
+		if counter > uint64(len(m.GetIdempotentRequestMock.expectationSeries)) {
+			m.t.Fatalf("Unexpected call to ArtifactManagerMock.GetIdempotentRequest. %v %v %v", p, p1, p2)
+			return
+		}
+
+		input := m.GetIdempotentRequestMock.expectationSeries[counter-1].input
+		testify_assert.Equal(m.t, *input, ArtifactManagerMockGetIdempotentRequestInput{p, p1, p2}, "ArtifactManager.GetIdempotentRequest got unexpected parameters")
+
+		result := m.GetIdempotentRequestMock.expectationSeries[counter-1].result
+		if result == nil {
+			m.t.Fatal("No results are set for the ArtifactManagerMock.GetIdempotentRequest")
+			return
+		}
+
+		r = result.r
+		r1 = result.r1
+
+		return
+	}
+
+	if m.GetIdempotentRequestMock.mainExpectation != nil {
+
+		input := m.GetIdempotentRequestMock.mainExpectation.input
+		if input != nil {
+			testify_assert.Equal(m.t, *input, ArtifactManagerMockGetIdempotentRequestInput{p, p1, p2}, "ArtifactManager.GetIdempotentRequest got unexpected parameters")
+		}
+
+		result := m.GetIdempotentRequestMock.mainExpectation.result
+		if result == nil {
+			m.t.Fatal("No results are set for the ArtifactManagerMock.GetIdempotentRequest")
+		}
+
+		r = result.r
+		r1 = result.r1
+
+		return
+	}
+
+	if m.GetIdempotentRequestFunc == nil {
+		m.t.Fatalf("Unexpected call to ArtifactManagerMock.GetIdempotentRequest. %v %v %v", p, p1, p2)
+		return
+	}
+
+	return m.GetIdempotentRequestFunc(p, p1, p2)
+}
+
+//GetIdempotentRequestMinimockCounter returns a count of ArtifactManagerMock.GetIdempotentRequestFunc invocations
+func (m *ArtifactManagerMock) GetIdempotentRequestMinimockCounter() uint64 {
+	return atomic.LoadUint64(&m.GetIdempotentRequestCounter)
+}
+
+//GetIdempotentRequestMinimockPreCounter returns the value of ArtifactManagerMock.GetIdempotentRequest invocations
+func (m *ArtifactManagerMock) GetIdempotentRequestMinimockPreCounter() uint64 {
+	return atomic.LoadUint64(&m.GetIdempotentRequestPreCounter)
+}
+
+//GetIdempotentRequestFinished returns true if mock invocations count is ok
+func (m *ArtifactManagerMock) GetIdempotentRequestFinished() bool {
+	// if expectation series were set then invocations count should be equal to expectations count
+	if len(m.GetIdempotentRequestMock.expectationSeries) > 0 {
+		return atomic.LoadUint64(&m.GetIdempotentRequestCounter) == uint64(len(m.GetIdempotentRequestMock.expectationSeries))
+	}
+
+	// if main expectation was set then invocations count should be greater than zero
+	if m.GetIdempotentRequestMock.mainExpectation != nil {
+		return atomic.LoadUint64(&m.GetIdempotentRequestCounter) > 0
+	}
+
+	// if func was set then invocations count should be greater than zero
+	if m.GetIdempotentRequestFunc != nil {
+		return atomic.LoadUint64(&m.GetIdempotentRequestCounter) > 0
+	}
+
+	return true
+}
+
 type mArtifactManagerMockGetObject struct {
 	mock              *ArtifactManagerMock
 	mainExpectation   *ArtifactManagerMockGetObjectExpectation
@@ -1805,6 +1976,158 @@ func (m *ArtifactManagerMock) GetPendingRequestFinished() bool {
 	return true
 }
 
+type mArtifactManagerMockGetRequestResult struct {
+	mock              *ArtifactManagerMock
+	mainExpectation   *ArtifactManagerMockGetRequestResultExpectation
+	expectationSeries []*ArtifactManagerMockGetRequestResultExpectation
+}
+
+type ArtifactManagerMockGetRequestResultExpectation struct {
+	input  *ArtifactManagerMockGetRequestResultInput
+	result *ArtifactManagerMockGetRequestResultResult
+}
+
+type ArtifactManagerMockGetRequestResultInput struct {
+	p  context.Context
+	p1 core.RecordRef
+	p2 core.RecordRef
+}
+
+type ArtifactManagerMockGetRequestResultResult struct {
+	r  *core.IdempotentRequest
+	r1 error
+}
+
+//Expect specifies that invocation of ArtifactManager.GetRequestResult is expected from 1 to Infinity times
+func (m *mArtifactManagerMockGetRequestResult) Expect(p context.Context, p1 core.RecordRef, p2 core.RecordRef) *mArtifactManagerMockGetRequestResult {
+	m.mock.GetRequestResultFunc = nil
+	m.expectationSeries = nil
+
+	if m.mainExpectation == nil {
+		m.mainExpectation = &ArtifactManagerMockGetRequestResultExpectation{}
+	}
+	m.mainExpectation.input = &ArtifactManagerMockGetRequestResultInput{p, p1, p2}
+	return m
+}
+
+//Return specifies results of invocation of ArtifactManager.GetRequestResult
+func (m *mArtifactManagerMockGetRequestResult) Return(r *core.IdempotentRequest, r1 error) *ArtifactManagerMock {
+	m.mock.GetRequestResultFunc = nil
+	m.expectationSeries = nil
+
+	if m.mainExpectation == nil {
+		m.mainExpectation = &ArtifactManagerMockGetRequestResultExpectation{}
+	}
+	m.mainExpectation.result = &ArtifactManagerMockGetRequestResultResult{r, r1}
+	return m.mock
+}
+
+//ExpectOnce specifies that invocation of ArtifactManager.GetRequestResult is expected once
+func (m *mArtifactManagerMockGetRequestResult) ExpectOnce(p context.Context, p1 core.RecordRef, p2 core.RecordRef) *ArtifactManagerMockGetRequestResultExpectation {
+	m.mock.GetRequestResultFunc = nil
+	m.mainExpectation = nil
+
+	expectation := &ArtifactManagerMockGetRequestResultExpectation{}
+	expectation.input = &ArtifactManagerMockGetRequestResultInput{p, p1, p2}
+	m.expectationSeries = append(m.expectationSeries, expectation)
+	return expectation
+}
+
+func (e *ArtifactManagerMockGetRequestResultExpectation) Return(r *core.IdempotentRequest, r1 error) {
+	e.result = &ArtifactManagerMockGetRequestResultResult{r, r1}
+}
+
+//Set uses given function f as a mock of ArtifactManager.GetRequestResult method
+func (m *mArtifactManagerMockGetRequestResult) Set(f func(p context.Context, p1 core.RecordRef, p2 core.RecordRef) (r *core.IdempotentRequest, r1 error)) *ArtifactManagerMock {
+	m.mainExpectation = nil
+	m.expectationSeries = nil
+
+	m.mock.GetRequestResultFunc = f
+	return m.mock
+}
+
+//GetRequestResult implements github.com/insolar/insolar/core.ArtifactManager interface
+func (m *ArtifactManagerMock) GetRequestResult(p context.Context, p1 core.RecordRef, p2 core.RecordRef) (r *core.IdempotentRequest, r1 error) {
+	counter := atomic.AddUint64(&m.GetRequestResultPreCounter, 1)
+	defer atomic.AddUint64(&m.GetRequestResultCounter, 1)
+
+	if len(m.GetRequestResultMock.expectationSeries) > 0 {
+		if counter > uint64(len(m.GetRequestResultMock.expectationSeries)) {
+			m.t.Fatalf("Unexpected call to ArtifactManagerMock.GetRequestResult. %v %v %v", p, p1, p2)
+			return
+		}
+
+		input := m.GetRequestResultMock.expectationSeries[counter-1].input
+		testify_assert.Equal(m.t, *input, ArtifactManagerMockGetRequestResultInput{p, p1, p2}, "ArtifactManager.GetRequestResult got unexpected parameters")
+
+		result := m.GetRequestResultMock.expectationSeries[counter-1].result
+		if result == nil {
+			m.t.Fatal("No results are set for the ArtifactManagerMock.GetRequestResult")
+			return
+		}
+
+		r = result.r
+		r1 = result.r1
+
+		return
+	}
+
+	if m.GetRequestResultMock.mainExpectation != nil {
+
+		input := m.GetRequestResultMock.mainExpectation.input
+		if input != nil {
+			testify_assert.Equal(m.t, *input, ArtifactManagerMockGetRequestResultInput{p, p1, p2}, "ArtifactManager.GetRequestResult got unexpected parameters")
+		}
+
+		result := m.GetRequestResultMock.mainExpectation.result
+		if result == nil {
+			m.t.Fatal("No results are set for the ArtifactManagerMock.GetRequestResult")
+		}
+
+		r = result.r
+		r1 = result.r1
+
+		return
+	}
+
+	if m.GetRequestResultFunc == nil {
+		m.t.Fatalf("Unexpected call to ArtifactManagerMock.GetRequestResult. %v %v %v", p, p1, p2)
+		return
+	}
+
+	return m.GetRequestResultFunc(p, p1, p2)
+}
+
+//GetRequestResultMinimockCounter returns a count of ArtifactManagerMock.GetRequestResultFunc invocations
+func (m *ArtifactManagerMock) GetRequestResultMinimockCounter() uint64 {
+	return atomic.LoadUint64(&m.GetRequestResultCounter)
+}
+
+//GetRequestResultMinimockPreCounter returns the value of ArtifactManagerMock.GetRequestResult invocations
+func (m *ArtifactManagerMock) GetRequestResultMinimockPreCounter() uint64 {
+	return atomic.LoadUint64(&m.GetRequestResultPreCounter)
+}
+
+//GetRequestResultFinished returns true if mock invocations count is ok
+func (m *ArtifactManagerMock) GetRequestResultFinished() bool {
+	// if expectation series were set then invocations count should be equal to expectations count
+	if len(m.GetRequestResultMock.expectationSeries) > 0 {
+		return atomic.LoadUint64(&m.GetRequestResultCounter) == uint64(len(m.GetRequestResultMock.expectationSeries))
+	}
+
+	// if main expectation was set then invocations count should be greater than zero
+	if m.GetRequestResultMock.mainExpectation != nil {
+		return atomic.LoadUint64(&m.GetRequestResultCounter) > 0
+	}
+
+	// if func was set then invocations count should be greater than zero
+	if m.GetRequestResultFunc != nil {
+		return atomic.LoadUint64(&m.GetRequestResultCounter) > 0
+	}
+
+	return true
+}
+
 type mArtifactManagerMockHasPendingRequests struct {
 	mock              *ArtifactManagerMock
 	mainExpectation   *ArtifactManagerMockHasPendingRequestsExpectation
@@ -2261,6 +2584,156 @@ func (m *ArtifactManagerMock) RegisterResultFinished() bool {
 	return true
 }
 
+type mArtifactManagerMockRegisterSeed struct {
+	mock              *ArtifactManagerMock
+	mainExpectation   *ArtifactManagerMockRegisterSeedExpectation
+	expectationSeries []*ArtifactManagerMockRegisterSeedExpectation
+}
+
+type ArtifactManagerMockRegisterSeedExpectation struct {
+	input  *ArtifactManagerMockRegisterSeedInput
+	result *ArtifactManagerMockRegisterSeedResult
+}
+
+type ArtifactManagerMockRegisterSeedInput struct {
+	p  context.Context
+	p1 core.RecordRef
+	p2 []byte
+	p3 core.PulseNumber
+}
+
+type ArtifactManagerMockRegisterSeedResult struct {
+	r error
+}
+
+//Expect specifies that invocation of ArtifactManager.RegisterSeed is expected from 1 to Infinity times
+func (m *mArtifactManagerMockRegisterSeed) Expect(p context.Context, p1 core.RecordRef, p2 []byte, p3 core.PulseNumber) *mArtifactManagerMockRegisterSeed {
+	m.mock.RegisterSeedFunc = nil
+	m.expectationSeries = nil
+
+	if m.mainExpectation == nil {
+		m.mainExpectation = &ArtifactManagerMockRegisterSeedExpectation{}
+	}
+	m.mainExpectation.input = &ArtifactManagerMockRegisterSeedInput{p, p1, p2, p3}
+	return m
+}
+
+//Return specifies results of invocation of ArtifactManager.RegisterSeed
+func (m *mArtifactManagerMockRegisterSeed) Return(r error) *ArtifactManagerMock {
+	m.mock.RegisterSeedFunc = nil
+	m.expectationSeries = nil
+
+	if m.mainExpectation == nil {
+		m.mainExpectation = &ArtifactManagerMockRegisterSeedExpectation{}
+	}
+	m.mainExpectation.result = &ArtifactManagerMockRegisterSeedResult{r}
+	return m.mock
+}
+
+//ExpectOnce specifies that invocation of ArtifactManager.RegisterSeed is expected once
+func (m *mArtifactManagerMockRegisterSeed) ExpectOnce(p context.Context, p1 core.RecordRef, p2 []byte, p3 core.PulseNumber) *ArtifactManagerMockRegisterSeedExpectation {
+	m.mock.RegisterSeedFunc = nil
+	m.mainExpectation = nil
+
+	expectation := &ArtifactManagerMockRegisterSeedExpectation{}
+	expectation.input = &ArtifactManagerMockRegisterSeedInput{p, p1, p2, p3}
+	m.expectationSeries = append(m.expectationSeries, expectation)
+	return expectation
+}
+
+func (e *ArtifactManagerMockRegisterSeedExpectation) Return(r error) {
+	e.result = &ArtifactManagerMockRegisterSeedResult{r}
+}
+
+//Set uses given function f as a mock of ArtifactManager.RegisterSeed method
+func (m *mArtifactManagerMockRegisterSeed) Set(f func(p context.Context, p1 core.RecordRef, p2 []byte, p3 core.PulseNumber) (r error)) *ArtifactManagerMock {
+	m.mainExpectation = nil
+	m.expectationSeries = nil
+
+	m.mock.RegisterSeedFunc = f
+	return m.mock
+}
+
+//RegisterSeed implements github.com/insolar/insolar/core.ArtifactManager interface
+func (m *ArtifactManagerMock) RegisterSeed(p context.Context, p1 core.RecordRef, p2 []byte, p3 core.PulseNumber) (r error) {
+	counter := atomic.AddUint64(&m.RegisterSeedPreCounter, 1)
+	defer atomic.AddUint64(&m.RegisterSeedCounter, 1)
+
+	if len(m.RegisterSeedMock.expectationSeries) > 0 {
+		if counter > uint64(len(m.RegisterSeedMock.expectationSeries)) {
+			m.t.Fatalf("Unexpected call to ArtifactManagerMock.RegisterSeed. %v %v %v %v", p, p1, p2, p3)
+			return
+		}
+
+		input := m.RegisterSeedMock.expectationSeries[counter-1].input
+		testify_assert.Equal(m.t, *input, ArtifactManagerMockRegisterSeedInput{p, p1, p2, p3}, "ArtifactManager.RegisterSeed got unexpected parameters")
+
+		result := m.RegisterSeedMock.expectationSeries[counter-1].result
+		if result == nil {
+			m.t.Fatal("No results are set for the ArtifactManagerMock.RegisterSeed")
+			return
+		}
+
+		r = result.r
+
+		return
+	}
+
+	if m.RegisterSeedMock.mainExpectation != nil {
+
+		input := m.RegisterSeedMock.mainExpectation.input
+		if input != nil {
+			testify_assert.Equal(m.t, *input, ArtifactManagerMockRegisterSeedInput{p, p1, p2, p3}, "ArtifactManager.RegisterSeed got unexpected parameters")
+		}
+
+		result := m.RegisterSeedMock.mainExpectation.result
+		if result == nil {
+			m.t.Fatal("No results are set for the ArtifactManagerMock.RegisterSeed")
+		}
+
+		r = result.r
+
+		return
+	}
+
+	if m.RegisterSeedFunc == nil {
+		m.t.Fatalf("Unexpected call to ArtifactManagerMock.RegisterSeed. %v %v %v %v", p, p1, p2, p3)
+		return
+	}
+
+	return m.RegisterSeedFunc(p, p1, p2, p3)
+}
+
+//RegisterSeedMinimockCounter returns a count of ArtifactManagerMock.RegisterSeedFunc invocations
+func (m *ArtifactManagerMock) RegisterSeedMinimockCounter() uint64 {
+	return atomic.LoadUint64(&m.RegisterSeedCounter)
+}
+
+//RegisterSeedMinimockPreCounter returns the value of ArtifactManagerMock.RegisterSeed invocations
+func (m *ArtifactManagerMock) RegisterSeedMinimockPreCounter() uint64 {
+	return atomic.LoadUint64(&m.RegisterSeedPreCounter)
+}
+
+//RegisterSeedFinished returns true if mock invocations count is ok
+func (m *ArtifactManagerMock) RegisterSeedFinished() bool {
+	// if expectation series were set then invocations count should be equal to expectations count
+	if len(m.RegisterSeedMock.expectationSeries) > 0 {
+		return atomic.LoadUint64(&m.RegisterSeedCounter) == uint64(len(m.RegisterSeedMock.expectationSeries))
+	}
+
+	// if main expectation was set then invocations count should be greater than zero
+	if m.RegisterSeedMock.mainExpectation != nil {
+		return atomic.LoadUint64(&m.RegisterSeedCounter) > 0
+	}
+
+	// if func was set then invocations count should be greater than zero
+	if m.RegisterSeedFunc != nil {
+		return atomic.LoadUint64(&m.RegisterSeedCounter) > 0
+	}
+
+	return true
+}
+
 type mArtifactManagerMockRegisterValidation struct {
 	mock              *ArtifactManagerMock
 	mainExpectation   *ArtifactManagerMockRegisterValidationExpectation
@@ -2898,6 +3371,10 @@ func (m *ArtifactManagerMock) ValidateCallCounters() {
 		m.t.Fatal("Expected call to ArtifactManagerMock.GetDelegate")
 	}
 
+	if !m.GetIdempotentRequestFinished() {
+		m.t.Fatal("Expected call to ArtifactManagerMock.GetIdempotentRequest")
+	}
+
 	if !m.GetObjectFinished() {
 		m.t.Fatal("Expected call to ArtifactManagerMock.GetObject")
 	}
@@ -2906,6 +3383,10 @@ func (m *ArtifactManagerMock) ValidateCallCounters() {
 		m.t.Fatal("Expected call to ArtifactManagerMock.GetPendingRequest")
 	}
 
+	if !m.GetRequestResultFinished() {
+		m.t.Fatal("Expected call to ArtifactManagerMock.GetRequestResult")
+	}
+
 	if !m.HasPendingRequestsFinished() {
 		m.t.Fatal("Expected call to ArtifactManagerMock.HasPendingRequests")
 	}
@@ -2918,6 +3399,10 @@ func (m *ArtifactManagerMock) ValidateCallCounters() {
 		m.t.Fatal("Expected call to ArtifactManagerMock.RegisterResult")
 	}
 
+	if !m.RegisterSeedFinished() {
+		m.t.Fatal("Expected call to ArtifactManagerMock.RegisterSeed")
+	}
+
 	if !m.RegisterValidationFinished() {
 		m.t.Fatal("Expected call to ArtifactManagerMock.RegisterValidation")
 	}
@@ -2987,6 +3472,10 @@ func (m *ArtifactManagerMock) MinimockFinish() {
 		m.t.Fatal("Expected call to ArtifactManagerMock.GetDelegate")
 	}
 
+	if !m.GetIdempotentRequestFinished() {
+		m.t.Fatal("Expected call to ArtifactManagerMock.GetIdempotentRequest")
+	}
+
 	if !m.GetObjectFinished() {
 		m.t.Fatal("Expected call to ArtifactManagerMock.GetObject")
 	}
@@ -2995,6 +3484,10 @@ func (m *ArtifactManagerMock) MinimockFinish() {
 		m.t.Fatal("Expected call to ArtifactManagerMock.GetPendingRequest")
 	}
 
+	if !m.GetRequestResultFinished() {
+		m.t.Fatal("Expected call to ArtifactManagerMock.GetRequestResult")
+	}
+
 	if !m.HasPendingRequestsFinished() {
 		m.t.Fatal("Expected call to ArtifactManagerMock.HasPendingRequests")
 	}
@@ -3007,6 +3500,10 @@ func (m *ArtifactManagerMock) MinimockFinish() {
 		m.t.Fatal("Expected call to ArtifactManagerMock.RegisterResult")
 	}
 
+	if !m.RegisterSeedFinished() {
+		m.t.Fatal("Expected call to ArtifactManagerMock.RegisterSeed")
+	}
+
 	if !m.RegisterValidationFinished() {
 		m.t.Fatal("Expected call to ArtifactManagerMock.RegisterValidation")
 	}
@@ -3046,11 +3543,14 @@ func (m *ArtifactManagerMock) MinimockWait(timeout time.Duration) {
 		ok = ok && m.GetChildrenFinished()
 		ok = ok && m.GetCodeFinished()
 		ok = ok && m.GetDelegateFinished()
+		ok = ok && m.GetIdempotentRequestFinished()
 		ok = ok && m.GetObjectFinished()
 		ok = ok && m.GetPendingRequestFinished()
+		ok = ok && m.GetRequestResultFinished()
 		ok = ok && m.HasPendingRequestsFinished()
 		ok = ok && m.RegisterRequestFinished()
 		ok = ok && m.RegisterResultFinished()
+		ok = ok && m.RegisterSeedFinished()
 		ok = ok && m.RegisterValidationFinished()
 		ok = ok && m.StateFinished()
 		ok = ok && m.UpdateObjectFinished()
@@ -3099,6 +3599,10 @@ func (m *ArtifactManagerMock) MinimockWait(timeout time.Duration) {
 				m.t.Error("Expected call to ArtifactManagerMock.GetDelegate")
 			}
 
+			if !m.GetIdempotentRequestFinished() {
+				m.t.Error("Expected call to ArtifactManagerMock.GetIdempotentRequest")
+			}
+
 			if !m.GetObjectFinished() {
 				m.t.Error("Expected call to ArtifactManagerMock.GetObject")
 			}
@@ -3107,6 +3611,10 @@ func (m *ArtifactManagerMock) MinimockWait(timeout time.Duration) {
 				m.t.Error("Expected call to ArtifactManagerMock.GetPendingRequest")
 			}
 
+			if !m.GetRequestResultFinished() {
+				m.t.Error("Expected call to ArtifactManagerMock.GetRequestResult")
+			}
+
 			if !m.HasPendingRequestsFinished() {
 				m.t.Error("Expected call to ArtifactManagerMock.HasPendingRequests")
 			}
@@ -3119,6 +3627,10 @@ func (m *ArtifactManagerMock) MinimockWait(timeout time.Duration) {
 				m.t.Error("Expected call to ArtifactManagerMock.RegisterResult")
 			}
 
+			if !m.RegisterSeedFinished() {
+				m.t.Error("Expected call to ArtifactManagerMock.RegisterSeed")
+			}
+
 			if !m.RegisterValidationFinished() {
 				m.t.Error("Expected call to ArtifactManagerMock.RegisterValidation")
 			}